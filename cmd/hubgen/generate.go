@@ -0,0 +1,373 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"regexp"
+	"slices"
+	"strings"
+	"text/template"
+	"unicode"
+)
+
+const hubImport = "github.com/ilyskies/QuestHub/pkg/hub"
+
+// packages a manifest type may be qualified with
+var knownImports = map[string]string{
+	"hub":  hubImport,
+	"time": "time",
+	"json": "encoding/json",
+}
+
+var hubQualifier = regexp.MustCompile(`\bhub\.`)
+
+type generator struct {
+	M       *Manifest
+	Source  string
+	imports map[string]bool
+}
+
+// generate renders the manifest as a gofmt'ed Go file; source names the
+// manifest in the header
+func generate(m *Manifest, source string) ([]byte, error) {
+	g := &generator{
+		M:       m,
+		Source:  source,
+		imports: map[string]bool{"context": true, "fmt": true, "sync": true},
+	}
+	if !m.inHub() {
+		g.imports[hubImport] = true
+	}
+
+	for _, model := range m.Models {
+		for _, f := range model.Fields {
+			if err := g.use(f.Type); err != nil {
+				return nil, fmt.Errorf("model %s field %s: %v", model.Name, f.Name, err)
+			}
+		}
+	}
+	for _, method := range m.Methods {
+		for _, a := range method.Args {
+			if err := g.use(a.Type); err != nil {
+				return nil, fmt.Errorf("method %s argument %s: %v", method.Name, a.Name, err)
+			}
+		}
+		if err := g.use(method.Returns); err != nil {
+			return nil, fmt.Errorf("method %s result: %v", method.Name, err)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := fileTemplate.Execute(&buf, g); err != nil {
+		return nil, err
+	}
+
+	out, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("formatting generated code: %v", err)
+	}
+	return out, nil
+}
+
+// use records the imports a type expression needs
+func (g *generator) use(typ string) error {
+	if typ == "" {
+		return nil
+	}
+	expr, err := parser.ParseExpr(typ)
+	if err != nil {
+		return fmt.Errorf("invalid type %q", typ)
+	}
+
+	ast.Inspect(expr, func(n ast.Node) bool {
+		sel, ok := n.(*ast.SelectorExpr)
+		if !ok || err != nil {
+			return err == nil
+		}
+		pkg, _ := sel.X.(*ast.Ident)
+		path, known := "", false
+		if pkg != nil {
+			path, known = knownImports[pkg.Name]
+		}
+		if !known {
+			err = fmt.Errorf("unknown package in %q; hubgen knows %s", typ, strings.Join(knownPackages(), ", "))
+			return false
+		}
+		if path != hubImport || !g.M.inHub() {
+			g.imports[path] = true
+		}
+		return false
+	})
+	return err
+}
+
+func knownPackages() []string {
+	names := make([]string, 0, len(knownImports))
+	for name := range knownImports {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+func (g *generator) InHub() bool {
+	return g.M.inHub()
+}
+
+func (g *generator) ByPointer(m Method) bool {
+	return byPointer(m.Returns)
+}
+
+// Imports lists the standard library imports, then the others
+func (g *generator) Imports() [][]string {
+	var std, other []string
+	for path := range g.imports {
+		if strings.Contains(path, ".") {
+			other = append(other, path)
+		} else {
+			std = append(std, path)
+		}
+	}
+	slices.Sort(std)
+	slices.Sort(other)
+
+	groups := [][]string{std}
+	if len(other) > 0 {
+		groups = append(groups, other)
+	}
+	return groups
+}
+
+// Type is typ as written in the generated package
+func (g *generator) Type(typ string) string {
+	if g.M.inHub() {
+		return hubQualifier.ReplaceAllString(typ, "")
+	}
+	return typ
+}
+
+// Hub qualifies a name from package hub
+func (g *generator) Hub(name string) string {
+	if g.M.inHub() {
+		return name
+	}
+	return "hub." + name
+}
+
+// Result is what a wrapper returns for the method's result: a pointer to
+// named types, the type itself for slices, maps and builtins
+func (g *generator) Result(m Method) string {
+	if byPointer(m.Returns) {
+		return "*" + g.Type(m.Returns)
+	}
+	return g.Type(m.Returns)
+}
+
+// Zero is the result returned alongside an error
+func (g *generator) Zero(m Method) string {
+	expr, _ := parser.ParseExpr(m.Returns)
+	switch t := expr.(type) {
+	case *ast.Ident:
+		switch {
+		case t.Name == "string":
+			return `""`
+		case t.Name == "bool":
+			return "false"
+		case t.Name == "any", t.Name == "error":
+			return "nil"
+		case builtins[t.Name]:
+			return "0"
+		}
+	case *ast.ArrayType:
+		if t.Len != nil {
+			return g.Type(m.Returns) + "{}"
+		}
+	}
+	return "nil"
+}
+
+func byPointer(typ string) bool {
+	expr, err := parser.ParseExpr(typ)
+	if err != nil {
+		return false
+	}
+	switch t := expr.(type) {
+	case *ast.Ident:
+		return !builtins[t.Name] && t.Name != "string" && t.Name != "bool"
+	case *ast.SelectorExpr:
+		return true
+	}
+	return false
+}
+
+// numeric builtins and the interface ones, which have no pointer form worth
+// returning
+var builtins = map[string]bool{
+	"int": true, "int8": true, "int16": true, "int32": true, "int64": true,
+	"uint": true, "uint8": true, "uint16": true, "uint32": true, "uint64": true,
+	"float32": true, "float64": true, "byte": true, "rune": true,
+	"any": true, "error": true,
+}
+
+func (g *generator) Params(m Method) string {
+	parts := make([]string, len(m.Args))
+	for i, a := range m.Args {
+		parts[i] = a.Name + " " + g.Type(a.Type)
+	}
+	return strings.Join(parts, ", ")
+}
+
+func (g *generator) ArgNames(m Method) string {
+	names := make([]string, len(m.Args))
+	for i, a := range m.Args {
+		names[i] = a.Name
+	}
+	return strings.Join(names, ", ")
+}
+
+func (g *generator) Tag(f Field) string {
+	name := f.JSON
+	if name == "" {
+		name = jsonName(f.Name)
+	}
+	if f.OmitEmpty {
+		name += ",omitempty"
+	}
+	return fmt.Sprintf("`json:%q`", name)
+}
+
+// jsonName follows the hub's wire names: lowerCamelCase with initialisms
+// written as words, e.g. QuestID -> questId, URLPath -> urlPath
+func jsonName(s string) string {
+	var words []string
+	r := []rune(s)
+	start := 0
+	for i := 1; i < len(r); i++ {
+		upper := unicode.IsUpper(r[i])
+		// a capital starts a word after a lower case letter, or ends a run of
+		// capitals when a lower case letter follows it
+		if upper && (unicode.IsLower(r[i-1]) || i+1 < len(r) && unicode.IsLower(r[i+1]) && unicode.IsUpper(r[i-1])) {
+			words = append(words, string(r[start:i]))
+			start = i
+		}
+	}
+	words = append(words, string(r[start:]))
+
+	var b strings.Builder
+	for i, w := range words {
+		w = strings.ToLower(w)
+		if i > 0 {
+			wr := []rune(w)
+			wr[0] = unicode.ToUpper(wr[0])
+			w = string(wr)
+		}
+		b.WriteString(w)
+	}
+	return b.String()
+}
+
+// Comment turns a doc string into // lines
+func (g *generator) Comment(doc string) string {
+	doc = strings.TrimSpace(doc)
+	if doc == "" {
+		return ""
+	}
+	lines := strings.Split(doc, "\n")
+	for i, l := range lines {
+		lines[i] = strings.TrimRight("// "+strings.TrimSpace(l), " ")
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+var fileTemplate = template.Must(template.New("file").Parse(`// Code generated by hubgen from {{.Source}}. DO NOT EDIT.
+
+package {{.M.Package}}
+
+import (
+{{- range $i, $group := .Imports}}{{if $i}}
+{{end}}
+{{- range $group}}
+	"{{.}}"
+{{- end}}
+{{- end}}
+)
+{{range .M.Models}}
+{{$.Comment .Doc}}type {{.Name}} struct {
+{{- range .Fields}}
+	{{.Name}} {{$.Type .Type}} {{$.Tag .}}
+{{- end}}
+}
+{{end}}
+// {{.M.Service}} is implemented by Client and {{.M.Mock}}
+type {{.M.Service}} interface {
+{{- range .M.Methods}}
+	{{.Name}}(ctx context.Context{{if .Args}}, {{$.Params .}}{{end}}, opts ...{{$.Hub "CallOption"}}) {{if .Returns}}({{$.Result .}}, error){{else}}error{{end}}
+{{- end}}
+}
+{{if not .InHub}}
+// Client adds the generated methods to a hub client
+type Client struct {
+	*hub.Client
+}
+
+func New(c *hub.Client) *Client {
+	return &Client{Client: c}
+}
+{{end}}
+var (
+	_ {{.M.Service}} = (*Client)(nil)
+	_ {{.M.Service}} = (*{{.M.Mock}})(nil)
+)
+{{range .M.Methods}}
+{{$.Comment .Doc}}func (c *Client) {{.Name}}(ctx context.Context{{if .Args}}, {{$.Params .}}{{end}}, opts ...{{$.Hub "CallOption"}}) {{if .Returns}}({{$.Result .}}, error){{else}}error{{end}} {
+{{- if not .Returns}}
+	_, err := c.{{if $.InHub}}invoke{{else}}RawInvoke{{end}}({{$.Hub "ContextWithCallOptions"}}(ctx, opts...), "{{.Name}}"{{if .Args}}, {{$.ArgNames .}}{{end}})
+	return err
+{{- else}}
+	out, err := {{$.Hub "Invoke"}}[{{$.Type .Returns}}]({{$.Hub "ContextWithCallOptions"}}(ctx, opts...), {{if $.InHub}}c{{else}}c.Client{{end}}, "{{.Name}}"{{if .Args}}, {{$.ArgNames .}}{{end}})
+	if err != nil {
+		return {{$.Zero .}}, err
+	}
+	return {{if $.ByPointer .}}&{{end}}out, nil
+{{- end}}
+}
+{{end}}
+// {{.M.Mock}} implements {{.M.Service}} with a func per method, for tests of code
+// built on the generated methods. A method whose func is nil fails with
+// {{$.Hub "ErrMethodNotFound"}}.
+type {{.M.Mock}} struct {
+{{- range .M.Methods}}
+	{{.Name}}Func func(ctx context.Context{{if .Args}}, {{$.Params .}}{{end}}) {{if .Returns}}({{$.Result .}}, error){{else}}error{{end}}
+{{- end}}
+
+	mu    sync.Mutex
+	calls map[string]int
+}
+
+// Calls reports how often method was called
+func (m *{{.M.Mock}}) Calls(method string) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.calls[method]
+}
+
+func (m *{{.M.Mock}}) record(method string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.calls == nil {
+		m.calls = make(map[string]int)
+	}
+	m.calls[method]++
+}
+{{range .M.Methods}}
+func (m *{{$.M.Mock}}) {{.Name}}(ctx context.Context{{if .Args}}, {{$.Params .}}{{end}}, _ ...{{$.Hub "CallOption"}}) {{if .Returns}}({{$.Result .}}, error){{else}}error{{end}} {
+	m.record("{{.Name}}")
+	if m.{{.Name}}Func == nil {
+		return {{if .Returns}}{{$.Zero .}}, {{end}}fmt.Errorf("%w: %s", {{$.Hub "ErrMethodNotFound"}}, "{{.Name}}")
+	}
+	return m.{{.Name}}Func(ctx{{if .Args}}, {{$.ArgNames .}}{{end}})
+}
+{{end}}`))
@@ -0,0 +1,29 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// the files generated in this repository must match what hubgen produces now
+func TestCheckedInOutputIsCurrent(t *testing.T) {
+	dir := filepath.Join("..", "..", "pkg", "hub")
+	m, err := loadManifest(filepath.Join(dir, "season.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := generate(m, "season.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := os.ReadFile(filepath.Join(dir, "season_gen.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Fatal("pkg/hub/season_gen.go is stale; run go generate ./pkg/hub")
+	}
+}
@@ -0,0 +1,191 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Manifest describes the hub methods to generate code for, and the models
+// their arguments and results use that the hub package does not have yet
+type Manifest struct {
+	// package of the generated file; "hub" puts the wrappers on hub.Client
+	Package string `json:"package" yaml:"package"`
+	// names of the generated interface and mock, Service and Mock by
+	// default; package hub already has a Service
+	Service string   `json:"service,omitempty" yaml:"service,omitempty"`
+	Mock    string   `json:"mock,omitempty" yaml:"mock,omitempty"`
+	Models  []Model  `json:"models,omitempty" yaml:"models,omitempty"`
+	Methods []Method `json:"methods" yaml:"methods"`
+}
+
+type Model struct {
+	Name   string  `json:"name" yaml:"name"`
+	Doc    string  `json:"doc,omitempty" yaml:"doc,omitempty"`
+	Fields []Field `json:"fields" yaml:"fields"`
+}
+
+type Field struct {
+	Name string `json:"name" yaml:"name"`
+	// a Go type; hub models are written qualified, e.g. []hub.QuestReward
+	Type string `json:"type" yaml:"type"`
+	// the wire name, lowerCamelCase of Name when empty
+	JSON      string `json:"json,omitempty" yaml:"json,omitempty"`
+	OmitEmpty bool   `json:"omitempty,omitempty" yaml:"omitempty,omitempty"`
+}
+
+type Method struct {
+	Name string `json:"name" yaml:"name"`
+	Doc  string `json:"doc,omitempty" yaml:"doc,omitempty"`
+	Args []Arg  `json:"args,omitempty" yaml:"args,omitempty"`
+	// the result type; empty for methods that return nothing
+	Returns string `json:"returns,omitempty" yaml:"returns,omitempty"`
+}
+
+type Arg struct {
+	Name string `json:"name" yaml:"name"`
+	Type string `json:"type" yaml:"type"`
+}
+
+// argument names the generated methods use themselves, or that would shadow
+// an import
+var reservedArgs = map[string]bool{
+	"ctx": true, "opts": true, "out": true, "err": true, "m": true, "c": true,
+	"context": true, "fmt": true, "sync": true, "hub": true, "time": true, "json": true,
+}
+
+func loadManifest(path string) (*Manifest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	m := &Manifest{}
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".yaml", ".yml":
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+		err = dec.Decode(m)
+	case ".json":
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+		err = dec.Decode(m)
+	default:
+		err = fmt.Errorf("unsupported manifest format %q", ext)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+
+	if err := m.validate(); err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	return m, nil
+}
+
+// inHub reports whether the code is generated into package hub itself
+func (m *Manifest) inHub() bool {
+	return m.Package == "hub"
+}
+
+func (m *Manifest) validate() error {
+	if !token.IsIdentifier(m.Package) {
+		return fmt.Errorf("invalid package name %q", m.Package)
+	}
+	if len(m.Methods) == 0 {
+		return errors.New("no methods")
+	}
+	if m.Service == "" {
+		m.Service = "Service"
+	}
+	if m.Mock == "" {
+		m.Mock = "Mock"
+	}
+
+	declared := make(map[string]bool)
+	declare := func(kind, name string) error {
+		if !token.IsIdentifier(name) || !token.IsExported(name) {
+			return fmt.Errorf("%s name %q is not an exported identifier", kind, name)
+		}
+		if declared[name] {
+			return fmt.Errorf("%s name %q is already taken", kind, name)
+		}
+		declared[name] = true
+		return nil
+	}
+	if err := declare("service", m.Service); err != nil {
+		return err
+	}
+	if err := declare("mock", m.Mock); err != nil {
+		return err
+	}
+	// Calls is a method of the mock
+	declared["Calls"] = true
+	if !m.inHub() {
+		declared["Client"], declared["New"] = true, true
+	}
+
+	for _, model := range m.Models {
+		if err := declare("model", model.Name); err != nil {
+			return err
+		}
+		fields := make(map[string]bool)
+		for _, f := range model.Fields {
+			if !token.IsIdentifier(f.Name) || !token.IsExported(f.Name) || fields[f.Name] {
+				return fmt.Errorf("model %s: invalid or duplicate field %q", model.Name, f.Name)
+			}
+			fields[f.Name] = true
+			if err := checkType(f.Type); err != nil {
+				return fmt.Errorf("model %s field %s: %v", model.Name, f.Name, err)
+			}
+		}
+	}
+
+	for _, method := range m.Methods {
+		if err := declare("method", method.Name); err != nil {
+			return err
+		}
+		args := make(map[string]bool)
+		for _, a := range method.Args {
+			if !token.IsIdentifier(a.Name) || reservedArgs[a.Name] || args[a.Name] {
+				return fmt.Errorf("method %s: invalid or duplicate argument %q", method.Name, a.Name)
+			}
+			args[a.Name] = true
+			if err := checkType(a.Type); err != nil {
+				return fmt.Errorf("method %s argument %s: %v", method.Name, a.Name, err)
+			}
+		}
+		if method.Returns != "" {
+			if err := checkType(method.Returns); err != nil {
+				return fmt.Errorf("method %s result: %v", method.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// checkType accepts any Go type expression, so the generated file fails to
+// compile rather than hubgen guessing what a type means
+func checkType(typ string) error {
+	if strings.TrimSpace(typ) == "" {
+		return errors.New("missing type")
+	}
+	expr, err := parser.ParseExpr(typ)
+	if err != nil {
+		return fmt.Errorf("invalid type %q", typ)
+	}
+	switch expr.(type) {
+	case *ast.Ident, *ast.SelectorExpr, *ast.StarExpr, *ast.ArrayType, *ast.MapType, *ast.InterfaceType:
+		return nil
+	}
+	return fmt.Errorf("invalid type %q", typ)
+}
@@ -0,0 +1,474 @@
+// Command questhub-gateway connects to a QuestHub SignalR hub and serves its
+// data over gRPC (questhub.v1.QuestHubService) and REST (/v1/...), for
+// consumers that cannot speak SignalR.
+//
+//	questhub-gateway [-config questhub.yaml] [-grpc-addr :9090] [-http-addr :8080]
+//
+// The hub client is set up from -config and the QUESTHUB_* environment, see
+// hub.LoadConfig. With -file the gateway serves an export from
+// questhub export instead of a live hub. /healthz and the gRPC health
+// service report whether the hub can be read. /calendar.ics is an
+// iCalendar feed of bundle unlocks.
+//
+// -graphql adds POST /graphql, answered from a local store that a watcher
+// keeps in sync with the hub, so GraphQL queries never wait on it. -feed
+// adds /feed.atom and /feed.rss, listing the bundles and daily quests the
+// same watcher sees appear. -bootstrap URL starts both from a journal
+// archive, a store.FileJournal published e.g. to a bucket, instead of the
+// hub's first full snapshot; the watcher then reports only what changed
+// since the archive, after its first interval.
+//
+// -signing-key FILE serves /exports/latest.json, a full export, to links
+// signed with the key in FILE and nobody else. -sign URL prints such a link,
+// valid for -sign-ttl, and exits:
+//
+//	questhub-gateway -signing-key key -sign https://gw.example.com/exports/latest.json
+//
+// -patch FILE applies an override file to everything served, correcting
+// values the hub gets wrong and hiding excluded entries, see package
+// override.
+//
+// -opaque-ids FILE answers every API, the feeds and exports included, with
+// short random slugs instead of the hub's quest and template IDs, kept in a
+// bolt store at FILE so links stay valid across restarts.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"syscall"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+
+	"github.com/ilyskies/QuestHub/pkg/feed"
+	"github.com/ilyskies/QuestHub/pkg/gateway"
+	"github.com/ilyskies/QuestHub/pkg/hub"
+	"github.com/ilyskies/QuestHub/pkg/lifecycle"
+	"github.com/ilyskies/QuestHub/pkg/override"
+	"github.com/ilyskies/QuestHub/pkg/store"
+	"github.com/ilyskies/QuestHub/pkg/store/boltstore"
+)
+
+type options struct {
+	config   string
+	file     string
+	grpcAddr string
+	httpAddr string
+	graphql  bool
+	feed     bool
+	idFile   string
+	patch    string
+	// journal archive the GraphQL store and the feed start from
+	bootstrap string
+
+	signingKey string
+	sign       string
+	signTTL    time.Duration
+
+	watchInterval   time.Duration
+	healthInterval  time.Duration
+	shutdownTimeout time.Duration
+}
+
+func main() {
+	var o options
+
+	fs := flag.NewFlagSet("questhub-gateway", flag.ExitOnError)
+	fs.StringVar(&o.config, "config", "", "client config `file` (YAML or JSON); QUESTHUB_* variables apply on top")
+	fs.StringVar(&o.file, "file", "", "serve an export `file or directory` instead of a live hub")
+	fs.StringVar(&o.grpcAddr, "grpc-addr", ":9090", "gRPC listen address; empty disables gRPC")
+	fs.StringVar(&o.httpAddr, "http-addr", ":8080", "REST and /healthz listen address; empty disables HTTP")
+	fs.BoolVar(&o.graphql, "graphql", false, "serve GraphQL at /graphql on -http-addr")
+	fs.BoolVar(&o.feed, "feed", false, "serve an Atom and RSS feed of new quests and bundles at /feed.atom and /feed.rss on -http-addr")
+	fs.StringVar(&o.signingKey, "signing-key", "", "serve /exports/latest.json on -http-addr to URLs signed with the key in this `file`")
+	fs.StringVar(&o.sign, "sign", "", "print `URL` signed with -signing-key and exit")
+	fs.DurationVar(&o.signTTL, "sign-ttl", 24*time.Hour, "how long a -sign URL stays valid")
+	fs.StringVar(&o.patch, "patch", "", "serve hub data corrected by this override `file` (YAML or JSON)")
+	fs.StringVar(&o.bootstrap, "bootstrap", "", "start the GraphQL store and the feed from the journal archive at this `URL` instead of a full hub poll")
+	fs.StringVar(&o.idFile, "opaque-ids", "", "hide hub IDs behind public slugs kept in this bolt `file`")
+	fs.DurationVar(&o.watchInterval, "watch-interval", time.Minute, "how often the GraphQL store and the feed poll the hub for changes")
+	fs.DurationVar(&o.healthInterval, "health-interval", 10*time.Second, "how often the gRPC health status is refreshed")
+	fs.DurationVar(&o.shutdownTimeout, "shutdown-timeout", 30*time.Second, "time allowed for a graceful shutdown")
+	_ = fs.Parse(os.Args[1:])
+
+	if err := run(o); err != nil {
+		fmt.Fprintf(os.Stderr, "questhub-gateway: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(o options) error {
+	var signer *gateway.Signer
+	if o.signingKey != "" {
+		key, err := os.ReadFile(o.signingKey)
+		if err != nil {
+			return err
+		}
+		if signer, err = gateway.NewSigner([]byte(strings.TrimSpace(string(key)))); err != nil {
+			return err
+		}
+	}
+	if o.sign != "" {
+		if signer == nil {
+			return errors.New("-sign needs -signing-key")
+		}
+		link, err := signer.Sign(o.sign, o.signTTL)
+		if err != nil {
+			return err
+		}
+		fmt.Println(link)
+		return nil
+	}
+
+	if o.grpcAddr == "" && o.httpAddr == "" {
+		return errors.New("both -grpc-addr and -http-addr are empty")
+	}
+	if o.graphql && o.httpAddr == "" {
+		return errors.New("-graphql needs -http-addr")
+	}
+	if o.feed && o.httpAddr == "" {
+		return errors.New("-feed needs -http-addr")
+	}
+	if o.bootstrap != "" && (o.file != "" || (!o.graphql && !o.feed)) {
+		return errors.New("-bootstrap needs a live hub and -graphql or -feed")
+	}
+	if signer != nil && o.httpAddr == "" {
+		return errors.New("-signing-key needs -http-addr")
+	}
+
+	var patch *override.Patch
+	if o.patch != "" {
+		var err error
+		if patch, err = override.Load(o.patch); err != nil {
+			return err
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// the watcher needs the backend itself and patches its snapshots
+	raw, checker, stopBackend, err := backend(ctx, o)
+	if err != nil {
+		return err
+	}
+	svc := raw
+	if patch != nil {
+		// Load validated it already
+		svc, _ = patch.Service(raw)
+	}
+	var gwOpts []gateway.Option
+	var gqlOpts []gateway.GraphQLOption
+	var ids *store.Store
+	if o.idFile != "" {
+		b, err := boltstore.Open(o.idFile)
+		if err != nil {
+			return err
+		}
+		if ids, err = store.Open(ctx, b); err != nil {
+			b.Close()
+			return err
+		}
+		mapper := gateway.StoreIDs(ids)
+		gwOpts = append(gwOpts, gateway.WithIDMapper(mapper))
+		gqlOpts = append(gqlOpts, gateway.WithGraphQLIDMapper(mapper))
+	}
+	srv := gateway.New(svc, gwOpts...)
+
+	// stages run in registration order: the servers stop first so requests
+	// in flight can still reach the hub
+	lc := lifecycle.New()
+	errc := make(chan error, 2)
+	var stopFollow lifecycle.StopFunc
+
+	if o.grpcAddr != "" {
+		lis, err := net.Listen("tcp", o.grpcAddr)
+		if err != nil {
+			return err
+		}
+
+		gs := grpc.NewServer()
+		srv.Register(gs)
+
+		hs := health.NewServer()
+		healthpb.RegisterHealthServer(gs, hs)
+		go reportHealth(ctx, hs, checker, o.healthInterval)
+
+		go func() { errc <- gs.Serve(lis) }()
+		slog.Info("Serving gRPC", "addr", lis.Addr().String())
+
+		_ = lc.Register("grpc server", 0, func(ctx context.Context) error {
+			hs.Shutdown()
+			stopped := make(chan struct{})
+			go func() {
+				gs.GracefulStop()
+				close(stopped)
+			}()
+			select {
+			case <-stopped:
+				return nil
+			case <-ctx.Done():
+				gs.Stop()
+				return ctx.Err()
+			}
+		})
+	}
+
+	if o.httpAddr != "" {
+		mux := http.NewServeMux()
+		mux.Handle("/v1/", srv.Handler())
+		mux.Handle("/healthz", hub.HealthHandler(checker))
+		mux.Handle("GET /calendar.ics", srv.Calendar())
+		if signer != nil {
+			mux.Handle("GET /exports/", signer.Protect(srv.Exports()))
+		}
+
+		var st *store.Store
+		var changes *feed.Log
+		if o.graphql {
+			st = store.New()
+			gql, err := gateway.NewGraphQL(st, gqlOpts...)
+			if err != nil {
+				return err
+			}
+			mux.Handle("POST /graphql", gql)
+		}
+		if o.feed {
+			changes = feed.NewLog(feed.DefaultLogSize)
+			mux.Handle("GET /feed.atom", srv.Changes(changes, feed.FormatAtom, "QuestHub changes"))
+			mux.Handle("GET /feed.rss", srv.Changes(changes, feed.FormatRSS, "QuestHub changes"))
+		}
+		if st != nil || changes != nil {
+			var archive store.Journal
+			if o.bootstrap != "" {
+				if archive, err = store.FetchJournal(ctx, nil, o.bootstrap); err != nil {
+					return err
+				}
+			}
+			stopFollow, err = follow(ctx, raw, o.watchInterval, st, changes, patch, archive)
+			if err != nil {
+				return err
+			}
+		}
+
+		lis, err := net.Listen("tcp", o.httpAddr)
+		if err != nil {
+			return err
+		}
+		hsrv := &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+
+		go func() { errc <- hsrv.Serve(lis) }()
+		slog.Info("Serving REST", "addr", lis.Addr().String())
+
+		_ = lc.Register("http server", 0, hsrv.Shutdown)
+	}
+
+	if stopFollow != nil {
+		_ = lc.Register("watcher", 0, stopFollow)
+	}
+	if stopBackend != nil {
+		_ = lc.Register("hub client", 0, stopBackend)
+	}
+	if ids != nil {
+		_ = lc.Register("id store", 0, func(context.Context) error { return ids.Close() })
+	}
+
+	lc.OnStageDone(func(r lifecycle.StageResult) {
+		if r.Err != nil {
+			slog.Warn("Stopped", "stage", r.Name, "took", r.Duration, "err", r.Err)
+			return
+		}
+		slog.Info("Stopped", "stage", r.Name, "took", r.Duration)
+	})
+
+	go func() {
+		if err := <-errc; err != nil && !errors.Is(err, http.ErrServerClosed) {
+			slog.Error("Server failed", "err", err)
+			cancel()
+		}
+	}()
+
+	return lc.ShutdownOnSignal(ctx, o.shutdownTimeout, os.Interrupt, syscall.SIGTERM)
+}
+
+// backend is the live hub client, or a FileBackend with -file
+func backend(ctx context.Context, o options) (hub.Service, hub.HealthChecker, lifecycle.StopFunc, error) {
+	if o.file != "" {
+		fb, err := hub.NewFileBackend(o.file)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		return fb, fileHealth{}, nil, nil
+	}
+
+	cfg, err := hub.LoadConfig(o.config)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	client, err := hub.NewClientFromConfig(cfg)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	// until the hub is reachable reads fail as Unavailable and /healthz
+	// reports it
+	if err := client.Connect(); err != nil {
+		slog.Warn("Initial connect failed", "url", cfg.URL, "err", err)
+	}
+	go keepConnected(ctx, client, o.healthInterval)
+	return client, client, client.DisconnectGraceful, nil
+}
+
+// the client only reconnects while retrying a read, and never after a
+// failed first connect, so a long running gateway reconnects it itself
+func keepConnected(ctx context.Context, client *hub.Client, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+
+		if client.State() != hub.StateDisconnected {
+			continue
+		}
+		if err := client.Connect(); err != nil {
+			slog.Warn("Reconnect failed", "err", err)
+		}
+	}
+}
+
+// follow fills st and changes, either of which may be nil, from svc: one
+// watcher applies the hub's changes as they happen, while an export is
+// loaded once. svc is the backend itself, not a patch.Service; a non-nil
+// patch is applied to every snapshot instead. A non-nil archive fills
+// both before the watcher starts, which then diffs against it.
+func follow(ctx context.Context, svc hub.Service, interval time.Duration, st *store.Store, changes *feed.Log, patch *override.Patch, archive store.Journal) (lifecycle.StopFunc, error) {
+	switch src := svc.(type) {
+	case *hub.Client:
+		// the first poll is reported as additions, which fills the store and
+		// starts the feed with what the hub serves now
+		watchOpts := []hub.WatcherOption{hub.WatchEmitInitial(), hub.WatchInterval(interval)}
+		if archive != nil {
+			from, err := bootstrap(ctx, archive, st, changes)
+			if err != nil {
+				return nil, err
+			}
+			watchOpts = append(watchOpts, hub.WatchFrom(from))
+		}
+		if patch != nil {
+			watchOpts = append(watchOpts, hub.WatchTransform(patch.Apply))
+		}
+		w := hub.NewWatcher(src, watchOpts...)
+
+		// the store needs every change, so it reads Events; the feed makes
+		// do with a subscription when the store is there
+		events := w.Events()
+		if changes != nil && st != nil {
+			sub, unsubscribe := w.Subscribe(hub.QuestAdded, hub.BundleAdded)
+			go func() {
+				defer unsubscribe()
+				_ = changes.Consume(ctx, sub)
+			}()
+		}
+		if err := w.Start(ctx); err != nil {
+			return nil, err
+		}
+
+		go func() {
+			var err error
+			if st != nil {
+				err = st.Consume(ctx, events)
+			} else {
+				err = changes.Consume(ctx, events)
+			}
+			if err != nil && ctx.Err() == nil {
+				slog.Error("Watcher consumer stopped", "err", err)
+			}
+		}()
+		return w.Stop, nil
+
+	case *hub.FileBackend:
+		snap, err := src.Snapshot(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if patch != nil {
+			if err := patch.Apply(ctx, snap); err != nil {
+				return nil, err
+			}
+		}
+		if changes != nil {
+			changes.Add(feed.EntriesFrom(hub.Diff(nil, snap), time.Now())...)
+		}
+		if st == nil {
+			return nil, nil
+		}
+		return nil, st.Reset(ctx, snap)
+
+	default:
+		return nil, fmt.Errorf("no watcher for %T", svc)
+	}
+}
+
+// bootstrap fills st and changes, either of which may be nil, from a
+// journal archive and returns the snapshot it ends with. A patch the
+// archive was written without is made up by the watcher's first changes.
+func bootstrap(ctx context.Context, archive store.Journal, st *store.Store, changes *feed.Log) (*hub.Snapshot, error) {
+	if st == nil {
+		st = store.New()
+	}
+	filled, err := st.Bootstrap(ctx, archive)
+	if err != nil {
+		return nil, err
+	}
+	if changes != nil {
+		if err := store.Replay(ctx, archive, func(cs hub.ChangeSet) error { return changes.Publish(ctx, cs) }); err != nil {
+			return nil, err
+		}
+	}
+	slog.Info("Bootstrapped from archive", "entries", archive.Next(), "filled", filled)
+	return st.Snapshot(), nil
+}
+
+// exported data is always readable
+type fileHealth struct{}
+
+func (fileHealth) Healthz(context.Context) error { return nil }
+
+func reportHealth(ctx context.Context, hs *health.Server, checker hub.HealthChecker, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		checkCtx, cancel := context.WithTimeout(ctx, interval)
+		err := checker.Healthz(checkCtx)
+		cancel()
+
+		st := healthpb.HealthCheckResponse_SERVING
+		if err != nil {
+			st = healthpb.HealthCheckResponse_NOT_SERVING
+		}
+		hs.SetServingStatus("", st)
+		hs.SetServingStatus("questhub.v1.QuestHubService", st)
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ilyskies/QuestHub/pkg/export"
+	"github.com/ilyskies/QuestHub/pkg/feed"
+	"github.com/ilyskies/QuestHub/pkg/hub"
+	"github.com/ilyskies/QuestHub/pkg/hubtest"
+	"github.com/ilyskies/QuestHub/pkg/override"
+	"github.com/ilyskies/QuestHub/pkg/store"
+)
+
+const bundleID = "ChallengeBundle:QuestBundle_Week_001"
+
+// -patch with -graphql and -feed: the store and the feed see the patched
+// data, from a live hub and from an export alike
+func TestFollowPatched(t *testing.T) {
+	srv := hubtest.NewServer(hubtest.DefaultFixtures())
+	defer srv.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	client, err := srv.NewClient(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Disconnect()
+
+	path := filepath.Join(t.TempDir(), "patch.yaml")
+	data := "quests:\n  - id: Quest_Daily_Eliminations\n    exclude: true\nbundles:\n  - id: " + bundleID + "\n    set: {rarity: Rare}\n"
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	patch, err := override.Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	exported, err := export.New(client).ExportDir(ctx, t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	fb, err := hub.NewFileBackend(exported)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, svc := range map[string]hub.Service{"hub": client, "file": fb} {
+		st, changes := store.New(), feed.NewLog(feed.DefaultLogSize)
+		stop, err := follow(ctx, svc, time.Hour, st, changes, patch, nil)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if stop != nil {
+			defer stop(ctx)
+		}
+
+		for _, ok := st.Bundle(bundleID); !ok || len(changes.Entries()) == 0; _, ok = st.Bundle(bundleID) {
+			if ctx.Err() != nil {
+				t.Fatalf("%s: nothing followed", name)
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+		if _, ok := st.Quest("Quest_Daily_Eliminations"); ok {
+			t.Errorf("%s: excluded quest in the store", name)
+		}
+		if b, ok := st.Bundle(bundleID); !ok || b.Rarity != "Rare" {
+			t.Errorf("%s: bundle = %+v, want rarity Rare", name, b)
+		}
+		for _, e := range changes.Entries() {
+			if e.ID == "Quest_Daily_Eliminations" {
+				t.Errorf("%s: excluded quest in the feed", name)
+			}
+		}
+	}
+}
+
+// -bootstrap: the store and the feed are filled from the archive before
+// the watcher polls the hub
+func TestFollowBootstrap(t *testing.T) {
+	srv := hubtest.NewServer(hubtest.DefaultFixtures())
+	defer srv.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	client, err := srv.NewClient(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Disconnect()
+
+	snap, err := client.Snapshot(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	archive := store.NewMemoryJournal()
+	if err := store.New(store.WithJournal(archive)).Reset(ctx, snap); err != nil {
+		t.Fatal(err)
+	}
+	polled := srv.Calls("GetDailyQuests")
+
+	st, changes := store.New(), feed.NewLog(feed.DefaultLogSize)
+	stop, err := follow(ctx, client, time.Hour, st, changes, nil, archive)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stop(ctx)
+
+	if _, ok := st.Bundle(bundleID); !ok {
+		t.Error("bundle from the archive missing")
+	}
+	if len(changes.Entries()) == 0 {
+		t.Error("feed not started from the archive")
+	}
+	if n := srv.Calls("GetDailyQuests") - polled; n != 0 {
+		t.Errorf("%d polls instead of starting from the archive", n)
+	}
+}
@@ -0,0 +1,758 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ilyskies/QuestHub/pkg/contract"
+	"github.com/ilyskies/QuestHub/pkg/export"
+	"github.com/ilyskies/QuestHub/pkg/feed"
+	"github.com/ilyskies/QuestHub/pkg/hub"
+	"github.com/ilyskies/QuestHub/pkg/hubtest"
+	"github.com/ilyskies/QuestHub/pkg/loadtest"
+	"github.com/ilyskies/QuestHub/pkg/plugin"
+	"github.com/ilyskies/QuestHub/pkg/store"
+)
+
+func (a *app) status(ctx context.Context) error {
+	client, err := a.connect(ctx)
+	if err != nil {
+		return err
+	}
+	defer client.Disconnect()
+
+	status, err := client.GetServiceStatus(ctx)
+	if err != nil {
+		return err
+	}
+
+	if a.output == "json" {
+		return writeJSON(status)
+	}
+
+	info := client.ConnectionInfo()
+	t := newTable("FIELD", "VALUE")
+	t.row("version", status.Version)
+	t.row("initialized", status.Initialized)
+	t.row("timestamp", status.Timestamp.Format("2006-01-02 15:04:05Z07:00"))
+	t.row("transport", info.Transport)
+	t.row("connection", info.ConnectionID)
+	return t.flush()
+}
+
+func (a *app) questsList(ctx context.Context) error {
+	client, err := a.connect(ctx)
+	if err != nil {
+		return err
+	}
+	defer client.Disconnect()
+
+	quests, err := client.GetDailyQuests(ctx)
+	if err != nil {
+		return err
+	}
+
+	if a.output == "json" {
+		return writeJSON(quests)
+	}
+
+	ids := make([]string, 0, len(quests))
+	for id := range quests {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	t := newTable("ID", "COUNT", "OBJECTIVES", "REWARDS")
+	for _, id := range ids {
+		q := quests[id]
+		t.row(id, q.Count, objectivesSummary(q.Objectives), rewardsSummary(q.Rewards))
+	}
+	return t.flush()
+}
+
+func (a *app) questsGet(ctx context.Context, id string) error {
+	client, err := a.connect(ctx)
+	if err != nil {
+		return err
+	}
+	defer client.Disconnect()
+
+	quest, err := client.GetDailyQuest(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if a.output == "json" {
+		return writeJSON(quest)
+	}
+
+	t := newTable("KIND", "NAME", "AMOUNT")
+	for _, o := range quest.Objectives {
+		t.row("objective", o.BackendName, o.Count)
+	}
+	for _, r := range quest.Rewards {
+		t.row("reward", r.TemplateID, r.Quantity)
+	}
+	return t.flush()
+}
+
+func (a *app) bundlesList(ctx context.Context) error {
+	client, err := a.connect(ctx)
+	if err != nil {
+		return err
+	}
+	defer client.Disconnect()
+
+	bundles, err := client.GetChallengeBundles(ctx)
+	if err != nil {
+		return err
+	}
+
+	if a.output == "json" {
+		return writeJSON(bundles)
+	}
+
+	t := newTable("TEMPLATE", "SCHEDULE", "RARITY", "QUESTS")
+	for _, b := range bundles {
+		t.row(b.TemplateID, b.ChallengeBundleSchedule, b.Rarity, len(b.Objects))
+	}
+	return t.flush()
+}
+
+func (a *app) bundlesGet(ctx context.Context, id string) error {
+	client, err := a.connect(ctx)
+	if err != nil {
+		return err
+	}
+	defer client.Disconnect()
+
+	bundle, err := client.GetChallengeBundle(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if a.output == "json" {
+		return writeJSON(bundle)
+	}
+
+	t := newTable("QUEST", "RARITY", "OBJECTIVES", "REWARDS")
+	for _, o := range bundle.Objects {
+		objectives := make([]string, 0, len(o.Objectives))
+		for _, obj := range o.Objectives {
+			objectives = append(objectives, fmt.Sprintf("%s=%d", obj.BackendName, obj.Count))
+		}
+		rewards := make([]string, 0, len(o.Rewards))
+		for _, r := range o.Rewards {
+			rewards = append(rewards, fmt.Sprintf("%s x%d", r.TemplateID, r.Quantity))
+		}
+		t.row(o.QuestDefinition, o.Rarity, strings.Join(objectives, ", "), strings.Join(rewards, ", "))
+	}
+	return t.flush()
+}
+
+func (a *app) schedules(ctx context.Context) error {
+	client, err := a.connect(ctx)
+	if err != nil {
+		return err
+	}
+	defer client.Disconnect()
+
+	schedules, err := client.GetChallengeBundleSchedules(ctx)
+	if err != nil {
+		return err
+	}
+
+	if a.output == "json" {
+		return writeJSON(schedules)
+	}
+
+	t := newTable("TEMPLATE", "BUNDLE", "ACTIVE FROM", "ACTIVE UNTIL", "VISIBILITY")
+	for _, s := range schedules {
+		t.row(s.TemplateID, s.QuestBundle, windowEdge(s.ActiveFrom), windowEdge(s.ActiveUntil), s.Visibility)
+	}
+	return t.flush()
+}
+
+func (a *app) calendar(ctx context.Context, dest string) error {
+	client, err := a.connect(ctx)
+	if err != nil {
+		return err
+	}
+	defer client.Disconnect()
+
+	schedules, err := client.GetChallengeBundleSchedules(ctx)
+	if err != nil {
+		return err
+	}
+	season, err := client.GetSeasonInfo(ctx)
+	if err != nil {
+		// the events only lose their week numbers
+		fmt.Fprintf(os.Stderr, "no season info: %v\n", err)
+		season = nil
+	}
+	unlocks := feed.Unlocks(schedules, season)
+
+	if dest == "-" {
+		return feed.WriteICS(os.Stdout, "QuestHub bundle unlocks", unlocks, time.Now())
+	}
+	f, err := os.Create(dest)
+	if err != nil {
+		return err
+	}
+	if err := feed.WriteICS(f, "QuestHub bundle unlocks", unlocks, time.Now()); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func (a *app) cacheClear(ctx context.Context, patterns []string) error {
+	client, err := a.connect(ctx)
+	if err != nil {
+		return err
+	}
+	defer client.Disconnect()
+
+	var result *hub.CacheResult
+	if len(patterns) > 0 {
+		result, err = client.ClearCacheByPattern(ctx, patterns)
+	} else {
+		result, err = client.ClearCache(ctx)
+	}
+	if err != nil {
+		return err
+	}
+
+	if a.output == "json" {
+		return writeJSON(result)
+	}
+
+	t := newTable("FIELD", "VALUE")
+	t.row("success", result.Success)
+	t.row("keys cleared", result.KeysCleared)
+	t.row("patterns", strings.Join(result.Patterns, ", "))
+	return t.flush()
+}
+
+func (a *app) cacheRefresh(ctx context.Context) error {
+	client, err := a.connect(ctx)
+	if err != nil {
+		return err
+	}
+	defer client.Disconnect()
+
+	if a.waitRefresh {
+		client.OnCacheRefreshProgress(func(p hub.RefreshProgress) {
+			if !p.Done {
+				fmt.Fprintf(os.Stderr, "%s %d/%d (%.0f%%)\n", p.Stage, p.Completed, p.Total, p.Percent())
+			}
+		})
+	}
+
+	jobID, err := client.RefreshCache(ctx)
+	if err != nil {
+		return err
+	}
+
+	if a.waitRefresh && jobID != "" {
+		if _, err := client.WaitForRefresh(ctx, jobID); err != nil {
+			return err
+		}
+	}
+
+	if a.output == "json" {
+		return writeJSON(map[string]interface{}{"refreshed": jobID == "" || a.waitRefresh, "jobId": jobID})
+	}
+	if jobID != "" && !a.waitRefresh {
+		fmt.Printf("cache refresh started, job %s\n", jobID)
+		return nil
+	}
+	fmt.Println("cache refreshed")
+	return nil
+}
+
+func (a *app) watch(ctx context.Context) error {
+	client, err := a.connect(ctx)
+	if err != nil {
+		return err
+	}
+	defer client.Disconnect()
+
+	events := make(chan event, 64)
+	emit := func(kind string, data interface{}) {
+		select {
+		case events <- event{Kind: kind, Data: data}:
+		default:
+		}
+	}
+
+	client.OnReady(func(s hub.ReadyStatus, _ bool) { emit("ready", s) })
+	client.OnDisconnect(func(err error) { emit("disconnected", err.Error()) })
+	client.OnQuestUpdated(func(u hub.QuestUpdate) { emit("quest", u) })
+	client.OnBundleUpdated(func(u hub.BundleUpdate) { emit("bundle", u) })
+	client.OnScheduleChanged(func(c hub.ScheduleChange) { emit("schedules", c) })
+
+	fmt.Fprintf(os.Stderr, "watching %s, press Ctrl-C to stop\n", a.url)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case e := <-events:
+			if err := a.printEvent(e); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// watchList prints quest or bundle changes between polls until interrupted
+func (a *app) watchList(ctx context.Context, kind string) error {
+	client, err := a.connect(ctx)
+	if err != nil {
+		return err
+	}
+	defer client.Disconnect()
+
+	w := hub.NewWatcher(client, hub.WatchInterval(a.watchInterval))
+	events := w.Events()
+	if err := w.Start(ctx); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(os.Stderr, "watching %s on %s every %s, press Ctrl-C to stop\n", kind, a.url, a.watchInterval)
+
+	for e := range events {
+		if (kind == "quests" && e.Quest == nil) || (kind == "bundles" && e.Bundle == nil) {
+			continue
+		}
+		if err := a.printChange(e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (a *app) export(ctx context.Context, dest string) error {
+	client, err := a.connect(ctx)
+	if err != nil {
+		return err
+	}
+	defer client.Disconnect()
+
+	if a.exportFormat != "json" {
+		return a.exportTableFiles(ctx, client, dest)
+	}
+
+	exporter := export.New(client, a.exportOptions()...)
+
+	if dest == "-" {
+		_, err := exporter.Export(ctx, os.Stdout)
+		return err
+	}
+
+	path, err := exporter.ExportDir(ctx, dest)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "snapshot written to %s\n", path)
+	return nil
+}
+
+func (a *app) exportOptions() []export.Option {
+	if a.patch == nil {
+		return nil
+	}
+	return []export.Option{export.WithTransform(a.patch)}
+}
+
+func (a *app) exportTableFiles(ctx context.Context, client *hub.Client, dest string) error {
+	opts := append(a.exportOptions(),
+		export.WithTableFormat(export.TableFormat(a.exportFormat)),
+		export.WithTables(a.exportTables...),
+	)
+	for t, cols := range a.exportColumns {
+		opts = append(opts, export.WithColumns(t, cols...))
+	}
+	exporter := export.New(client, opts...)
+
+	if dest == "-" {
+		if len(a.exportTables) != 1 {
+			return fmt.Errorf("exporting tables to stdout needs exactly one -export-tables entry")
+		}
+		snap, err := exporter.Snapshot(ctx)
+		if err != nil {
+			return err
+		}
+		return exporter.WriteTable(os.Stdout, snap, a.exportTables[0])
+	}
+
+	paths, err := exporter.ExportTables(ctx, dest)
+	for _, path := range paths {
+		fmt.Fprintf(os.Stderr, "table written to %s\n", path)
+	}
+	return err
+}
+
+func (a *app) contractGenerate(path string) error {
+	if err := contract.Generate().Save(path); err != nil {
+		return err
+	}
+	fmt.Printf("contract written to %s\n", path)
+	return nil
+}
+
+func (a *app) contractCheck(ctx context.Context, path string) error {
+	c, err := contract.Load(path)
+	if err != nil {
+		return err
+	}
+
+	client, err := a.connect(ctx)
+	if err != nil {
+		return err
+	}
+	defer client.Disconnect()
+
+	violations, err := c.CheckLive(ctx, client)
+	if err != nil {
+		return err
+	}
+
+	if a.output == "json" {
+		if err := writeJSON(violations); err != nil {
+			return err
+		}
+	} else {
+		for _, v := range violations {
+			fmt.Println(v)
+		}
+	}
+
+	if contract.HasBreaking(violations) {
+		return fmt.Errorf("breaking contract changes detected")
+	}
+	return nil
+}
+
+func (a *app) pluginsList() error {
+	infos := plugin.List()
+
+	if a.output == "json" {
+		return writeJSON(infos)
+	}
+
+	t := newTable("KIND", "NAME")
+	for _, info := range infos {
+		t.row(info.Kind, info.Name)
+	}
+	return t.flush()
+}
+
+func (a *app) usage() error {
+	if a.usageFile == "" {
+		return errors.New("no -usage-file to read")
+	}
+	report, err := hub.LoadUsage(a.usageFile)
+	if err != nil {
+		return err
+	}
+
+	if a.output == "json" {
+		return writeJSON(report)
+	}
+
+	t := newTable("DAY", "METHOD", "CALLS", "ERRORS", "BYTES")
+	for _, e := range report.Entries {
+		t.row(e.Day, e.Method, e.Calls, e.Errors, e.Bytes)
+	}
+	t.row("total", "", report.TotalCalls(), report.TotalErrors(), report.TotalBytes())
+	return t.flush()
+}
+
+func (a *app) usageReset() error {
+	if a.usageFile == "" {
+		return errors.New("no -usage-file to reset")
+	}
+	if err := os.Remove(a.usageFile); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// verify exits non-zero when the store diverged and was not repaired
+func (a *app) verify(ctx context.Context, backend, path string) error {
+	b, err := plugin.NewStore(backend, plugin.Config{"path": path})
+	if err != nil {
+		return err
+	}
+	st, err := store.Open(ctx, b)
+	if err != nil {
+		b.Close()
+		return err
+	}
+	defer st.Close()
+
+	client, err := a.connect(ctx)
+	if err != nil {
+		return err
+	}
+	defer client.Disconnect()
+
+	// a partial snapshot would make everything missing look orphaned
+	live, err := client.Snapshot(ctx)
+	if err != nil {
+		return err
+	}
+
+	report, err := st.Verify(ctx, live, a.repair)
+	if err != nil {
+		return err
+	}
+	if err := a.printVerifyReport(report); err != nil {
+		return err
+	}
+
+	if len(report.Divergences) > 0 && !report.Repaired {
+		return fmt.Errorf("store %s diverged from the hub", path)
+	}
+	return nil
+}
+
+// compare reads two store backends and reports how the second differs from
+// the first, as a mirror store does continuously
+func (a *app) compare(ctx context.Context, primary, primaryPath, secondary, secondaryPath string) error {
+	pb, err := plugin.NewStore(primary, plugin.Config{"path": primaryPath})
+	if err != nil {
+		return err
+	}
+	sb, err := plugin.NewStore(secondary, plugin.Config{"path": secondaryPath})
+	if err != nil {
+		pb.Close()
+		return err
+	}
+	m := store.NewMirror(pb, sb, store.WithCompareEvery(0))
+	defer m.Close()
+
+	report, err := m.Compare(ctx)
+	if err != nil {
+		return err
+	}
+	if err := a.printVerifyReport(report); err != nil {
+		return err
+	}
+
+	if len(report.Divergences) > 0 {
+		return fmt.Errorf("store %s diverged from %s", secondaryPath, primaryPath)
+	}
+	return nil
+}
+
+// replay sends what a store journal, or an archive of one at a URL,
+// recorded to a sink, e.g.
+// replay -from 2026-10-01 -speed 10x qh.journal feed path=feed.xml, or with
+// -dry-run prints it like quests list -watch
+func (a *app) replay(ctx context.Context, args []string) error {
+	var (
+		opts   []store.ReplayOption
+		dryRun bool
+	)
+	fs := flag.NewFlagSet("replay", flag.ContinueOnError)
+	fs.Func("from", "replay changes written at or after `date`, RFC 3339 or 2006-01-02", func(v string) error {
+		t, err := parseReplayTime(v)
+		opts = append(opts, store.ReplayFrom(t))
+		return err
+	})
+	fs.Func("to", "stop at changes written at or after `date`", func(v string) error {
+		t, err := parseReplayTime(v)
+		opts = append(opts, store.ReplayTo(t))
+		return err
+	})
+	fs.Func("speed", "replay `n` times faster than recorded, e.g. 10x (default: no waiting)", func(v string) error {
+		x, err := strconv.ParseFloat(strings.TrimSuffix(v, "x"), 64)
+		if err != nil || x <= 0 {
+			return fmt.Errorf("want a positive speed like 10x, got %q", v)
+		}
+		opts = append(opts, store.ReplaySpeed(x))
+		return nil
+	})
+	fs.BoolVar(&dryRun, "dry-run", false, "print the changes instead of publishing them")
+	if err := fs.Parse(args); err != nil {
+		return errUsage
+	}
+
+	args = fs.Args()
+	if len(args) == 0 || (!dryRun && len(args) < 2) {
+		return errUsage
+	}
+
+	publish := func(cs hub.ChangeSet) error {
+		for _, e := range cs.Events(cs.To.FetchedAt) {
+			if err := a.printChange(e); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if !dryRun {
+		cfg := plugin.Config{}
+		for _, kv := range args[2:] {
+			k, v, ok := strings.Cut(kv, "=")
+			if !ok {
+				return fmt.Errorf("want key=value, got %q", kv)
+			}
+			cfg[k] = v
+		}
+		sink, err := plugin.NewSink(args[1], cfg)
+		if err != nil {
+			return err
+		}
+		publish = func(cs hub.ChangeSet) error {
+			return sink.Publish(ctx, cs)
+		}
+	}
+
+	j, err := openJournal(ctx, args[0])
+	if err != nil {
+		return err
+	}
+	defer j.Close()
+
+	err = store.Replay(ctx, j, publish, opts...)
+	if errors.Is(err, context.Canceled) {
+		return nil
+	}
+	return err
+}
+
+// openJournal opens a journal file, or downloads a journal archive
+// published at an http or https URL
+func openJournal(ctx context.Context, path string) (store.Journal, error) {
+	if strings.HasPrefix(path, "http://") || strings.HasPrefix(path, "https://") {
+		return store.FetchJournal(ctx, nil, path)
+	}
+	// opening would create a journal that is not there
+	if _, err := os.Stat(path); err != nil {
+		return nil, err
+	}
+	return store.OpenFileJournal(path)
+}
+
+func parseReplayTime(v string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, v); err == nil {
+		return t, nil
+	}
+	return time.ParseInLocation(time.DateOnly, v, time.Local)
+}
+
+func (a *app) printVerifyReport(report *store.VerifyReport) error {
+	if a.output == "json" {
+		return writeJSON(report)
+	}
+
+	t := newTable("TABLE", "ID", "KIND", "FIELDS")
+	for _, d := range report.Divergences {
+		t.row(d.Table, d.ID, d.Kind, strings.Join(d.Fields, ","))
+	}
+	if err := t.flush(); err != nil {
+		return err
+	}
+	fmt.Printf("%d checked, %d diverged", report.Checked, len(report.Divergences))
+	if report.Repaired {
+		fmt.Print(", repaired")
+	}
+	fmt.Println()
+	return nil
+}
+
+func (a *app) loadtest(ctx context.Context) error {
+	if a.loadConnections < 1 {
+		return errors.New("-connections must be at least 1")
+	}
+	if a.fakeHub {
+		srv := hubtest.NewServer(hubtest.DefaultFixtures())
+		defer srv.Close()
+		a.url = srv.URL
+	}
+	// a load test would swamp the usage record
+	a.usageFile = ""
+
+	services := make([]hub.Service, 0, a.loadConnections)
+	for range a.loadConnections {
+		client, err := a.connect(ctx)
+		if err != nil {
+			return err
+		}
+		defer client.Disconnect()
+		services = append(services, client)
+	}
+
+	report, err := loadtest.Run(ctx, services, loadtest.Config{
+		Mix:         a.loadMix,
+		Concurrency: a.loadConcurrency,
+		Duration:    a.loadDuration,
+	})
+	if err != nil {
+		return err
+	}
+
+	if a.output == "json" {
+		return writeJSON(report)
+	}
+
+	ms := func(d time.Duration) string {
+		return fmt.Sprintf("%.2fms", float64(d)/float64(time.Millisecond))
+	}
+	t := newTable("METHOD", "CALLS", "ERRORS", "ERROR RATE", "CALLS/S", "P50", "P90", "P99", "MAX")
+	for _, m := range append(report.Methods, report.Total) {
+		t.row(m.Method, m.Calls, m.Errors, fmt.Sprintf("%.2f%%", 100*m.ErrorRate), fmt.Sprintf("%.1f", m.Throughput), ms(m.P50), ms(m.P90), ms(m.P99), ms(m.Max))
+	}
+	if err := t.flush(); err != nil {
+		return err
+	}
+
+	kinds := report.Total.ErrorsByKind
+	if len(kinds) > 0 {
+		fmt.Println()
+		t := newTable("ERROR", "COUNT")
+		for _, kind := range slices.Sorted(maps.Keys(kinds)) {
+			t.row(kind, kinds[kind])
+		}
+		return t.flush()
+	}
+	return nil
+}
+
+// open ends of a schedule window print as a dash
+func windowEdge(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.Format("2006-01-02 15:04Z07:00")
+}
+
+func objectivesSummary(objectives hub.QuestObjectives) string {
+	parts := make([]string, 0, len(objectives))
+	for _, o := range objectives {
+		parts = append(parts, fmt.Sprintf("%s=%d", o.BackendName, o.Count))
+	}
+	return strings.Join(parts, ", ")
+}
+
+func rewardsSummary(rewards hub.QuestRewards) string {
+	parts := make([]string, 0, len(rewards))
+	for _, r := range rewards {
+		parts = append(parts, fmt.Sprintf("%s x%d", r.TemplateID, r.Quantity))
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,335 @@
+// Command questhub inspects a QuestHub SignalR hub from the command line.
+//
+//	questhub [flags] <command> [args]
+//
+// Commands:
+//
+//	status              service status
+//	quests list         all daily quests; with -watch, changes as they happen
+//	quests get <id>     a single daily quest
+//	bundles list        all challenge bundles; with -watch, changes as they happen
+//	bundles get <id>    a single challenge bundle
+//	schedules           challenge bundle schedules
+//	calendar <file|->   write the bundle unlocks as an iCalendar feed
+//	cache clear [pat..] clear the hub cache, or only keys matching the
+//	                    patterns, e.g. cache clear 'quests:*'
+//	cache refresh       refresh the hub cache; with -wait, until it finishes
+//	watch               print hub events until interrupted
+//	export <dir|->      write a snapshot of all hub data; with -export-format
+//	                    csv or parquet, one file per table; with -patch,
+//	                    corrected by an override file
+//	contract generate   write the SDK's data contract to a file
+//	contract check      compare live payloads against a contract
+//	plugins list        registered sinks, transforms and store backends
+//	usage               calls and bytes per method per day, as recorded in
+//	                    -usage-file by earlier commands
+//	usage reset         start the usage record over
+//	verify <store> <path>
+//	                    compare a store backend, e.g. verify bolt qh.db,
+//	                    with the live hub; with -repair, reset it to the hub
+//	compare <store> <path> <store> <path>
+//	                    report how the second store backend differs from
+//	                    the first, e.g. before cutting over from a "mirror"
+//	                    store's primary to its secondary
+//	replay [-from date] [-to date] [-speed 10x] <journal> <sink> [key=value..]
+//	                    send the changes a store journal, or a journal
+//	                    archive at a URL, recorded to a sink, e.g. feed
+//	                    path=feed.xml; with -dry-run, print them instead
+//	                    of naming a sink
+//	loadtest            call the hub with -mix from -concurrency workers for
+//	                    -duration and report latency and errors per method;
+//	                    with -fake, against an in-process test hub
+//
+// -watch may also be given after the command, e.g. questhub quests list --watch.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/ilyskies/QuestHub/pkg/export"
+	"github.com/ilyskies/QuestHub/pkg/hub"
+	"github.com/ilyskies/QuestHub/pkg/loadtest"
+	"github.com/ilyskies/QuestHub/pkg/override"
+	"github.com/ilyskies/QuestHub/pkg/plugin"
+	_ "github.com/ilyskies/QuestHub/pkg/store/boltstore"
+)
+
+type app struct {
+	url     string
+	timeout time.Duration
+	output  string
+	verbose bool
+
+	// keep list commands running and print only what changed
+	watchChanges  bool
+	watchInterval time.Duration
+
+	// follow cache refresh until the hub reports it done
+	waitRefresh bool
+
+	// Go plugins loaded before the command runs
+	plugins []string
+
+	// where every command adds its hub calls; empty records nothing
+	usageFile string
+
+	// let verify fix the store it checks
+	repair bool
+
+	loadMix         loadtest.Mix
+	loadConcurrency int
+	loadConnections int
+	loadDuration    time.Duration
+	fakeHub         bool
+
+	exportFormat  string
+	exportTables  []export.Table
+	exportColumns map[export.Table][]string
+	patch         *override.Patch
+}
+
+var errUsage = errors.New("usage")
+
+func main() {
+	a := &app{}
+
+	fs := flag.NewFlagSet("questhub", flag.ExitOnError)
+	fs.StringVar(&a.url, "url", envOr("QUESTHUB_URL", "http://localhost:5294/hub"), "hub endpoint")
+	fs.DurationVar(&a.timeout, "timeout", 30*time.Second, "per-call timeout")
+	fs.StringVar(&a.output, "o", "table", "output format: table or json")
+	fs.BoolVar(&a.verbose, "v", false, "log client activity to stderr")
+	fs.BoolVar(&a.watchChanges, "watch", false, "with quests or bundles list, print changes until interrupted")
+	fs.DurationVar(&a.watchInterval, "interval", 30*time.Second, "poll interval for -watch")
+	fs.BoolVar(&a.waitRefresh, "wait", false, "with cache refresh, print progress until the refresh finishes")
+	fs.BoolVar(&a.repair, "repair", false, "with verify, reset a diverged store to the live hub")
+	fs.StringVar(&a.usageFile, "usage-file", envOr("QUESTHUB_USAGE_FILE", defaultUsageFile()), "`file` recording hub calls for the usage command; empty to disable")
+	fs.Func("plugin", "load a Go plugin `file` (repeatable)", func(path string) error {
+		a.plugins = append(a.plugins, path)
+		return nil
+	})
+	a.loadMix = loadtest.DefaultMix()
+	fs.Func("mix", "loadtest calls as `method=weight,...`, e.g. GetDailyQuests=3,GetServiceStatus=1 (default: mostly the quest and bundle lists)", func(v string) error {
+		mix, err := loadtest.ParseMix(v)
+		if err != nil {
+			return err
+		}
+		a.loadMix = mix
+		return nil
+	})
+	fs.IntVar(&a.loadConcurrency, "concurrency", 16, "loadtest workers calling at once")
+	fs.IntVar(&a.loadConnections, "connections", 1, "loadtest hub connections the workers share")
+	fs.DurationVar(&a.loadDuration, "duration", 30*time.Second, "how long loadtest runs")
+	fs.BoolVar(&a.fakeHub, "fake", false, "run loadtest against an in-process test hub instead of -url")
+	fs.StringVar(&a.exportFormat, "export-format", "json", "export format: json, csv or parquet")
+	fs.Func("export-tables", "comma separated `tables` to export as csv or parquet", func(v string) error {
+		for _, t := range strings.Split(v, ",") {
+			a.exportTables = append(a.exportTables, export.Table(strings.TrimSpace(t)))
+		}
+		return nil
+	})
+	fs.Func("export-columns", "columns to export for one table, as `table=col,col` (repeatable)", func(v string) error {
+		table, cols, ok := strings.Cut(v, "=")
+		if !ok {
+			return fmt.Errorf("want table=col,col, got %q", v)
+		}
+		if a.exportColumns == nil {
+			a.exportColumns = make(map[export.Table][]string)
+		}
+		for _, c := range strings.Split(cols, ",") {
+			a.exportColumns[export.Table(table)] = append(a.exportColumns[export.Table(table)], strings.TrimSpace(c))
+		}
+		return nil
+	})
+	fs.Func("patch", "apply the override `file` (YAML or JSON) to exported snapshots", func(path string) error {
+		p, err := override.Load(path)
+		if err != nil {
+			return err
+		}
+		a.patch = p
+		return nil
+	})
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: questhub [flags] <command> [args]")
+		fmt.Fprintln(fs.Output(), "\ncommands: status, quests list|get, bundles list|get, schedules, calendar, cache clear|refresh, watch, export, contract generate|check, plugins list, usage [reset], verify, compare, replay, loadtest")
+		fmt.Fprintln(fs.Output(), "\nflags:")
+		fs.PrintDefaults()
+	}
+	_ = fs.Parse(os.Args[1:])
+
+	if a.output != "table" && a.output != "json" {
+		fmt.Fprintf(os.Stderr, "unknown output format %q\n", a.output)
+		os.Exit(2)
+	}
+	switch a.exportFormat {
+	case "json", string(export.FormatCSV), string(export.FormatParquet):
+	default:
+		fmt.Fprintf(os.Stderr, "unknown export format %q\n", a.exportFormat)
+		os.Exit(2)
+	}
+
+	for _, path := range a.plugins {
+		if err := plugin.Open(path); err != nil {
+			fmt.Fprintf(os.Stderr, "questhub: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if err := a.run(ctx, fs.Args()); err != nil {
+		if errors.Is(err, errUsage) {
+			fs.Usage()
+			os.Exit(2)
+		}
+		fmt.Fprintf(os.Stderr, "questhub: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func (a *app) run(ctx context.Context, args []string) error {
+	if len(args) == 0 {
+		return errUsage
+	}
+
+	cmd, rest := args[0], args[1:]
+	rest = a.trailingWatch(rest)
+	sub := ""
+	if len(rest) > 0 {
+		sub = rest[0]
+	}
+
+	if a.watchChanges {
+		switch {
+		case cmd == "quests" && sub == "list":
+			return a.watchList(ctx, "quests")
+		case cmd == "bundles" && sub == "list":
+			return a.watchList(ctx, "bundles")
+		}
+		return errUsage
+	}
+
+	switch {
+	case cmd == "status":
+		return a.status(ctx)
+	case cmd == "quests" && sub == "list":
+		return a.questsList(ctx)
+	case cmd == "quests" && sub == "get" && len(rest) == 2:
+		return a.questsGet(ctx, rest[1])
+	case cmd == "bundles" && sub == "list":
+		return a.bundlesList(ctx)
+	case cmd == "bundles" && sub == "get" && len(rest) == 2:
+		return a.bundlesGet(ctx, rest[1])
+	case cmd == "schedules":
+		return a.schedules(ctx)
+	case cmd == "calendar" && len(rest) == 1:
+		return a.calendar(ctx, rest[0])
+	case cmd == "cache" && sub == "clear":
+		return a.cacheClear(ctx, rest[1:])
+	case cmd == "cache" && sub == "refresh":
+		return a.cacheRefresh(ctx)
+	case cmd == "watch":
+		return a.watch(ctx)
+	case cmd == "export" && len(rest) == 1:
+		return a.export(ctx, rest[0])
+	case cmd == "contract" && sub == "generate" && len(rest) == 2:
+		return a.contractGenerate(rest[1])
+	case cmd == "contract" && sub == "check" && len(rest) == 2:
+		return a.contractCheck(ctx, rest[1])
+	case cmd == "plugins" && sub == "list":
+		return a.pluginsList()
+	case cmd == "usage" && len(rest) == 0:
+		return a.usage()
+	case cmd == "usage" && sub == "reset" && len(rest) == 1:
+		return a.usageReset()
+	case cmd == "verify" && len(rest) == 2:
+		return a.verify(ctx, rest[0], rest[1])
+	case cmd == "compare" && len(rest) == 4:
+		return a.compare(ctx, rest[0], rest[1], rest[2], rest[3])
+	case cmd == "replay":
+		return a.replay(ctx, rest)
+	case cmd == "loadtest" && len(rest) == 0:
+		return a.loadtest(ctx)
+	}
+	return errUsage
+}
+
+func (a *app) connect(ctx context.Context) (*hub.Client, error) {
+	opts := []hub.ClientOption{hub.WithTimeout(a.timeout)}
+	if a.verbose {
+		opts = append(opts, hub.WithLogger(stderrLogger{}))
+	}
+	if a.usageFile != "" {
+		opts = append(opts, hub.WithUsageFile(a.usageFile))
+	}
+
+	client := hub.NewClient(a.url, opts...)
+	if err := client.Connect(); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, a.timeout)
+	defer cancel()
+
+	ticker := time.NewTicker(20 * time.Millisecond)
+	defer ticker.Stop()
+
+	for !client.IsConnected() {
+		select {
+		case <-ctx.Done():
+			client.Disconnect()
+			return nil, fmt.Errorf("waiting for connection: %w", ctx.Err())
+		case <-ticker.C:
+		}
+	}
+	return client, nil
+}
+
+// the flag package stops at the command, so accept --watch after it too
+func (a *app) trailingWatch(args []string) []string {
+	out := args[:0:0]
+	for _, arg := range args {
+		if arg == "--watch" || arg == "-watch" {
+			a.watchChanges = true
+			continue
+		}
+		out = append(out, arg)
+	}
+	return out
+}
+
+// in the user's cache directory, or nowhere when there is none
+func defaultUsageFile() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "questhub", "usage.json")
+}
+
+func envOr(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return fallback
+}
+
+type stderrLogger struct{}
+
+func (stderrLogger) Debug(msg string, args ...interface{}) { logf("DEBUG", msg, args...) }
+func (stderrLogger) Info(msg string, args ...interface{})  { logf("INFO", msg, args...) }
+func (stderrLogger) Warn(msg string, args ...interface{})  { logf("WARN", msg, args...) }
+func (stderrLogger) Error(msg string, args ...interface{}) { logf("ERROR", msg, args...) }
+
+func logf(level, msg string, args ...interface{}) {
+	fmt.Fprintf(os.Stderr, "%s %-5s %s\n", time.Now().Format(time.TimeOnly), level, fmt.Sprintf(msg, args...))
+}
@@ -0,0 +1,167 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/ilyskies/QuestHub/pkg/hub"
+)
+
+type table struct {
+	w *tabwriter.Writer
+}
+
+func newTable(headers ...string) *table {
+	t := &table{w: tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)}
+	fmt.Fprintln(t.w, strings.Join(headers, "\t"))
+	return t
+}
+
+func (t *table) row(cols ...interface{}) {
+	parts := make([]string, len(cols))
+	for i, c := range cols {
+		parts[i] = fmt.Sprint(c)
+	}
+	fmt.Fprintln(t.w, strings.Join(parts, "\t"))
+}
+
+func (t *table) flush() error {
+	return t.w.Flush()
+}
+
+func writeJSON(v interface{}) error {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+type event struct {
+	Time time.Time   `json:"time"`
+	Kind string      `json:"kind"`
+	Data interface{} `json:"data"`
+}
+
+func (a *app) printEvent(e event) error {
+	e.Time = time.Now()
+
+	if a.output == "json" {
+		return json.NewEncoder(os.Stdout).Encode(e)
+	}
+
+	data, err := json.Marshal(e.Data)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s  %-12s %s\n", e.Time.Format(time.TimeOnly), e.Kind, data)
+	return nil
+}
+
+const (
+	colorRed    = "31"
+	colorGreen  = "32"
+	colorYellow = "33"
+)
+
+// colors only when writing to a terminal and NO_COLOR is unset
+var useColor = func() bool {
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	fi, err := os.Stdout.Stat()
+	return err == nil && fi.Mode()&os.ModeCharDevice != 0
+}()
+
+func paint(color, s string) string {
+	if !useColor {
+		return s
+	}
+	return "\x1b[" + color + "m" + s + "\x1b[0m"
+}
+
+// printChange writes one watcher event as +/- lines; modifications list the
+// old and new value of every changed field
+func (a *app) printChange(e hub.ChangeEvent) error {
+	if a.output == "json" {
+		return json.NewEncoder(os.Stdout).Encode(e)
+	}
+
+	ts := e.At.Format(time.TimeOnly)
+	if r := e.Rollover; r != nil {
+		fmt.Println(paint(colorYellow, fmt.Sprintf("%s * season %s -> %s  %d items replaced by %d", ts, r.FromVersion, r.ToVersion, r.Removed.Total(), r.Added.Total())))
+		return nil
+	}
+
+	var (
+		kind          hub.ChangeKind
+		before, after interface{}
+		fields        []string
+		summary       string
+	)
+	switch {
+	case e.Quest != nil:
+		kind, fields = e.Quest.Kind, e.Quest.Fields
+		before, after = e.Quest.Old, e.Quest.New
+		if q := firstQuest(e.Quest.New, e.Quest.Old); q != nil {
+			summary = objectivesSummary(q.Objectives) + "  " + rewardsSummary(q.Rewards)
+		}
+	case e.Bundle != nil:
+		kind, fields = e.Bundle.Kind, e.Bundle.Fields
+		before, after = e.Bundle.Old, e.Bundle.New
+		if b := firstBundle(e.Bundle.New, e.Bundle.Old); b != nil {
+			summary = fmt.Sprintf("%s  %s  %d quests", b.ChallengeBundleSchedule, b.Rarity, len(b.Objects))
+		}
+	default:
+		return nil
+	}
+
+	switch kind {
+	case hub.ChangeAdded:
+		fmt.Println(paint(colorGreen, strings.TrimSpace(fmt.Sprintf("%s + %s  %s", ts, e.ID, summary))))
+	case hub.ChangeRemoved:
+		fmt.Println(paint(colorRed, strings.TrimSpace(fmt.Sprintf("%s - %s  %s", ts, e.ID, summary))))
+	case hub.ChangeModified:
+		fmt.Println(paint(colorYellow, fmt.Sprintf("%s ~ %s", ts, e.ID)))
+		for _, f := range fields {
+			fmt.Println(paint(colorRed, fmt.Sprintf("    - %s: %s", f, jsonField(before, f))))
+			fmt.Println(paint(colorGreen, fmt.Sprintf("    + %s: %s", f, jsonField(after, f))))
+		}
+	}
+	return nil
+}
+
+func firstQuest(qs ...*hub.BaseQuest) *hub.BaseQuest {
+	for _, q := range qs {
+		if q != nil {
+			return q
+		}
+	}
+	return nil
+}
+
+func firstBundle(bs ...*hub.AthenaChallengeBundle) *hub.AthenaChallengeBundle {
+	for _, b := range bs {
+		if b != nil {
+			return b
+		}
+	}
+	return nil
+}
+
+func jsonField(v interface{}, field string) string {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return "?"
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(b, &fields); err != nil {
+		return "?"
+	}
+	if raw, ok := fields[field]; ok {
+		return string(raw)
+	}
+	return "null"
+}
@@ -0,0 +1,25 @@
+package main
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"github.com/ilyskies/QuestHub/pkg/hub"
+	"github.com/ilyskies/QuestHub/pkg/hubtest"
+)
+
+func Example() {
+	srv := hubtest.NewServer(hubtest.DefaultFixtures())
+	defer srv.Close()
+
+	client := hub.NewClient(srv.URL)
+	if err := run(context.Background(), client, 5*time.Second, os.Stdout); err != nil {
+		panic(err)
+	}
+	// Output:
+	// hub ready (version test, initialized true)
+	// 1 daily quests
+	// 1 challenge bundles
+	// 1 bundle schedules
+}
@@ -0,0 +1,67 @@
+// Command basic-fetch connects to a hub, waits for it to report ready and
+// prints a short summary of the quest data it serves.
+//
+//	go run ./examples/basic-fetch -url http://localhost:5294/hub
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"time"
+
+	"github.com/ilyskies/QuestHub/pkg/hub"
+)
+
+func main() {
+	url := flag.String("url", "http://localhost:5294/hub", "hub endpoint")
+	wait := flag.Duration("wait", 15*time.Second, "how long to wait for Ready")
+	flag.Parse()
+
+	client := hub.NewClient(*url, hub.WithTimeout(30*time.Second))
+	if err := run(context.Background(), client, *wait, os.Stdout); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// run returns rather than exiting so the deferred Disconnect always runs
+func run(ctx context.Context, client *hub.Client, wait time.Duration, out io.Writer) error {
+	if err := client.Connect(); err != nil {
+		return fmt.Errorf("connect: %w", err)
+	}
+	defer client.Disconnect()
+
+	waitCtx, cancelWait := context.WithTimeout(ctx, wait)
+	defer cancelWait()
+
+	status, err := client.WaitForReady(waitCtx)
+	if err != nil {
+		return fmt.Errorf("waiting for Ready: %w", err)
+	}
+	fmt.Fprintf(out, "hub ready (version %s, initialized %v)\n", status.Version, status.Initialized)
+
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	quests, err := client.GetDailyQuests(ctx)
+	if err != nil {
+		return fmt.Errorf("get daily quests: %w", err)
+	}
+	fmt.Fprintf(out, "%d daily quests\n", len(quests))
+
+	bundles, err := client.GetChallengeBundles(ctx)
+	if err != nil {
+		return fmt.Errorf("get challenge bundles: %w", err)
+	}
+	fmt.Fprintf(out, "%d challenge bundles\n", len(bundles))
+
+	schedules, err := client.GetChallengeBundleSchedules(ctx)
+	if err != nil {
+		return fmt.Errorf("get schedules: %w", err)
+	}
+	fmt.Fprintf(out, "%d bundle schedules\n", len(schedules))
+	return nil
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protojson"
+
+	"github.com/ilyskies/QuestHub/pkg/gateway/questhubv1"
+	"github.com/ilyskies/QuestHub/pkg/hub"
+	"github.com/ilyskies/QuestHub/pkg/hubtest"
+)
+
+func Example() {
+	srv := hubtest.NewServer(hubtest.DefaultFixtures())
+	defer srv.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		panic(err)
+	}
+	done := make(chan error, 1)
+	go func() { done <- run(ctx, hub.NewClient(srv.URL), lis) }()
+
+	// waits in the listen backlog until run serves
+	resp, err := http.Get("http://" + lis.Addr().String() + "/v1/quests")
+	if err != nil {
+		panic(err)
+	}
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		panic(err)
+	}
+
+	var list questhubv1.ListDailyQuestsResponse
+	if err := protojson.Unmarshal(body, &list); err != nil {
+		panic(err)
+	}
+	for _, q := range list.Quests {
+		fmt.Printf("%s: %d objective(s), count %d\n", q.Id, len(q.Objectives), q.Count)
+	}
+
+	cancel()
+	if err := <-done; err != nil {
+		panic(err)
+	}
+	// Output:
+	// Quest_Daily_Eliminations: 1 objective(s), count 3
+}
@@ -0,0 +1,68 @@
+// Command gateway serves a hub's data as the gateway package's REST API,
+// for embedding the gateway in a server of your own. cmd/questhub-gateway
+// is the full version with gRPC, GraphQL and health checks.
+//
+//	go run ./examples/gateway -url http://localhost:5294/hub -listen :8080
+//	curl localhost:8080/v1/quests?pretty
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/ilyskies/QuestHub/pkg/gateway"
+	"github.com/ilyskies/QuestHub/pkg/hub"
+)
+
+func main() {
+	url := flag.String("url", "http://localhost:5294/hub", "hub endpoint")
+	listen := flag.String("listen", ":8080", "address to serve REST on")
+	flag.Parse()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	lis, err := net.Listen("tcp", *listen)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// the response cache spares the hub repeated reads from REST clients
+	client := hub.NewClient(*url, hub.WithResponseCache(30*time.Second))
+	if err := run(ctx, client, lis); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// run serves on lis until ctx is done
+func run(ctx context.Context, client *hub.Client, lis net.Listener) error {
+	if err := client.Connect(); err != nil {
+		return fmt.Errorf("connect: %w", err)
+	}
+	defer client.Disconnect()
+
+	if _, err := client.WaitForReady(ctx); err != nil {
+		return fmt.Errorf("waiting for Ready: %w", err)
+	}
+
+	srv := &http.Server{Handler: gateway.New(client).Handler()}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		srv.Shutdown(shutdownCtx)
+	}()
+
+	if err := srv.Serve(lis); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
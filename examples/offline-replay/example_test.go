@@ -0,0 +1,36 @@
+package main
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"github.com/ilyskies/QuestHub/pkg/hub"
+	"github.com/ilyskies/QuestHub/pkg/hubtest"
+)
+
+func Example() {
+	dir, err := os.MkdirTemp("", "offline-replay")
+	if err != nil {
+		panic(err)
+	}
+	defer os.RemoveAll(dir)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// record once while the hub is up
+	srv := hubtest.NewServer(hubtest.DefaultFixtures())
+	if _, err := recordHub(ctx, hub.NewClient(srv.URL), dir); err != nil {
+		panic(err)
+	}
+	srv.Close()
+
+	if err := replay(ctx, dir, os.Stdout); err != nil {
+		panic(err)
+	}
+	// Output:
+	// hub version test
+	// quest Quest_Daily_Eliminations: 1 reward(s)
+	// bundle ChallengeBundle:QuestBundle_Week_001: 1 quest(s)
+}
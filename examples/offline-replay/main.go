@@ -0,0 +1,92 @@
+// Command offline-replay records a hub's data to disk once and then answers
+// from the recording, so code written against hub.Service runs in CI or on
+// a plane without a live hub.
+//
+//	go run ./examples/offline-replay -record http://localhost:5294/hub -dir ./snapshots
+//	go run ./examples/offline-replay -dir ./snapshots
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"maps"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/ilyskies/QuestHub/pkg/export"
+	"github.com/ilyskies/QuestHub/pkg/hub"
+)
+
+func main() {
+	record := flag.String("record", "", "hub endpoint to record from before replaying")
+	dir := flag.String("dir", "snapshots", "where recordings are kept")
+	flag.Parse()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	if *record != "" {
+		path, err := recordHub(ctx, hub.NewClient(*record), *dir)
+		if err != nil {
+			log.Fatal(err)
+		}
+		log.Printf("recorded %s", path)
+	}
+
+	if err := replay(ctx, *dir, os.Stdout); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// recordHub exports one snapshot into dir
+func recordHub(ctx context.Context, client *hub.Client, dir string) (string, error) {
+	if err := client.Connect(); err != nil {
+		return "", fmt.Errorf("connect: %w", err)
+	}
+	defer client.Disconnect()
+
+	if _, err := client.WaitForReady(ctx); err != nil {
+		return "", fmt.Errorf("waiting for Ready: %w", err)
+	}
+	return export.New(client).ExportDir(ctx, dir)
+}
+
+// replay serves the newest recording in dir and summarises it
+func replay(ctx context.Context, dir string, out io.Writer) error {
+	fb, err := hub.NewFileBackend(dir)
+	if err != nil {
+		return err
+	}
+	return summarize(ctx, fb, out)
+}
+
+// summarize only needs a hub.Service, so it runs unchanged against a live
+// Client
+func summarize(ctx context.Context, svc hub.Service, out io.Writer) error {
+	status, err := svc.GetServiceStatus(ctx)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "hub version %s\n", status.Version)
+
+	quests, err := svc.GetDailyQuests(ctx)
+	if err != nil {
+		return err
+	}
+	for _, id := range slices.Sorted(maps.Keys(quests)) {
+		fmt.Fprintf(out, "quest %s: %d reward(s)\n", id, len(quests[id].Rewards))
+	}
+
+	bundles, err := svc.GetChallengeBundles(ctx)
+	if err != nil {
+		return err
+	}
+	for _, b := range bundles {
+		fmt.Fprintf(out, "bundle %s: %d quest(s)\n", b.TemplateID, len(b.Objects))
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"time"
+
+	"github.com/ilyskies/QuestHub/pkg/hub"
+	"github.com/ilyskies/QuestHub/pkg/hubtest"
+)
+
+func Example() {
+	srv := hubtest.NewServer(hubtest.DefaultFixtures())
+	defer srv.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// stands in for Discord; it stops the example after two messages
+	var posted atomic.Int32
+	discord := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var msg struct {
+			Content string `json:"content"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&msg); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		fmt.Println(msg.Content)
+		w.WriteHeader(http.StatusNoContent)
+		if posted.Add(1) == 2 {
+			cancel()
+		}
+	}))
+	defer discord.Close()
+
+	// the initial poll reports the fixtures as added
+	client := hub.NewClient(srv.URL)
+	if err := run(ctx, client, discord.URL, hub.WatchEmitInitial()); err != nil {
+		panic(err)
+	}
+	// Output:
+	// New quest: Quest_Daily_Eliminations
+	// New challenge bundle: ChallengeBundle:QuestBundle_Week_001
+}
@@ -0,0 +1,116 @@
+// Command watcher-discord watches a hub and posts every quest or bundle
+// that is added or removed to a Discord webhook.
+//
+//	go run ./examples/watcher-discord -url http://localhost:5294/hub -webhook https://discord.com/api/webhooks/...
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/ilyskies/QuestHub/pkg/hub"
+)
+
+func main() {
+	url := flag.String("url", "http://localhost:5294/hub", "hub endpoint")
+	webhook := flag.String("webhook", "", "Discord webhook URL")
+	interval := flag.Duration("interval", time.Minute, "how often to poll")
+	initial := flag.Bool("initial", false, "also post what the hub serves at start")
+	flag.Parse()
+
+	if *webhook == "" {
+		log.Fatal("-webhook is required")
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	opts := []hub.WatcherOption{hub.WatchInterval(*interval)}
+	if *initial {
+		opts = append(opts, hub.WatchEmitInitial())
+	}
+
+	client := hub.NewClient(*url)
+	if err := run(ctx, client, *webhook, opts...); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// run posts changes until ctx is done
+func run(ctx context.Context, client *hub.Client, webhook string, opts ...hub.WatcherOption) error {
+	if err := client.Connect(); err != nil {
+		return fmt.Errorf("connect: %w", err)
+	}
+	defer client.Disconnect()
+
+	// a poll before the connection is up fails and waits a whole interval
+	if _, err := client.WaitForReady(ctx); err != nil {
+		return fmt.Errorf("waiting for Ready: %w", err)
+	}
+
+	w := hub.NewWatcher(client, opts...)
+	changes, _ := w.Subscribe(hub.QuestAdded, hub.QuestRemoved, hub.BundleAdded, hub.BundleRemoved, hub.SeasonRollover)
+	if err := w.Start(ctx); err != nil {
+		return err
+	}
+	defer w.Stop(context.Background())
+
+	for e := range changes {
+		if err := post(ctx, webhook, describe(e)); err != nil {
+			if ctx.Err() != nil {
+				break
+			}
+			log.Printf("discord: %v", err)
+		}
+	}
+	return nil
+}
+
+func describe(e hub.ChangeEvent) string {
+	switch e.Type {
+	case hub.QuestAdded:
+		return "New quest: " + e.ID
+	case hub.QuestRemoved:
+		return "Quest gone: " + e.ID
+	case hub.BundleAdded:
+		return "New challenge bundle: " + e.ID
+	case hub.SeasonRollover:
+		return fmt.Sprintf("New season %s: %d challenge bundles and %d daily quests", e.ID, e.Rollover.Added.Bundles, e.Rollover.Added.Quests)
+	default:
+		return "Challenge bundle gone: " + e.ID
+	}
+}
+
+// post sends content as a webhook message, see
+// https://discord.com/developers/docs/resources/webhook#execute-webhook
+func post(ctx context.Context, webhook, content string) error {
+	body, err := json.Marshal(map[string]string{"content": content})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook answered %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,198 @@
+package contract
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+
+	"github.com/ilyskies/QuestHub/pkg/hub"
+)
+
+type Violation struct {
+	Method   string `json:"method"`
+	Path     string `json:"path"`
+	Problem  string `json:"problem"`
+	Breaking bool   `json:"breaking"`
+}
+
+func (v Violation) String() string {
+	kind := "info"
+	if v.Breaking {
+		kind = "BREAKING"
+	}
+	return fmt.Sprintf("%s %s%s: %s", kind, v.Method, v.Path, v.Problem)
+}
+
+func HasBreaking(vs []Violation) bool {
+	for _, v := range vs {
+		if v.Breaking {
+			return true
+		}
+	}
+	return false
+}
+
+// Check compares a live payload against the contract; array elements and
+// map values share a path so repeated problems are reported once
+func (c *Contract) Check(method string, raw json.RawMessage) ([]Violation, error) {
+	shape, ok := c.Methods[method]
+	if !ok {
+		return nil, fmt.Errorf("contract has no method %s", method)
+	}
+
+	var value interface{}
+	if err := json.Unmarshal(raw, &value); err != nil {
+		return nil, fmt.Errorf("invalid %s payload: %w", method, err)
+	}
+
+	ck := &checker{method: method, seen: make(map[string]bool)}
+	ck.check("", shape, value)
+
+	sort.Slice(ck.out, func(i, j int) bool {
+		if ck.out[i].Path != ck.out[j].Path {
+			return ck.out[i].Path < ck.out[j].Path
+		}
+		return ck.out[i].Problem < ck.out[j].Problem
+	})
+	return ck.out, nil
+}
+
+// CheckLive fetches every method in the contract from the hub and checks it
+func (c *Contract) CheckLive(ctx context.Context, client *hub.Client) ([]Violation, error) {
+	methods := make([]string, 0, len(c.Methods))
+	for method := range c.Methods {
+		methods = append(methods, method)
+	}
+	sort.Strings(methods)
+
+	var out []Violation
+	for _, method := range methods {
+		raw, err := hub.Invoke[json.RawMessage](ctx, client, method, methodArgs[method]...)
+		if errors.Is(err, hub.ErrMethodNotFound) {
+			// older hubs lack newer methods; consumers of the rest are unaffected
+			out = append(out, Violation{Method: method, Problem: "method not served by the hub"})
+			continue
+		}
+		if err != nil {
+			return out, fmt.Errorf("fetch %s: %w", method, err)
+		}
+
+		vs, err := c.Check(method, raw)
+		if err != nil {
+			return out, err
+		}
+		out = append(out, vs...)
+	}
+	return out, nil
+}
+
+type checker struct {
+	method string
+	seen   map[string]bool
+	out    []Violation
+}
+
+func (ck *checker) report(path, problem string, breaking bool) {
+	key := path + "\x00" + problem
+	if ck.seen[key] {
+		return
+	}
+	ck.seen[key] = true
+	ck.out = append(ck.out, Violation{
+		Method:   ck.method,
+		Path:     path,
+		Problem:  problem,
+		Breaking: breaking,
+	})
+}
+
+func (ck *checker) check(path string, shape *Shape, value interface{}) {
+	// null decodes to the zero value, so it never breaks a consumer
+	if value == nil || shape.Type == TypeAny {
+		return
+	}
+
+	switch shape.Type {
+	case TypeObject:
+		obj, ok := value.(map[string]interface{})
+		if !ok {
+			ck.report(path, fmt.Sprintf("expected object, got %s", jsonType(value)), true)
+			return
+		}
+		for name, field := range shape.Fields {
+			v, present := obj[name]
+			if !present {
+				if !field.Optional {
+					ck.report(path+"."+name, "field removed", true)
+				}
+				continue
+			}
+			ck.check(path+"."+name, field, v)
+		}
+		for name := range obj {
+			if _, known := shape.Fields[name]; !known {
+				ck.report(path+"."+name, "field added", false)
+			}
+		}
+
+	case TypeMap:
+		obj, ok := value.(map[string]interface{})
+		if !ok {
+			ck.report(path, fmt.Sprintf("expected map, got %s", jsonType(value)), true)
+			return
+		}
+		for _, v := range obj {
+			ck.check(path+"{}", shape.Elem, v)
+		}
+
+	case TypeArray:
+		arr, ok := value.([]interface{})
+		if !ok {
+			ck.report(path, fmt.Sprintf("expected array, got %s", jsonType(value)), true)
+			return
+		}
+		for _, v := range arr {
+			ck.check(path+"[]", shape.Elem, v)
+		}
+
+	case TypeString:
+		s, ok := value.(string)
+		if !ok {
+			ck.report(path, fmt.Sprintf("expected string, got %s", jsonType(value)), true)
+			return
+		}
+		if len(shape.Enum) > 0 && !slices.Contains(shape.Enum, s) {
+			ck.report(path, fmt.Sprintf("unexpected enum value %q", s), true)
+		}
+
+	case TypeNumber:
+		if _, ok := value.(float64); !ok {
+			ck.report(path, fmt.Sprintf("expected number, got %s", jsonType(value)), true)
+		}
+
+	case TypeBool:
+		if _, ok := value.(bool); !ok {
+			ck.report(path, fmt.Sprintf("expected bool, got %s", jsonType(value)), true)
+		}
+	}
+}
+
+func jsonType(v interface{}) string {
+	switch v.(type) {
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	case string:
+		return "string"
+	case float64:
+		return "number"
+	case bool:
+		return "bool"
+	default:
+		return "null"
+	}
+}
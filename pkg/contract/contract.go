@@ -0,0 +1,145 @@
+package contract
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ilyskies/QuestHub/pkg/hub"
+)
+
+const (
+	TypeObject = "object"
+	TypeMap    = "map"
+	TypeArray  = "array"
+	TypeString = "string"
+	TypeNumber = "number"
+	TypeBool   = "bool"
+	TypeAny    = "any"
+)
+
+type Shape struct {
+	Type     string            `json:"type"`
+	Fields   map[string]*Shape `json:"fields,omitempty"`
+	Elem     *Shape            `json:"elem,omitempty"`
+	Enum     []string          `json:"enum,omitempty"`
+	Optional bool              `json:"optional,omitempty"`
+}
+
+type Contract struct {
+	Methods map[string]*Shape `json:"methods"`
+}
+
+// result types of the hub methods wrapped by the SDK
+var methodResults = map[string]reflect.Type{
+	"GetServiceStatus":            reflect.TypeOf(hub.ServiceStatus{}),
+	"GetDailyQuests":              reflect.TypeOf(map[string]hub.BaseQuest{}),
+	"GetChallengeBundles":         reflect.TypeOf([]hub.AthenaChallengeBundle{}),
+	"GetChallengeBundleSchedules": reflect.TypeOf([]hub.ChallengeBundleSchedule{}),
+	"GetSeasonInfo":               reflect.TypeOf(hub.SeasonInfo{}),
+	"GetChallengeBundlesPage":     reflect.TypeOf(hub.BundlePage{}),
+}
+
+// arguments CheckLive passes to methods that need them
+var methodArgs = map[string][]interface{}{
+	"GetChallengeBundlesPage": {hub.PageRequest{Limit: 10}},
+}
+
+// Generate describes what the SDK models currently expect from the hub
+func Generate() *Contract {
+	c := &Contract{Methods: make(map[string]*Shape, len(methodResults))}
+	for method, t := range methodResults {
+		c.Methods[method] = ShapeOf(t)
+	}
+	return c
+}
+
+func Methods() []string {
+	out := make([]string, 0, len(methodResults))
+	for method := range methodResults {
+		out = append(out, method)
+	}
+	sort.Strings(out)
+	return out
+}
+
+var (
+	timeType        = reflect.TypeOf(time.Time{})
+	unmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+)
+
+func ShapeOf(t reflect.Type) *Shape {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	if t == timeType {
+		return &Shape{Type: TypeString}
+	}
+
+	// custom decoders accept more than one wire shape
+	if reflect.PointerTo(t).Implements(unmarshalerType) {
+		return &Shape{Type: TypeAny}
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		s := &Shape{Type: TypeObject, Fields: make(map[string]*Shape)}
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if !f.IsExported() {
+				continue
+			}
+			name, opts, _ := strings.Cut(f.Tag.Get("json"), ",")
+			if name == "-" {
+				continue
+			}
+			if name == "" {
+				name = f.Name
+			}
+			field := ShapeOf(f.Type)
+			field.Optional = strings.Contains(opts, "omitempty") || strings.Contains(opts, "omitzero")
+			s.Fields[name] = field
+		}
+		return s
+	case reflect.Map:
+		return &Shape{Type: TypeMap, Elem: ShapeOf(t.Elem())}
+	case reflect.Slice, reflect.Array:
+		return &Shape{Type: TypeArray, Elem: ShapeOf(t.Elem())}
+	case reflect.String:
+		return &Shape{Type: TypeString}
+	case reflect.Bool:
+		return &Shape{Type: TypeBool}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return &Shape{Type: TypeNumber}
+	default:
+		return &Shape{Type: TypeAny}
+	}
+}
+
+func Load(path string) (*Contract, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var c Contract
+	if err := json.Unmarshal(b, &c); err != nil {
+		return nil, fmt.Errorf("invalid contract %s: %w", path, err)
+	}
+	return &c, nil
+}
+
+func (c *Contract) Save(path string) error {
+	b, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, b, 0644)
+}
@@ -0,0 +1,216 @@
+// Package export writes point-in-time snapshots of everything the hub serves
+// as versioned JSON documents, or flattened into CSV and Parquet tables.
+package export
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/ilyskies/QuestHub/pkg/hub"
+	"github.com/ilyskies/QuestHub/pkg/plugin"
+)
+
+// FormatVersion is bumped, in hub, whenever the snapshot layout changes
+// incompatibly; FileBackend reads the same versions
+const FormatVersion = hub.ExportFormatVersion
+
+// recorded on the provenance of every exported snapshot
+const TransformExport = "export"
+
+var ErrUnsupportedVersion = errors.New("unsupported snapshot format version")
+
+type Snapshot struct {
+	FormatVersion int    `json:"formatVersion"`
+	Source        string `json:"source"`
+	hub.Snapshot
+
+	// calls that failed when the exporter allows partial snapshots
+	Errors []string `json:"errors,omitempty"`
+}
+
+type Option func(*Exporter)
+
+// name receives the snapshot being written and returns a file name relative
+// to the export directory
+func WithFilename(name func(*Snapshot) string) Option {
+	return func(e *Exporter) {
+		e.filename = name
+	}
+}
+
+// snapshots are written even when some calls fail; the failures are listed
+// in Snapshot.Errors
+func WithAllowPartial() Option {
+	return func(e *Exporter) {
+		e.allowPartial = true
+	}
+}
+
+// WithTransform rewrites every snapshot before it is written, e.g. with an
+// override.Patch. Transforms run in the order given.
+func WithTransform(t plugin.Transform) Option {
+	return func(e *Exporter) {
+		e.transforms = append(e.transforms, t)
+	}
+}
+
+func WithIndent(indent string) Option {
+	return func(e *Exporter) {
+		e.indent = indent
+	}
+}
+
+// DefaultFilename names snapshots after the time they were taken,
+// e.g. questhub-20240101T120000Z.json
+func DefaultFilename(s *Snapshot) string {
+	return "questhub-" + s.TakenAt.UTC().Format("20060102T150405Z") + ".json"
+}
+
+type Exporter struct {
+	client       *hub.Client
+	filename     func(*Snapshot) string
+	allowPartial bool
+	indent       string
+	transforms   []plugin.Transform
+
+	tableFormat TableFormat
+	tables      []Table
+	columns     map[Table][]string
+}
+
+func New(client *hub.Client, opts ...Option) *Exporter {
+	e := &Exporter{
+		client:      client,
+		filename:    DefaultFilename,
+		indent:      "  ",
+		tableFormat: FormatCSV,
+	}
+
+	for _, opt := range opts {
+		opt(e)
+	}
+	return e
+}
+
+// Snapshot wraps Client.Snapshot with the export metadata
+func (e *Exporter) Snapshot(ctx context.Context) (*Snapshot, error) {
+	data, err := e.client.Snapshot(ctx)
+	for _, t := range e.transforms {
+		if terr := t.Apply(ctx, data); terr != nil {
+			return nil, fmt.Errorf("snapshot: %w", terr)
+		}
+	}
+
+	snap := &Snapshot{
+		FormatVersion: FormatVersion,
+		Source:        e.client.ConnectionInfo().URL,
+		Snapshot:      *data,
+	}
+	snap.Provenance = data.Provenance.With(TransformExport)
+
+	if err != nil {
+		if !e.allowPartial {
+			return nil, fmt.Errorf("snapshot: %w", err)
+		}
+
+		var multi *hub.MultiError
+		if errors.As(err, &multi) {
+			for _, callErr := range multi.Errors {
+				snap.Errors = append(snap.Errors, callErr.Error())
+			}
+		} else {
+			snap.Errors = append(snap.Errors, err.Error())
+		}
+	}
+	return snap, nil
+}
+
+// Export takes a snapshot and encodes it to w
+func (e *Exporter) Export(ctx context.Context, w io.Writer) (*Snapshot, error) {
+	snap, err := e.Snapshot(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := e.encode(w, snap); err != nil {
+		return nil, err
+	}
+	return snap, nil
+}
+
+// ExportDir takes a snapshot and writes it into dir, returning the file path.
+// The file is written under a temporary name and renamed into place, so
+// readers never see a partial snapshot.
+func (e *Exporter) ExportDir(ctx context.Context, dir string) (string, error) {
+	snap, err := e.Snapshot(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+
+	path := filepath.Join(dir, e.filename(snap))
+	if err := writeAtomic(path, func(w io.Writer) error {
+		return e.encode(w, snap)
+	}); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+func (e *Exporter) encode(w io.Writer, snap *Snapshot) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", e.indent)
+	return enc.Encode(snap)
+}
+
+func writeAtomic(path string, write func(io.Writer) error) (err error) {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			tmp.Close()
+			os.Remove(tmp.Name())
+		}
+	}()
+
+	if err = write(tmp); err != nil {
+		return err
+	}
+	if err = tmp.Sync(); err != nil {
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// Read decodes a snapshot written by any exporter with a compatible format version
+func Read(r io.Reader) (*Snapshot, error) {
+	var snap Snapshot
+	if err := json.NewDecoder(r).Decode(&snap); err != nil {
+		return nil, err
+	}
+	if snap.FormatVersion < 1 || snap.FormatVersion > FormatVersion {
+		return nil, fmt.Errorf("%w: %d", ErrUnsupportedVersion, snap.FormatVersion)
+	}
+	return &snap, nil
+}
+
+func Load(path string) (*Snapshot, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return Read(f)
+}
@@ -0,0 +1,63 @@
+package export
+
+import (
+	"io"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+)
+
+func parquetNode(kind columnKind) parquet.Node {
+	switch kind {
+	case kindInt:
+		return parquet.Int(64)
+	case kindBool:
+		return parquet.Leaf(parquet.BooleanType)
+	case kindTime:
+		return parquet.Timestamp(parquet.Millisecond)
+	default:
+		return parquet.String()
+	}
+}
+
+func writeParquet(w io.Writer, t Table, cols []column, rows [][]interface{}) error {
+	group := make(parquet.Group, len(cols))
+	for _, c := range cols {
+		group[c.name] = parquetNode(c.kind)
+	}
+	schema := parquet.NewSchema(string(t), group)
+
+	// parquet orders the columns of a group by name, not as selected
+	leaf := make([]int, len(cols))
+	for i, c := range cols {
+		col, _ := schema.Lookup(c.name)
+		leaf[i] = col.ColumnIndex
+	}
+
+	pw := parquet.NewWriter(w, schema, parquet.Compression(&parquet.Snappy))
+
+	batch := make([]parquet.Row, 0, len(rows))
+	for _, row := range rows {
+		values := make(parquet.Row, len(row))
+		for i, v := range row {
+			var value parquet.Value
+			switch v := v.(type) {
+			case string:
+				value = parquet.ByteArrayValue([]byte(v))
+			case int:
+				value = parquet.Int64Value(int64(v))
+			case bool:
+				value = parquet.BooleanValue(v)
+			case time.Time:
+				value = parquet.Int64Value(v.UnixMilli())
+			}
+			values[leaf[i]] = value.Level(0, 0, leaf[i])
+		}
+		batch = append(batch, values)
+	}
+
+	if _, err := pw.WriteRows(batch); err != nil {
+		return err
+	}
+	return pw.Close()
+}
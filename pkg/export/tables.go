@@ -0,0 +1,353 @@
+package export
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ilyskies/QuestHub/pkg/hub"
+	"github.com/ilyskies/QuestHub/pkg/hub/rewards"
+)
+
+// Table names one flat view of a snapshot, with a row per objective or
+// reward, for loading into analytics tools
+type Table string
+
+const (
+	TableQuestObjectives  Table = "quest_objectives"
+	TableQuestRewards     Table = "quest_rewards"
+	TableBundleObjectives Table = "bundle_objectives"
+	// quest rewards and completion rewards, told apart by the source column
+	TableBundleRewards Table = "bundle_rewards"
+)
+
+type TableFormat string
+
+const (
+	FormatCSV     TableFormat = "csv"
+	FormatParquet TableFormat = "parquet"
+)
+
+var (
+	ErrUnknownTable    = errors.New("unknown export table")
+	ErrUnknownColumn   = errors.New("unknown export column")
+	ErrDuplicateColumn = errors.New("export column selected twice")
+	ErrUnknownFormat   = errors.New("unknown table format")
+)
+
+type columnKind int
+
+const (
+	kindString columnKind = iota
+	kindInt
+	kindBool
+	kindTime
+)
+
+type column struct {
+	name string
+	kind columnKind
+}
+
+// every table starts with taken_at so exports from several runs can be
+// loaded into the same table
+var tableColumns = map[Table][]column{
+	TableQuestObjectives: {
+		{"taken_at", kindTime},
+		{"quest_id", kindString},
+		{"backend_name", kindString},
+		{"count", kindInt},
+		{"stage", kindInt},
+	},
+	TableQuestRewards: {
+		{"taken_at", kindTime},
+		{"quest_id", kindString},
+		{"template_id", kindString},
+		{"reward_type", kindString},
+		{"reward_name", kindString},
+		{"quantity", kindInt},
+	},
+	TableBundleObjectives: {
+		{"taken_at", kindTime},
+		{"bundle_id", kindString},
+		{"schedule_id", kindString},
+		{"bundle_rarity", kindString},
+		{"quest_definition", kindString},
+		{"quest_rarity", kindString},
+		{"backend_name", kindString},
+		{"count", kindInt},
+		{"stage", kindInt},
+		{"is_battle_pass", kindBool},
+	},
+	TableBundleRewards: {
+		{"taken_at", kindTime},
+		{"bundle_id", kindString},
+		{"schedule_id", kindString},
+		{"quest_definition", kindString},
+		{"source", kindString},
+		{"template_id", kindString},
+		{"reward_type", kindString},
+		{"reward_name", kindString},
+		{"quantity", kindInt},
+	},
+}
+
+// Tables lists every table, in the order ExportTables writes them
+func Tables() []Table {
+	return []Table{TableQuestObjectives, TableQuestRewards, TableBundleObjectives, TableBundleRewards}
+}
+
+// Columns lists the columns of t in their default order
+func Columns(t Table) []string {
+	cols := tableColumns[t]
+	out := make([]string, len(cols))
+	for i, c := range cols {
+		out[i] = c.name
+	}
+	return out
+}
+
+// WithTableFormat sets the format ExportTables and WriteTable use; CSV by
+// default
+func WithTableFormat(f TableFormat) Option {
+	return func(e *Exporter) {
+		e.tableFormat = f
+	}
+}
+
+// WithTables limits ExportTables to the given tables
+func WithTables(tables ...Table) Option {
+	return func(e *Exporter) {
+		e.tables = tables
+	}
+}
+
+// WithColumns writes only the named columns of t, in the order given
+func WithColumns(t Table, columns ...string) Option {
+	return func(e *Exporter) {
+		if e.columns == nil {
+			e.columns = make(map[Table][]string)
+		}
+		e.columns[t] = columns
+	}
+}
+
+// ExportTables takes a snapshot and writes each table into dir as its own
+// file, named after the snapshot file with the table appended, e.g.
+// questhub-20240101T120000Z-quest_rewards.csv. It returns the file paths.
+func (e *Exporter) ExportTables(ctx context.Context, dir string) ([]string, error) {
+	if e.tableFormat != FormatCSV && e.tableFormat != FormatParquet {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownFormat, e.tableFormat)
+	}
+	tables, err := e.selectedTables()
+	if err != nil {
+		return nil, err
+	}
+	for _, t := range tables {
+		if _, _, err := e.selectedColumns(t); err != nil {
+			return nil, err
+		}
+	}
+
+	snap, err := e.Snapshot(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	name := e.filename(snap)
+	base := strings.TrimSuffix(name, filepath.Ext(name))
+
+	paths := make([]string, 0, len(tables))
+	for _, t := range tables {
+		path := filepath.Join(dir, base+"-"+string(t)+"."+string(e.tableFormat))
+		if err := writeAtomic(path, func(w io.Writer) error {
+			return e.WriteTable(w, snap, t)
+		}); err != nil {
+			return paths, fmt.Errorf("%s: %w", t, err)
+		}
+		paths = append(paths, path)
+	}
+	return paths, nil
+}
+
+// WriteTable flattens one table of snap and encodes it to w in the
+// exporter's table format
+func (e *Exporter) WriteTable(w io.Writer, snap *Snapshot, t Table) error {
+	cols, idx, err := e.selectedColumns(t)
+	if err != nil {
+		return err
+	}
+
+	rows := tableRows(snap, t)
+	for i, row := range rows {
+		picked := make([]interface{}, len(idx))
+		for j, k := range idx {
+			picked[j] = row[k]
+		}
+		rows[i] = picked
+	}
+
+	switch e.tableFormat {
+	case FormatCSV:
+		return writeCSV(w, cols, rows)
+	case FormatParquet:
+		return writeParquet(w, t, cols, rows)
+	default:
+		return fmt.Errorf("%w: %q", ErrUnknownFormat, e.tableFormat)
+	}
+}
+
+func (e *Exporter) selectedTables() ([]Table, error) {
+	if len(e.tables) == 0 {
+		return Tables(), nil
+	}
+	for _, t := range e.tables {
+		if _, ok := tableColumns[t]; !ok {
+			return nil, fmt.Errorf("%w: %q", ErrUnknownTable, t)
+		}
+	}
+	return e.tables, nil
+}
+
+// selectedColumns returns the columns to write and their index in a full row
+func (e *Exporter) selectedColumns(t Table) ([]column, []int, error) {
+	all, ok := tableColumns[t]
+	if !ok {
+		return nil, nil, fmt.Errorf("%w: %q", ErrUnknownTable, t)
+	}
+
+	names, ok := e.columns[t]
+	if !ok {
+		idx := make([]int, len(all))
+		for i := range all {
+			idx[i] = i
+		}
+		return all, idx, nil
+	}
+
+	cols := make([]column, 0, len(names))
+	idx := make([]int, 0, len(names))
+	for _, name := range names {
+		i := slices.IndexFunc(all, func(c column) bool { return c.name == name })
+		if i < 0 {
+			return nil, nil, fmt.Errorf("%w: %s.%s", ErrUnknownColumn, t, name)
+		}
+		// parquet merges columns of the same name, and a CSV header with a
+		// repeated name is no use either
+		if slices.Contains(idx, i) {
+			return nil, nil, fmt.Errorf("%w: %s.%s", ErrDuplicateColumn, t, name)
+		}
+		cols = append(cols, all[i])
+		idx = append(idx, i)
+	}
+	return cols, idx, nil
+}
+
+// tableRows flattens snap into full rows of t; daily quests are ordered by
+// ID, bundles keep the snapshot's order
+func tableRows(snap *Snapshot, t Table) [][]interface{} {
+	var rows [][]interface{}
+	at := snap.TakenAt
+
+	switch t {
+	case TableQuestObjectives, TableQuestRewards:
+		ids := make([]string, 0, len(snap.DailyQuests))
+		for id := range snap.DailyQuests {
+			ids = append(ids, id)
+		}
+		slices.Sort(ids)
+
+		for _, id := range ids {
+			q := snap.DailyQuests[id]
+			if t == TableQuestObjectives {
+				for _, o := range q.Objectives {
+					rows = append(rows, []interface{}{at, id, o.BackendName, o.Count, o.Stage})
+				}
+				continue
+			}
+			for _, r := range q.Rewards {
+				p := rewards.Parse(r.TemplateID)
+				rows = append(rows, []interface{}{at, id, r.TemplateID, p.Type, p.Name, r.Quantity})
+			}
+		}
+
+	case TableBundleObjectives:
+		for _, b := range snap.Bundles {
+			for _, obj := range b.Objects {
+				for _, o := range obj.Objectives {
+					rows = append(rows, []interface{}{
+						at, b.TemplateID, b.ChallengeBundleSchedule, b.Rarity,
+						obj.QuestDefinition, obj.Rarity,
+						o.BackendName, o.Count, o.Stage, obj.Options.IsBattlePass,
+					})
+				}
+			}
+		}
+
+	case TableBundleRewards:
+		reward := func(b hub.AthenaChallengeBundle, quest, source, templateID string, quantity int) []interface{} {
+			p := rewards.Parse(templateID)
+			return []interface{}{
+				at, b.TemplateID, b.ChallengeBundleSchedule, quest, source,
+				templateID, p.Type, p.Name, quantity,
+			}
+		}
+		for _, b := range snap.Bundles {
+			for _, obj := range b.Objects {
+				for _, r := range obj.Rewards {
+					rows = append(rows, reward(b, obj.QuestDefinition, "quest", r.TemplateID, r.Quantity))
+				}
+			}
+			for _, r := range b.CompletionRewards {
+				rows = append(rows, reward(b, "", "completion", r.TemplateID, r.Quantity))
+			}
+		}
+	}
+	return rows
+}
+
+func writeCSV(w io.Writer, cols []column, rows [][]interface{}) error {
+	cw := csv.NewWriter(w)
+
+	header := make([]string, len(cols))
+	for i, c := range cols {
+		header[i] = c.name
+	}
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+
+	record := make([]string, len(cols))
+	for _, row := range rows {
+		for i, v := range row {
+			switch v := v.(type) {
+			case string:
+				record[i] = v
+			case int:
+				record[i] = strconv.Itoa(v)
+			case bool:
+				record[i] = strconv.FormatBool(v)
+			case time.Time:
+				record[i] = v.UTC().Format(time.RFC3339)
+			}
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,398 @@
+package feed
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ilyskies/QuestHub/pkg/hub"
+	"github.com/ilyskies/QuestHub/pkg/plugin"
+)
+
+// entries a Log keeps unless told otherwise
+const DefaultLogSize = 50
+
+// Entry is one item of the change feed: a bundle or daily quest the hub
+// started serving, or a new season in place of all of them
+type Entry struct {
+	ID      string
+	Type    hub.EventType
+	Subject string
+	At      time.Time
+}
+
+func (e Entry) Title() string {
+	name := e.Subject
+	if _, id, ok := strings.Cut(name, ":"); ok {
+		name = id
+	}
+	switch e.Type {
+	case hub.BundleAdded:
+		return "New challenge bundle: " + name
+	case hub.SeasonRollover:
+		return "New season: " + e.Subject
+	}
+	return "New daily quest: " + name
+}
+
+// EntriesFrom picks the new bundles and daily quests out of cs, or the one
+// entry for a rollover. at is used when cs does not say when its snapshot
+// was fetched.
+func EntriesFrom(cs hub.ChangeSet, at time.Time) []Entry {
+	if cs.To != nil && !cs.To.FetchedAt.IsZero() {
+		at = cs.To.FetchedAt
+	}
+	if cs.Rollover != nil {
+		return []Entry{newEntry(hub.SeasonRollover, cs.Rollover.ToVersion, at)}
+	}
+
+	var out []Entry
+	for _, d := range cs.Quests {
+		if d.Kind == hub.ChangeAdded {
+			out = append(out, newEntry(hub.QuestAdded, d.ID, at))
+		}
+	}
+	for _, d := range cs.Bundles {
+		if d.Kind == hub.ChangeAdded {
+			out = append(out, newEntry(hub.BundleAdded, d.TemplateID, at))
+		}
+	}
+	return out
+}
+
+// EntryFrom is EntriesFrom for one watcher event
+func EntryFrom(e hub.ChangeEvent) (Entry, bool) {
+	if e.Type != hub.QuestAdded && e.Type != hub.BundleAdded && e.Type != hub.SeasonRollover {
+		return Entry{}, false
+	}
+	return newEntry(e.Type, e.ID, e.At), true
+}
+
+// WithSubject is e about subject instead, e.g. a public ID standing in
+// for the hub's
+func (e Entry) WithSubject(subject string) Entry {
+	return newEntry(e.Type, subject, e.At)
+}
+
+// an addition is told apart from a later re-addition by its time
+func newEntry(typ hub.EventType, subject string, at time.Time) Entry {
+	return Entry{
+		ID:      "urn:questhub:" + string(typ) + ":" + subject + ":" + strconv.FormatInt(at.Unix(), 10),
+		Type:    typ,
+		Subject: subject,
+		At:      at,
+	}
+}
+
+// Log keeps the latest entries, newest first, for the feed handlers and
+// sinks. It is a plugin.Sink, so a pipeline can Publish to it.
+type Log struct {
+	mu      sync.RWMutex
+	max     int
+	entries []Entry
+	updated time.Time
+}
+
+var _ plugin.Sink = (*Log)(nil)
+
+// NewLog keeps at most max entries, DefaultLogSize when max is not positive
+func NewLog(max int) *Log {
+	if max <= 0 {
+		max = DefaultLogSize
+	}
+	return &Log{max: max}
+}
+
+func (l *Log) Add(entries ...Entry) {
+	if len(entries) == 0 {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for _, e := range entries {
+		l.entries = append(l.entries, e)
+		if e.At.After(l.updated) {
+			l.updated = e.At
+		}
+	}
+	slices.SortStableFunc(l.entries, func(a, b Entry) int {
+		return b.At.Compare(a.At)
+	})
+	if len(l.entries) > l.max {
+		l.entries = slices.Clip(l.entries[:l.max])
+	}
+}
+
+func (l *Log) Publish(_ context.Context, cs hub.ChangeSet) error {
+	l.Add(EntriesFrom(cs, time.Now())...)
+	return nil
+}
+
+// Consume adds the entries for events until the channel closes or ctx is
+// done, e.g. from Watcher.Subscribe
+func (l *Log) Consume(ctx context.Context, events <-chan hub.ChangeEvent) error {
+	for {
+		select {
+		case e, ok := <-events:
+			if !ok {
+				return nil
+			}
+			if entry, ok := EntryFrom(e); ok {
+				l.Add(entry)
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+func (l *Log) Entries() []Entry {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return slices.Clone(l.entries)
+}
+
+// Updated is the time of the newest entry ever added
+func (l *Log) Updated() time.Time {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.updated
+}
+
+// Format is the syndication format a feed is written in
+type Format string
+
+const (
+	FormatAtom Format = "atom"
+	FormatRSS  Format = "rss"
+)
+
+func (f Format) contentType() string {
+	if f == FormatRSS {
+		return "application/rss+xml; charset=utf-8"
+	}
+	return "application/atom+xml; charset=utf-8"
+}
+
+// Write renders the log as an Atom 1.0 or RSS 2.0 feed titled title. link
+// is where the feed is served, if anywhere.
+func (l *Log) Write(w io.Writer, f Format, title, link string) error {
+	return WriteFeed(w, f, title, link, l.Entries(), l.Updated())
+}
+
+// WriteFeed is Log.Write for entries from elsewhere, newest first
+func WriteFeed(w io.Writer, f Format, title, link string, entries []Entry, updated time.Time) error {
+	var doc interface{}
+	switch f {
+	case FormatAtom:
+		doc = atomFeed(title, link, entries, updated)
+	case FormatRSS:
+		doc = rssFeed(title, link, entries, updated)
+	default:
+		return fmt.Errorf("feed: unknown format %q", f)
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// Handler serves the log in format f; the feed links to the request URL
+func (l *Log) Handler(f Format, title string) http.Handler {
+	return Handler(f, title, func(context.Context) ([]Entry, time.Time, error) {
+		return l.Entries(), l.Updated(), nil
+	})
+}
+
+// Handler serves the entries source returns for each request, e.g. a
+// Log's rewritten on the way; an error answers 500
+func Handler(f Format, title string, source func(ctx context.Context) ([]Entry, time.Time, error)) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		entries, updated, err := source(r.Context())
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		link := "http://" + r.Host + r.URL.Path
+		if r.TLS != nil {
+			link = "https://" + r.Host + r.URL.Path
+		}
+		w.Header().Set("Content-Type", f.contentType())
+		_ = WriteFeed(w, f, title, link, entries, updated)
+	})
+}
+
+type atomDoc struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	Title   string      `xml:"title"`
+	ID      string      `xml:"id"`
+	Updated string      `xml:"updated"`
+	Link    *atomLink   `xml:"link,omitempty"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Rel  string `xml:"rel,attr,omitempty"`
+	Href string `xml:"href,attr"`
+}
+
+type atomEntry struct {
+	Title   string `xml:"title"`
+	ID      string `xml:"id"`
+	Updated string `xml:"updated"`
+	Summary string `xml:"summary"`
+}
+
+func atomFeed(title, link string, entries []Entry, updated time.Time) atomDoc {
+	doc := atomDoc{
+		Title:   title,
+		ID:      "urn:questhub:feed",
+		Updated: updated.UTC().Format(time.RFC3339),
+	}
+	if link != "" {
+		doc.ID = link
+		doc.Link = &atomLink{Rel: "self", Href: link}
+	}
+	for _, e := range entries {
+		doc.Entries = append(doc.Entries, atomEntry{
+			Title:   e.Title(),
+			ID:      e.ID,
+			Updated: e.At.UTC().Format(time.RFC3339),
+			Summary: e.Subject,
+		})
+	}
+	return doc
+}
+
+type rssDoc struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title       string    `xml:"title"`
+	Link        string    `xml:"link"`
+	Description string    `xml:"description"`
+	LastBuild   string    `xml:"lastBuildDate,omitempty"`
+	Items       []rssItem `xml:"item"`
+}
+
+type rssItem struct {
+	Title       string  `xml:"title"`
+	GUID        rssGUID `xml:"guid"`
+	PubDate     string  `xml:"pubDate"`
+	Description string  `xml:"description"`
+}
+
+type rssGUID struct {
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+func rssFeed(title, link string, entries []Entry, updated time.Time) rssDoc {
+	doc := rssDoc{Version: "2.0", Channel: rssChannel{
+		Title:       title,
+		Link:        link,
+		Description: "Challenge bundles and daily quests as the hub starts serving them",
+	}}
+	if !updated.IsZero() {
+		doc.Channel.LastBuild = updated.UTC().Format(time.RFC1123Z)
+	}
+	for _, e := range entries {
+		doc.Channel.Items = append(doc.Channel.Items, rssItem{
+			Title:       e.Title(),
+			GUID:        rssGUID{Value: e.ID},
+			PubDate:     e.At.UTC().Format(time.RFC1123Z),
+			Description: e.Subject,
+		})
+	}
+	return doc
+}
+
+func init() {
+	plugin.RegisterSink("feed", newFileSink)
+}
+
+// fileSink rewrites the feed at cfg["path"] after every change set, as
+// RSS for a .rss path and Atom otherwise. cfg["title"], cfg["link"] and
+// cfg["size"] are optional.
+type fileSink struct {
+	log         *Log
+	path        string
+	format      Format
+	title, link string
+}
+
+func newFileSink(cfg plugin.Config) (plugin.Sink, error) {
+	if cfg["path"] == "" {
+		return nil, errors.New("feed sink: path is required")
+	}
+
+	size := 0
+	if v := cfg["size"]; v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, fmt.Errorf("feed sink: size: %w", err)
+		}
+		size = n
+	}
+
+	s := &fileSink{
+		log:    NewLog(size),
+		path:   cfg["path"],
+		format: FormatAtom,
+		title:  cfg["title"],
+		link:   cfg["link"],
+	}
+	if filepath.Ext(s.path) == ".rss" {
+		s.format = FormatRSS
+	}
+	if s.title == "" {
+		s.title = "QuestHub changes"
+	}
+	return s, nil
+}
+
+// the feed is written even without new entries, so it exists from the
+// first run
+func (s *fileSink) Publish(ctx context.Context, cs hub.ChangeSet) error {
+	if err := s.log.Publish(ctx, cs); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), "."+filepath.Base(s.path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := s.log.Write(tmp, s.format, s.title, s.link); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), s.path)
+}
@@ -0,0 +1,102 @@
+package feed
+
+import (
+	"context"
+	"encoding/xml"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ilyskies/QuestHub/pkg/hub"
+	"github.com/ilyskies/QuestHub/pkg/plugin"
+)
+
+func TestLogFromChangeSets(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2024, 1, d, 0, 0, 0, 0, time.UTC) }
+
+	first := &hub.Snapshot{DailyQuests: map[string]hub.BaseQuest{"Quest_Daily_Eliminations": {Count: 3}}}
+	second := &hub.Snapshot{
+		DailyQuests: map[string]hub.BaseQuest{"Quest_Daily_Eliminations": {Count: 5}},
+		Bundles:     []hub.AthenaChallengeBundle{{TemplateID: "ChallengeBundle:QuestBundle_Week_002"}},
+	}
+
+	l := NewLog(2)
+	l.Add(EntriesFrom(hub.Diff(nil, first), day(1))...)
+	// the changed quest is not news, only the new bundle
+	l.Add(EntriesFrom(hub.Diff(first, second), day(2))...)
+	l.Add(newEntry(hub.QuestAdded, "Quest_Old", day(0)))
+
+	got := l.Entries()
+	if len(got) != 2 {
+		t.Fatalf("got %d entries, want 2: %+v", len(got), got)
+	}
+	if got[0].Title() != "New challenge bundle: QuestBundle_Week_002" || got[1].Title() != "New daily quest: Quest_Daily_Eliminations" {
+		t.Errorf("got %q, %q", got[0].Title(), got[1].Title())
+	}
+
+	var atom strings.Builder
+	if err := l.Write(&atom, FormatAtom, "Changes", "http://example.com/feed.atom"); err != nil {
+		t.Fatal(err)
+	}
+	var doc struct {
+		Updated string `xml:"updated"`
+		Entries []struct {
+			Title string `xml:"title"`
+		} `xml:"entry"`
+	}
+	if err := xml.Unmarshal([]byte(atom.String()), &doc); err != nil {
+		t.Fatalf("%v\n%s", err, atom.String())
+	}
+	if doc.Updated != "2024-01-02T00:00:00Z" || len(doc.Entries) != 2 {
+		t.Errorf("got %+v\n%s", doc, atom.String())
+	}
+}
+
+// a new season is one entry, not one per quest and bundle
+func TestRolloverEntry(t *testing.T) {
+	old := &hub.Snapshot{Status: &hub.ServiceStatus{Version: "9.0"}, DailyQuests: map[string]hub.BaseQuest{"Quest_A": {Count: 1}}}
+	next := &hub.Snapshot{
+		Status:      &hub.ServiceStatus{Version: "10.0"},
+		DailyQuests: map[string]hub.BaseQuest{"Quest_B": {Count: 1}},
+		Bundles:     []hub.AthenaChallengeBundle{{TemplateID: "ChallengeBundle:QuestBundle_S10_Week_001"}},
+	}
+
+	entries := EntriesFrom(hub.SeasonDiff(old, next), time.Now())
+	if len(entries) != 1 || entries[0].Type != hub.SeasonRollover || entries[0].Title() != "New season: 10.0" {
+		t.Errorf("entries = %+v", entries)
+	}
+}
+
+func TestFileSink(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "changes.rss")
+	sink, err := plugin.NewSink("feed", plugin.Config{"path": path, "title": "Week 2"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	snap := &hub.Snapshot{Bundles: []hub.AthenaChallengeBundle{{TemplateID: "ChallengeBundle:QuestBundle_Week_002"}}}
+	if err := sink.Publish(context.Background(), hub.Diff(nil, snap)); err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc struct {
+		Channel struct {
+			Title string `xml:"title"`
+			Items []struct {
+				Title string `xml:"title"`
+			} `xml:"item"`
+		} `xml:"channel"`
+	}
+	if err := xml.Unmarshal(b, &doc); err != nil {
+		t.Fatalf("%v\n%s", err, b)
+	}
+	if doc.Channel.Title != "Week 2" || len(doc.Channel.Items) != 1 || doc.Channel.Items[0].Title != "New challenge bundle: QuestBundle_Week_002" {
+		t.Errorf("got %+v", doc)
+	}
+}
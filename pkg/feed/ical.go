@@ -0,0 +1,116 @@
+// Package feed renders hub data in formats other software already
+// subscribes to: an iCalendar feed of bundle unlocks for calendar apps, and
+// an Atom or RSS feed of the bundles and daily quests the hub starts
+// serving, built from the diff engine's change sets.
+package feed
+
+import (
+	"bufio"
+	"cmp"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/ilyskies/QuestHub/pkg/hub"
+)
+
+// Unlock is one bundle becoming available. Week is 0 without season info
+// or outside the season; End is zero for bundles that never expire.
+type Unlock struct {
+	ScheduleID string
+	BundleID   string
+	Week       int
+	Start      time.Time
+	End        time.Time
+}
+
+// Unlocks lists the public schedules that have a start, ordered by it.
+// season may be nil.
+func Unlocks(schedules []hub.ChallengeBundleSchedule, season *hub.SeasonInfo) []Unlock {
+	var out []Unlock
+	for _, s := range schedules {
+		if s.Visibility == hub.VisibilityHidden || s.ActiveFrom.IsZero() {
+			continue
+		}
+		u := Unlock{
+			ScheduleID: s.TemplateID,
+			BundleID:   s.QuestBundle,
+			Start:      s.ActiveFrom,
+			End:        s.ActiveUntil,
+		}
+		if season != nil {
+			u.Week = season.CurrentWeek(s.ActiveFrom)
+		}
+		out = append(out, u)
+	}
+
+	slices.SortFunc(out, func(a, b Unlock) int {
+		return cmp.Or(a.Start.Compare(b.Start), strings.Compare(a.ScheduleID, b.ScheduleID))
+	})
+	return out
+}
+
+// Title is the event summary, e.g. "Week 2: QuestBundle_Week_002"
+func (u Unlock) Title() string {
+	name := u.BundleID
+	if _, id, ok := strings.Cut(name, ":"); ok {
+		name = id
+	}
+	if u.Week > 0 {
+		return fmt.Sprintf("Week %d: %s", u.Week, name)
+	}
+	return name
+}
+
+const icalTime = "20060102T150405Z"
+
+// WriteICS writes unlocks as an iCalendar (RFC 5545) feed named name.
+// generated stamps every event, so the same data always renders the same.
+// Unlocks without an end become one-hour events.
+func WriteICS(w io.Writer, name string, unlocks []Unlock, generated time.Time) error {
+	bw := bufio.NewWriter(w)
+	line := func(s string) {
+		// lines are folded at 75 octets, continuing with a space
+		for len(s) > 75 {
+			cut := 75
+			for cut > 0 && !isRuneStart(s[cut]) {
+				cut--
+			}
+			bw.WriteString(s[:cut] + "\r\n")
+			s = " " + s[cut:]
+		}
+		bw.WriteString(s + "\r\n")
+	}
+
+	line("BEGIN:VCALENDAR")
+	line("VERSION:2.0")
+	line("PRODID:-//QuestHub//Bundle unlocks//EN")
+	line("CALSCALE:GREGORIAN")
+	line("X-WR-CALNAME:" + escapeText(name))
+	for _, u := range unlocks {
+		end := u.End
+		if end.IsZero() {
+			end = u.Start.Add(time.Hour)
+		}
+		line("BEGIN:VEVENT")
+		line("UID:" + escapeText(u.ScheduleID) + "@questhub")
+		line("DTSTAMP:" + generated.UTC().Format(icalTime))
+		line("DTSTART:" + u.Start.UTC().Format(icalTime))
+		line("DTEND:" + end.UTC().Format(icalTime))
+		line("SUMMARY:" + escapeText(u.Title()))
+		line("DESCRIPTION:" + escapeText(u.BundleID+" unlocks with "+u.ScheduleID))
+		line("END:VEVENT")
+	}
+	line("END:VCALENDAR")
+	return bw.Flush()
+}
+
+func escapeText(s string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\n", `\n`).Replace(s)
+}
+
+func isRuneStart(b byte) bool {
+	return b&0xc0 != 0x80
+}
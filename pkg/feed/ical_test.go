@@ -0,0 +1,52 @@
+package feed
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ilyskies/QuestHub/pkg/hub"
+)
+
+func TestWriteICS(t *testing.T) {
+	week := func(d int) time.Time { return time.Date(2024, 1, 1+7*d, 0, 0, 0, 0, time.UTC) }
+	season := &hub.SeasonInfo{
+		Season: 1,
+		Start:  week(0),
+		End:    week(8),
+		Weeks:  []hub.WeekInfo{{Week: 1, UnlockAt: week(0)}, {Week: 2, UnlockAt: week(1)}},
+	}
+	schedules := []hub.ChallengeBundleSchedule{
+		{TemplateID: "ChallengeBundleSchedule:Week_002", QuestBundle: "ChallengeBundle:QuestBundle_Week_002", ActiveFrom: week(1)},
+		{TemplateID: "ChallengeBundleSchedule:Week_001", QuestBundle: "ChallengeBundle:QuestBundle_Week_001", ActiveFrom: week(0), ActiveUntil: week(8)},
+		{TemplateID: "ChallengeBundleSchedule:Secret", QuestBundle: "ChallengeBundle:Secret", ActiveFrom: week(0), Visibility: hub.VisibilityHidden},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteICS(&buf, "Unlocks, season 1", Unlocks(schedules, season), week(0)); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+
+	for _, want := range []string{
+		"X-WR-CALNAME:Unlocks\\, season 1\r\n",
+		"SUMMARY:Week 1: QuestBundle_Week_001\r\nDESCRIPTION:",
+		"DTSTART:20240108T000000Z\r\nDTEND:20240108T010000Z\r\nSUMMARY:Week 2: QuestBundle_Week_002",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("missing %q in\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "Secret") {
+		t.Error("hidden schedule in the feed")
+	}
+	if strings.Index(out, "Week 1:") > strings.Index(out, "Week 2:") {
+		t.Error("events not ordered by start")
+	}
+	for _, line := range strings.Split(strings.TrimSuffix(out, "\r\n"), "\r\n") {
+		if len(line) > 75 {
+			t.Errorf("unfolded line %q", line)
+		}
+	}
+}
@@ -0,0 +1,43 @@
+package gateway
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/ilyskies/QuestHub/pkg/feed"
+	"github.com/ilyskies/QuestHub/pkg/hub"
+)
+
+// a hub.Service that also knows the season, as *hub.Client does
+type seasonSource interface {
+	GetSeasonInfo(ctx context.Context, opts ...hub.CallOption) (*hub.SeasonInfo, error)
+}
+
+// Calendar serves the bundle unlocks as an iCalendar feed calendar apps
+// can subscribe to. Events carry week numbers when svc can tell the
+// season.
+func (s *Server) Calendar() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		schedules, err := s.svc.GetChallengeBundleSchedules(r.Context())
+		if err != nil {
+			writeError(w, statusError(err))
+			return
+		}
+
+		var season *hub.SeasonInfo
+		if src, ok := s.svc.(seasonSource); ok {
+			// without it the events only lose their week numbers
+			season, _ = src.GetSeasonInfo(r.Context())
+		}
+
+		unlocks := feed.Unlocks(schedules, season)
+		if err := s.publicUnlocks(r.Context(), unlocks); err != nil {
+			writeError(w, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+		_ = feed.WriteICS(w, "QuestHub bundle unlocks", unlocks, time.Now())
+	})
+}
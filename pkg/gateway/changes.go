@@ -0,0 +1,37 @@
+package gateway
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/ilyskies/QuestHub/pkg/feed"
+	"github.com/ilyskies/QuestHub/pkg/hub"
+)
+
+// Changes serves l in format f, as feed.Log.Handler does, with the
+// entries' IDs swapped for public ones under WithIDMapper
+func (s *Server) Changes(l *feed.Log, f feed.Format, title string) http.Handler {
+	if s.ids == nil {
+		return l.Handler(f, title)
+	}
+	return feed.Handler(f, title, func(ctx context.Context) ([]feed.Entry, time.Time, error) {
+		entries := l.Entries()
+		subjects := make([]string, len(entries))
+		var ids []*string
+		for i, e := range entries {
+			subjects[i] = e.Subject
+			// a rollover is about a version, not an ID
+			if e.Type != hub.SeasonRollover {
+				ids = append(ids, &subjects[i])
+			}
+		}
+		if err := s.publicIDs(ctx, ids...); err != nil {
+			return nil, time.Time{}, err
+		}
+		for i, e := range entries {
+			entries[i] = e.WithSubject(subjects[i])
+		}
+		return entries, l.Updated(), nil
+	})
+}
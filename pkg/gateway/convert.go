@@ -0,0 +1,94 @@
+package gateway
+
+import (
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/ilyskies/QuestHub/pkg/gateway/questhubv1"
+	"github.com/ilyskies/QuestHub/pkg/hub"
+)
+
+func serviceStatusProto(s *hub.ServiceStatus) *questhubv1.ServiceStatus {
+	return &questhubv1.ServiceStatus{
+		Initialized: s.Initialized,
+		Version:     s.Version,
+		Timestamp:   timestampProto(s.Timestamp),
+	}
+}
+
+func questProto(id string, q hub.BaseQuest) *questhubv1.DailyQuest {
+	out := &questhubv1.DailyQuest{Id: id, Count: int32(q.Count)}
+	for _, o := range q.Objectives {
+		out.Objectives = append(out.Objectives, &questhubv1.Objective{
+			BackendName: o.BackendName,
+			Count:       int32(o.Count),
+			Stage:       int32(o.Stage),
+		})
+	}
+	for _, r := range q.Rewards {
+		out.Rewards = append(out.Rewards, rewardProto(r.TemplateID, r.Quantity))
+	}
+	return out
+}
+
+func bundleProto(b *hub.AthenaChallengeBundle) *questhubv1.ChallengeBundle {
+	out := &questhubv1.ChallengeBundle{
+		TemplateId:              b.TemplateID,
+		ChallengeBundleSchedule: b.ChallengeBundleSchedule,
+		Amount:                  int32(b.Amount),
+		Rarity:                  b.Rarity,
+	}
+	for _, o := range b.Objects {
+		obj := &questhubv1.ChallengeBundleObject{
+			QuestDefinition: o.QuestDefinition,
+			Rarity:          o.Rarity,
+			Options: &questhubv1.ChallengeBundleOptions{
+				IsBattlePass:                  o.Options.IsBattlePass,
+				IsOvertime:                    o.Options.IsOvertime,
+				GrantWithPass:                 o.Options.GrantWithPass,
+				ProgressOnBattlePassPurchased: o.Options.ProgressOnBattlePassPurchased,
+				AthenaSeasonProgress:          o.Options.AthenaSeasonProgress,
+				BattlePassProgress:            o.Options.BattlePassProgress,
+				GainAthenaSeasonXp:            o.Options.GainAthenaSeasonXP,
+			},
+		}
+		for _, r := range o.Rewards {
+			obj.Rewards = append(obj.Rewards, rewardProto(r.TemplateID, r.Quantity))
+		}
+		for _, ob := range o.Objectives {
+			obj.Objectives = append(obj.Objectives, &questhubv1.Objective{
+				BackendName: ob.BackendName,
+				Count:       int32(ob.Count),
+				Stage:       int32(ob.Stage),
+			})
+		}
+		out.Objects = append(out.Objects, obj)
+	}
+	for _, r := range b.CompletionRewards {
+		out.CompletionRewards = append(out.CompletionRewards, rewardProto(r.TemplateID, r.Quantity))
+	}
+	return out
+}
+
+func scheduleProto(s *hub.ChallengeBundleSchedule) *questhubv1.ChallengeBundleSchedule {
+	return &questhubv1.ChallengeBundleSchedule{
+		TemplateId:  s.TemplateID,
+		QuestBundle: s.QuestBundle,
+		ActiveFrom:  timestampProto(s.ActiveFrom),
+		ActiveUntil: timestampProto(s.ActiveUntil),
+		Visibility:  string(s.Visibility),
+	}
+}
+
+func rewardProto(templateID string, quantity int) *questhubv1.Reward {
+	return &questhubv1.Reward{TemplateId: templateID, Quantity: int32(quantity)}
+}
+
+// zero times stay unset rather than becoming the Unix epoch
+func timestampProto(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+	return timestamppb.New(t)
+}
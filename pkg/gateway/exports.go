@@ -0,0 +1,83 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/ilyskies/QuestHub/pkg/export"
+	"github.com/ilyskies/QuestHub/pkg/hub"
+)
+
+// Exports serves /exports/latest.json, everything svc has in the layout
+// questhub export writes, for bulk downloads. It is meant to be handed out
+// behind Signer.Protect rather than served openly. With WithIDMapper the
+// export carries the public IDs.
+func (s *Server) Exports() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /exports/latest.json", func(w http.ResponseWriter, r *http.Request) {
+		snap, err := s.snapshot(r.Context())
+		if err != nil {
+			writeError(w, statusError(err))
+			return
+		}
+		if s.ids != nil {
+			// svc may share what it returns, e.g. with a response cache
+			snap.Snapshot = *snap.Snapshot.Clone()
+			if err := s.publicSnapshot(r.Context(), &snap.Snapshot); err != nil {
+				writeError(w, err)
+				return
+			}
+		}
+		data, err := json.MarshalIndent(snap, "", "  ")
+		if err != nil {
+			writeError(w, status.Error(codes.Internal, err.Error()))
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Content-Disposition", `attachment; filename="`+export.DefaultFilename(snap)+`"`)
+		_, _ = w.Write(data)
+	})
+	return mux
+}
+
+// snapshot reads svc the way Client.Snapshot reads the hub, so data svc
+// rewrites on the way, e.g. an override.Patch, is exported as served
+func (s *Server) snapshot(ctx context.Context) (*export.Snapshot, error) {
+	now := time.Now().UTC()
+	st, err := s.svc.GetServiceStatus(ctx)
+	if err != nil {
+		return nil, err
+	}
+	quests, err := s.svc.GetDailyQuests(ctx)
+	if err != nil {
+		return nil, err
+	}
+	bundles, err := s.svc.GetChallengeBundles(ctx)
+	if err != nil {
+		return nil, err
+	}
+	schedules, err := s.svc.GetChallengeBundleSchedules(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	prov := hub.Provenance{Source: "questhub-gateway", ServerVersion: st.Version, FetchedAt: now}
+	return &export.Snapshot{
+		FormatVersion: export.FormatVersion,
+		Source:        prov.Source,
+		Snapshot: hub.Snapshot{
+			TakenAt:     now,
+			Status:      st,
+			DailyQuests: quests,
+			Bundles:     bundles,
+			Schedules:   schedules,
+			Provenance:  prov.With(export.TransformExport),
+		},
+	}, nil
+}
@@ -0,0 +1,209 @@
+// Package gateway re-exposes a hub.Service as the gRPC service in
+// questhubv1 and as a REST API carrying the same messages as JSON, for
+// consumers that cannot speak SignalR. NewGraphQL adds a GraphQL schema
+// over a store.Store. cmd/questhub-gateway serves all three.
+//
+// WithIDMapper hides the hub's quest and template IDs behind public ones in
+// the gRPC and REST APIs, the calendar, the change feeds and exports;
+// requests take the public IDs. WithGraphQLIDMapper does the same for
+// GraphQL.
+//
+// Exports serves a full export for bulk downloads, and a Signer hands out
+// time-limited links to it.
+package gateway
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strconv"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/ilyskies/QuestHub/pkg/gateway/questhubv1"
+	"github.com/ilyskies/QuestHub/pkg/hub"
+)
+
+// Server answers every call from svc, so a Client's response cache applies
+// and a FileBackend serves exported data offline
+type Server struct {
+	questhubv1.UnimplementedQuestHubServiceServer
+
+	svc hub.Service
+	ids IDMapper
+}
+
+var _ questhubv1.QuestHubServiceServer = (*Server)(nil)
+
+type Option func(*Server)
+
+// WithIDMapper answers with the public IDs m gives out, e.g. StoreIDs
+func WithIDMapper(m IDMapper) Option {
+	return func(s *Server) {
+		s.ids = m
+	}
+}
+
+func New(svc hub.Service, opts ...Option) *Server {
+	s := &Server{svc: svc}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+func (s *Server) Register(gs *grpc.Server) {
+	questhubv1.RegisterQuestHubServiceServer(gs, s)
+}
+
+func (s *Server) GetServiceStatus(ctx context.Context, _ *questhubv1.GetServiceStatusRequest) (*questhubv1.ServiceStatus, error) {
+	st, err := s.svc.GetServiceStatus(ctx)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return serviceStatusProto(st), nil
+}
+
+func (s *Server) ListDailyQuests(ctx context.Context, _ *questhubv1.ListDailyQuestsRequest) (*questhubv1.ListDailyQuestsResponse, error) {
+	quests, err := s.svc.GetDailyQuests(ctx)
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	resp := &questhubv1.ListDailyQuestsResponse{}
+	var ids []*string
+	for _, id := range hub.QuestSet(quests).IDs() {
+		q := questProto(id, quests[id])
+		resp.Quests = append(resp.Quests, q)
+		ids = append(ids, &q.Id)
+	}
+	if err := s.publicIDs(ctx, ids...); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (s *Server) GetDailyQuest(ctx context.Context, req *questhubv1.GetDailyQuestRequest) (*questhubv1.DailyQuest, error) {
+	id, err := s.internalID(ctx, req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	q, err := s.svc.GetDailyQuest(ctx, id)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return questProto(req.GetId(), *q), nil
+}
+
+// page tokens are the offset of the page's first bundle
+func (s *Server) ListChallengeBundles(ctx context.Context, req *questhubv1.ListChallengeBundlesRequest) (*questhubv1.ListChallengeBundlesResponse, error) {
+	if req.GetPageSize() < 0 {
+		return nil, status.Error(codes.InvalidArgument, "negative page_size")
+	}
+	offset := 0
+	if tok := req.GetPageToken(); tok != "" {
+		n, err := strconv.Atoi(tok)
+		if err != nil || n < 0 {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid page_token %q", tok)
+		}
+		offset = n
+	}
+
+	bundles, err := s.svc.GetChallengeBundles(ctx)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	bundles = slices.Clone(bundles)
+	slices.SortFunc(bundles, func(a, b hub.AthenaChallengeBundle) int {
+		return strings.Compare(a.TemplateID, b.TemplateID)
+	})
+
+	resp := &questhubv1.ListChallengeBundlesResponse{TotalSize: int32(len(bundles))}
+	end := len(bundles)
+	if size := int(req.GetPageSize()); size > 0 {
+		end = min(offset+size, len(bundles))
+	}
+	if offset < end {
+		var ids []*string
+		for i := range bundles[offset:end] {
+			b := bundleProto(&bundles[offset+i])
+			resp.Bundles = append(resp.Bundles, b)
+			ids = append(ids, bundleIDs(b)...)
+		}
+		if err := s.publicIDs(ctx, ids...); err != nil {
+			return nil, err
+		}
+	}
+	if end < len(bundles) && offset < end {
+		resp.NextPageToken = strconv.Itoa(end)
+	}
+	return resp, nil
+}
+
+func (s *Server) GetChallengeBundle(ctx context.Context, req *questhubv1.GetChallengeBundleRequest) (*questhubv1.ChallengeBundle, error) {
+	id, err := s.internalID(ctx, req.GetTemplateId())
+	if err != nil {
+		return nil, err
+	}
+	b, err := s.svc.GetChallengeBundle(ctx, id)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	out := bundleProto(b)
+	if err := s.publicIDs(ctx, bundleIDs(out)...); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (s *Server) ListChallengeBundleSchedules(ctx context.Context, _ *questhubv1.ListChallengeBundleSchedulesRequest) (*questhubv1.ListChallengeBundleSchedulesResponse, error) {
+	schedules, err := s.svc.GetChallengeBundleSchedules(ctx)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	schedules = slices.Clone(schedules)
+	slices.SortFunc(schedules, func(a, b hub.ChallengeBundleSchedule) int {
+		return strings.Compare(a.TemplateID, b.TemplateID)
+	})
+
+	resp := &questhubv1.ListChallengeBundleSchedulesResponse{}
+	var ids []*string
+	for i := range schedules {
+		sched := scheduleProto(&schedules[i])
+		resp.Schedules = append(resp.Schedules, sched)
+		ids = append(ids, scheduleIDs(sched)...)
+	}
+	if err := s.publicIDs(ctx, ids...); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// statusError maps the hub's sentinels onto gRPC codes; the REST handler
+// maps those onto HTTP statuses in turn
+func statusError(err error) error {
+	code := codes.Unknown
+	switch {
+	case errors.Is(err, hub.ErrQuestNotFound), errors.Is(err, hub.ErrBundleNotFound):
+		code = codes.NotFound
+	case errors.Is(err, hub.ErrInvalidQuestID), errors.Is(err, hub.ErrInvalidTemplateID),
+		errors.Is(err, hub.ErrInvalidPage), errors.Is(err, hub.ErrInvalidWeek):
+		code = codes.InvalidArgument
+	case errors.Is(err, hub.ErrNotConnected), errors.Is(err, hub.ErrNotInitialized),
+		errors.Is(err, hub.ErrConnectionLost), errors.Is(err, hub.ErrDraining),
+		errors.Is(err, hub.ErrConnectionTimeout):
+		code = codes.Unavailable
+	case errors.Is(err, hub.ErrMethodNotFound):
+		code = codes.Unimplemented
+	case errors.Is(err, hub.ErrBudgetExhausted), errors.Is(err, context.DeadlineExceeded):
+		code = codes.DeadlineExceeded
+	case errors.Is(err, context.Canceled):
+		code = codes.Canceled
+	case errors.Is(err, hub.ErrInvokeFailed):
+		code = codes.Internal
+	}
+	return status.Error(code, err.Error())
+}
@@ -0,0 +1,476 @@
+package gateway
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/graph-gophers/graphql-go"
+	"github.com/graph-gophers/graphql-go/relay"
+
+	"github.com/ilyskies/QuestHub/pkg/hub"
+	"github.com/ilyskies/QuestHub/pkg/hub/rewards"
+	"github.com/ilyskies/QuestHub/pkg/store"
+)
+
+//go:embed schema.graphql
+var graphQLSchema string
+
+// relations let a query nest without bound, e.g. bundle -> reward ->
+// bundles -> ...; deeper queries are rejected
+const graphQLMaxDepth = 12
+
+type GraphQLOption func(*graphRoot)
+
+// WithGraphQLIDMapper answers with the public IDs m gives out and takes
+// them in arguments, as WithIDMapper does for the other APIs
+func WithGraphQLIDMapper(m IDMapper) GraphQLOption {
+	return func(r *graphRoot) {
+		r.ids = m
+	}
+}
+
+// NewGraphQL serves schema.graphql at POST with a JSON {query, variables}
+// body. Every field resolves from st, so queries never reach the hub.
+func NewGraphQL(st *store.Store, opts ...GraphQLOption) (http.Handler, error) {
+	root := &graphRoot{st: st}
+	for _, opt := range opts {
+		opt(root)
+	}
+	schema, err := graphql.ParseSchema(graphQLSchema, root, graphql.MaxDepth(graphQLMaxDepth))
+	if err != nil {
+		return nil, fmt.Errorf("gateway: graphql schema: %w", err)
+	}
+	h := &relay.Handler{Schema: schema}
+	if root.ids == nil {
+		return h, nil
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := root.mapIDs(r.Context()); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		h.ServeHTTP(w, r)
+	}), nil
+}
+
+type graphRoot struct {
+	st  *store.Store
+	ids IDMapper
+
+	// the store version whose IDs all have public ones
+	mu     sync.Mutex
+	mapped uint64
+}
+
+// mapIDs maps every ID in the store at once whenever it has changed, so
+// the IDs a query resolves are already mapped and new ones are persisted
+// together rather than field by field
+func (r *graphRoot) mapIDs(ctx context.Context) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	version := r.st.Version()
+	if version == r.mapped {
+		return nil
+	}
+	snap := r.st.Snapshot()
+	var ids []*string
+	for id := range snap.DailyQuests {
+		ids = append(ids, &id)
+	}
+	for i := range snap.Bundles {
+		b := &snap.Bundles[i]
+		ids = append(ids, &b.TemplateID, &b.ChallengeBundleSchedule)
+		for j := range b.Objects {
+			ids = append(ids, &b.Objects[j].QuestDefinition)
+		}
+	}
+	for i := range snap.Schedules {
+		ids = append(ids, &snap.Schedules[i].TemplateID, &snap.Schedules[i].QuestBundle)
+	}
+	if err := mapPublic(ctx, r.ids, ids...); err != nil {
+		return err
+	}
+	r.mapped = version
+	return nil
+}
+
+func (r *graphRoot) public(ctx context.Context, id string) (string, error) {
+	if r.ids == nil || id == "" {
+		return id, nil
+	}
+	return r.ids.Public(ctx, id)
+}
+
+// the ID behind one given in an argument; false for an unknown public ID
+func (r *graphRoot) internal(ctx context.Context, id string) (string, bool, error) {
+	if r.ids == nil {
+		return id, true, nil
+	}
+	return r.ids.Internal(ctx, id)
+}
+
+func (r *graphRoot) UpdatedAt() *string {
+	return timeString(r.st.UpdatedAt())
+}
+
+func (r *graphRoot) Quests(args struct{ Reward *string }) []*questResolver {
+	quests := r.st.Quests()
+	if args.Reward != nil {
+		quests = r.st.QuestsByReward(*args.Reward)
+	}
+
+	out := make([]*questResolver, 0, len(quests))
+	for _, id := range quests.IDs() {
+		out = append(out, &questResolver{g: r, id: id, q: quests[id]})
+	}
+	return out
+}
+
+func (r *graphRoot) Quest(ctx context.Context, args struct{ ID graphql.ID }) (*questResolver, error) {
+	id, ok, err := r.internal(ctx, string(args.ID))
+	if err != nil || !ok {
+		return nil, err
+	}
+	q, ok := r.st.Quest(id)
+	if !ok {
+		return nil, nil
+	}
+	return &questResolver{g: r, id: id, q: q}, nil
+}
+
+func (r *graphRoot) Bundles(ctx context.Context, args struct {
+	Rarity         *string
+	Schedule       *string
+	Reward         *string
+	BattlePassOnly *bool
+}) ([]*bundleResolver, error) {
+	if args.Schedule != nil {
+		id, ok, err := r.internal(ctx, *args.Schedule)
+		if err != nil || !ok {
+			return nil, err
+		}
+		args.Schedule = &id
+	}
+
+	// start from the narrowest index given, then filter by the rest
+	var set hub.BundleSet
+	switch {
+	case args.Schedule != nil:
+		set = r.st.BundlesBySchedule(*args.Schedule)
+	case args.Reward != nil:
+		set = r.st.BundlesByReward(*args.Reward)
+	case args.Rarity != nil:
+		set = r.st.BundlesByRarity(*args.Rarity)
+	default:
+		set = r.st.Bundles()
+	}
+
+	if args.Rarity != nil {
+		set = set.ByRarity(*args.Rarity)
+	}
+	if args.Reward != nil {
+		byReward := r.st.BundlesByReward(*args.Reward)
+		set = set.Filter(func(b *hub.AthenaChallengeBundle) bool {
+			_, ok := byReward.Get(b.TemplateID)
+			return ok
+		})
+	}
+	if args.BattlePassOnly != nil && *args.BattlePassOnly {
+		set = set.BattlePassOnly()
+	}
+	return r.bundleResolvers(set), nil
+}
+
+func (r *graphRoot) Bundle(ctx context.Context, args struct{ TemplateID graphql.ID }) (*bundleResolver, error) {
+	id, ok, err := r.internal(ctx, string(args.TemplateID))
+	if err != nil || !ok {
+		return nil, err
+	}
+	b, ok := r.st.Bundle(id)
+	if !ok {
+		return nil, nil
+	}
+	return &bundleResolver{g: r, b: b}, nil
+}
+
+func (r *graphRoot) Schedules(args struct{ ActiveAt *string }) ([]*scheduleResolver, error) {
+	var at time.Time
+	if args.ActiveAt != nil {
+		t, err := time.Parse(time.RFC3339, *args.ActiveAt)
+		if err != nil {
+			return nil, fmt.Errorf("activeAt: %w", err)
+		}
+		at = t
+	}
+
+	var out []*scheduleResolver
+	for _, s := range r.st.Schedules() {
+		if args.ActiveAt != nil && !s.IsActive(at) {
+			continue
+		}
+		out = append(out, &scheduleResolver{g: r, s: s})
+	}
+	return out, nil
+}
+
+func (r *graphRoot) Schedule(ctx context.Context, args struct{ TemplateID graphql.ID }) (*scheduleResolver, error) {
+	id, ok, err := r.internal(ctx, string(args.TemplateID))
+	if err != nil || !ok {
+		return nil, err
+	}
+	s, ok := r.st.Schedule(id)
+	if !ok {
+		return nil, nil
+	}
+	return &scheduleResolver{g: r, s: s}, nil
+}
+
+func (r *graphRoot) Reward(args struct{ TemplateID string }) *rewardTypeResolver {
+	return &rewardTypeResolver{g: r, r: rewards.Parse(args.TemplateID)}
+}
+
+func (r *graphRoot) bundleResolvers(set hub.BundleSet) []*bundleResolver {
+	out := make([]*bundleResolver, 0, len(set))
+	for _, b := range set {
+		out = append(out, &bundleResolver{g: r, b: b})
+	}
+	return out
+}
+
+type questResolver struct {
+	g  *graphRoot
+	id string
+	q  hub.BaseQuest
+}
+
+func (r *questResolver) ID(ctx context.Context) (graphql.ID, error) {
+	id, err := r.g.public(ctx, r.id)
+	return graphql.ID(id), err
+}
+
+func (r *questResolver) Count() int32 { return int32(r.q.Count) }
+
+func (r *questResolver) Objectives() []*objectiveResolver {
+	out := make([]*objectiveResolver, 0, len(r.q.Objectives))
+	for _, o := range r.q.Objectives {
+		out = append(out, &objectiveResolver{backendName: o.BackendName, count: o.Count, stage: o.Stage})
+	}
+	return out
+}
+
+func (r *questResolver) Rewards() []*rewardResolver {
+	out := make([]*rewardResolver, 0, len(r.q.Rewards))
+	for _, rw := range r.q.Rewards {
+		out = append(out, &rewardResolver{g: r.g, templateID: rw.TemplateID, quantity: rw.Quantity})
+	}
+	return out
+}
+
+type objectiveResolver struct {
+	backendName string
+	count       int
+	stage       int
+}
+
+func (r *objectiveResolver) BackendName() string { return r.backendName }
+func (r *objectiveResolver) Count() int32        { return int32(r.count) }
+func (r *objectiveResolver) Stage() int32        { return int32(r.stage) }
+
+type rewardResolver struct {
+	g          *graphRoot
+	templateID string
+	quantity   int
+}
+
+func (r *rewardResolver) TemplateID() string { return r.templateID }
+func (r *rewardResolver) Quantity() int32    { return int32(r.quantity) }
+
+func (r *rewardResolver) Reward() *rewardTypeResolver {
+	return &rewardTypeResolver{g: r.g, r: rewards.Parse(r.templateID)}
+}
+
+type rewardTypeResolver struct {
+	g *graphRoot
+	r rewards.Reward
+}
+
+func (r *rewardTypeResolver) TemplateID() string { return r.r.String() }
+func (r *rewardTypeResolver) Type() string       { return r.r.Type }
+func (r *rewardTypeResolver) Name() string       { return r.r.Name }
+
+func (r *rewardTypeResolver) Variant() *string {
+	if r.r.Variant == "" {
+		return nil
+	}
+	return &r.r.Variant
+}
+
+func (r *rewardTypeResolver) Quests() []*questResolver {
+	id := r.r.String()
+	return r.g.Quests(struct{ Reward *string }{&id})
+}
+
+func (r *rewardTypeResolver) Bundles() []*bundleResolver {
+	return r.g.bundleResolvers(r.g.st.BundlesByReward(r.r.String()))
+}
+
+type rewardTotalResolver struct {
+	g *graphRoot
+	a rewards.Amount
+}
+
+func (r *rewardTotalResolver) Reward() *rewardTypeResolver {
+	return &rewardTypeResolver{g: r.g, r: r.a.Reward}
+}
+
+func (r *rewardTotalResolver) Quantity() int32 { return int32(r.a.Quantity) }
+
+type bundleResolver struct {
+	g *graphRoot
+	b hub.AthenaChallengeBundle
+}
+
+func (r *bundleResolver) TemplateID(ctx context.Context) (graphql.ID, error) {
+	id, err := r.g.public(ctx, r.b.TemplateID)
+	return graphql.ID(id), err
+}
+
+func (r *bundleResolver) Rarity() string { return r.b.Rarity }
+func (r *bundleResolver) Amount() int32  { return int32(r.b.Amount) }
+
+func (r *bundleResolver) ScheduleID(ctx context.Context) (string, error) {
+	return r.g.public(ctx, r.b.ChallengeBundleSchedule)
+}
+
+func (r *bundleResolver) Schedule() *scheduleResolver {
+	s, ok := r.g.st.Schedule(r.b.ChallengeBundleSchedule)
+	if !ok {
+		return nil
+	}
+	return &scheduleResolver{g: r.g, s: s}
+}
+
+func (r *bundleResolver) Objects() []*bundleObjectResolver {
+	out := make([]*bundleObjectResolver, 0, len(r.b.Objects))
+	for _, o := range r.b.Objects {
+		out = append(out, &bundleObjectResolver{g: r.g, o: o})
+	}
+	return out
+}
+
+func (r *bundleResolver) CompletionRewards() []*rewardResolver {
+	out := make([]*rewardResolver, 0, len(r.b.CompletionRewards))
+	for _, rw := range r.b.CompletionRewards {
+		out = append(out, &rewardResolver{g: r.g, templateID: rw.TemplateID, quantity: rw.Quantity})
+	}
+	return out
+}
+
+func (r *bundleResolver) TotalRewards() []*rewardTotalResolver {
+	amounts := r.b.TotalRewards().Amounts()
+	out := make([]*rewardTotalResolver, 0, len(amounts))
+	for _, a := range amounts {
+		out = append(out, &rewardTotalResolver{g: r.g, a: a})
+	}
+	return out
+}
+
+type bundleObjectResolver struct {
+	g *graphRoot
+	o hub.ChallengeBundleObject
+}
+
+func (r *bundleObjectResolver) QuestDefinition(ctx context.Context) (string, error) {
+	return r.g.public(ctx, r.o.QuestDefinition)
+}
+
+func (r *bundleObjectResolver) Rarity() string { return r.o.Rarity }
+
+func (r *bundleObjectResolver) Rewards() []*rewardResolver {
+	out := make([]*rewardResolver, 0, len(r.o.Rewards))
+	for _, rw := range r.o.Rewards {
+		out = append(out, &rewardResolver{g: r.g, templateID: rw.TemplateID, quantity: rw.Quantity})
+	}
+	return out
+}
+
+func (r *bundleObjectResolver) Objectives() []*objectiveResolver {
+	out := make([]*objectiveResolver, 0, len(r.o.Objectives))
+	for _, o := range r.o.Objectives {
+		out = append(out, &objectiveResolver{backendName: o.BackendName, count: o.Count, stage: o.Stage})
+	}
+	return out
+}
+
+func (r *bundleObjectResolver) Options() *bundleOptionsResolver {
+	return &bundleOptionsResolver{o: r.o.Options}
+}
+
+type bundleOptionsResolver struct {
+	o hub.ChallengeBundleOptions
+}
+
+func (r *bundleOptionsResolver) IsBattlePass() bool         { return r.o.IsBattlePass }
+func (r *bundleOptionsResolver) IsOvertime() bool           { return r.o.IsOvertime }
+func (r *bundleOptionsResolver) GrantWithPass() bool        { return r.o.GrantWithPass }
+func (r *bundleOptionsResolver) AthenaSeasonProgress() bool { return r.o.AthenaSeasonProgress }
+func (r *bundleOptionsResolver) BattlePassProgress() bool   { return r.o.BattlePassProgress }
+func (r *bundleOptionsResolver) GainAthenaSeasonXP() bool   { return r.o.GainAthenaSeasonXP }
+
+func (r *bundleOptionsResolver) ProgressOnBattlePassPurchased() bool {
+	return r.o.ProgressOnBattlePassPurchased
+}
+
+type scheduleResolver struct {
+	g *graphRoot
+	s hub.ChallengeBundleSchedule
+}
+
+func (r *scheduleResolver) TemplateID(ctx context.Context) (graphql.ID, error) {
+	id, err := r.g.public(ctx, r.s.TemplateID)
+	return graphql.ID(id), err
+}
+
+func (r *scheduleResolver) QuestBundle(ctx context.Context) (string, error) {
+	return r.g.public(ctx, r.s.QuestBundle)
+}
+
+func (r *scheduleResolver) ActiveFrom() *string  { return timeString(r.s.ActiveFrom) }
+func (r *scheduleResolver) ActiveUntil() *string { return timeString(r.s.ActiveUntil) }
+
+func (r *scheduleResolver) Visibility() *string {
+	if r.s.Visibility == "" {
+		return nil
+	}
+	v := string(r.s.Visibility)
+	return &v
+}
+
+func (r *scheduleResolver) IsActive(args struct{ At *string }) (bool, error) {
+	at := time.Now()
+	if args.At != nil {
+		t, err := time.Parse(time.RFC3339, *args.At)
+		if err != nil {
+			return false, fmt.Errorf("at: %w", err)
+		}
+		at = t
+	}
+	return r.s.IsActive(at), nil
+}
+
+func (r *scheduleResolver) Bundles() []*bundleResolver {
+	return r.g.bundleResolvers(r.g.st.BundlesBySchedule(r.s.TemplateID))
+}
+
+func timeString(t time.Time) *string {
+	if t.IsZero() {
+		return nil
+	}
+	s := t.UTC().Format(time.RFC3339)
+	return &s
+}
@@ -0,0 +1,200 @@
+package gateway
+
+import (
+	"context"
+	"maps"
+	"slices"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/ilyskies/QuestHub/pkg/feed"
+	"github.com/ilyskies/QuestHub/pkg/gateway/questhubv1"
+	"github.com/ilyskies/QuestHub/pkg/hub"
+	"github.com/ilyskies/QuestHub/pkg/store"
+)
+
+// IDMapper swaps the hub's quest and template IDs for public ones, for
+// deployments that should not expose them. Public must always give the
+// same answer for an ID.
+type IDMapper interface {
+	Public(ctx context.Context, id string) (string, error)
+	// false for a public ID Public never returned
+	Internal(ctx context.Context, public string) (string, bool, error)
+}
+
+// BatchIDMapper is an IDMapper that can map the IDs of a whole response
+// at once, e.g. to persist the new ones in one write
+type BatchIDMapper interface {
+	IDMapper
+	PublicIDs(ctx context.Context, ids ...string) (map[string]string, error)
+}
+
+// StoreIDs maps IDs to the slugs st hands out, see store.Slug; with a
+// persistent backend they stay valid across restarts
+func StoreIDs(st *store.Store) IDMapper {
+	return storeIDs{st}
+}
+
+var _ BatchIDMapper = storeIDs{}
+
+type storeIDs struct {
+	st *store.Store
+}
+
+func (m storeIDs) Public(ctx context.Context, id string) (string, error) {
+	return m.st.Slug(ctx, id)
+}
+
+func (m storeIDs) PublicIDs(ctx context.Context, ids ...string) (map[string]string, error) {
+	return m.st.Slugs(ctx, ids...)
+}
+
+func (m storeIDs) Internal(_ context.Context, public string) (string, bool, error) {
+	id, ok := m.st.ResolveSlug(public)
+	return id, ok, nil
+}
+
+// the hub's ID behind a requested one; NotFound for unknown public IDs
+func (s *Server) internalID(ctx context.Context, id string) (string, error) {
+	if s.ids == nil {
+		return id, nil
+	}
+	internal, ok, err := s.ids.Internal(ctx, id)
+	if err != nil {
+		return "", status.Error(codes.Internal, err.Error())
+	}
+	if !ok {
+		return "", status.Errorf(codes.NotFound, "no such id %q", id)
+	}
+	return internal, nil
+}
+
+// publicIDs rewrites each ID in place; empty ones stay empty. Callers pass
+// every ID of a response at once, so a BatchIDMapper maps them together.
+func (s *Server) publicIDs(ctx context.Context, ids ...*string) error {
+	if s.ids == nil {
+		return nil
+	}
+	if err := mapPublic(ctx, s.ids, ids...); err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+	return nil
+}
+
+func mapPublic(ctx context.Context, m IDMapper, ids ...*string) error {
+	var internal []string
+	seen := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		if *id != "" && !seen[*id] {
+			seen[*id] = true
+			internal = append(internal, *id)
+		}
+	}
+	if len(internal) == 0 {
+		return nil
+	}
+
+	var public map[string]string
+	if bm, ok := m.(BatchIDMapper); ok {
+		var err error
+		if public, err = bm.PublicIDs(ctx, internal...); err != nil {
+			return err
+		}
+	} else {
+		public = make(map[string]string, len(internal))
+		for _, id := range internal {
+			p, err := m.Public(ctx, id)
+			if err != nil {
+				return err
+			}
+			public[id] = p
+		}
+	}
+
+	for _, id := range ids {
+		if *id != "" {
+			*id = public[*id]
+		}
+	}
+	return nil
+}
+
+func bundleIDs(b *questhubv1.ChallengeBundle) []*string {
+	ids := []*string{&b.TemplateId, &b.ChallengeBundleSchedule}
+	for _, o := range b.Objects {
+		ids = append(ids, &o.QuestDefinition)
+	}
+	return ids
+}
+
+func scheduleIDs(sched *questhubv1.ChallengeBundleSchedule) []*string {
+	return []*string{&sched.TemplateId, &sched.QuestBundle}
+}
+
+// publicSnapshot rewrites the IDs of snap, which must not be shared, in
+// place: the quests' keys, the bundles', schedules' and objects' IDs, and
+// those the names and overrides refer to
+func (s *Server) publicSnapshot(ctx context.Context, snap *hub.Snapshot) error {
+	if s.ids == nil {
+		return nil
+	}
+
+	var ids []*string
+	questIDs := slices.Collect(maps.Keys(snap.DailyQuests))
+	for i := range questIDs {
+		ids = append(ids, &questIDs[i])
+	}
+	for i := range snap.Bundles {
+		b := &snap.Bundles[i]
+		ids = append(ids, &b.TemplateID, &b.ChallengeBundleSchedule)
+		for j := range b.Objects {
+			ids = append(ids, &b.Objects[j].QuestDefinition)
+		}
+	}
+	for i := range snap.Schedules {
+		ids = append(ids, &snap.Schedules[i].TemplateID, &snap.Schedules[i].QuestBundle)
+	}
+	named := slices.Collect(maps.Keys(snap.Names))
+	for i := range named {
+		ids = append(ids, &named[i])
+	}
+	for i := range snap.Provenance.Overrides {
+		ids = append(ids, &snap.Provenance.Overrides[i].ID)
+	}
+
+	// the keys are rewritten along with the rest, so remember the values
+	questValues := make([]hub.BaseQuest, len(questIDs))
+	for i, id := range questIDs {
+		questValues[i] = snap.DailyQuests[id]
+	}
+	nameValues := make([]string, len(named))
+	for i, id := range named {
+		nameValues[i] = snap.Names[id]
+	}
+	if err := s.publicIDs(ctx, ids...); err != nil {
+		return err
+	}
+
+	if snap.DailyQuests != nil {
+		snap.DailyQuests = make(map[string]hub.BaseQuest, len(questIDs))
+		for i, id := range questIDs {
+			snap.DailyQuests[id] = questValues[i]
+		}
+	}
+	if snap.Names != nil {
+		snap.Names = make(map[string]string, len(named))
+		for i, id := range named {
+			snap.Names[id] = nameValues[i]
+		}
+	}
+	return nil
+}
+
+func (s *Server) publicUnlocks(ctx context.Context, unlocks []feed.Unlock) error {
+	var ids []*string
+	for i := range unlocks {
+		ids = append(ids, &unlocks[i].ScheduleID, &unlocks[i].BundleID)
+	}
+	return s.publicIDs(ctx, ids...)
+}
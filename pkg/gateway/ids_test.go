@@ -0,0 +1,205 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ilyskies/QuestHub/pkg/export"
+	"github.com/ilyskies/QuestHub/pkg/feed"
+	"github.com/ilyskies/QuestHub/pkg/gateway/questhubv1"
+	"github.com/ilyskies/QuestHub/pkg/hub"
+	"github.com/ilyskies/QuestHub/pkg/hubtest"
+	"github.com/ilyskies/QuestHub/pkg/store"
+	"github.com/ilyskies/QuestHub/pkg/store/boltstore"
+)
+
+func TestOpaqueIDs(t *testing.T) {
+	hs := hubtest.NewServer(hubtest.DefaultFixtures())
+	defer hs.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	client, err := hs.NewClient(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Disconnect()
+
+	path := filepath.Join(t.TempDir(), "ids.db")
+	serve := func() (*httptest.Server, *store.Store) {
+		b, err := boltstore.Open(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids, err := store.Open(ctx, b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return httptest.NewServer(New(client, WithIDMapper(StoreIDs(ids))).Handler()), ids
+	}
+	get := func(srv *httptest.Server, path string) (int, string) {
+		resp, err := http.Get(srv.URL + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		return resp.StatusCode, string(body)
+	}
+
+	srv, ids := serve()
+	code, body := get(srv, "/v1/bundles")
+	if code != http.StatusOK || strings.Contains(body, "QuestBundle_Week_001") {
+		t.Fatalf("got %d %s, want the bundle under a slug", code, body)
+	}
+	var list struct {
+		Bundles []struct {
+			TemplateID string `json:"templateId"`
+		} `json:"bundles"`
+	}
+	if err := json.Unmarshal([]byte(body), &list); err != nil || len(list.Bundles) != 1 {
+		t.Fatalf("got %s, %v", body, err)
+	}
+	slug := list.Bundles[0].TemplateID
+
+	if code, _ := get(srv, "/v1/bundles/ChallengeBundle:QuestBundle_Week_001"); code != http.StatusNotFound {
+		t.Errorf("raw template ID: got %d, want 404", code)
+	}
+	srv.Close()
+	ids.Close()
+
+	// the slug still resolves after a restart
+	srv, ids = serve()
+	defer ids.Close()
+	defer srv.Close()
+	if code, body := get(srv, "/v1/bundles/"+slug); code != http.StatusOK || !strings.Contains(body, slug) {
+		t.Errorf("slug after restart: got %d %s", code, body)
+	}
+}
+
+// batchIDs prefixes IDs and counts how it was asked
+type batchIDs struct {
+	single, batches int
+}
+
+func (m *batchIDs) Public(_ context.Context, id string) (string, error) {
+	m.single++
+	return "pub-" + id, nil
+}
+
+func (m *batchIDs) PublicIDs(_ context.Context, ids ...string) (map[string]string, error) {
+	m.batches++
+	out := make(map[string]string, len(ids))
+	for _, id := range ids {
+		out[id] = "pub-" + id
+	}
+	return out, nil
+}
+
+func (m *batchIDs) Internal(_ context.Context, public string) (string, bool, error) {
+	id, ok := strings.CutPrefix(public, "pub-")
+	return id, ok, nil
+}
+
+// every ID of a response is mapped in one call
+func TestPublicIDsBatched(t *testing.T) {
+	hs := hubtest.NewServer(hubtest.DefaultFixtures())
+	defer hs.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	client, err := hs.NewClient(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Disconnect()
+
+	ids := &batchIDs{}
+	srv := New(client, WithIDMapper(ids))
+	resp, err := srv.ListChallengeBundles(ctx, &questhubv1.ListChallengeBundlesRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	b := resp.GetBundles()[0]
+	if b.GetTemplateId() != "pub-ChallengeBundle:QuestBundle_Week_001" || !strings.HasPrefix(b.GetChallengeBundleSchedule(), "pub-") {
+		t.Errorf("bundle = %v", b)
+	}
+	if ids.batches != 1 || ids.single != 0 {
+		t.Errorf("%d batches and %d single lookups, want one batch", ids.batches, ids.single)
+	}
+}
+
+// GraphQL, the change feed and exports take and give public IDs as well
+func TestPublicIDsEverywhere(t *testing.T) {
+	hs := hubtest.NewServer(hubtest.DefaultFixtures())
+	defer hs.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	client, err := hs.NewClient(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Disconnect()
+	snap, err := client.Snapshot(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ids := &batchIDs{}
+	srv := New(client, WithIDMapper(ids))
+	const raw = `"ChallengeBundle:QuestBundle_Week_001"`
+
+	st := store.New()
+	if err := st.Reset(ctx, snap); err != nil {
+		t.Fatal(err)
+	}
+	gql, err := NewGraphQL(st, WithGraphQLIDMapper(ids))
+	if err != nil {
+		t.Fatal(err)
+	}
+	query := `{"query": "{ quest(id: \"pub-Quest_Daily_Eliminations\") { id } bundles { templateId scheduleId } schedule(templateId: \"ChallengeBundleSchedule:Schedule_Week_001\") { templateId } }"}`
+	rec := httptest.NewRecorder()
+	gql.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(query)))
+	body := rec.Body.String()
+	if !strings.Contains(body, `"id":"pub-Quest_Daily_Eliminations"`) || !strings.Contains(body, `"templateId":"pub-ChallengeBundle:QuestBundle_Week_001"`) ||
+		!strings.Contains(body, `"schedule":null`) {
+		t.Errorf("graphql: %s", body)
+	}
+
+	changes := feed.NewLog(0)
+	changes.Add(feed.EntriesFrom(hub.Diff(nil, snap), time.Now())...)
+	rec = httptest.NewRecorder()
+	srv.Changes(changes, feed.FormatAtom, "changes").ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/feed.atom", nil))
+	if body := rec.Body.String(); !strings.Contains(body, "pub-ChallengeBundle:QuestBundle_Week_001") || strings.Contains(body, ":ChallengeBundle:") {
+		t.Errorf("feed: %s", body)
+	}
+
+	rec = httptest.NewRecorder()
+	srv.Exports().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/exports/latest.json", nil))
+	var exported export.Snapshot
+	if err := json.Unmarshal(rec.Body.Bytes(), &exported); err != nil {
+		t.Fatalf("export: %v: %s", err, rec.Body)
+	}
+	if _, ok := exported.DailyQuests["pub-Quest_Daily_Eliminations"]; !ok || len(exported.DailyQuests) != 1 {
+		t.Errorf("exported quests = %v", exported.DailyQuests)
+	}
+	if strings.Contains(rec.Body.String(), raw) {
+		t.Errorf("export carries a hub ID: %s", rec.Body)
+	}
+
+	// what svc returned is left alone
+	if bundles, _ := client.GetChallengeBundles(ctx); bundles[0].TemplateID != "ChallengeBundle:QuestBundle_Week_001" {
+		t.Errorf("export rewrote the client's bundles: %v", bundles[0].TemplateID)
+	}
+}
@@ -0,0 +1,6 @@
+// Package questhubv1 holds the gateway's protobuf models and gRPC service,
+// generated from questhub.proto. Regenerate with buf, protoc-gen-go and
+// protoc-gen-go-grpc on PATH.
+package questhubv1
+
+//go:generate buf generate
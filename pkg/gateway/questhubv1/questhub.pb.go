@@ -0,0 +1,1178 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        (unknown)
+// source: questhub.proto
+
+// The QuestHub data re-exposed for consumers that cannot speak SignalR.
+// cmd/questhub-gateway serves it over gRPC and, with the same messages in
+// their JSON form, over REST.
+
+package questhubv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type GetServiceStatusRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetServiceStatusRequest) Reset() {
+	*x = GetServiceStatusRequest{}
+	mi := &file_questhub_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetServiceStatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetServiceStatusRequest) ProtoMessage() {}
+
+func (x *GetServiceStatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_questhub_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetServiceStatusRequest.ProtoReflect.Descriptor instead.
+func (*GetServiceStatusRequest) Descriptor() ([]byte, []int) {
+	return file_questhub_proto_rawDescGZIP(), []int{0}
+}
+
+type ServiceStatus struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Initialized   bool                   `protobuf:"varint,1,opt,name=initialized,proto3" json:"initialized,omitempty"`
+	Version       string                 `protobuf:"bytes,2,opt,name=version,proto3" json:"version,omitempty"`
+	Timestamp     *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ServiceStatus) Reset() {
+	*x = ServiceStatus{}
+	mi := &file_questhub_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ServiceStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ServiceStatus) ProtoMessage() {}
+
+func (x *ServiceStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_questhub_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ServiceStatus.ProtoReflect.Descriptor instead.
+func (*ServiceStatus) Descriptor() ([]byte, []int) {
+	return file_questhub_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ServiceStatus) GetInitialized() bool {
+	if x != nil {
+		return x.Initialized
+	}
+	return false
+}
+
+func (x *ServiceStatus) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+func (x *ServiceStatus) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+type ListDailyQuestsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListDailyQuestsRequest) Reset() {
+	*x = ListDailyQuestsRequest{}
+	mi := &file_questhub_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListDailyQuestsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListDailyQuestsRequest) ProtoMessage() {}
+
+func (x *ListDailyQuestsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_questhub_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListDailyQuestsRequest.ProtoReflect.Descriptor instead.
+func (*ListDailyQuestsRequest) Descriptor() ([]byte, []int) {
+	return file_questhub_proto_rawDescGZIP(), []int{2}
+}
+
+type ListDailyQuestsResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// sorted by id
+	Quests        []*DailyQuest `protobuf:"bytes,1,rep,name=quests,proto3" json:"quests,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListDailyQuestsResponse) Reset() {
+	*x = ListDailyQuestsResponse{}
+	mi := &file_questhub_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListDailyQuestsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListDailyQuestsResponse) ProtoMessage() {}
+
+func (x *ListDailyQuestsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_questhub_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListDailyQuestsResponse.ProtoReflect.Descriptor instead.
+func (*ListDailyQuestsResponse) Descriptor() ([]byte, []int) {
+	return file_questhub_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ListDailyQuestsResponse) GetQuests() []*DailyQuest {
+	if x != nil {
+		return x.Quests
+	}
+	return nil
+}
+
+type GetDailyQuestRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetDailyQuestRequest) Reset() {
+	*x = GetDailyQuestRequest{}
+	mi := &file_questhub_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetDailyQuestRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDailyQuestRequest) ProtoMessage() {}
+
+func (x *GetDailyQuestRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_questhub_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDailyQuestRequest.ProtoReflect.Descriptor instead.
+func (*GetDailyQuestRequest) Descriptor() ([]byte, []int) {
+	return file_questhub_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *GetDailyQuestRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type DailyQuest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Objectives    []*Objective           `protobuf:"bytes,2,rep,name=objectives,proto3" json:"objectives,omitempty"`
+	Rewards       []*Reward              `protobuf:"bytes,3,rep,name=rewards,proto3" json:"rewards,omitempty"`
+	Count         int32                  `protobuf:"varint,4,opt,name=count,proto3" json:"count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DailyQuest) Reset() {
+	*x = DailyQuest{}
+	mi := &file_questhub_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DailyQuest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DailyQuest) ProtoMessage() {}
+
+func (x *DailyQuest) ProtoReflect() protoreflect.Message {
+	mi := &file_questhub_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DailyQuest.ProtoReflect.Descriptor instead.
+func (*DailyQuest) Descriptor() ([]byte, []int) {
+	return file_questhub_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *DailyQuest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *DailyQuest) GetObjectives() []*Objective {
+	if x != nil {
+		return x.Objectives
+	}
+	return nil
+}
+
+func (x *DailyQuest) GetRewards() []*Reward {
+	if x != nil {
+		return x.Rewards
+	}
+	return nil
+}
+
+func (x *DailyQuest) GetCount() int32 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+type Objective struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	BackendName   string                 `protobuf:"bytes,1,opt,name=backend_name,json=backendName,proto3" json:"backend_name,omitempty"`
+	Count         int32                  `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
+	Stage         int32                  `protobuf:"varint,3,opt,name=stage,proto3" json:"stage,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Objective) Reset() {
+	*x = Objective{}
+	mi := &file_questhub_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Objective) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Objective) ProtoMessage() {}
+
+func (x *Objective) ProtoReflect() protoreflect.Message {
+	mi := &file_questhub_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Objective.ProtoReflect.Descriptor instead.
+func (*Objective) Descriptor() ([]byte, []int) {
+	return file_questhub_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *Objective) GetBackendName() string {
+	if x != nil {
+		return x.BackendName
+	}
+	return ""
+}
+
+func (x *Objective) GetCount() int32 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+func (x *Objective) GetStage() int32 {
+	if x != nil {
+		return x.Stage
+	}
+	return 0
+}
+
+type Reward struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TemplateId    string                 `protobuf:"bytes,1,opt,name=template_id,json=templateId,proto3" json:"template_id,omitempty"`
+	Quantity      int32                  `protobuf:"varint,2,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Reward) Reset() {
+	*x = Reward{}
+	mi := &file_questhub_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Reward) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Reward) ProtoMessage() {}
+
+func (x *Reward) ProtoReflect() protoreflect.Message {
+	mi := &file_questhub_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Reward.ProtoReflect.Descriptor instead.
+func (*Reward) Descriptor() ([]byte, []int) {
+	return file_questhub_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *Reward) GetTemplateId() string {
+	if x != nil {
+		return x.TemplateId
+	}
+	return ""
+}
+
+func (x *Reward) GetQuantity() int32 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+type ListChallengeBundlesRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// zero returns every bundle
+	PageSize int32 `protobuf:"varint,1,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	// from a previous response's next_page_token
+	PageToken     string `protobuf:"bytes,2,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListChallengeBundlesRequest) Reset() {
+	*x = ListChallengeBundlesRequest{}
+	mi := &file_questhub_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListChallengeBundlesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListChallengeBundlesRequest) ProtoMessage() {}
+
+func (x *ListChallengeBundlesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_questhub_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListChallengeBundlesRequest.ProtoReflect.Descriptor instead.
+func (*ListChallengeBundlesRequest) Descriptor() ([]byte, []int) {
+	return file_questhub_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ListChallengeBundlesRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListChallengeBundlesRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+type ListChallengeBundlesResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// sorted by template_id
+	Bundles []*ChallengeBundle `protobuf:"bytes,1,rep,name=bundles,proto3" json:"bundles,omitempty"`
+	// empty on the last page
+	NextPageToken string `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	TotalSize     int32  `protobuf:"varint,3,opt,name=total_size,json=totalSize,proto3" json:"total_size,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListChallengeBundlesResponse) Reset() {
+	*x = ListChallengeBundlesResponse{}
+	mi := &file_questhub_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListChallengeBundlesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListChallengeBundlesResponse) ProtoMessage() {}
+
+func (x *ListChallengeBundlesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_questhub_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListChallengeBundlesResponse.ProtoReflect.Descriptor instead.
+func (*ListChallengeBundlesResponse) Descriptor() ([]byte, []int) {
+	return file_questhub_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *ListChallengeBundlesResponse) GetBundles() []*ChallengeBundle {
+	if x != nil {
+		return x.Bundles
+	}
+	return nil
+}
+
+func (x *ListChallengeBundlesResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+func (x *ListChallengeBundlesResponse) GetTotalSize() int32 {
+	if x != nil {
+		return x.TotalSize
+	}
+	return 0
+}
+
+type GetChallengeBundleRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TemplateId    string                 `protobuf:"bytes,1,opt,name=template_id,json=templateId,proto3" json:"template_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetChallengeBundleRequest) Reset() {
+	*x = GetChallengeBundleRequest{}
+	mi := &file_questhub_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetChallengeBundleRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetChallengeBundleRequest) ProtoMessage() {}
+
+func (x *GetChallengeBundleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_questhub_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetChallengeBundleRequest.ProtoReflect.Descriptor instead.
+func (*GetChallengeBundleRequest) Descriptor() ([]byte, []int) {
+	return file_questhub_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *GetChallengeBundleRequest) GetTemplateId() string {
+	if x != nil {
+		return x.TemplateId
+	}
+	return ""
+}
+
+type ChallengeBundle struct {
+	state                   protoimpl.MessageState   `protogen:"open.v1"`
+	TemplateId              string                   `protobuf:"bytes,1,opt,name=template_id,json=templateId,proto3" json:"template_id,omitempty"`
+	ChallengeBundleSchedule string                   `protobuf:"bytes,2,opt,name=challenge_bundle_schedule,json=challengeBundleSchedule,proto3" json:"challenge_bundle_schedule,omitempty"`
+	Objects                 []*ChallengeBundleObject `protobuf:"bytes,3,rep,name=objects,proto3" json:"objects,omitempty"`
+	Amount                  int32                    `protobuf:"varint,4,opt,name=amount,proto3" json:"amount,omitempty"`
+	Rarity                  string                   `protobuf:"bytes,5,opt,name=rarity,proto3" json:"rarity,omitempty"`
+	CompletionRewards       []*Reward                `protobuf:"bytes,6,rep,name=completion_rewards,json=completionRewards,proto3" json:"completion_rewards,omitempty"`
+	unknownFields           protoimpl.UnknownFields
+	sizeCache               protoimpl.SizeCache
+}
+
+func (x *ChallengeBundle) Reset() {
+	*x = ChallengeBundle{}
+	mi := &file_questhub_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChallengeBundle) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChallengeBundle) ProtoMessage() {}
+
+func (x *ChallengeBundle) ProtoReflect() protoreflect.Message {
+	mi := &file_questhub_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChallengeBundle.ProtoReflect.Descriptor instead.
+func (*ChallengeBundle) Descriptor() ([]byte, []int) {
+	return file_questhub_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *ChallengeBundle) GetTemplateId() string {
+	if x != nil {
+		return x.TemplateId
+	}
+	return ""
+}
+
+func (x *ChallengeBundle) GetChallengeBundleSchedule() string {
+	if x != nil {
+		return x.ChallengeBundleSchedule
+	}
+	return ""
+}
+
+func (x *ChallengeBundle) GetObjects() []*ChallengeBundleObject {
+	if x != nil {
+		return x.Objects
+	}
+	return nil
+}
+
+func (x *ChallengeBundle) GetAmount() int32 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *ChallengeBundle) GetRarity() string {
+	if x != nil {
+		return x.Rarity
+	}
+	return ""
+}
+
+func (x *ChallengeBundle) GetCompletionRewards() []*Reward {
+	if x != nil {
+		return x.CompletionRewards
+	}
+	return nil
+}
+
+type ChallengeBundleObject struct {
+	state           protoimpl.MessageState  `protogen:"open.v1"`
+	QuestDefinition string                  `protobuf:"bytes,1,opt,name=quest_definition,json=questDefinition,proto3" json:"quest_definition,omitempty"`
+	Rarity          string                  `protobuf:"bytes,2,opt,name=rarity,proto3" json:"rarity,omitempty"`
+	Rewards         []*Reward               `protobuf:"bytes,3,rep,name=rewards,proto3" json:"rewards,omitempty"`
+	Objectives      []*Objective            `protobuf:"bytes,4,rep,name=objectives,proto3" json:"objectives,omitempty"`
+	Options         *ChallengeBundleOptions `protobuf:"bytes,5,opt,name=options,proto3" json:"options,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *ChallengeBundleObject) Reset() {
+	*x = ChallengeBundleObject{}
+	mi := &file_questhub_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChallengeBundleObject) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChallengeBundleObject) ProtoMessage() {}
+
+func (x *ChallengeBundleObject) ProtoReflect() protoreflect.Message {
+	mi := &file_questhub_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChallengeBundleObject.ProtoReflect.Descriptor instead.
+func (*ChallengeBundleObject) Descriptor() ([]byte, []int) {
+	return file_questhub_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *ChallengeBundleObject) GetQuestDefinition() string {
+	if x != nil {
+		return x.QuestDefinition
+	}
+	return ""
+}
+
+func (x *ChallengeBundleObject) GetRarity() string {
+	if x != nil {
+		return x.Rarity
+	}
+	return ""
+}
+
+func (x *ChallengeBundleObject) GetRewards() []*Reward {
+	if x != nil {
+		return x.Rewards
+	}
+	return nil
+}
+
+func (x *ChallengeBundleObject) GetObjectives() []*Objective {
+	if x != nil {
+		return x.Objectives
+	}
+	return nil
+}
+
+func (x *ChallengeBundleObject) GetOptions() *ChallengeBundleOptions {
+	if x != nil {
+		return x.Options
+	}
+	return nil
+}
+
+type ChallengeBundleOptions struct {
+	state                         protoimpl.MessageState `protogen:"open.v1"`
+	IsBattlePass                  bool                   `protobuf:"varint,1,opt,name=is_battle_pass,json=isBattlePass,proto3" json:"is_battle_pass,omitempty"`
+	IsOvertime                    bool                   `protobuf:"varint,2,opt,name=is_overtime,json=isOvertime,proto3" json:"is_overtime,omitempty"`
+	GrantWithPass                 bool                   `protobuf:"varint,3,opt,name=grant_with_pass,json=grantWithPass,proto3" json:"grant_with_pass,omitempty"`
+	ProgressOnBattlePassPurchased bool                   `protobuf:"varint,4,opt,name=progress_on_battle_pass_purchased,json=progressOnBattlePassPurchased,proto3" json:"progress_on_battle_pass_purchased,omitempty"`
+	AthenaSeasonProgress          bool                   `protobuf:"varint,5,opt,name=athena_season_progress,json=athenaSeasonProgress,proto3" json:"athena_season_progress,omitempty"`
+	BattlePassProgress            bool                   `protobuf:"varint,6,opt,name=battle_pass_progress,json=battlePassProgress,proto3" json:"battle_pass_progress,omitempty"`
+	GainAthenaSeasonXp            bool                   `protobuf:"varint,7,opt,name=gain_athena_season_xp,json=gainAthenaSeasonXp,proto3" json:"gain_athena_season_xp,omitempty"`
+	unknownFields                 protoimpl.UnknownFields
+	sizeCache                     protoimpl.SizeCache
+}
+
+func (x *ChallengeBundleOptions) Reset() {
+	*x = ChallengeBundleOptions{}
+	mi := &file_questhub_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChallengeBundleOptions) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChallengeBundleOptions) ProtoMessage() {}
+
+func (x *ChallengeBundleOptions) ProtoReflect() protoreflect.Message {
+	mi := &file_questhub_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChallengeBundleOptions.ProtoReflect.Descriptor instead.
+func (*ChallengeBundleOptions) Descriptor() ([]byte, []int) {
+	return file_questhub_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *ChallengeBundleOptions) GetIsBattlePass() bool {
+	if x != nil {
+		return x.IsBattlePass
+	}
+	return false
+}
+
+func (x *ChallengeBundleOptions) GetIsOvertime() bool {
+	if x != nil {
+		return x.IsOvertime
+	}
+	return false
+}
+
+func (x *ChallengeBundleOptions) GetGrantWithPass() bool {
+	if x != nil {
+		return x.GrantWithPass
+	}
+	return false
+}
+
+func (x *ChallengeBundleOptions) GetProgressOnBattlePassPurchased() bool {
+	if x != nil {
+		return x.ProgressOnBattlePassPurchased
+	}
+	return false
+}
+
+func (x *ChallengeBundleOptions) GetAthenaSeasonProgress() bool {
+	if x != nil {
+		return x.AthenaSeasonProgress
+	}
+	return false
+}
+
+func (x *ChallengeBundleOptions) GetBattlePassProgress() bool {
+	if x != nil {
+		return x.BattlePassProgress
+	}
+	return false
+}
+
+func (x *ChallengeBundleOptions) GetGainAthenaSeasonXp() bool {
+	if x != nil {
+		return x.GainAthenaSeasonXp
+	}
+	return false
+}
+
+type ListChallengeBundleSchedulesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListChallengeBundleSchedulesRequest) Reset() {
+	*x = ListChallengeBundleSchedulesRequest{}
+	mi := &file_questhub_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListChallengeBundleSchedulesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListChallengeBundleSchedulesRequest) ProtoMessage() {}
+
+func (x *ListChallengeBundleSchedulesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_questhub_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListChallengeBundleSchedulesRequest.ProtoReflect.Descriptor instead.
+func (*ListChallengeBundleSchedulesRequest) Descriptor() ([]byte, []int) {
+	return file_questhub_proto_rawDescGZIP(), []int{14}
+}
+
+type ListChallengeBundleSchedulesResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// sorted by template_id
+	Schedules     []*ChallengeBundleSchedule `protobuf:"bytes,1,rep,name=schedules,proto3" json:"schedules,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListChallengeBundleSchedulesResponse) Reset() {
+	*x = ListChallengeBundleSchedulesResponse{}
+	mi := &file_questhub_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListChallengeBundleSchedulesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListChallengeBundleSchedulesResponse) ProtoMessage() {}
+
+func (x *ListChallengeBundleSchedulesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_questhub_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListChallengeBundleSchedulesResponse.ProtoReflect.Descriptor instead.
+func (*ListChallengeBundleSchedulesResponse) Descriptor() ([]byte, []int) {
+	return file_questhub_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *ListChallengeBundleSchedulesResponse) GetSchedules() []*ChallengeBundleSchedule {
+	if x != nil {
+		return x.Schedules
+	}
+	return nil
+}
+
+type ChallengeBundleSchedule struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	TemplateId  string                 `protobuf:"bytes,1,opt,name=template_id,json=templateId,proto3" json:"template_id,omitempty"`
+	QuestBundle string                 `protobuf:"bytes,2,opt,name=quest_bundle,json=questBundle,proto3" json:"quest_bundle,omitempty"`
+	// unset leaves that end of the window open
+	ActiveFrom  *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=active_from,json=activeFrom,proto3" json:"active_from,omitempty"`
+	ActiveUntil *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=active_until,json=activeUntil,proto3" json:"active_until,omitempty"`
+	// "public" or "hidden"; empty from hubs that predate visibility
+	Visibility    string `protobuf:"bytes,5,opt,name=visibility,proto3" json:"visibility,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ChallengeBundleSchedule) Reset() {
+	*x = ChallengeBundleSchedule{}
+	mi := &file_questhub_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChallengeBundleSchedule) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChallengeBundleSchedule) ProtoMessage() {}
+
+func (x *ChallengeBundleSchedule) ProtoReflect() protoreflect.Message {
+	mi := &file_questhub_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChallengeBundleSchedule.ProtoReflect.Descriptor instead.
+func (*ChallengeBundleSchedule) Descriptor() ([]byte, []int) {
+	return file_questhub_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *ChallengeBundleSchedule) GetTemplateId() string {
+	if x != nil {
+		return x.TemplateId
+	}
+	return ""
+}
+
+func (x *ChallengeBundleSchedule) GetQuestBundle() string {
+	if x != nil {
+		return x.QuestBundle
+	}
+	return ""
+}
+
+func (x *ChallengeBundleSchedule) GetActiveFrom() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ActiveFrom
+	}
+	return nil
+}
+
+func (x *ChallengeBundleSchedule) GetActiveUntil() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ActiveUntil
+	}
+	return nil
+}
+
+func (x *ChallengeBundleSchedule) GetVisibility() string {
+	if x != nil {
+		return x.Visibility
+	}
+	return ""
+}
+
+var File_questhub_proto protoreflect.FileDescriptor
+
+const file_questhub_proto_rawDesc = "" +
+	"\n" +
+	"\x0equesthub.proto\x12\vquesthub.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"\x19\n" +
+	"\x17GetServiceStatusRequest\"\x85\x01\n" +
+	"\rServiceStatus\x12 \n" +
+	"\vinitialized\x18\x01 \x01(\bR\vinitialized\x12\x18\n" +
+	"\aversion\x18\x02 \x01(\tR\aversion\x128\n" +
+	"\ttimestamp\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\"\x18\n" +
+	"\x16ListDailyQuestsRequest\"J\n" +
+	"\x17ListDailyQuestsResponse\x12/\n" +
+	"\x06quests\x18\x01 \x03(\v2\x17.questhub.v1.DailyQuestR\x06quests\"&\n" +
+	"\x14GetDailyQuestRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"\x99\x01\n" +
+	"\n" +
+	"DailyQuest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x126\n" +
+	"\n" +
+	"objectives\x18\x02 \x03(\v2\x16.questhub.v1.ObjectiveR\n" +
+	"objectives\x12-\n" +
+	"\arewards\x18\x03 \x03(\v2\x13.questhub.v1.RewardR\arewards\x12\x14\n" +
+	"\x05count\x18\x04 \x01(\x05R\x05count\"Z\n" +
+	"\tObjective\x12!\n" +
+	"\fbackend_name\x18\x01 \x01(\tR\vbackendName\x12\x14\n" +
+	"\x05count\x18\x02 \x01(\x05R\x05count\x12\x14\n" +
+	"\x05stage\x18\x03 \x01(\x05R\x05stage\"E\n" +
+	"\x06Reward\x12\x1f\n" +
+	"\vtemplate_id\x18\x01 \x01(\tR\n" +
+	"templateId\x12\x1a\n" +
+	"\bquantity\x18\x02 \x01(\x05R\bquantity\"Y\n" +
+	"\x1bListChallengeBundlesRequest\x12\x1b\n" +
+	"\tpage_size\x18\x01 \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x02 \x01(\tR\tpageToken\"\x9d\x01\n" +
+	"\x1cListChallengeBundlesResponse\x126\n" +
+	"\abundles\x18\x01 \x03(\v2\x1c.questhub.v1.ChallengeBundleR\abundles\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\x12\x1d\n" +
+	"\n" +
+	"total_size\x18\x03 \x01(\x05R\ttotalSize\"<\n" +
+	"\x19GetChallengeBundleRequest\x12\x1f\n" +
+	"\vtemplate_id\x18\x01 \x01(\tR\n" +
+	"templateId\"\xa0\x02\n" +
+	"\x0fChallengeBundle\x12\x1f\n" +
+	"\vtemplate_id\x18\x01 \x01(\tR\n" +
+	"templateId\x12:\n" +
+	"\x19challenge_bundle_schedule\x18\x02 \x01(\tR\x17challengeBundleSchedule\x12<\n" +
+	"\aobjects\x18\x03 \x03(\v2\".questhub.v1.ChallengeBundleObjectR\aobjects\x12\x16\n" +
+	"\x06amount\x18\x04 \x01(\x05R\x06amount\x12\x16\n" +
+	"\x06rarity\x18\x05 \x01(\tR\x06rarity\x12B\n" +
+	"\x12completion_rewards\x18\x06 \x03(\v2\x13.questhub.v1.RewardR\x11completionRewards\"\x80\x02\n" +
+	"\x15ChallengeBundleObject\x12)\n" +
+	"\x10quest_definition\x18\x01 \x01(\tR\x0fquestDefinition\x12\x16\n" +
+	"\x06rarity\x18\x02 \x01(\tR\x06rarity\x12-\n" +
+	"\arewards\x18\x03 \x03(\v2\x13.questhub.v1.RewardR\arewards\x126\n" +
+	"\n" +
+	"objectives\x18\x04 \x03(\v2\x16.questhub.v1.ObjectiveR\n" +
+	"objectives\x12=\n" +
+	"\aoptions\x18\x05 \x01(\v2#.questhub.v1.ChallengeBundleOptionsR\aoptions\"\xec\x02\n" +
+	"\x16ChallengeBundleOptions\x12$\n" +
+	"\x0eis_battle_pass\x18\x01 \x01(\bR\fisBattlePass\x12\x1f\n" +
+	"\vis_overtime\x18\x02 \x01(\bR\n" +
+	"isOvertime\x12&\n" +
+	"\x0fgrant_with_pass\x18\x03 \x01(\bR\rgrantWithPass\x12H\n" +
+	"!progress_on_battle_pass_purchased\x18\x04 \x01(\bR\x1dprogressOnBattlePassPurchased\x124\n" +
+	"\x16athena_season_progress\x18\x05 \x01(\bR\x14athenaSeasonProgress\x120\n" +
+	"\x14battle_pass_progress\x18\x06 \x01(\bR\x12battlePassProgress\x121\n" +
+	"\x15gain_athena_season_xp\x18\a \x01(\bR\x12gainAthenaSeasonXp\"%\n" +
+	"#ListChallengeBundleSchedulesRequest\"j\n" +
+	"$ListChallengeBundleSchedulesResponse\x12B\n" +
+	"\tschedules\x18\x01 \x03(\v2$.questhub.v1.ChallengeBundleScheduleR\tschedules\"\xf9\x01\n" +
+	"\x17ChallengeBundleSchedule\x12\x1f\n" +
+	"\vtemplate_id\x18\x01 \x01(\tR\n" +
+	"templateId\x12!\n" +
+	"\fquest_bundle\x18\x02 \x01(\tR\vquestBundle\x12;\n" +
+	"\vactive_from\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"activeFrom\x12=\n" +
+	"\factive_until\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\vactiveUntil\x12\x1e\n" +
+	"\n" +
+	"visibility\x18\x05 \x01(\tR\n" +
+	"visibility2\xe1\x04\n" +
+	"\x0fQuestHubService\x12T\n" +
+	"\x10GetServiceStatus\x12$.questhub.v1.GetServiceStatusRequest\x1a\x1a.questhub.v1.ServiceStatus\x12\\\n" +
+	"\x0fListDailyQuests\x12#.questhub.v1.ListDailyQuestsRequest\x1a$.questhub.v1.ListDailyQuestsResponse\x12K\n" +
+	"\rGetDailyQuest\x12!.questhub.v1.GetDailyQuestRequest\x1a\x17.questhub.v1.DailyQuest\x12k\n" +
+	"\x14ListChallengeBundles\x12(.questhub.v1.ListChallengeBundlesRequest\x1a).questhub.v1.ListChallengeBundlesResponse\x12Z\n" +
+	"\x12GetChallengeBundle\x12&.questhub.v1.GetChallengeBundleRequest\x1a\x1c.questhub.v1.ChallengeBundle\x12\x83\x01\n" +
+	"\x1cListChallengeBundleSchedules\x120.questhub.v1.ListChallengeBundleSchedulesRequest\x1a1.questhub.v1.ListChallengeBundleSchedulesResponseB5Z3github.com/ilyskies/QuestHub/pkg/gateway/questhubv1b\x06proto3"
+
+var (
+	file_questhub_proto_rawDescOnce sync.Once
+	file_questhub_proto_rawDescData []byte
+)
+
+func file_questhub_proto_rawDescGZIP() []byte {
+	file_questhub_proto_rawDescOnce.Do(func() {
+		file_questhub_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_questhub_proto_rawDesc), len(file_questhub_proto_rawDesc)))
+	})
+	return file_questhub_proto_rawDescData
+}
+
+var file_questhub_proto_msgTypes = make([]protoimpl.MessageInfo, 17)
+var file_questhub_proto_goTypes = []any{
+	(*GetServiceStatusRequest)(nil),              // 0: questhub.v1.GetServiceStatusRequest
+	(*ServiceStatus)(nil),                        // 1: questhub.v1.ServiceStatus
+	(*ListDailyQuestsRequest)(nil),               // 2: questhub.v1.ListDailyQuestsRequest
+	(*ListDailyQuestsResponse)(nil),              // 3: questhub.v1.ListDailyQuestsResponse
+	(*GetDailyQuestRequest)(nil),                 // 4: questhub.v1.GetDailyQuestRequest
+	(*DailyQuest)(nil),                           // 5: questhub.v1.DailyQuest
+	(*Objective)(nil),                            // 6: questhub.v1.Objective
+	(*Reward)(nil),                               // 7: questhub.v1.Reward
+	(*ListChallengeBundlesRequest)(nil),          // 8: questhub.v1.ListChallengeBundlesRequest
+	(*ListChallengeBundlesResponse)(nil),         // 9: questhub.v1.ListChallengeBundlesResponse
+	(*GetChallengeBundleRequest)(nil),            // 10: questhub.v1.GetChallengeBundleRequest
+	(*ChallengeBundle)(nil),                      // 11: questhub.v1.ChallengeBundle
+	(*ChallengeBundleObject)(nil),                // 12: questhub.v1.ChallengeBundleObject
+	(*ChallengeBundleOptions)(nil),               // 13: questhub.v1.ChallengeBundleOptions
+	(*ListChallengeBundleSchedulesRequest)(nil),  // 14: questhub.v1.ListChallengeBundleSchedulesRequest
+	(*ListChallengeBundleSchedulesResponse)(nil), // 15: questhub.v1.ListChallengeBundleSchedulesResponse
+	(*ChallengeBundleSchedule)(nil),              // 16: questhub.v1.ChallengeBundleSchedule
+	(*timestamppb.Timestamp)(nil),                // 17: google.protobuf.Timestamp
+}
+var file_questhub_proto_depIdxs = []int32{
+	17, // 0: questhub.v1.ServiceStatus.timestamp:type_name -> google.protobuf.Timestamp
+	5,  // 1: questhub.v1.ListDailyQuestsResponse.quests:type_name -> questhub.v1.DailyQuest
+	6,  // 2: questhub.v1.DailyQuest.objectives:type_name -> questhub.v1.Objective
+	7,  // 3: questhub.v1.DailyQuest.rewards:type_name -> questhub.v1.Reward
+	11, // 4: questhub.v1.ListChallengeBundlesResponse.bundles:type_name -> questhub.v1.ChallengeBundle
+	12, // 5: questhub.v1.ChallengeBundle.objects:type_name -> questhub.v1.ChallengeBundleObject
+	7,  // 6: questhub.v1.ChallengeBundle.completion_rewards:type_name -> questhub.v1.Reward
+	7,  // 7: questhub.v1.ChallengeBundleObject.rewards:type_name -> questhub.v1.Reward
+	6,  // 8: questhub.v1.ChallengeBundleObject.objectives:type_name -> questhub.v1.Objective
+	13, // 9: questhub.v1.ChallengeBundleObject.options:type_name -> questhub.v1.ChallengeBundleOptions
+	16, // 10: questhub.v1.ListChallengeBundleSchedulesResponse.schedules:type_name -> questhub.v1.ChallengeBundleSchedule
+	17, // 11: questhub.v1.ChallengeBundleSchedule.active_from:type_name -> google.protobuf.Timestamp
+	17, // 12: questhub.v1.ChallengeBundleSchedule.active_until:type_name -> google.protobuf.Timestamp
+	0,  // 13: questhub.v1.QuestHubService.GetServiceStatus:input_type -> questhub.v1.GetServiceStatusRequest
+	2,  // 14: questhub.v1.QuestHubService.ListDailyQuests:input_type -> questhub.v1.ListDailyQuestsRequest
+	4,  // 15: questhub.v1.QuestHubService.GetDailyQuest:input_type -> questhub.v1.GetDailyQuestRequest
+	8,  // 16: questhub.v1.QuestHubService.ListChallengeBundles:input_type -> questhub.v1.ListChallengeBundlesRequest
+	10, // 17: questhub.v1.QuestHubService.GetChallengeBundle:input_type -> questhub.v1.GetChallengeBundleRequest
+	14, // 18: questhub.v1.QuestHubService.ListChallengeBundleSchedules:input_type -> questhub.v1.ListChallengeBundleSchedulesRequest
+	1,  // 19: questhub.v1.QuestHubService.GetServiceStatus:output_type -> questhub.v1.ServiceStatus
+	3,  // 20: questhub.v1.QuestHubService.ListDailyQuests:output_type -> questhub.v1.ListDailyQuestsResponse
+	5,  // 21: questhub.v1.QuestHubService.GetDailyQuest:output_type -> questhub.v1.DailyQuest
+	9,  // 22: questhub.v1.QuestHubService.ListChallengeBundles:output_type -> questhub.v1.ListChallengeBundlesResponse
+	11, // 23: questhub.v1.QuestHubService.GetChallengeBundle:output_type -> questhub.v1.ChallengeBundle
+	15, // 24: questhub.v1.QuestHubService.ListChallengeBundleSchedules:output_type -> questhub.v1.ListChallengeBundleSchedulesResponse
+	19, // [19:25] is the sub-list for method output_type
+	13, // [13:19] is the sub-list for method input_type
+	13, // [13:13] is the sub-list for extension type_name
+	13, // [13:13] is the sub-list for extension extendee
+	0,  // [0:13] is the sub-list for field type_name
+}
+
+func init() { file_questhub_proto_init() }
+func file_questhub_proto_init() {
+	if File_questhub_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_questhub_proto_rawDesc), len(file_questhub_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   17,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_questhub_proto_goTypes,
+		DependencyIndexes: file_questhub_proto_depIdxs,
+		MessageInfos:      file_questhub_proto_msgTypes,
+	}.Build()
+	File_questhub_proto = out.File
+	file_questhub_proto_goTypes = nil
+	file_questhub_proto_depIdxs = nil
+}
@@ -0,0 +1,327 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: questhub.proto
+
+// The QuestHub data re-exposed for consumers that cannot speak SignalR.
+// cmd/questhub-gateway serves it over gRPC and, with the same messages in
+// their JSON form, over REST.
+
+package questhubv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	QuestHubService_GetServiceStatus_FullMethodName             = "/questhub.v1.QuestHubService/GetServiceStatus"
+	QuestHubService_ListDailyQuests_FullMethodName              = "/questhub.v1.QuestHubService/ListDailyQuests"
+	QuestHubService_GetDailyQuest_FullMethodName                = "/questhub.v1.QuestHubService/GetDailyQuest"
+	QuestHubService_ListChallengeBundles_FullMethodName         = "/questhub.v1.QuestHubService/ListChallengeBundles"
+	QuestHubService_GetChallengeBundle_FullMethodName           = "/questhub.v1.QuestHubService/GetChallengeBundle"
+	QuestHubService_ListChallengeBundleSchedules_FullMethodName = "/questhub.v1.QuestHubService/ListChallengeBundleSchedules"
+)
+
+// QuestHubServiceClient is the client API for QuestHubService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type QuestHubServiceClient interface {
+	// GET /v1/status
+	GetServiceStatus(ctx context.Context, in *GetServiceStatusRequest, opts ...grpc.CallOption) (*ServiceStatus, error)
+	// GET /v1/quests
+	ListDailyQuests(ctx context.Context, in *ListDailyQuestsRequest, opts ...grpc.CallOption) (*ListDailyQuestsResponse, error)
+	// GET /v1/quests/{id}
+	GetDailyQuest(ctx context.Context, in *GetDailyQuestRequest, opts ...grpc.CallOption) (*DailyQuest, error)
+	// GET /v1/bundles?page_size=&page_token=
+	ListChallengeBundles(ctx context.Context, in *ListChallengeBundlesRequest, opts ...grpc.CallOption) (*ListChallengeBundlesResponse, error)
+	// GET /v1/bundles/{template_id}
+	GetChallengeBundle(ctx context.Context, in *GetChallengeBundleRequest, opts ...grpc.CallOption) (*ChallengeBundle, error)
+	// GET /v1/schedules
+	ListChallengeBundleSchedules(ctx context.Context, in *ListChallengeBundleSchedulesRequest, opts ...grpc.CallOption) (*ListChallengeBundleSchedulesResponse, error)
+}
+
+type questHubServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewQuestHubServiceClient(cc grpc.ClientConnInterface) QuestHubServiceClient {
+	return &questHubServiceClient{cc}
+}
+
+func (c *questHubServiceClient) GetServiceStatus(ctx context.Context, in *GetServiceStatusRequest, opts ...grpc.CallOption) (*ServiceStatus, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ServiceStatus)
+	err := c.cc.Invoke(ctx, QuestHubService_GetServiceStatus_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *questHubServiceClient) ListDailyQuests(ctx context.Context, in *ListDailyQuestsRequest, opts ...grpc.CallOption) (*ListDailyQuestsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListDailyQuestsResponse)
+	err := c.cc.Invoke(ctx, QuestHubService_ListDailyQuests_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *questHubServiceClient) GetDailyQuest(ctx context.Context, in *GetDailyQuestRequest, opts ...grpc.CallOption) (*DailyQuest, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DailyQuest)
+	err := c.cc.Invoke(ctx, QuestHubService_GetDailyQuest_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *questHubServiceClient) ListChallengeBundles(ctx context.Context, in *ListChallengeBundlesRequest, opts ...grpc.CallOption) (*ListChallengeBundlesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListChallengeBundlesResponse)
+	err := c.cc.Invoke(ctx, QuestHubService_ListChallengeBundles_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *questHubServiceClient) GetChallengeBundle(ctx context.Context, in *GetChallengeBundleRequest, opts ...grpc.CallOption) (*ChallengeBundle, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ChallengeBundle)
+	err := c.cc.Invoke(ctx, QuestHubService_GetChallengeBundle_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *questHubServiceClient) ListChallengeBundleSchedules(ctx context.Context, in *ListChallengeBundleSchedulesRequest, opts ...grpc.CallOption) (*ListChallengeBundleSchedulesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListChallengeBundleSchedulesResponse)
+	err := c.cc.Invoke(ctx, QuestHubService_ListChallengeBundleSchedules_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// QuestHubServiceServer is the server API for QuestHubService service.
+// All implementations must embed UnimplementedQuestHubServiceServer
+// for forward compatibility.
+type QuestHubServiceServer interface {
+	// GET /v1/status
+	GetServiceStatus(context.Context, *GetServiceStatusRequest) (*ServiceStatus, error)
+	// GET /v1/quests
+	ListDailyQuests(context.Context, *ListDailyQuestsRequest) (*ListDailyQuestsResponse, error)
+	// GET /v1/quests/{id}
+	GetDailyQuest(context.Context, *GetDailyQuestRequest) (*DailyQuest, error)
+	// GET /v1/bundles?page_size=&page_token=
+	ListChallengeBundles(context.Context, *ListChallengeBundlesRequest) (*ListChallengeBundlesResponse, error)
+	// GET /v1/bundles/{template_id}
+	GetChallengeBundle(context.Context, *GetChallengeBundleRequest) (*ChallengeBundle, error)
+	// GET /v1/schedules
+	ListChallengeBundleSchedules(context.Context, *ListChallengeBundleSchedulesRequest) (*ListChallengeBundleSchedulesResponse, error)
+	mustEmbedUnimplementedQuestHubServiceServer()
+}
+
+// UnimplementedQuestHubServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedQuestHubServiceServer struct{}
+
+func (UnimplementedQuestHubServiceServer) GetServiceStatus(context.Context, *GetServiceStatusRequest) (*ServiceStatus, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetServiceStatus not implemented")
+}
+func (UnimplementedQuestHubServiceServer) ListDailyQuests(context.Context, *ListDailyQuestsRequest) (*ListDailyQuestsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListDailyQuests not implemented")
+}
+func (UnimplementedQuestHubServiceServer) GetDailyQuest(context.Context, *GetDailyQuestRequest) (*DailyQuest, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetDailyQuest not implemented")
+}
+func (UnimplementedQuestHubServiceServer) ListChallengeBundles(context.Context, *ListChallengeBundlesRequest) (*ListChallengeBundlesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListChallengeBundles not implemented")
+}
+func (UnimplementedQuestHubServiceServer) GetChallengeBundle(context.Context, *GetChallengeBundleRequest) (*ChallengeBundle, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetChallengeBundle not implemented")
+}
+func (UnimplementedQuestHubServiceServer) ListChallengeBundleSchedules(context.Context, *ListChallengeBundleSchedulesRequest) (*ListChallengeBundleSchedulesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListChallengeBundleSchedules not implemented")
+}
+func (UnimplementedQuestHubServiceServer) mustEmbedUnimplementedQuestHubServiceServer() {}
+func (UnimplementedQuestHubServiceServer) testEmbeddedByValue()                         {}
+
+// UnsafeQuestHubServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to QuestHubServiceServer will
+// result in compilation errors.
+type UnsafeQuestHubServiceServer interface {
+	mustEmbedUnimplementedQuestHubServiceServer()
+}
+
+func RegisterQuestHubServiceServer(s grpc.ServiceRegistrar, srv QuestHubServiceServer) {
+	// If the following call pancis, it indicates UnimplementedQuestHubServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&QuestHubService_ServiceDesc, srv)
+}
+
+func _QuestHubService_GetServiceStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetServiceStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(QuestHubServiceServer).GetServiceStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: QuestHubService_GetServiceStatus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QuestHubServiceServer).GetServiceStatus(ctx, req.(*GetServiceStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _QuestHubService_ListDailyQuests_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListDailyQuestsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(QuestHubServiceServer).ListDailyQuests(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: QuestHubService_ListDailyQuests_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QuestHubServiceServer).ListDailyQuests(ctx, req.(*ListDailyQuestsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _QuestHubService_GetDailyQuest_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetDailyQuestRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(QuestHubServiceServer).GetDailyQuest(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: QuestHubService_GetDailyQuest_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QuestHubServiceServer).GetDailyQuest(ctx, req.(*GetDailyQuestRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _QuestHubService_ListChallengeBundles_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListChallengeBundlesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(QuestHubServiceServer).ListChallengeBundles(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: QuestHubService_ListChallengeBundles_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QuestHubServiceServer).ListChallengeBundles(ctx, req.(*ListChallengeBundlesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _QuestHubService_GetChallengeBundle_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetChallengeBundleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(QuestHubServiceServer).GetChallengeBundle(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: QuestHubService_GetChallengeBundle_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QuestHubServiceServer).GetChallengeBundle(ctx, req.(*GetChallengeBundleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _QuestHubService_ListChallengeBundleSchedules_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListChallengeBundleSchedulesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(QuestHubServiceServer).ListChallengeBundleSchedules(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: QuestHubService_ListChallengeBundleSchedules_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QuestHubServiceServer).ListChallengeBundleSchedules(ctx, req.(*ListChallengeBundleSchedulesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// QuestHubService_ServiceDesc is the grpc.ServiceDesc for QuestHubService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var QuestHubService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "questhub.v1.QuestHubService",
+	HandlerType: (*QuestHubServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetServiceStatus",
+			Handler:    _QuestHubService_GetServiceStatus_Handler,
+		},
+		{
+			MethodName: "ListDailyQuests",
+			Handler:    _QuestHubService_ListDailyQuests_Handler,
+		},
+		{
+			MethodName: "GetDailyQuest",
+			Handler:    _QuestHubService_GetDailyQuest_Handler,
+		},
+		{
+			MethodName: "ListChallengeBundles",
+			Handler:    _QuestHubService_ListChallengeBundles_Handler,
+		},
+		{
+			MethodName: "GetChallengeBundle",
+			Handler:    _QuestHubService_GetChallengeBundle_Handler,
+		},
+		{
+			MethodName: "ListChallengeBundleSchedules",
+			Handler:    _QuestHubService_ListChallengeBundleSchedules_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "questhub.proto",
+}
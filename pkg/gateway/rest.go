@@ -0,0 +1,110 @@
+package gateway
+
+import (
+	"net/http"
+	"strconv"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/ilyskies/QuestHub/pkg/gateway/questhubv1"
+)
+
+// Handler serves the same calls as REST under /v1, with the protobuf
+// messages in their JSON form. Errors are a google.rpc.Status as JSON, the
+// shape grpc-gateway uses, with the matching HTTP status.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("GET /v1/status", func(w http.ResponseWriter, r *http.Request) {
+		writeProto(w, r)(s.GetServiceStatus(r.Context(), &questhubv1.GetServiceStatusRequest{}))
+	})
+	mux.HandleFunc("GET /v1/quests", func(w http.ResponseWriter, r *http.Request) {
+		writeProto(w, r)(s.ListDailyQuests(r.Context(), &questhubv1.ListDailyQuestsRequest{}))
+	})
+	mux.HandleFunc("GET /v1/quests/{id}", func(w http.ResponseWriter, r *http.Request) {
+		writeProto(w, r)(s.GetDailyQuest(r.Context(), &questhubv1.GetDailyQuestRequest{Id: r.PathValue("id")}))
+	})
+	mux.HandleFunc("GET /v1/bundles", func(w http.ResponseWriter, r *http.Request) {
+		req := &questhubv1.ListChallengeBundlesRequest{PageToken: r.URL.Query().Get("page_token")}
+		if v := r.URL.Query().Get("page_size"); v != "" {
+			n, err := strconv.ParseInt(v, 10, 32)
+			if err != nil {
+				writeError(w, status.Errorf(codes.InvalidArgument, "invalid page_size %q", v))
+				return
+			}
+			req.PageSize = int32(n)
+		}
+		writeProto(w, r)(s.ListChallengeBundles(r.Context(), req))
+	})
+	mux.HandleFunc("GET /v1/bundles/{template_id}", func(w http.ResponseWriter, r *http.Request) {
+		writeProto(w, r)(s.GetChallengeBundle(r.Context(), &questhubv1.GetChallengeBundleRequest{TemplateId: r.PathValue("template_id")}))
+	})
+	mux.HandleFunc("GET /v1/schedules", func(w http.ResponseWriter, r *http.Request) {
+		writeProto(w, r)(s.ListChallengeBundleSchedules(r.Context(), &questhubv1.ListChallengeBundleSchedulesRequest{}))
+	})
+
+	return mux
+}
+
+// ?pretty indents the output for people reading it with curl
+func writeProto(w http.ResponseWriter, r *http.Request) func(proto.Message, error) {
+	return func(m proto.Message, err error) {
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		opts := protojson.MarshalOptions{}
+		if _, ok := r.URL.Query()["pretty"]; ok {
+			opts.Multiline = true
+		}
+		data, err := opts.Marshal(m)
+		if err != nil {
+			writeError(w, status.Error(codes.Internal, err.Error()))
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(data)
+	}
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	st := status.Convert(err)
+	data, _ := protojson.Marshal(st.Proto())
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(httpStatus(st.Code()))
+	_, _ = w.Write(data)
+}
+
+func httpStatus(c codes.Code) int {
+	switch c {
+	case codes.OK:
+		return http.StatusOK
+	case codes.InvalidArgument, codes.OutOfRange, codes.FailedPrecondition:
+		return http.StatusBadRequest
+	case codes.NotFound:
+		return http.StatusNotFound
+	case codes.Unimplemented:
+		return http.StatusNotImplemented
+	case codes.Unavailable:
+		return http.StatusServiceUnavailable
+	case codes.DeadlineExceeded:
+		return http.StatusGatewayTimeout
+	case codes.Canceled:
+		// nginx's "client closed request"
+		return 499
+	case codes.PermissionDenied:
+		return http.StatusForbidden
+	case codes.Unauthenticated:
+		return http.StatusUnauthorized
+	case codes.ResourceExhausted:
+		return http.StatusTooManyRequests
+	default:
+		return http.StatusInternalServerError
+	}
+}
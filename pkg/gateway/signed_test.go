@@ -0,0 +1,93 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ilyskies/QuestHub/pkg/export"
+	"github.com/ilyskies/QuestHub/pkg/hubtest"
+)
+
+func TestSignedExports(t *testing.T) {
+	hs := hubtest.NewServer(hubtest.DefaultFixtures())
+	defer hs.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	client, err := hs.NewClient(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Disconnect()
+
+	if _, err := NewSigner([]byte("short")); err == nil {
+		t.Error("NewSigner accepted a short key")
+	}
+	signer, err := NewSigner([]byte(strings.Repeat("k", MinSigningKeySize)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	signer.now = func() time.Time { return now }
+
+	srv := httptest.NewServer(signer.Protect(New(client).Exports()))
+	defer srv.Close()
+	get := func(link string) *http.Response {
+		resp, err := http.Get(link)
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { resp.Body.Close() })
+		return resp
+	}
+
+	link, err := signer.Sign(srv.URL+"/exports/latest.json", time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp := get(link)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("signed link: got %d", resp.StatusCode)
+	}
+	var snap export.Snapshot
+	if err := json.NewDecoder(resp.Body).Decode(&snap); err != nil {
+		t.Fatal(err)
+	}
+	if snap.FormatVersion != export.FormatVersion || len(snap.DailyQuests) != 1 || len(snap.Bundles) != 1 {
+		t.Errorf("export = %+v", snap)
+	}
+
+	if resp := get(srv.URL + "/exports/latest.json"); resp.StatusCode != http.StatusForbidden {
+		t.Errorf("unsigned: got %d, want 403", resp.StatusCode)
+	}
+
+	u, _ := url.Parse(link)
+	q := u.Query()
+	q.Set(ParamExpires, "9999999999")
+	u.RawQuery = q.Encode()
+	if err := signer.Verify(u); !errors.Is(err, ErrBadSignature) {
+		t.Errorf("extended expiry: err = %v, want ErrBadSignature", err)
+	}
+	u, _ = url.Parse(link)
+	u.Path = "/exports/other.json"
+	if err := signer.Verify(u); !errors.Is(err, ErrBadSignature) {
+		t.Errorf("other path: err = %v, want ErrBadSignature", err)
+	}
+
+	now = now.Add(time.Hour)
+	if resp := get(link); resp.StatusCode != http.StatusForbidden {
+		t.Errorf("expired: got %d, want 403", resp.StatusCode)
+	}
+	u, _ = url.Parse(link)
+	if err := signer.Verify(u); !errors.Is(err, ErrURLExpired) {
+		t.Errorf("expired: err = %v, want ErrURLExpired", err)
+	}
+}
@@ -0,0 +1,103 @@
+package hub
+
+import (
+	"context"
+	"sync"
+)
+
+type BatchResult struct {
+	Status      *ServiceStatus
+	DailyQuests map[string]BaseQuest
+	Bundles     []AthenaChallengeBundle
+	Schedules   []ChallengeBundleSchedule
+}
+
+// queued calls run concurrently over the shared connection
+type Batch struct {
+	client *Client
+	ctx    context.Context
+	calls  []func(ctx context.Context, out *BatchResult) error
+}
+
+func (c *Client) Batch(ctx context.Context) *Batch {
+	return &Batch{client: c, ctx: ctx}
+}
+
+func (b *Batch) GetServiceStatus() *Batch {
+	b.calls = append(b.calls, func(ctx context.Context, out *BatchResult) error {
+		status, err := b.client.GetServiceStatus(ctx)
+		out.Status = status
+		return err
+	})
+	return b
+}
+
+func (b *Batch) GetDailyQuests() *Batch {
+	b.calls = append(b.calls, func(ctx context.Context, out *BatchResult) error {
+		quests, err := b.client.GetDailyQuests(ctx)
+		out.DailyQuests = quests
+		return err
+	})
+	return b
+}
+
+func (b *Batch) GetChallengeBundles() *Batch {
+	b.calls = append(b.calls, func(ctx context.Context, out *BatchResult) error {
+		bundles, err := b.client.GetChallengeBundles(ctx)
+		out.Bundles = bundles
+		return err
+	})
+	return b
+}
+
+func (b *Batch) GetChallengeBundleSchedules() *Batch {
+	b.calls = append(b.calls, func(ctx context.Context, out *BatchResult) error {
+		schedules, err := b.client.GetChallengeBundleSchedules(ctx)
+		out.Schedules = schedules
+		return err
+	})
+	return b
+}
+
+// Run returns whatever succeeded alongside a *MultiError for the calls that failed
+func (b *Batch) Run() (*BatchResult, error) {
+	var (
+		out  BatchResult
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		errs MultiError
+	)
+
+	for _, call := range b.calls {
+		wg.Add(1)
+		go func(call func(context.Context, *BatchResult) error) {
+			defer wg.Done()
+
+			var partial BatchResult
+			err := call(b.ctx, &partial)
+
+			mu.Lock()
+			defer mu.Unlock()
+			errs.Add(err)
+			mergeBatchResult(&out, &partial)
+		}(call)
+	}
+
+	wg.Wait()
+	return &out, errs.ErrOrNil()
+}
+
+func mergeBatchResult(dst, src *BatchResult) {
+	if src.Status != nil {
+		dst.Status = src.Status
+	}
+	if src.DailyQuests != nil {
+		dst.DailyQuests = src.DailyQuests
+	}
+	if src.Bundles != nil {
+		dst.Bundles = src.Bundles
+	}
+	if src.Schedules != nil {
+		dst.Schedules = src.Schedules
+	}
+}
@@ -0,0 +1,156 @@
+package hub
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+type CircuitState int
+
+const (
+	CircuitClosed CircuitState = iota
+	// calls fail fast with ErrCircuitOpen until the cooldown has passed
+	CircuitOpen
+	// one probe call is let through; its outcome closes or reopens the circuit
+	CircuitHalfOpen
+)
+
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+type circuitBreaker struct {
+	threshold int
+	cooldown  time.Duration
+
+	mu       sync.Mutex
+	state    CircuitState
+	failures int
+	openedAt time.Time
+	probing  bool
+}
+
+// allow admits a call, or fails it while the circuit is open. probe is true
+// for the single call let through a half-open circuit.
+func (b *circuitBreaker) allow() (probe bool, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case CircuitOpen:
+		if time.Since(b.openedAt) < b.cooldown {
+			return false, ErrCircuitOpen
+		}
+		b.state = CircuitHalfOpen
+		fallthrough
+	case CircuitHalfOpen:
+		if b.probing {
+			return false, ErrCircuitOpen
+		}
+		b.probing = true
+		return true, nil
+	}
+	return false, nil
+}
+
+// record reports how an admitted call ended and returns the state before
+// and after it
+func (b *circuitBreaker) record(probe bool, err error) (prev, next CircuitState) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	prev = b.state
+	if probe {
+		b.probing = false
+	}
+
+	switch {
+	case breakerFailure(err):
+		b.failures++
+		if b.state == CircuitHalfOpen || b.failures >= b.threshold {
+			b.state = CircuitOpen
+			b.openedAt = time.Now()
+		}
+	case breakerSuccess(err):
+		b.failures = 0
+		b.state = CircuitClosed
+	default:
+		// the caller gave up or the client failed the call itself, which
+		// says nothing about the hub; a half-open circuit probes again
+	}
+	return prev, b.state
+}
+
+// breakerFailure reports whether err says the hub is degraded, as opposed to
+// a bad request or the caller giving up
+func breakerFailure(err error) bool {
+	switch {
+	case err == nil, errors.Is(err, context.Canceled), errors.Is(err, ErrBudgetExhausted):
+		return false
+	case errors.Is(err, ErrConnectionTimeout), errors.Is(err, ErrConnectionLost):
+		return true
+	case breakerSuccess(err):
+		return false
+	default:
+		return errors.Is(err, ErrInvokeFailed)
+	}
+}
+
+// breakerSuccess reports whether the hub answered: with a result, or with an
+// error about the request rather than itself
+func breakerSuccess(err error) bool {
+	return err == nil ||
+		errors.Is(err, ErrQuestNotFound) || errors.Is(err, ErrBundleNotFound) ||
+		errors.Is(err, ErrInvalidQuestID) || errors.Is(err, ErrInvalidTemplateID) ||
+		errors.Is(err, ErrMethodNotFound)
+}
+
+// admitCall passes a call that is about to be sent through the breaker, if
+// any. answered must then be called once with how the hub answered, so the
+// client's own queueing and limits never count against the hub.
+func (c *Client) admitCall(method string) (answered func(error), err error) {
+	if c.breaker == nil {
+		return func(error) {}, nil
+	}
+	probe, err := c.breaker.allow()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", err, method)
+	}
+	return func(err error) { c.recordCall(probe, method, err) }, nil
+}
+
+// CircuitState reports the state of the breaker set by WithCircuitBreaker;
+// always CircuitClosed without one
+func (c *Client) CircuitState() CircuitState {
+	if c.breaker == nil {
+		return CircuitClosed
+	}
+	c.breaker.mu.Lock()
+	defer c.breaker.mu.Unlock()
+	return c.breaker.state
+}
+
+func (c *Client) recordCall(probe bool, method string, err error) {
+	prev, next := c.breaker.record(probe, err)
+	if prev == next {
+		return
+	}
+	switch next {
+	case CircuitOpen:
+		c.logger.Warn("Circuit opened after %s failed: %v", method, err)
+	case CircuitClosed:
+		c.logger.Info("Circuit closed after %s succeeded", method)
+	}
+}
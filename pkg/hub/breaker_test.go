@@ -0,0 +1,75 @@
+package hub
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	b := &circuitBreaker{threshold: 2, cooldown: 50 * time.Millisecond}
+	call := func(err error) CircuitState {
+		t.Helper()
+		probe, aerr := b.allow()
+		if aerr != nil {
+			t.Fatalf("allow: %v", aerr)
+		}
+		_, next := b.record(probe, err)
+		return next
+	}
+
+	if s := call(fmt.Errorf("%w: GetDailyQuests", ErrConnectionTimeout)); s != CircuitClosed {
+		t.Fatalf("after one failure: %s", s)
+	}
+	// a caller giving up and a not-found answer do not count
+	if s := call(context.Canceled); s != CircuitClosed {
+		t.Fatalf("after cancel: %s", s)
+	}
+	if s := call(ErrConnectionLost); s != CircuitOpen {
+		t.Fatalf("after threshold: %s, want open", s)
+	}
+	if _, err := b.allow(); err != ErrCircuitOpen {
+		t.Fatalf("open circuit admitted a call: %v", err)
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	probe, err := b.allow()
+	if err != nil || !probe {
+		t.Fatalf("after cooldown: probe %v, err %v", probe, err)
+	}
+	// only one probe at a time
+	if _, err := b.allow(); err != ErrCircuitOpen {
+		t.Errorf("second call while probing: %v", err)
+	}
+	if _, s := b.record(true, ErrInvokeFailed); s != CircuitOpen {
+		t.Fatalf("failed probe: %s, want open", s)
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	probe, _ = b.allow()
+	if _, s := b.record(probe, ErrQuestNotFound); s != CircuitClosed {
+		t.Errorf("probe answered not found: %s, want closed", s)
+	}
+}
+
+func TestBreakerFailure(t *testing.T) {
+	cases := []struct {
+		err     error
+		failure bool
+	}{
+		{nil, false},
+		{context.Canceled, false},
+		{ErrBudgetExhausted, false},
+		{ErrConnectionTimeout, true},
+		{ErrConnectionLost, true},
+		{ErrInvokeFailed, true},
+		{&HubError{Method: "GetDailyQuest", Message: "not found", sentinel: ErrQuestNotFound}, false},
+		{ErrRateLimited, false},
+	}
+	for _, tc := range cases {
+		if got := breakerFailure(tc.err); got != tc.failure {
+			t.Errorf("breakerFailure(%v) = %v, want %v", tc.err, got, tc.failure)
+		}
+	}
+}
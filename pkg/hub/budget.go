@@ -0,0 +1,67 @@
+package hub
+
+import (
+	"context"
+	"time"
+)
+
+type budgetKey struct{}
+
+type latencyBudget struct {
+	total    time.Duration
+	deadline time.Time
+}
+
+// WithLatencyBudget gives every call made with ctx a shared total latency
+// budget covering cache lookups, the invoke itself and any retries. A nested
+// budget can only shrink the one it is derived from.
+func WithLatencyBudget(ctx context.Context, total time.Duration) context.Context {
+	b := &latencyBudget{total: total, deadline: time.Now().Add(total)}
+	if parent, ok := ctx.Value(budgetKey{}).(*latencyBudget); ok && parent.deadline.Before(b.deadline) {
+		return ctx
+	}
+	return context.WithValue(ctx, budgetKey{}, b)
+}
+
+// BudgetRemaining reports how much of the latency budget is left; ok is false
+// when ctx carries no budget
+func BudgetRemaining(ctx context.Context) (remaining time.Duration, ok bool) {
+	b, ok := ctx.Value(budgetKey{}).(*latencyBudget)
+	if !ok {
+		return 0, false
+	}
+	return b.remaining(), true
+}
+
+func (b *latencyBudget) remaining() time.Duration {
+	return max(time.Until(b.deadline), 0)
+}
+
+// bounds ctx by the budget deadline; expiry is reported as ErrBudgetExhausted
+// through context.Cause
+func withBudgetDeadline(ctx context.Context) (context.Context, context.CancelFunc, error) {
+	b, ok := ctx.Value(budgetKey{}).(*latencyBudget)
+	if !ok {
+		return ctx, func() {}, nil
+	}
+	if b.remaining() <= 0 {
+		return ctx, func() {}, ErrBudgetExhausted
+	}
+
+	ctx, cancel := context.WithDeadlineCause(ctx, b.deadline, ErrBudgetExhausted)
+	return ctx, cancel, nil
+}
+
+// a retry only fits when the backoff plus another attempt as slow as the last
+// one still ends inside the budget
+func retryFitsBudget(ctx context.Context, delay, lastAttempt time.Duration) bool {
+	remaining, ok := BudgetRemaining(ctx)
+	return !ok || delay+lastAttempt < remaining
+}
+
+func timeoutKind(ctx context.Context) error {
+	if context.Cause(ctx) == ErrBudgetExhausted {
+		return ErrBudgetExhausted
+	}
+	return ErrConnectionTimeout
+}
@@ -0,0 +1,139 @@
+package hub
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// in-process fan-out; each subscriber gets its own buffered channel and a
+// full buffer drops the event for that subscriber only
+type Bus[T any] struct {
+	mu      sync.RWMutex
+	subs    map[uint64]subscriber[T]
+	nextID  uint64
+	buffer  int
+	closed  bool
+	dropped atomic.Uint64
+}
+
+type subscriber[T any] struct {
+	ch   chan T
+	keep func(T) bool
+	// set instead of ch for Handle
+	fn func(T)
+}
+
+func NewBus[T any](buffer int) *Bus[T] {
+	if buffer < 1 {
+		buffer = 1
+	}
+	return &Bus[T]{
+		subs:   make(map[uint64]subscriber[T]),
+		buffer: buffer,
+	}
+}
+
+func (b *Bus[T]) Subscribe() (<-chan T, func()) {
+	return b.SubscribeFunc(nil)
+}
+
+// SubscribeFunc subscribes to the values keep returns true for; the others
+// take no room in the buffer. A nil keep takes everything.
+func (b *Bus[T]) SubscribeFunc(keep func(T) bool) (<-chan T, func()) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	ch := make(chan T, b.buffer)
+	if b.closed {
+		close(ch)
+		return ch, func() {}
+	}
+
+	return ch, b.addLocked(subscriber[T]{ch: ch, keep: keep})
+}
+
+// Handle calls fn with every value published, on the publishing goroutine,
+// so fn must not block. Unlike a subscriber it never misses a value.
+func (b *Bus[T]) Handle(fn func(T)) func() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return func() {}
+	}
+	return b.addLocked(subscriber[T]{fn: fn})
+}
+
+// addLocked registers sub and returns its unsubscribe func. callers hold b.mu
+func (b *Bus[T]) addLocked(sub subscriber[T]) func() {
+	id := b.nextID
+	b.nextID++
+	b.subs[id] = sub
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			b.mu.Lock()
+			defer b.mu.Unlock()
+			if sub, ok := b.subs[id]; ok {
+				delete(b.subs, id)
+				sub.close()
+			}
+		})
+	}
+}
+
+func (s subscriber[T]) close() {
+	if s.ch != nil {
+		close(s.ch)
+	}
+}
+
+func (b *Bus[T]) Publish(v T) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	if b.closed {
+		return
+	}
+
+	for _, sub := range b.subs {
+		if sub.fn != nil {
+			sub.fn(v)
+			continue
+		}
+		if sub.keep != nil && !sub.keep(v) {
+			continue
+		}
+		select {
+		case sub.ch <- v:
+		default:
+			b.dropped.Add(1)
+		}
+	}
+}
+
+func (b *Bus[T]) Subscribers() int {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return len(b.subs)
+}
+
+func (b *Bus[T]) Dropped() uint64 {
+	return b.dropped.Load()
+}
+
+func (b *Bus[T]) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return
+	}
+	b.closed = true
+
+	for id, sub := range b.subs {
+		delete(b.subs, id)
+		sub.close()
+	}
+}
@@ -0,0 +1,453 @@
+package hub
+
+import (
+	"container/list"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+)
+
+// read-only hub methods whose results may be served from the local cache
+var cacheableMethods = map[string]bool{
+	"GetDailyQuests":              true,
+	"GetDailyQuest":               true,
+	"GetChallengeBundles":         true,
+	"GetChallengeBundle":          true,
+	"GetChallengeBundleSchedules": true,
+	"GetSeasonInfo":               true,
+	"GetWeeklyChallenges":         true,
+	"GetChallengeBundlesPage":     true,
+}
+
+// the hub's cache key namespace behind each cacheable method, so clearing
+// hub keys by pattern drops the matching local entries too. This is a
+// contract with the hub, which does not report it: the hub keys its cache
+// as "<namespace>:<id>", with quests under "quests", bundles, schedules and
+// weekly challenges under "bundles", and season info under "season". A
+// method missing here, or a namespace the hub renames, makes every pattern
+// drop that method's entries, which is safe but clears more than needed.
+var cacheNamespaces = map[string]string{
+	"GetDailyQuests":              "quests",
+	"GetDailyQuest":               "quests",
+	"GetChallengeBundles":         "bundles",
+	"GetChallengeBundle":          "bundles",
+	"GetChallengeBundleSchedules": "bundles",
+	"GetSeasonInfo":               "season",
+	"GetWeeklyChallenges":         "bundles",
+	"GetChallengeBundlesPage":     "bundles",
+}
+
+// rough per-entry bookkeeping cost on top of key and payload
+const cacheEntryOverhead = 96
+
+type cacheEntry struct {
+	key     string
+	raw     json.RawMessage
+	expires time.Time
+}
+
+func (e *cacheEntry) size() int64 {
+	return int64(len(e.key) + len(e.raw) + cacheEntryOverhead)
+}
+
+// entries hold the raw result so every caller decodes its own copy. With a
+// budget the least recently used entries are evicted once the cache and the
+// reserved bytes of the live snapshot exceed it.
+type responseCache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	entries map[string]*list.Element
+	lru     *list.List
+
+	// server version the entries were fetched from
+	version string
+
+	budget    int64
+	reserved  int64
+	bytes     int64
+	evictions uint64
+
+	// how long past expiry an entry may still answer a failed call
+	staleFor time.Duration
+	methods  map[string]*methodCounters
+
+	metrics *metrics
+}
+
+// callers hold rc.mu
+type methodCounters struct {
+	hits, misses, evictions, staleServes uint64
+	refreshes                            uint64
+	refreshTotal, refreshMax             time.Duration
+}
+
+func newResponseCache(ttl time.Duration) *responseCache {
+	return &responseCache{
+		ttl:     ttl,
+		entries: make(map[string]*list.Element),
+		lru:     list.New(),
+		methods: make(map[string]*methodCounters),
+	}
+}
+
+func cacheKey(method string, args []interface{}) (string, bool) {
+	if !cacheableMethods[method] {
+		return "", false
+	}
+	if len(args) == 0 {
+		return method, true
+	}
+
+	b, err := json.Marshal(args)
+	if err != nil {
+		return "", false
+	}
+	return method + ":" + string(b), true
+}
+
+// the method a cache key belongs to
+func keyMethod(key string) string {
+	method, _, _ := strings.Cut(key, ":")
+	return method
+}
+
+func (rc *responseCache) counters(method string) *methodCounters {
+	mc, ok := rc.methods[method]
+	if !ok {
+		mc = &methodCounters{}
+		rc.methods[method] = mc
+	}
+	return mc
+}
+
+func (rc *responseCache) get(key string) (json.RawMessage, bool) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	method := keyMethod(key)
+	el, ok := rc.entries[key]
+	if !ok {
+		rc.miss(method)
+		return nil, false
+	}
+
+	// expired entries are kept for stale serving until that runs out too
+	e := el.Value.(*cacheEntry)
+	if now := time.Now(); now.After(e.expires) {
+		if now.After(e.expires.Add(rc.staleFor)) {
+			rc.removeElement(el)
+		}
+		rc.miss(method)
+		return nil, false
+	}
+
+	rc.lru.MoveToFront(el)
+	rc.counters(method).hits++
+	rc.metrics.cacheLookup(method, "hit")
+	return e.raw, true
+}
+
+func (rc *responseCache) miss(method string) {
+	rc.counters(method).misses++
+	rc.metrics.cacheLookup(method, "miss")
+}
+
+// stale returns an entry past its TTL but within staleFor, for answering a
+// call the hub could not
+func (rc *responseCache) stale(key string) (json.RawMessage, bool) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	if rc.staleFor <= 0 {
+		return nil, false
+	}
+	el, ok := rc.entries[key]
+	if !ok {
+		return nil, false
+	}
+	e := el.Value.(*cacheEntry)
+	if time.Now().After(e.expires.Add(rc.staleFor)) {
+		rc.removeElement(el)
+		return nil, false
+	}
+
+	method := keyMethod(key)
+	rc.counters(method).staleServes++
+	rc.metrics.cacheLookup(method, "stale")
+	return e.raw, true
+}
+
+// refreshed records how long the hub call refilling a missed entry took
+func (rc *responseCache) refreshed(method string, took time.Duration) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	mc := rc.counters(method)
+	mc.refreshes++
+	mc.refreshTotal += took
+	mc.refreshMax = max(mc.refreshMax, took)
+	rc.metrics.cacheRefresh(method, took)
+}
+
+func (rc *responseCache) put(key string, raw json.RawMessage) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	if el, ok := rc.entries[key]; ok {
+		rc.removeElement(el)
+	}
+
+	e := &cacheEntry{key: key, raw: raw, expires: time.Now().Add(rc.ttl)}
+
+	// an entry that cannot fit even in an empty cache is not worth evicting for
+	if rc.budget > 0 && e.size()+rc.reserved > rc.budget {
+		return
+	}
+
+	rc.entries[key] = rc.lru.PushFront(e)
+	rc.addBytes(e.size())
+	rc.evict()
+}
+
+// unexpired entries, keyed like the cache
+func (rc *responseCache) snapshot() map[string]json.RawMessage {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	now := time.Now()
+	out := make(map[string]json.RawMessage, len(rc.entries))
+	for key, el := range rc.entries {
+		e := el.Value.(*cacheEntry)
+		if now.Before(e.expires) {
+			out[key] = e.raw
+		}
+	}
+	return out
+}
+
+func (rc *responseCache) remove(key string) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	if el, ok := rc.entries[key]; ok {
+		rc.removeElement(el)
+	}
+}
+
+func (rc *responseCache) invalidate(prefix string) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	for key, el := range rc.entries {
+		if strings.HasPrefix(key, prefix) {
+			rc.removeElement(el)
+		}
+	}
+}
+
+// invalidateMatching drops the entries of every method whose namespace a
+// pattern could match. Only the literal part before the first wildcard is
+// compared, so this errs on the side of dropping too much.
+func (rc *responseCache) invalidateMatching(patterns []string) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	for key, el := range rc.entries {
+		method, _, _ := strings.Cut(key, ":")
+		if namespaceMatches(cacheNamespaces[method], patterns) {
+			rc.removeElement(el)
+		}
+	}
+}
+
+func namespaceMatches(ns string, patterns []string) bool {
+	if ns == "" {
+		return true
+	}
+	for _, p := range patterns {
+		literal := p
+		if i := strings.IndexAny(p, "*?["); i >= 0 {
+			literal = p[:i]
+		}
+		if strings.HasPrefix(ns+":", literal) || strings.HasPrefix(literal, ns+":") {
+			return true
+		}
+	}
+	return false
+}
+
+// setVersion records the version the hub reports and drops every entry when
+// it differs from the one the entries came from. It returns how many were
+// dropped.
+func (rc *responseCache) setVersion(v string) (dropped int, changed bool) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	if v == "" || v == rc.version {
+		return 0, false
+	}
+	prev := rc.version
+	rc.version = v
+	if prev == "" {
+		return 0, false
+	}
+
+	dropped = len(rc.entries)
+	for _, el := range rc.entries {
+		rc.removeElement(el)
+	}
+	return dropped, true
+}
+
+// reserve sets how much of the budget other resident data already takes
+func (rc *responseCache) reserve(n int64) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	rc.reserved = n
+	rc.evict()
+}
+
+func (rc *responseCache) stats() (entries int, bytes int64, evictions uint64, version string, methods map[string]MethodCacheStats) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	methods = make(map[string]MethodCacheStats, len(rc.methods))
+	for method, mc := range rc.methods {
+		ms := MethodCacheStats{
+			Hits:              mc.hits,
+			Misses:            mc.misses,
+			Evictions:         mc.evictions,
+			StaleServes:       mc.staleServes,
+			Refreshes:         mc.refreshes,
+			MaxRefreshLatency: mc.refreshMax,
+		}
+		if mc.refreshes > 0 {
+			ms.RefreshLatency = mc.refreshTotal / time.Duration(mc.refreshes)
+		}
+		methods[method] = ms
+	}
+	return len(rc.entries), rc.bytes, rc.evictions, rc.version, methods
+}
+
+// callers hold rc.mu
+func (rc *responseCache) evict() {
+	if rc.budget <= 0 {
+		return
+	}
+
+	for rc.bytes+rc.reserved > rc.budget {
+		el := rc.lru.Back()
+		if el == nil {
+			return
+		}
+		method := keyMethod(el.Value.(*cacheEntry).key)
+		rc.removeElement(el)
+		rc.evictions++
+		rc.counters(method).evictions++
+		rc.metrics.cacheEviction(method)
+	}
+}
+
+func (rc *responseCache) removeElement(el *list.Element) {
+	e := rc.lru.Remove(el).(*cacheEntry)
+	delete(rc.entries, e.key)
+	rc.addBytes(-e.size())
+}
+
+func (rc *responseCache) addBytes(n int64) {
+	rc.bytes += n
+	rc.metrics.cacheBytes(n)
+}
+
+type CacheStats struct {
+	Entries   int    `json:"entries"`
+	Bytes     int64  `json:"bytes"`
+	LiveBytes int64  `json:"liveBytes"`
+	Budget    int64  `json:"budget"`
+	Evictions uint64 `json:"evictions"`
+
+	// hub version the cached entries belong to
+	Version string `json:"version,omitempty"`
+
+	// by hub method, for the methods the cache has seen
+	Methods map[string]MethodCacheStats `json:"methods,omitempty"`
+}
+
+// MethodCacheStats counts the cache lookups of one method. A miss includes
+// an entry past its TTL; a stale serve is such an entry answering a call the
+// hub failed, see WithStaleIfError. Evictions are for the byte budget.
+type MethodCacheStats struct {
+	Hits        uint64 `json:"hits"`
+	Misses      uint64 `json:"misses"`
+	Evictions   uint64 `json:"evictions"`
+	StaleServes uint64 `json:"staleServes"`
+
+	// hub calls that refilled a missed entry, and how long they took
+	Refreshes         uint64        `json:"refreshes"`
+	RefreshLatency    time.Duration `json:"refreshLatency"`
+	MaxRefreshLatency time.Duration `json:"maxRefreshLatency"`
+}
+
+// HitRatio is hits over lookups, 0 before the first
+func (s MethodCacheStats) HitRatio() float64 {
+	if s.Hits+s.Misses == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(s.Hits+s.Misses)
+}
+
+// CacheStats reports memory held by the response cache and the live snapshot
+func (c *Client) CacheStats() CacheStats {
+	stats := CacheStats{Budget: c.cacheBudget}
+	if v := c.live.Load(); v != nil {
+		stats.LiveBytes = v.size
+	}
+	if c.cache != nil {
+		stats.Entries, stats.Bytes, stats.Evictions, stats.Version, stats.Methods = c.cache.stats()
+	}
+	return stats
+}
+
+// serveStale answers a failed call from an expired entry when
+// WithStaleIfError allows it. Only failures to reach the hub qualify: an
+// error the hub sent back, or a result that did not decode, is the answer.
+func (c *Client) serveStale(ctx context.Context, method string, args []interface{}, err error) (json.RawMessage, bool) {
+	if c.cache == nil || cacheBypassed(ctx) {
+		return nil, false
+	}
+	var herr *HubError
+	if errors.As(err, &herr) || errors.Is(err, errDecode) || errors.Is(err, ErrResponseTooLarge) || errors.Is(err, context.Canceled) {
+		return nil, false
+	}
+	key, ok := cacheKey(method, args)
+	if !ok {
+		return nil, false
+	}
+
+	raw, ok := c.cache.stale(key)
+	if ok {
+		c.logger.Warn("Serving stale %s after: %v", method, err)
+	}
+	return raw, ok
+}
+
+// InvalidateLocal drops every locally cached response
+func (c *Client) InvalidateLocal() {
+	if c.cache != nil {
+		c.cache.invalidate("")
+	}
+}
+
+// observeVersion is fed every version the hub reports, from Ready and
+// GetServiceStatus; a new version means a deployment, so nothing cached
+// from the old one is served
+func (c *Client) observeVersion(v string) {
+	if c.cache == nil {
+		return
+	}
+	if dropped, changed := c.cache.setVersion(v); changed {
+		c.logger.Info("Hub version changed to %s, dropped %d cached responses", v, dropped)
+	}
+}
@@ -0,0 +1,119 @@
+package hub
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestResponseCacheExpiryAndStale(t *testing.T) {
+	rc := newResponseCache(20 * time.Millisecond)
+	rc.staleFor = time.Hour
+
+	key, ok := cacheKey("GetDailyQuest", []interface{}{"Quest_A"})
+	if !ok || key != `GetDailyQuest:["Quest_A"]` {
+		t.Fatalf("cacheKey = %q, %v", key, ok)
+	}
+	if _, ok := cacheKey("SubscribeToUpdates", nil); ok {
+		t.Error("a method that is not a read got a cache key")
+	}
+
+	rc.put(key, json.RawMessage(`{"count":1}`))
+	if raw, ok := rc.get(key); !ok || string(raw) != `{"count":1}` {
+		t.Fatalf("get = %s, %v", raw, ok)
+	}
+
+	time.Sleep(30 * time.Millisecond)
+	if _, ok := rc.get(key); ok {
+		t.Error("expired entry was a hit")
+	}
+	if raw, ok := rc.stale(key); !ok || string(raw) != `{"count":1}` {
+		t.Errorf("stale = %s, %v; want the expired entry", raw, ok)
+	}
+
+	_, _, _, _, methods := rc.stats()
+	if m := methods["GetDailyQuest"]; m.Hits != 1 || m.Misses != 1 || m.StaleServes != 1 {
+		t.Errorf("stats = %+v", m)
+	}
+}
+
+func TestResponseCacheBudgetEvictsLRU(t *testing.T) {
+	rc := newResponseCache(time.Hour)
+	payload := json.RawMessage(strings.Repeat("x", 100))
+	key := func(id string) string {
+		k, _ := cacheKey("GetDailyQuest", []interface{}{id})
+		return k
+	}
+	entry := (&cacheEntry{key: key("A"), raw: payload}).size()
+	rc.budget = 3 * entry
+
+	rc.put(key("A"), payload)
+	rc.put(key("B"), payload)
+	rc.put(key("C"), payload)
+	// touching the oldest makes B the least recently used
+	rc.get(key("A"))
+	rc.put(key("D"), payload)
+
+	if _, ok := rc.get(key("B")); ok {
+		t.Error("least recently used entry survived")
+	}
+	if _, ok := rc.get(key("A")); !ok {
+		t.Error("recently used entry was evicted")
+	}
+	entries, bytes, evictions, _, _ := rc.stats()
+	if entries != 3 || bytes != rc.budget || evictions != 1 {
+		t.Errorf("%d entries, %d bytes, %d evictions", entries, bytes, evictions)
+	}
+
+	// the live snapshot's share pushes entries out
+	rc.reserve(2 * entry)
+	if entries, _, _, _, _ := rc.stats(); entries != 1 {
+		t.Errorf("%d entries after reserving two thirds, want 1", entries)
+	}
+
+	// an entry bigger than the budget is not cached at all
+	rc.reserve(0)
+	rc.put(key("E"), json.RawMessage(strings.Repeat("x", int(rc.budget))))
+	if _, ok := rc.get(key("E")); ok {
+		t.Error("oversized entry was cached")
+	}
+}
+
+func TestResponseCacheVersion(t *testing.T) {
+	rc := newResponseCache(time.Hour)
+	rc.put("GetDailyQuests", json.RawMessage(`{}`))
+
+	if dropped, changed := rc.setVersion("1.0"); changed || dropped != 0 {
+		t.Errorf("first version: dropped %d, changed %v", dropped, changed)
+	}
+	if _, changed := rc.setVersion("1.0"); changed {
+		t.Error("same version counted as a change")
+	}
+	if dropped, changed := rc.setVersion("2.0"); !changed || dropped != 1 {
+		t.Errorf("new version: dropped %d, changed %v", dropped, changed)
+	}
+	if _, ok := rc.get("GetDailyQuests"); ok {
+		t.Error("entry survived a version change")
+	}
+}
+
+func TestResponseCacheInvalidate(t *testing.T) {
+	rc := newResponseCache(time.Hour)
+	rc.put(`GetDailyQuest:["Quest_A"]`, json.RawMessage(`{}`))
+	rc.put("GetDailyQuests", json.RawMessage(`{}`))
+	rc.put("GetChallengeBundles", json.RawMessage(`[]`))
+
+	rc.invalidate("GetDailyQuest:")
+	if _, ok := rc.get(`GetDailyQuest:["Quest_A"]`); ok {
+		t.Error("prefix was not invalidated")
+	}
+	if _, ok := rc.get("GetDailyQuests"); !ok {
+		t.Error("invalidate dropped a key outside the prefix")
+	}
+
+	rc.invalidateMatching([]string{"bundles:*"})
+	if _, ok := rc.get("GetChallengeBundles"); ok {
+		t.Error("bundles entry survived a bundles:* invalidation")
+	}
+}
@@ -0,0 +1,82 @@
+package hub
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+type CallOption func(*callOptions)
+
+type callOptions struct {
+	timeout           time.Duration
+	priority          Priority
+	correlationPrefix string
+
+	// explicit timeouts also cap a deadline the context already has; the
+	// client-wide default only applies to contexts without one
+	explicitTimeout bool
+}
+
+func CallTimeout(d time.Duration) CallOption {
+	return func(o *callOptions) {
+		o.timeout = d
+		o.explicitTimeout = true
+	}
+}
+
+func CallPriority(p Priority) CallOption {
+	return func(o *callOptions) {
+		o.priority = p
+	}
+}
+
+// invocations are tagged prefix-N in log output
+func CallCorrelationPrefix(prefix string) CallOption {
+	return func(o *callOptions) {
+		o.correlationPrefix = prefix
+	}
+}
+
+type callOptionsKey struct{}
+
+// per-call overrides, applied on top of the client defaults
+func ContextWithCallOptions(ctx context.Context, opts ...CallOption) context.Context {
+	// a nil context is left for the invoke path to reject
+	if ctx == nil || len(opts) == 0 {
+		return ctx
+	}
+	existing, _ := ctx.Value(callOptionsKey{}).([]CallOption)
+	merged := append(append([]CallOption(nil), existing...), opts...)
+	return context.WithValue(ctx, callOptionsKey{}, merged)
+}
+
+// precedence, lowest first: client timeout, client default call options,
+// per-method timeouts, options on the context, WithPriority
+func (c *Client) resolveCallOptions(ctx context.Context, method string) callOptions {
+	o := callOptions{timeout: c.timeout}
+	for _, opt := range c.defaultCallOptions {
+		opt(&o)
+	}
+	if d, ok := c.methodTimeouts[method]; ok {
+		o.timeout = d
+		o.explicitTimeout = true
+	}
+	if opts, ok := ctx.Value(callOptionsKey{}).([]CallOption); ok {
+		for _, opt := range opts {
+			opt(&o)
+		}
+	}
+	if p, ok := ctx.Value(priorityKey{}).(Priority); ok {
+		o.priority = p
+	}
+	return o
+}
+
+func (c *Client) correlationID(o callOptions) string {
+	seq := c.invokeSeq.Add(1)
+	if o.correlationPrefix == "" {
+		return fmt.Sprintf("%d", seq)
+	}
+	return fmt.Sprintf("%s-%d", o.correlationPrefix, seq)
+}
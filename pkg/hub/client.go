@@ -2,14 +2,25 @@ package hub
 
 import (
 	"context"
+	"crypto/tls"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"log/slog"
+	"net/http"
+	"path"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/philippseith/signalr"
+	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/sync/singleflight"
 )
 
+// default cap on a single hub response, see WithMaxResponseSize
+const defaultMaxResponseSize = 10 << 20
+
 type Client struct {
 	connection signalr.Client
 	url        string
@@ -19,15 +30,90 @@ type Client struct {
 
 	timeout time.Duration
 
-	logger    Logger
-	connected bool
+	keepAlive     time.Duration
+	serverTimeout time.Duration
+
+	maxResponseSize   int
+	slowCallThreshold time.Duration
+
+	// zero leaves stateful reconnect off, see WithStatefulReconnect
+	resumeWindow time.Duration
+
+	startupJitter time.Duration
+	startupOnce   sync.Once
+
+	pinnedCerts []string
+	httpClient  *http.Client
+	tlsConfig   *tls.Config
+	transports  Transport
+	protocol    HubProtocol
+	compression Compression
+
+	buckets      map[Priority]*tokenBucket
+	rateLimit    *tokenBucket
+	methodLimits map[string]*tokenBucket
+	breaker      *circuitBreaker
+
+	defaultCallOptions []CallOption
+	pageSize           int
+	methodTimeouts     map[string]time.Duration
+	invokeSeq          atomic.Uint64
+
+	cache       *responseCache
+	cacheBudget int64
+	cacheStale  time.Duration
+	metrics     *metrics
+	metricsReg  prometheus.Registerer
+
+	strictDecoding bool
+	decodeFallback bool
+	drift          driftLog
+
+	instanceID     string
+	instanceIDFile string
+	usageFile      string
+	locale         string
+	headers        http.Header
+
+	retry       *RetryPolicy
+	retryBudget RetryBudget
+
+	logger   Logger
+	slog     *slog.Logger
+	state    ConnectionState
+	connInfo ConnectionInfo
 
 	mu sync.RWMutex
 
-	readyHandlers      []func(ReadyStatus)
-	disconnectHandlers []func(error)
+	refreshHandlers []func(RefreshProgress)
+
+	handlers             handlerRunner
+	handlerErrorHandlers []func(interface{})
+	// what Events delivers; the On* handlers other than OnCacheRefreshProgress
+	// and OnRefreshed are driven from it too
+	events *Bus[Event]
+
+	deprecations map[deprecationKey]DeprecationNotice
+	refreshes    refreshJobs
 
 	observeCancel context.CancelFunc
+
+	usage *usageTracker
+
+	interceptors []Interceptor
+	invoker      Invoker
+
+	inflight   inflightCalls
+	coalesce   *singleflight.Group
+	dispatcher dispatcher
+
+	init   initTracker
+	health healthCache
+
+	prefetch bool
+	warm     warmState
+	ready    readyState
+	live     LiveSnapshot
 }
 
 // receiver for server->client callbacks
@@ -37,18 +123,35 @@ type hubReceiver struct {
 }
 
 func (r *hubReceiver) Ready(status ReadyStatus) {
-	r.client.logger.Info(
-		"Service ready - Version: %s, Initialized: %v",
-		status.Version,
-		status.Initialized,
-	)
+	r.client.onConnected()
+	r.client.observeVersion(status.Version)
+	r.client.observeInitialized(status.Initialized, status.Version)
+
+	initial := r.client.markReady(status)
+	if initial {
+		r.client.logger.Info(
+			"Service ready - Version: %s, Initialized: %v",
+			status.Version,
+			status.Initialized,
+		)
+	} else {
+		r.client.logger.Info(
+			"Service ready again - Version: %s, Initialized: %v",
+			status.Version,
+			status.Initialized,
+		)
+	}
 
 	r.client.mu.RLock()
-	handlers := append([]func(ReadyStatus){}, r.client.readyHandlers...)
+	var refreshed []func(ReadyStatus)
+	if !initial {
+		refreshed = append(refreshed, r.client.ready.refreshed...)
+	}
 	r.client.mu.RUnlock()
 
-	for _, h := range handlers {
-		go h(status)
+	r.client.emit(Event{Kind: EventReady, Ready: status, Initial: initial})
+	for _, h := range refreshed {
+		r.client.runHandler(func() { h(status) })
 	}
 }
 
@@ -56,54 +159,115 @@ func NewClient(url string, opts ...ClientOption) *Client {
 	ctx, cancel := context.WithCancel(context.Background())
 
 	c := &Client{
-		url:                url,
-		ctx:                ctx,
-		cancel:             cancel,
-		timeout:            30 * time.Second,
-		logger:             &DefaultLogger{},
-		readyHandlers:      make([]func(ReadyStatus), 0),
-		disconnectHandlers: make([]func(error), 0),
+		url:             url,
+		ctx:             ctx,
+		cancel:          cancel,
+		timeout:         30 * time.Second,
+		maxResponseSize: defaultMaxResponseSize,
+		pageSize:        100,
+		logger:          &DefaultLogger{},
+		usage:           newUsageTracker(),
+		warm:            warmState{done: make(chan struct{})},
+		ready:           readyState{done: make(chan struct{})},
+		init:            newInitTracker(),
+		dispatcher:      newDispatcher(),
+		events:          NewBus[Event](eventBuffer),
+		health:          healthCache{ttl: 2 * time.Second},
 	}
 
 	for _, opt := range opts {
 		opt(c)
 	}
 
+	if c.usageFile != "" {
+		if err := c.usage.load(c.usageFile); err != nil {
+			c.logger.Warn("Usage starts over: %v", err)
+		}
+	}
+
+	c.resolveInstanceID()
+	if c.instanceID != "" {
+		if l, ok := c.logger.(slogLogger); ok {
+			c.logger = slogLogger{l: l.l.With("instance_id", c.instanceID)}
+		}
+		if c.slog != nil {
+			c.slog = c.slog.With("instance_id", c.instanceID)
+		}
+	}
+	if c.metricsReg != nil {
+		c.metrics = newMetrics(c.metricsReg, c.instanceID)
+	}
+	if len(c.interceptors) > 0 {
+		c.invoker = chainInterceptors(c.interceptors, c.invokeOnce)
+	}
+
+	if c.cache != nil {
+		c.cache.budget = c.cacheBudget
+		c.cache.staleFor = c.cacheStale
+		c.cache.metrics = c.metrics
+	}
+
 	return c
 }
 
 func (c *Client) Connect() error {
+	c.startupDelay()
+
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	if c.connected {
+	switch c.state {
+	case StateConnected, StateConnecting, StateReconnecting:
 		return nil
 	}
 
+	if c.connection != nil && c.state == StateDisconnected {
+		c.metrics.reconnect()
+		c.setStateLocked(StateReconnecting)
+	} else {
+		c.setStateLocked(StateConnecting)
+	}
+
+	if c.observeCancel != nil {
+		c.observeCancel()
+		c.observeCancel = nil
+	}
+
 	creationCtx, cancel := context.WithTimeout(c.ctx, c.timeout)
 	defer cancel()
 
-	conn, err := signalr.NewHTTPConnection(
-		creationCtx,
-		c.url,
-	)
-
+	conn, transport, err := c.newConnection(creationCtx)
 	if err != nil {
+		c.setStateLocked(StateDisconnected)
 		c.logger.Error("Failed to create SignalR connection: %v", err)
 		return fmt.Errorf("failed to create connection: %w", err)
 	}
 
+	c.connInfo = newConnectionInfo(c.url, conn, transport, c.protocol)
+	c.connInfo.InstanceID = c.instanceID
+	conn = c.traceInvocations(conn)
+
 	rcv := &hubReceiver{client: c}
+	srLogger, srDebug := c.signalrLogger()
 
-	client, err := signalr.NewClient(
-		c.ctx,
+	srOpts := []func(signalr.Party) error{
 		signalr.WithConnection(conn),
 		signalr.WithReceiver(rcv),
+		signalr.TransferFormat(c.protocol.transferFormat()),
 
-		signalr.Logger(noopSignalRLogger{}, false),
-		signalr.MaximumReceiveMessageSize(10*1024*1024),
-	)
+		signalr.Logger(srLogger, srDebug),
+		signalr.MaximumReceiveMessageSize(uint(c.maxResponseSize)),
+	}
+	if c.keepAlive > 0 {
+		srOpts = append(srOpts, signalr.KeepAliveInterval(c.keepAlive))
+	}
+	if c.serverTimeout > 0 {
+		srOpts = append(srOpts, signalr.TimeoutInterval(c.serverTimeout))
+	}
+
+	client, err := signalr.NewClient(c.ctx, srOpts...)
 	if err != nil {
+		c.setStateLocked(StateDisconnected)
 		c.logger.Error("Failed to create SignalR client: %v", err)
 		return fmt.Errorf("failed to create client: %w", err)
 	}
@@ -113,53 +277,79 @@ func (c *Client) Connect() error {
 	stateCh := make(chan signalr.ClientState, 8)
 	c.observeCancel = c.connection.ObserveStateChanged(stateCh)
 
-	go c.watchStates(stateCh)
+	go c.watchStates(client, stateCh)
 
 	c.connection.Start()
 
-	c.logger.Info("Connecting to Hub at %s", c.url)
+	if c.instanceID != "" {
+		c.logger.Info("Connecting to Hub at %s as instance %s", c.url, c.instanceID)
+	} else {
+		c.logger.Info("Connecting to Hub at %s", c.url)
+	}
 	return nil
 }
 
-func (c *Client) watchStates(stateCh <-chan signalr.ClientState) {
+// conn is the connection the states belong to, which c.connection may no
+// longer be by the time one arrives
+func (c *Client) watchStates(conn signalr.Client, stateCh <-chan signalr.ClientState) {
 	for state := range stateCh {
 		switch state {
 		case signalr.ClientConnected:
-			c.mu.Lock()
-			c.connected = true
-			c.mu.Unlock()
-
-			c.logger.Info("Connected to Hub")
+			c.onConnected()
 
 		case signalr.ClientClosed:
 			c.mu.Lock()
-			c.connected = false
+			if c.state == StateClosed {
+				c.mu.Unlock()
+				continue
+			}
+			c.setStateLocked(StateDisconnected)
 			c.mu.Unlock()
 
-			err := c.connection.Err()
+			err := conn.Err()
 			if err == nil {
 				err = ErrNotConnected
 			}
 
-			c.logger.Info("Disconnected from Hub: %v", err)
-
-			c.mu.RLock()
-			handlers := append([]func(error){}, c.disconnectHandlers...)
-			c.mu.RUnlock()
-
-			for _, h := range handlers {
-				go h(err)
+			if errors.Is(err, ErrResponseTooLarge) {
+				c.logger.Warn("Disconnected from Hub: %v", err)
+			} else {
+				c.logger.Info("Disconnected from Hub: %v", err)
 			}
+
+			c.emit(Event{Kind: EventDisconnected, Err: err})
 		}
 	}
 }
 
+// runs for signalr's connected notification and for Ready, whichever comes first
+func (c *Client) onConnected() {
+	if !c.markConnected() {
+		return
+	}
+
+	info := c.ConnectionInfo()
+	c.logger.Info(
+		"Connected to Hub - Transport: %s, Connection: %s",
+		info.Transport,
+		info.ConnectionID,
+	)
+
+	if c.prefetch && !c.IsWarm() {
+		go c.prefetchSnapshot()
+	}
+}
+
 func (c *Client) Disconnect() error {
+	if err := c.usage.save(false); err != nil {
+		c.logger.Warn("%v", err)
+	}
+
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
 	if c.connection == nil {
-		c.connected = false
+		c.setStateLocked(StateClosed)
 		return nil
 	}
 
@@ -170,184 +360,366 @@ func (c *Client) Disconnect() error {
 
 	c.connection.Stop()
 
-	c.connected = false
+	c.setStateLocked(StateClosed)
 	c.logger.Info("Disconnected from Hub")
 	return nil
 }
 
-func (c *Client) IsConnected() bool {
+func (c *Client) currentConnection() signalr.Client {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
-	return c.connected
+	return c.connection
 }
 
-func (c *Client) OnReady(handler func(ReadyStatus)) {
-	c.mu.Lock()
-	defer c.mu.Unlock()
-	c.readyHandlers = append(c.readyHandlers, handler)
+func (c *Client) IsConnected() bool {
+	return c.State() == StateConnected
+}
+
+// OnReady is called for every Ready from the hub. initial is true only for
+// the first; the hub sends Ready again after each reconnect.
+func (c *Client) OnReady(handler func(status ReadyStatus, initial bool)) {
+	c.handleEvents(EventReady, func(e Event) { handler(e.Ready, e.Initial) })
 }
 
 func (c *Client) OnDisconnect(handler func(error)) {
-	c.mu.Lock()
-	defer c.mu.Unlock()
-	c.disconnectHandlers = append(c.disconnectHandlers, handler)
+	c.handleEvents(EventDisconnected, func(e Event) { handler(e.Err) })
 }
 
-func (c *Client) invoke(ctx context.Context, method string, args ...interface{}) (interface{}, error) {
+func (c *Client) invokeOnce(ctx context.Context, method string, args ...interface{}) (raw json.RawMessage, err error) {
+	var key string
+	if c.cache != nil && !cacheBypassed(ctx) {
+		var ok bool
+		if key, ok = cacheKey(method, args); ok {
+			if raw, hit := c.cache.get(key); hit {
+				c.metrics.cacheHit(method)
+				c.logInvoke(ctx, method, "", "", time.Now(), true, nil)
+				return raw, nil
+			}
+		}
+	}
+
+	start := time.Now()
+	var id, wireID string
+	defer func() {
+		c.metrics.observe(method, start, len(raw), err)
+		c.logInvoke(ctx, method, id, wireID, start, false, err)
+		c.logSlowCall(method, id, start, len(raw), err)
+	}()
+
 	if !c.IsConnected() {
 		return nil, ErrNotConnected
 	}
 
+	if !c.inflight.enter() {
+		return nil, fmt.Errorf("%w: %s", ErrDraining, method)
+	}
+	defer c.inflight.leave()
+
 	if ctx == nil {
 		ctx = context.Background()
 	}
 
-	if _, hasDeadline := ctx.Deadline(); !hasDeadline {
+	opts := c.resolveCallOptions(ctx, method)
+	id = c.correlationID(opts)
+
+	ctx, cancelBudget, err := withBudgetDeadline(ctx)
+	defer cancelBudget()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", err, method)
+	}
+
+	if _, hasDeadline := ctx.Deadline(); !hasDeadline || opts.explicitTimeout {
 		var cancel context.CancelFunc
-		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+		ctx, cancel = context.WithTimeout(ctx, opts.timeout)
 		defer cancel()
 	}
 
-	ch := c.connection.Invoke(method, args...)
+	if err := c.waitForSlot(ctx, opts.priority); err != nil {
+		return nil, fmt.Errorf(
+			"%w: %s - %v",
+			timeoutKind(ctx),
+			method,
+			err,
+		)
+	}
+
+	if err := c.waitForRateLimit(ctx, method); err != nil {
+		c.logger.Warn("Method %s [%s] rate limited: %v", method, id, err)
+		return nil, fmt.Errorf("%w: %s - %v", ErrRateLimited, method, err)
+	}
+
+	if err := c.acquireInvokeSlot(ctx, opts.priority); err != nil {
+		return nil, fmt.Errorf(
+			"%w: %s - waiting for an invoke slot: %v",
+			timeoutKind(ctx),
+			method,
+			err,
+		)
+	}
+	defer c.releaseInvokeSlot()
+
+	// Connect replaces the connection; the call and the loss check below
+	// must use the same one
+	conn := c.currentConnection()
+	answered, err := c.admitCall(method)
+	if err != nil {
+		return nil, err
+	}
+	ch, wireID := c.dispatch(ctx, conn, method, args...)
+	if wireID != "" {
+		c.logger.Debug("Method %s [%s] sent as invocation %s", method, id, wireID)
+		defer c.dispatcher.received.take(wireID)
+	}
+	// what the hub's answer took on the wire, or fallback when unseen
+	wireBytes := func(fallback int) int {
+		if n, ok := c.dispatcher.received.take(wireID); ok {
+			return n
+		}
+		return fallback
+	}
 
 	select {
-	case res := <-ch:
+	case res, ok := <-ch:
+		// void methods also complete by closing the channel, so a closed
+		// channel only means loss once the signalr client has stopped
+		lost := isConnectionLoss(res.Error) || (!ok && conn.Context().Err() != nil)
+		if lost {
+			c.recordUsage(method, 0, true)
+			c.logger.Warn("Method %s [%s] lost its connection", method, id)
+
+			cause := res.Error
+			if err := conn.Err(); errors.Is(err, ErrResponseTooLarge) {
+				cause = err
+			}
+			if cause == nil {
+				cause = ErrNotConnected
+			}
+			err := fmt.Errorf(
+				"%w: %w: %s - %w",
+				ErrInvokeFailed,
+				ErrConnectionLost,
+				method,
+				cause,
+			)
+			answered(err)
+			return nil, err
+		}
+
 		if res.Error != nil {
+			c.recordUsage(method, wireBytes(0), true)
 			c.logger.Error(
-				"Method %s failed: %v",
+				"Method %s [%s] failed: %v",
 				method,
+				id,
 				res.Error,
 			)
+			herr := parseHubError(method, c.locale, res.Error)
+			answered(herr)
+			if errors.Is(herr, ErrNotInitialized) {
+				c.observeInitialized(false, "")
+			}
+			return nil, herr
+		}
+		answered(nil)
+
+		raw, err := c.protocol.encodeResult(res.Value)
+		if err != nil {
+			c.recordUsage(method, wireBytes(0), true)
+			return nil, fmt.Errorf("failed to marshal result: %w", err)
+		}
+		if len(raw) > c.maxResponseSize {
+			c.recordUsage(method, wireBytes(len(raw)), true)
+			c.logger.Warn(
+				"Method %s [%s] response of %d bytes exceeds limit of %d",
+				method,
+				id,
+				len(raw),
+				c.maxResponseSize,
+			)
 			return nil, fmt.Errorf(
-				"%w: %s - %v",
-				ErrInvokeFailed,
+				"%w: %s - %d bytes, limit %d",
+				ErrResponseTooLarge,
 				method,
-				res.Error,
+				len(raw),
+				c.maxResponseSize,
 			)
 		}
-		return res.Value, nil
+		size := wireBytes(len(raw))
+		if err := decodeResult(ctx, raw); err != nil {
+			c.recordUsage(method, size, true)
+			return nil, err
+		}
+		c.recordUsage(method, size, false)
+		c.depositRetry()
+
+		if key != "" {
+			c.cache.put(key, raw)
+			c.cache.refreshed(method, time.Since(start))
+		}
+		return raw, nil
 
 	case <-ctx.Done():
-		return nil, fmt.Errorf(
+		c.recordUsage(method, 0, true)
+		err := fmt.Errorf(
 			"%w: %s - %v",
-			ErrConnectionTimeout,
+			timeoutKind(ctx),
 			method,
 			ctx.Err(),
 		)
+		if errors.Is(ctx.Err(), context.Canceled) {
+			answered(ctx.Err())
+		} else {
+			answered(err)
+		}
+		return nil, err
 	}
 }
 
-func (c *Client) unmarshalResult(result interface{}, target interface{}) error {
-	b, err := json.Marshal(result)
+// Invoke calls a hub method and decodes its result into T, for methods the
+// client does not wrap yet
+func Invoke[T any](ctx context.Context, c *Client, method string, args ...interface{}) (T, error) {
+	var out, zero T
+
+	d := &resultDecoder{decode: func(raw json.RawMessage) error {
+		return c.unmarshalResult(ctx, method, raw, &out)
+	}}
+	val, err := c.invoke(withResultDecoder(ctx, d), method, args...)
 	if err != nil {
-		return fmt.Errorf("failed to marshal result: %w", err)
+		return zero, err
 	}
-	if err := json.Unmarshal(b, target); err != nil {
-		return fmt.Errorf("failed to unmarshal result: %w", err)
+
+	if !d.decoded(val) {
+		out = zero
+		if err := c.unmarshalResult(ctx, method, val, &out); err != nil {
+			c.metrics.decodeError(method)
+			return zero, err
+		}
 	}
-	return nil
+	return out, nil
 }
 
-func (c *Client) GetServiceStatus(ctx context.Context) (*ServiceStatus, error) {
-	val, err := c.invoke(ctx, "GetServiceStatus")
+func (c *Client) GetServiceStatus(ctx context.Context, opts ...CallOption) (*ServiceStatus, error) {
+	out, err := Invoke[ServiceStatus](ContextWithCallOptions(ctx, opts...), c, "GetServiceStatus")
 	if err != nil {
 		return nil, err
 	}
 
-	var out ServiceStatus
-	if err := c.unmarshalResult(val, &out); err != nil {
-		return nil, err
-	}
+	c.observeVersion(out.Version)
+	c.observeInitialized(out.Initialized, out.Version)
 	return &out, nil
 }
 
-func (c *Client) GetDailyQuests(ctx context.Context) (map[string]BaseQuest, error) {
-	val, err := c.invoke(ctx, "GetDailyQuests")
-	if err != nil {
-		return nil, err
-	}
-
-	var out map[string]BaseQuest
-	if err := c.unmarshalResult(val, &out); err != nil {
-		return nil, err
-	}
-	return out, nil
+func (c *Client) GetDailyQuests(ctx context.Context, opts ...CallOption) (map[string]BaseQuest, error) {
+	return Invoke[map[string]BaseQuest](ContextWithCallOptions(ctx, opts...), c, "GetDailyQuests")
 }
 
-func (c *Client) GetDailyQuest(ctx context.Context, questID string) (*BaseQuest, error) {
+func (c *Client) GetDailyQuest(ctx context.Context, questID string, opts ...CallOption) (*BaseQuest, error) {
 	if questID == "" {
 		return nil, ErrInvalidQuestID
 	}
 
-	val, err := c.invoke(ctx, "GetDailyQuest", questID)
+	out, err := Invoke[BaseQuest](ContextWithCallOptions(ctx, opts...), c, "GetDailyQuest", questID)
 	if err != nil {
 		return nil, err
 	}
-
-	var out BaseQuest
-	if err := c.unmarshalResult(val, &out); err != nil {
-		return nil, err
-	}
 	return &out, nil
 }
 
-func (c *Client) GetChallengeBundles(ctx context.Context) ([]AthenaChallengeBundle, error) {
-	val, err := c.invoke(ctx, "GetChallengeBundles")
-	if err != nil {
-		return nil, err
-	}
-
-	var out []AthenaChallengeBundle
-	if err := c.unmarshalResult(val, &out); err != nil {
-		return nil, err
-	}
-	return out, nil
+func (c *Client) GetChallengeBundles(ctx context.Context, opts ...CallOption) ([]AthenaChallengeBundle, error) {
+	return Invoke[[]AthenaChallengeBundle](ContextWithCallOptions(ctx, opts...), c, "GetChallengeBundles")
 }
 
-func (c *Client) GetChallengeBundle(ctx context.Context, templateID string) (*AthenaChallengeBundle, error) {
+func (c *Client) GetChallengeBundle(ctx context.Context, templateID string, opts ...CallOption) (*AthenaChallengeBundle, error) {
 	if templateID == "" {
 		return nil, ErrInvalidTemplateID
 	}
 
-	val, err := c.invoke(ctx, "GetChallengeBundle", templateID)
+	out, err := Invoke[AthenaChallengeBundle](ContextWithCallOptions(ctx, opts...), c, "GetChallengeBundle", templateID)
 	if err != nil {
 		return nil, err
 	}
-
-	var out AthenaChallengeBundle
-	if err := c.unmarshalResult(val, &out); err != nil {
-		return nil, err
-	}
 	return &out, nil
 }
 
-func (c *Client) GetChallengeBundleSchedules(ctx context.Context) ([]ChallengeBundleSchedule, error) {
-	val, err := c.invoke(ctx, "GetChallengeBundleSchedules")
+func (c *Client) GetChallengeBundleSchedules(ctx context.Context, opts ...CallOption) ([]ChallengeBundleSchedule, error) {
+	return Invoke[[]ChallengeBundleSchedule](ContextWithCallOptions(ctx, opts...), c, "GetChallengeBundleSchedules")
+}
+
+func (c *Client) ClearCache(ctx context.Context, opts ...CallOption) (*CacheResult, error) {
+	out, err := Invoke[CacheResult](ContextWithCallOptions(ctx, opts...), c, "ClearCache")
 	if err != nil {
 		return nil, err
 	}
 
-	var out []ChallengeBundleSchedule
-	if err := c.unmarshalResult(val, &out); err != nil {
+	c.InvalidateLocal()
+	return &out, nil
+}
+
+// hub cache key patterns covering the quest and bundle entries
+const (
+	QuestCachePattern  = "quests:*"
+	BundleCachePattern = "bundles:*"
+)
+
+// ClearCacheByPattern clears only the hub cache keys matching one of the
+// patterns, in the hub's glob syntax where * matches any run of characters,
+// e.g. "quests:*". Local entries those keys could back are dropped with them.
+//
+// The hub does the matching. The client only checks that each pattern is
+// well formed using path.Match syntax, so a pattern path.Match rejects,
+// such as one with an unclosed [, fails with ErrInvalidCachePattern. The
+// two differ in one way: path.Match stops * at a '/', and the hub's * does
+// not. Hub keys contain no '/', so the difference does not show. The key
+// namespaces the hub uses are listed with cacheNamespaces in cache.go.
+func (c *Client) ClearCacheByPattern(ctx context.Context, patterns []string, opts ...CallOption) (*CacheResult, error) {
+	if err := validateCachePatterns(patterns); err != nil {
 		return nil, err
 	}
-	return out, nil
-}
 
-func (c *Client) ClearCache(ctx context.Context) (*CacheResult, error) {
-	val, err := c.invoke(ctx, "ClearCache")
+	out, err := Invoke[CacheResult](ContextWithCallOptions(ctx, opts...), c, "ClearCacheByPattern", patterns)
 	if err != nil {
 		return nil, err
 	}
 
-	var out CacheResult
-	if err := c.unmarshalResult(val, &out); err != nil {
-		return nil, err
+	if c.cache != nil {
+		c.cache.invalidateMatching(patterns)
 	}
 	return &out, nil
 }
 
-func (c *Client) RefreshCache(ctx context.Context) error {
-	_, err := c.invoke(ctx, "RefreshCache")
-	return err
+// ClearQuestCache clears the hub's cached daily quests
+func (c *Client) ClearQuestCache(ctx context.Context, opts ...CallOption) (*CacheResult, error) {
+	return c.ClearCacheByPattern(ctx, []string{QuestCachePattern}, opts...)
+}
+
+// ClearBundleCache clears the hub's cached challenge bundles
+func (c *Client) ClearBundleCache(ctx context.Context, opts ...CallOption) (*CacheResult, error) {
+	return c.ClearCacheByPattern(ctx, []string{BundleCachePattern}, opts...)
+}
+
+// path.Match is only used for its syntax check, see ClearCacheByPattern
+func validateCachePatterns(patterns []string) error {
+	if len(patterns) == 0 {
+		return fmt.Errorf("%w: no patterns", ErrInvalidCachePattern)
+	}
+	for _, p := range patterns {
+		if _, err := path.Match(p, ""); p == "" || err != nil {
+			return fmt.Errorf("%w: %q", ErrInvalidCachePattern, p)
+		}
+	}
+	return nil
+}
+
+// RefreshCache starts a refresh of the hub's cache and returns its job ID.
+// The hub keeps working after the call returns; follow it with
+// OnCacheRefreshProgress or WaitForRefresh. The ID is empty from hubs that
+// refresh synchronously and report no progress.
+func (c *Client) RefreshCache(ctx context.Context, opts ...CallOption) (string, error) {
+	jobID, err := Invoke[string](ContextWithCallOptions(ctx, opts...), c, "RefreshCache")
+	if err != nil {
+		return "", err
+	}
+
+	c.InvalidateLocal()
+	return jobID, nil
 }
@@ -0,0 +1,56 @@
+package hub
+
+import (
+	"maps"
+	"slices"
+)
+
+// deep copies used wherever shared data is handed to callers
+
+func (q BaseQuest) Clone() BaseQuest {
+	q.Objectives = slices.Clone(q.Objectives)
+	q.Rewards = slices.Clone(q.Rewards)
+	return q
+}
+
+func (b AthenaChallengeBundle) Clone() AthenaChallengeBundle {
+	if b.Objects != nil {
+		objects := make([]ChallengeBundleObject, len(b.Objects))
+		for i, o := range b.Objects {
+			o.Rewards = slices.Clone(o.Rewards)
+			o.Objectives = slices.Clone(o.Objectives)
+			objects[i] = o
+		}
+		b.Objects = objects
+	}
+	b.CompletionRewards = slices.Clone(b.CompletionRewards)
+	return b
+}
+
+func (s *Snapshot) Clone() *Snapshot {
+	if s == nil {
+		return nil
+	}
+
+	out := *s
+	if s.Status != nil {
+		status := *s.Status
+		out.Status = &status
+	}
+	if s.DailyQuests != nil {
+		out.DailyQuests = make(map[string]BaseQuest, len(s.DailyQuests))
+		for id, q := range s.DailyQuests {
+			out.DailyQuests[id] = q.Clone()
+		}
+	}
+	if s.Bundles != nil {
+		out.Bundles = make([]AthenaChallengeBundle, len(s.Bundles))
+		for i, b := range s.Bundles {
+			out.Bundles[i] = b.Clone()
+		}
+	}
+	out.Schedules = slices.Clone(s.Schedules)
+	out.Provenance = s.Provenance.clone()
+	out.Names = maps.Clone(s.Names)
+	return &out
+}
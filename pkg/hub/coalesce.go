@@ -0,0 +1,62 @@
+package hub
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+)
+
+// what a shared call hands its callers; abandoned means the caller that
+// started it gave up, so its error says nothing about the others
+type sharedResult struct {
+	raw       json.RawMessage
+	abandoned bool
+}
+
+// coalesceKey keys reads (Get*) on method and arguments
+func coalesceKey(method string, args []interface{}) (string, bool) {
+	if !strings.HasPrefix(method, "Get") {
+		return "", false
+	}
+	if len(args) == 0 {
+		return method, true
+	}
+
+	b, err := json.Marshal(args)
+	if err != nil {
+		return "", false
+	}
+	return method + ":" + string(b), true
+}
+
+// invokeShared joins an identical call already in flight, or starts one
+// the next identical calls join. The call runs with the context and call
+// options of whoever started it; when that caller gives up, the others
+// start over rather than fail with its cancellation.
+func (c *Client) invokeShared(ctx context.Context, key, method string, args []interface{}) (json.RawMessage, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	for {
+		ch := c.coalesce.DoChan(key, func() (interface{}, error) {
+			raw, err := c.invokeWithRetry(ctx, method, args...)
+			c.invokeFailed(ctx, method, err)
+			return sharedResult{raw: raw, abandoned: ctx.Err() != nil}, err
+		})
+
+		select {
+		case res := <-ch:
+			shared := res.Val.(sharedResult)
+			if res.Err != nil && shared.abandoned && ctx.Err() == nil {
+				continue
+			}
+			if res.Shared {
+				c.metrics.coalesced(method)
+			}
+			return shared.raw, res.Err
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package hub
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/andybalholm/brotli"
+)
+
+// Compression selects how hub traffic is compressed. Hub payloads travel as
+// websocket messages, which only support permessage-deflate, so any choice
+// other than CompressionNone enables deflate there; gzip and brotli apply to
+// the plain HTTP requests such as negotiate.
+type Compression int
+
+const (
+	// leaves the http client's default gzip handling in place
+	CompressionNone Compression = iota
+	CompressionGzip
+	// offers brotli and falls back to gzip
+	CompressionBrotli
+)
+
+func (c Compression) String() string {
+	switch c {
+	case CompressionNone:
+		return "none"
+	case CompressionGzip:
+		return "gzip"
+	case CompressionBrotli:
+		return "brotli"
+	default:
+		return fmt.Sprintf("Compression(%d)", int(c))
+	}
+}
+
+func (c Compression) acceptEncoding() string {
+	switch c {
+	case CompressionGzip:
+		return "gzip"
+	case CompressionBrotli:
+		return "br, gzip"
+	default:
+		return ""
+	}
+}
+
+// net/http only decompresses transparently when it chose Accept-Encoding
+// itself, so responses to our own header are decoded here
+func decodeBody(resp *http.Response) (io.ReadCloser, error) {
+	switch enc := resp.Header.Get("Content-Encoding"); enc {
+	case "", "identity":
+		return resp.Body, nil
+	case "gzip":
+		zr, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			return nil, err
+		}
+		return zr, nil
+	case "br":
+		return io.NopCloser(brotli.NewReader(resp.Body)), nil
+	default:
+		return nil, fmt.Errorf("unsupported content encoding %q", enc)
+	}
+}
@@ -0,0 +1,494 @@
+package hub
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Config describes a client declaratively, for deployments that set it up
+// from a file or the environment rather than code. Zero fields keep the
+// NewClient defaults.
+type Config struct {
+	URL     string   `json:"url" yaml:"url"`
+	Timeout Duration `json:"timeout,omitzero" yaml:"timeout,omitempty"`
+
+	KeepAliveInterval Duration `json:"keepAliveInterval,omitzero" yaml:"keepAliveInterval,omitempty"`
+	ServerTimeout     Duration `json:"serverTimeout,omitzero" yaml:"serverTimeout,omitempty"`
+	// turns on stateful reconnect, see WithStatefulReconnect
+	ResumeWindow Duration `json:"resumeWindow,omitzero" yaml:"resumeWindow,omitempty"`
+
+	// bytes
+	MaxResponseSize   int      `json:"maxResponseSize,omitempty" yaml:"maxResponseSize,omitempty"`
+	SlowCallThreshold Duration `json:"slowCallThreshold,omitzero" yaml:"slowCallThreshold,omitempty"`
+
+	// nil keeps failed reads from being retried
+	Retry          *RetryConfig          `json:"retry,omitempty" yaml:"retry,omitempty"`
+	RateLimit      *RateLimitConfig      `json:"rateLimit,omitempty" yaml:"rateLimit,omitempty"`
+	CircuitBreaker *CircuitBreakerConfig `json:"circuitBreaker,omitempty" yaml:"circuitBreaker,omitempty"`
+	Auth           AuthConfig            `json:"auth,omitzero" yaml:"auth,omitempty"`
+
+	// tried in order of capability, whatever order they are listed in:
+	// websockets, sse
+	Transports  []string `json:"transports,omitempty" yaml:"transports,omitempty"`
+	Protocol    string   `json:"protocol,omitempty" yaml:"protocol,omitempty"`
+	Compression string   `json:"compression,omitempty" yaml:"compression,omitempty"`
+	// language of hub error messages, see WithLocale
+	Locale string `json:"locale,omitempty" yaml:"locale,omitempty"`
+
+	Log LogConfig `json:"log,omitzero" yaml:"log,omitempty"`
+}
+
+type RetryConfig struct {
+	MaxAttempts    int      `json:"maxAttempts,omitempty" yaml:"maxAttempts,omitempty"`
+	InitialBackoff Duration `json:"initialBackoff,omitzero" yaml:"initialBackoff,omitempty"`
+	MaxBackoff     Duration `json:"maxBackoff,omitzero" yaml:"maxBackoff,omitempty"`
+	Multiplier     float64  `json:"multiplier,omitempty" yaml:"multiplier,omitempty"`
+	Exclude        []string `json:"exclude,omitempty" yaml:"exclude,omitempty"`
+}
+
+// RateLimitConfig throttles hub calls, see WithRateLimit. Methods override
+// the client-wide limit for single methods; a zero PerSecond leaves the
+// client, or that method, unthrottled.
+type RateLimitConfig struct {
+	PerSecond float64                    `json:"perSecond,omitempty" yaml:"perSecond,omitempty"`
+	Burst     int                        `json:"burst,omitempty" yaml:"burst,omitempty"`
+	Methods   map[string]MethodRateLimit `json:"methods,omitempty" yaml:"methods,omitempty"`
+}
+
+type MethodRateLimit struct {
+	PerSecond float64 `json:"perSecond" yaml:"perSecond"`
+	Burst     int     `json:"burst,omitempty" yaml:"burst,omitempty"`
+}
+
+// CircuitBreakerConfig sets up WithCircuitBreaker; a zero Threshold
+// leaves it off
+type CircuitBreakerConfig struct {
+	Threshold int      `json:"threshold" yaml:"threshold"`
+	Cooldown  Duration `json:"cooldown" yaml:"cooldown"`
+}
+
+// AuthConfig adds a bearer token and any other headers to hub requests.
+// TokenFile suits mounted secrets and is read when the client is created.
+type AuthConfig struct {
+	Token     string            `json:"token,omitempty" yaml:"token,omitempty"`
+	TokenFile string            `json:"tokenFile,omitempty" yaml:"tokenFile,omitempty"`
+	Headers   map[string]string `json:"headers,omitempty" yaml:"headers,omitempty"`
+}
+
+// LogConfig sends client logs to stderr through slog; an empty level keeps
+// the client quiet
+type LogConfig struct {
+	Level string `json:"level,omitempty" yaml:"level,omitempty"`
+	// text or json
+	Format string `json:"format,omitempty" yaml:"format,omitempty"`
+}
+
+// Duration is a time.Duration written as a string such as "30s"
+type Duration time.Duration
+
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(time.Duration(d).String()), nil
+}
+
+func (d *Duration) UnmarshalText(b []byte) error {
+	v, err := time.ParseDuration(string(b))
+	if err != nil {
+		return err
+	}
+	*d = Duration(v)
+	return nil
+}
+
+// environment variables read by LoadConfig, over any file values
+const (
+	EnvURL                 = "QUESTHUB_URL"
+	EnvTimeout             = "QUESTHUB_TIMEOUT"
+	EnvKeepAliveInterval   = "QUESTHUB_KEEPALIVE_INTERVAL"
+	EnvServerTimeout       = "QUESTHUB_SERVER_TIMEOUT"
+	EnvResumeWindow        = "QUESTHUB_RESUME_WINDOW"
+	EnvMaxResponseSize     = "QUESTHUB_MAX_RESPONSE_SIZE"
+	EnvSlowCallThreshold   = "QUESTHUB_SLOW_CALL_THRESHOLD"
+	EnvRetryMaxAttempts    = "QUESTHUB_RETRY_MAX_ATTEMPTS"
+	EnvRetryInitialBackoff = "QUESTHUB_RETRY_INITIAL_BACKOFF"
+	EnvRetryMaxBackoff     = "QUESTHUB_RETRY_MAX_BACKOFF"
+	EnvRetryMultiplier     = "QUESTHUB_RETRY_MULTIPLIER"
+	EnvRateLimit           = "QUESTHUB_RATE_LIMIT"
+	EnvRateLimitBurst      = "QUESTHUB_RATE_LIMIT_BURST"
+	EnvCircuitThreshold    = "QUESTHUB_CIRCUIT_THRESHOLD"
+	EnvCircuitCooldown     = "QUESTHUB_CIRCUIT_COOLDOWN"
+	EnvAuthToken           = "QUESTHUB_AUTH_TOKEN"
+	EnvAuthTokenFile       = "QUESTHUB_AUTH_TOKEN_FILE"
+	// comma separated
+	EnvTransports  = "QUESTHUB_TRANSPORTS"
+	EnvProtocol    = "QUESTHUB_PROTOCOL"
+	EnvCompression = "QUESTHUB_COMPRESSION"
+	EnvLocale      = "QUESTHUB_LOCALE"
+	EnvLogLevel    = "QUESTHUB_LOG_LEVEL"
+	EnvLogFormat   = "QUESTHUB_LOG_FORMAT"
+)
+
+// LoadConfig reads a YAML or JSON file, chosen by its extension, and then
+// applies the QUESTHUB_* environment variables on top. An empty path loads
+// the environment alone.
+func LoadConfig(path string) (*Config, error) {
+	cfg := &Config{}
+
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidConfig, err)
+		}
+
+		switch ext := strings.ToLower(filepath.Ext(path)); ext {
+		case ".yaml", ".yml":
+			dec := yaml.NewDecoder(bytes.NewReader(data))
+			dec.KnownFields(true)
+			// an empty file leaves everything to the environment
+			if err = dec.Decode(cfg); errors.Is(err, io.EOF) {
+				err = nil
+			}
+		case ".json":
+			dec := json.NewDecoder(bytes.NewReader(data))
+			dec.DisallowUnknownFields()
+			err = dec.Decode(cfg)
+		default:
+			err = fmt.Errorf("unsupported config format %q", ext)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s - %v", ErrInvalidConfig, path, err)
+		}
+	}
+
+	if err := cfg.applyEnv(os.LookupEnv); err != nil {
+		return nil, err
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+func (cfg *Config) applyEnv(lookup func(string) (string, bool)) error {
+	str := func(key string, dst *string) {
+		if v, ok := lookup(key); ok {
+			*dst = v
+		}
+	}
+	dur := func(key string, dst *Duration) error {
+		if v, ok := lookup(key); ok {
+			if err := dst.UnmarshalText([]byte(v)); err != nil {
+				return fmt.Errorf("%w: %s - %v", ErrInvalidConfig, key, err)
+			}
+		}
+		return nil
+	}
+	retry := func() *RetryConfig {
+		if cfg.Retry == nil {
+			cfg.Retry = &RetryConfig{}
+		}
+		return cfg.Retry
+	}
+
+	str(EnvURL, &cfg.URL)
+	if err := dur(EnvTimeout, &cfg.Timeout); err != nil {
+		return err
+	}
+	if err := dur(EnvKeepAliveInterval, &cfg.KeepAliveInterval); err != nil {
+		return err
+	}
+	if err := dur(EnvServerTimeout, &cfg.ServerTimeout); err != nil {
+		return err
+	}
+	if err := dur(EnvResumeWindow, &cfg.ResumeWindow); err != nil {
+		return err
+	}
+	if v, ok := lookup(EnvMaxResponseSize); ok {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return fmt.Errorf("%w: %s - %v", ErrInvalidConfig, EnvMaxResponseSize, err)
+		}
+		cfg.MaxResponseSize = n
+	}
+	if err := dur(EnvSlowCallThreshold, &cfg.SlowCallThreshold); err != nil {
+		return err
+	}
+
+	if v, ok := lookup(EnvRetryMaxAttempts); ok {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return fmt.Errorf("%w: %s - %v", ErrInvalidConfig, EnvRetryMaxAttempts, err)
+		}
+		retry().MaxAttempts = n
+	}
+	if _, ok := lookup(EnvRetryInitialBackoff); ok {
+		if err := dur(EnvRetryInitialBackoff, &retry().InitialBackoff); err != nil {
+			return err
+		}
+	}
+	if _, ok := lookup(EnvRetryMaxBackoff); ok {
+		if err := dur(EnvRetryMaxBackoff, &retry().MaxBackoff); err != nil {
+			return err
+		}
+	}
+	if v, ok := lookup(EnvRetryMultiplier); ok {
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return fmt.Errorf("%w: %s - %v", ErrInvalidConfig, EnvRetryMultiplier, err)
+		}
+		retry().Multiplier = f
+	}
+
+	rateLimit := func() *RateLimitConfig {
+		if cfg.RateLimit == nil {
+			cfg.RateLimit = &RateLimitConfig{}
+		}
+		return cfg.RateLimit
+	}
+	if v, ok := lookup(EnvRateLimit); ok {
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return fmt.Errorf("%w: %s - %v", ErrInvalidConfig, EnvRateLimit, err)
+		}
+		rateLimit().PerSecond = f
+	}
+	if v, ok := lookup(EnvRateLimitBurst); ok {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return fmt.Errorf("%w: %s - %v", ErrInvalidConfig, EnvRateLimitBurst, err)
+		}
+		rateLimit().Burst = n
+	}
+
+	breaker := func() *CircuitBreakerConfig {
+		if cfg.CircuitBreaker == nil {
+			cfg.CircuitBreaker = &CircuitBreakerConfig{}
+		}
+		return cfg.CircuitBreaker
+	}
+	if v, ok := lookup(EnvCircuitThreshold); ok {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return fmt.Errorf("%w: %s - %v", ErrInvalidConfig, EnvCircuitThreshold, err)
+		}
+		breaker().Threshold = n
+	}
+	if _, ok := lookup(EnvCircuitCooldown); ok {
+		if err := dur(EnvCircuitCooldown, &breaker().Cooldown); err != nil {
+			return err
+		}
+	}
+
+	// a credential from the environment replaces the file's, whichever
+	// kind it set; setting both variables is still an error
+	token, hasToken := lookup(EnvAuthToken)
+	tokenFile, hasTokenFile := lookup(EnvAuthTokenFile)
+	if hasToken || hasTokenFile {
+		cfg.Auth.Token, cfg.Auth.TokenFile = token, tokenFile
+	}
+	if v, ok := lookup(EnvTransports); ok {
+		cfg.Transports = nil
+		for _, name := range strings.Split(v, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				cfg.Transports = append(cfg.Transports, name)
+			}
+		}
+	}
+	str(EnvProtocol, &cfg.Protocol)
+	str(EnvCompression, &cfg.Compression)
+	str(EnvLocale, &cfg.Locale)
+	str(EnvLogLevel, &cfg.Log.Level)
+	str(EnvLogFormat, &cfg.Log.Format)
+	return nil
+}
+
+// Validate reports the first setting NewClientFromConfig could not use
+func (cfg *Config) Validate() error {
+	_, err := cfg.options()
+	return err
+}
+
+// NewClientFromConfig creates a client from cfg. Options given here are
+// applied after the config's own, so code can still override it.
+func NewClientFromConfig(cfg *Config, opts ...ClientOption) (*Client, error) {
+	if cfg == nil {
+		return nil, fmt.Errorf("%w: nil config", ErrInvalidConfig)
+	}
+	cfgOpts, err := cfg.options()
+	if err != nil {
+		return nil, err
+	}
+
+	token := cfg.Auth.Token
+	if cfg.Auth.TokenFile != "" {
+		data, err := os.ReadFile(cfg.Auth.TokenFile)
+		if err != nil {
+			return nil, fmt.Errorf("%w: auth token file - %v", ErrInvalidConfig, err)
+		}
+		token = strings.TrimSpace(string(data))
+	}
+	if token != "" {
+		cfgOpts = append(cfgOpts, WithBearerToken(token))
+	}
+
+	return NewClient(cfg.URL, append(cfgOpts, opts...)...), nil
+}
+
+func (cfg *Config) options() ([]ClientOption, error) {
+	if cfg.URL == "" {
+		return nil, fmt.Errorf("%w: url is required", ErrInvalidConfig)
+	}
+
+	var opts []ClientOption
+
+	if cfg.Timeout < 0 {
+		return nil, fmt.Errorf("%w: negative timeout", ErrInvalidConfig)
+	}
+	if cfg.Timeout > 0 {
+		opts = append(opts, WithTimeout(time.Duration(cfg.Timeout)))
+	}
+	if cfg.KeepAliveInterval < 0 || cfg.ServerTimeout < 0 {
+		return nil, fmt.Errorf("%w: negative keepalive interval or server timeout", ErrInvalidConfig)
+	}
+	if cfg.KeepAliveInterval > 0 {
+		opts = append(opts, WithKeepAliveInterval(time.Duration(cfg.KeepAliveInterval)))
+	}
+	if cfg.ServerTimeout > 0 {
+		opts = append(opts, WithServerTimeout(time.Duration(cfg.ServerTimeout)))
+	}
+	if cfg.ResumeWindow < 0 {
+		return nil, fmt.Errorf("%w: negative resume window", ErrInvalidConfig)
+	}
+	if cfg.ResumeWindow > 0 {
+		opts = append(opts, WithStatefulReconnect(time.Duration(cfg.ResumeWindow)))
+	}
+	if cfg.MaxResponseSize < 0 || cfg.SlowCallThreshold < 0 {
+		return nil, fmt.Errorf("%w: negative max response size or slow call threshold", ErrInvalidConfig)
+	}
+	if cfg.MaxResponseSize > 0 {
+		opts = append(opts, WithMaxResponseSize(cfg.MaxResponseSize))
+	}
+	if cfg.SlowCallThreshold > 0 {
+		opts = append(opts, WithSlowCallThreshold(time.Duration(cfg.SlowCallThreshold)))
+	}
+
+	if r := cfg.Retry; r != nil {
+		// zero fields fall back to DefaultRetryPolicy in WithRetryPolicy
+		opts = append(opts, WithRetryPolicy(RetryPolicy{
+			MaxAttempts:    r.MaxAttempts,
+			InitialBackoff: time.Duration(r.InitialBackoff),
+			MaxBackoff:     time.Duration(r.MaxBackoff),
+			Multiplier:     r.Multiplier,
+			Exclude:        r.Exclude,
+		}))
+	}
+
+	if rl := cfg.RateLimit; rl != nil {
+		if rl.PerSecond < 0 || rl.Burst < 0 {
+			return nil, fmt.Errorf("%w: negative rate limit", ErrInvalidConfig)
+		}
+		if rl.PerSecond > 0 {
+			opts = append(opts, WithRateLimit(rl.PerSecond, rl.Burst))
+		}
+		for method, m := range rl.Methods {
+			if m.PerSecond < 0 || m.Burst < 0 {
+				return nil, fmt.Errorf("%w: negative rate limit for %s", ErrInvalidConfig, method)
+			}
+			opts = append(opts, WithMethodRateLimit(method, m.PerSecond, m.Burst))
+		}
+	}
+
+	if cb := cfg.CircuitBreaker; cb != nil {
+		if cb.Threshold < 0 || cb.Cooldown < 0 {
+			return nil, fmt.Errorf("%w: negative circuit breaker threshold or cooldown", ErrInvalidConfig)
+		}
+		if cb.Threshold > 0 {
+			opts = append(opts, WithCircuitBreaker(cb.Threshold, time.Duration(cb.Cooldown)))
+		}
+	}
+
+	for k, v := range cfg.Auth.Headers {
+		opts = append(opts, WithHeader(k, v))
+	}
+	if cfg.Auth.Token != "" && cfg.Auth.TokenFile != "" {
+		return nil, fmt.Errorf("%w: auth token and token file are both set", ErrInvalidConfig)
+	}
+
+	if len(cfg.Transports) > 0 {
+		var t Transport
+		for _, name := range cfg.Transports {
+			parsed, err := parseTransport(name)
+			if err != nil {
+				return nil, err
+			}
+			t |= parsed
+		}
+		opts = append(opts, WithTransport(t))
+	}
+
+	switch strings.ToLower(cfg.Protocol) {
+	case "", "json":
+	case "messagepack", "msgpack":
+		opts = append(opts, WithHubProtocol(ProtocolMessagePack))
+	default:
+		return nil, fmt.Errorf("%w: unknown protocol %q", ErrInvalidConfig, cfg.Protocol)
+	}
+
+	switch strings.ToLower(cfg.Compression) {
+	case "", "none":
+	case "gzip":
+		opts = append(opts, WithCompression(CompressionGzip))
+	case "brotli", "br":
+		opts = append(opts, WithCompression(CompressionBrotli))
+	default:
+		return nil, fmt.Errorf("%w: unknown compression %q", ErrInvalidConfig, cfg.Compression)
+	}
+	if cfg.Locale != "" {
+		opts = append(opts, WithLocale(cfg.Locale))
+	}
+
+	if cfg.Log.Level != "" {
+		var level slog.Level
+		if err := level.UnmarshalText([]byte(cfg.Log.Level)); err != nil {
+			return nil, fmt.Errorf("%w: log level - %v", ErrInvalidConfig, err)
+		}
+		handlerOpts := &slog.HandlerOptions{Level: level}
+
+		var h slog.Handler
+		switch strings.ToLower(cfg.Log.Format) {
+		case "", "text":
+			h = slog.NewTextHandler(os.Stderr, handlerOpts)
+		case "json":
+			h = slog.NewJSONHandler(os.Stderr, handlerOpts)
+		default:
+			return nil, fmt.Errorf("%w: unknown log format %q", ErrInvalidConfig, cfg.Log.Format)
+		}
+		opts = append(opts, WithSlog(slog.New(h)))
+	}
+
+	return opts, nil
+}
+
+func parseTransport(name string) (Transport, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "websockets", "websocket", "ws":
+		return TransportWebSockets, nil
+	case "serversentevents", "sse":
+		return TransportServerSentEvents, nil
+	case "longpolling", "long-polling":
+		// named so a config written for other SignalR clients fails clearly
+		return 0, fmt.Errorf("%w: transport %q is not supported, use websockets or sse", ErrInvalidConfig, name)
+	default:
+		return 0, fmt.Errorf("%w: unknown transport %q", ErrInvalidConfig, name)
+	}
+}
@@ -0,0 +1,113 @@
+package hub
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestValidateRejectsLongPolling(t *testing.T) {
+	cfg := &Config{URL: "http://localhost", Transports: []string{"websockets", "longpolling"}}
+	if err := cfg.Validate(); !errors.Is(err, ErrInvalidConfig) || !strings.Contains(err.Error(), "not supported") {
+		t.Errorf("Validate = %v, want unsupported transport", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "questhub.yaml")
+	if err := os.WriteFile(path, []byte("url: http://localhost\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(EnvTransports, "sse, LongPolling")
+	if _, err := LoadConfig(path); !errors.Is(err, ErrInvalidConfig) {
+		t.Errorf("LoadConfig with %s=LongPolling: err = %v", EnvTransports, err)
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, data string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	yamlPath := write("questhub.yaml", `
+url: http://hub.local
+timeout: 30s
+retry:
+  maxAttempts: 5
+  initialBackoff: 100ms
+rateLimit:
+  perSecond: 10
+  methods:
+    GetChallengeBundles:
+      perSecond: 1
+transports: [websockets]
+`)
+	cfg, err := LoadConfig(yamlPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.URL != "http://hub.local" || time.Duration(cfg.Timeout) != 30*time.Second ||
+		cfg.Retry.MaxAttempts != 5 || time.Duration(cfg.Retry.InitialBackoff) != 100*time.Millisecond ||
+		cfg.RateLimit.Methods["GetChallengeBundles"].PerSecond != 1 {
+		t.Errorf("yaml config = %+v", cfg)
+	}
+
+	// the environment wins over the file
+	t.Setenv(EnvURL, "http://env.local")
+	t.Setenv(EnvRetryMaxAttempts, "2")
+	t.Setenv(EnvCircuitThreshold, "3")
+	t.Setenv(EnvCircuitCooldown, "1m")
+	jsonPath := write("questhub.json", `{"url": "http://file.local", "retry": {"maxAttempts": 4}}`)
+	cfg, err = LoadConfig(jsonPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.URL != "http://env.local" || cfg.Retry.MaxAttempts != 2 ||
+		cfg.CircuitBreaker.Threshold != 3 || time.Duration(cfg.CircuitBreaker.Cooldown) != time.Minute {
+		t.Errorf("json config with env = %+v", cfg)
+	}
+
+	// the environment alone
+	if cfg, err := LoadConfig(""); err != nil || cfg.URL != "http://env.local" {
+		t.Errorf("env only: %+v, %v", cfg, err)
+	}
+}
+
+func TestLoadConfigInvalid(t *testing.T) {
+	dir := t.TempDir()
+	cases := map[string]string{
+		"unknown.yaml":  "url: http://hub.local\ntimout: 30s\n",
+		"unknown.json":  `{"url": "http://hub.local", "retries": 3}`,
+		"negative.yaml": "url: http://hub.local\nrateLimit:\n  perSecond: -1\n",
+		"nourl.yaml":    "timeout: 30s\n",
+		"badtime.yaml":  "url: http://hub.local\ntimeout: soon\n",
+		"config.toml":   "url = \"http://hub.local\"\n",
+	}
+	for name, data := range cases {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := LoadConfig(path); !errors.Is(err, ErrInvalidConfig) {
+			t.Errorf("%s: err = %v, want ErrInvalidConfig", name, err)
+		}
+	}
+	if _, err := LoadConfig(filepath.Join(dir, "missing.yaml")); !errors.Is(err, ErrInvalidConfig) {
+		t.Errorf("missing file: err = %v", err)
+	}
+
+	path := filepath.Join(dir, "ok.yaml")
+	if err := os.WriteFile(path, []byte("url: http://hub.local\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(EnvTimeout, "soon")
+	if _, err := LoadConfig(path); !errors.Is(err, ErrInvalidConfig) || !strings.Contains(err.Error(), EnvTimeout) {
+		t.Errorf("bad %s: err = %v", EnvTimeout, err)
+	}
+}
@@ -0,0 +1,297 @@
+package hub
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"sync"
+
+	"github.com/coder/websocket"
+	"github.com/philippseith/signalr"
+)
+
+// signalr.NewHTTPConnection dials websockets with the default http client,
+// so connections that need control over TLS are negotiated and dialed here
+type negotiateResponse struct {
+	ConnectionToken     string               `json:"connectionToken,omitempty"`
+	ConnectionID        string               `json:"connectionId"`
+	NegotiateVersion    int                  `json:"negotiateVersion,omitempty"`
+	AvailableTransports []availableTransport `json:"availableTransports"`
+	// the hub agreed to stateful reconnect
+	UseStatefulReconnect bool `json:"useStatefulReconnect,omitempty"`
+}
+
+type availableTransport struct {
+	Transport       string   `json:"transport"`
+	TransferFormats []string `json:"transferFormats"`
+}
+
+func (n *negotiateResponse) supports(transport signalr.TransportType, format signalr.TransferFormatType) bool {
+	for _, t := range n.AvailableTransports {
+		if t.Transport != string(transport) {
+			continue
+		}
+		for _, f := range t.TransferFormats {
+			if f == string(format) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func negotiate(ctx context.Context, httpClient *http.Client, address string, header http.Header, compression Compression, stateful bool) (*negotiateResponse, error) {
+	u, err := url.Parse(address)
+	if err != nil {
+		return nil, err
+	}
+	u.Path = path.Join(u.Path, "negotiate")
+	q := u.Query()
+	q.Set("negotiateVersion", "1")
+	if stateful {
+		q.Set("useStatefulReconnect", "true")
+	}
+	u.RawQuery = q.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	if enc := compression.acceptEncoding(); enc != "" {
+		req.Header.Set("Accept-Encoding", enc)
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("negotiate %s -> %s", u.String(), resp.Status)
+	}
+
+	r, err := decodeBody(resp)
+	if err != nil {
+		return nil, fmt.Errorf("negotiate %s: %w", u.String(), err)
+	}
+	defer r.Close()
+
+	body, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var out negotiateResponse
+	if err := json.Unmarshal(body, &out); err != nil {
+		return nil, fmt.Errorf("invalid negotiate response: %w", err)
+	}
+	return &out, nil
+}
+
+// with resume set and a hub that agrees, a dropped websocket is resumed
+// rather than ending the connection
+func dialWebSocket(ctx, connCtx context.Context, httpClient *http.Client, address string, format signalr.TransferFormatType, header http.Header, compression Compression, readLimit int, resume *resumeOptions) (signalr.Connection, error) {
+	nr, err := negotiate(ctx, httpClient, address, header, compression, resume != nil)
+	if err != nil {
+		return nil, err
+	}
+	if !nr.supports(signalr.TransportWebSockets, format) {
+		return nil, fmt.Errorf("hub does not offer websockets with %s transfer: %v", format, nr.AvailableTransports)
+	}
+
+	u, err := url.Parse(address)
+	if err != nil {
+		return nil, err
+	}
+
+	q := u.Query()
+	if nr.NegotiateVersion == 0 {
+		q.Set("id", nr.ConnectionID)
+	} else {
+		q.Set("id", nr.ConnectionToken)
+	}
+	u.RawQuery = q.Encode()
+
+	if u.Scheme == "https" {
+		u.Scheme = "wss"
+	} else {
+		u.Scheme = "ws"
+	}
+
+	opts := &websocket.DialOptions{
+		HTTPClient: httpClient,
+		HTTPHeader: header,
+	}
+	if compression != CompressionNone {
+		// falls back to uncompressed messages when the hub declines
+		opts.CompressionMode = websocket.CompressionContextTakeover
+	}
+
+	ws, _, err := websocket.Dial(ctx, u.String(), opts)
+	if err != nil {
+		return nil, err
+	}
+	// Read enforces the limit itself, one byte over lets it tell an
+	// oversized frame from one that fits exactly
+	ws.SetReadLimit(int64(readLimit) + 1)
+
+	w := &wsConnection{
+		ConnectionBase: signalr.NewConnectionBase(connCtx, nr.ConnectionID),
+		conn:           ws,
+		readLimit:      readLimit,
+	}
+	if resume != nil && nr.UseStatefulReconnect {
+		w.session = newSession(*resume, format == signalr.TransferFormatBinary, redialer(u.String(), opts))
+	}
+	return w, nil
+}
+
+type wsConnection struct {
+	*signalr.ConnectionBase
+	transferMode signalr.TransferMode
+	readLimit    int
+
+	// guards conn, which a resumed session replaces, and the session
+	mu      sync.Mutex
+	conn    *websocket.Conn
+	session *wsSession
+
+	// rest of a frame larger than the reader's buffer
+	pending []byte
+}
+
+func (w *wsConnection) messageType() websocket.MessageType {
+	if w.transferMode == signalr.BinaryTransferMode {
+		return websocket.MessageBinary
+	}
+	return websocket.MessageText
+}
+
+func (w *wsConnection) Write(p []byte) (int, error) {
+	messageType := w.messageType()
+
+	// numbering a message and picking the websocket it goes out on happen
+	// together, so a resume either replays it or sends it on the new one
+	w.mu.Lock()
+	conn, frame := w.conn, p
+	if w.session != nil {
+		var err error
+		if frame, err = w.session.outgoing(p); err != nil {
+			w.mu.Unlock()
+			return 0, err
+		}
+	}
+	w.mu.Unlock()
+
+	n, err := signalr.ReadWriteWithContext(w.Context(),
+		func() (int, error) {
+			if err := conn.Write(w.Context(), messageType, frame); err != nil {
+				return 0, err
+			}
+			return len(p), nil
+		},
+		func() {},
+	)
+	if err != nil && w.session != nil && w.Context().Err() == nil {
+		// kept for replay if numbered; the reader notices the dead
+		// websocket and resumes
+		return len(p), nil
+	}
+	if err != nil {
+		_ = conn.Close(websocket.StatusNormalClosure, err.Error())
+	}
+	return n, err
+}
+
+func (w *wsConnection) Read(p []byte) (int, error) {
+	w.mu.Lock()
+	if len(w.pending) > 0 {
+		n := copy(p, w.pending)
+		w.pending = w.pending[n:]
+		w.mu.Unlock()
+		return n, nil
+	}
+	w.mu.Unlock()
+
+	n, err := signalr.ReadWriteWithContext(w.Context(),
+		func() (int, error) {
+			for {
+				data, err := w.readFrame()
+				if err != nil && w.session != nil && w.Context().Err() == nil && !errors.Is(err, ErrResponseTooLarge) {
+					if err = w.resume(err); err == nil {
+						continue
+					}
+				}
+				if err != nil {
+					return 0, err
+				}
+
+				w.mu.Lock()
+				if w.session != nil {
+					var fresh bool
+					data, fresh = w.session.incoming(data)
+					if fresh {
+						w.scheduleAck()
+					}
+				}
+				data = append(w.pending, data...)
+				n := copy(p, data)
+				w.pending = data[n:]
+				w.mu.Unlock()
+
+				// a frame of nothing but Acks and replayed duplicates
+				if n > 0 {
+					return n, nil
+				}
+			}
+		},
+		func() {},
+	)
+	if err != nil {
+		status := websocket.StatusNormalClosure
+		if errors.Is(err, ErrResponseTooLarge) {
+			status = websocket.StatusMessageTooBig
+		}
+		w.mu.Lock()
+		conn := w.conn
+		w.mu.Unlock()
+		_ = conn.Close(status, err.Error())
+	}
+	return n, err
+}
+
+func (w *wsConnection) readFrame() ([]byte, error) {
+	w.mu.Lock()
+	conn := w.conn
+	w.mu.Unlock()
+
+	_, r, err := conn.Reader(w.Context())
+	if err != nil {
+		return nil, err
+	}
+	data, err := io.ReadAll(io.LimitReader(r, int64(w.readLimit)+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > w.readLimit {
+		return nil, fmt.Errorf("%w: frame over %d bytes", ErrResponseTooLarge, w.readLimit)
+	}
+	return data, nil
+}
+
+func (w *wsConnection) TransferMode() signalr.TransferMode {
+	return w.transferMode
+}
+
+func (w *wsConnection) SetTransferMode(mode signalr.TransferMode) {
+	w.transferMode = mode
+}
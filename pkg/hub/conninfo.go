@@ -0,0 +1,41 @@
+package hub
+
+import (
+	"net/url"
+	"time"
+
+	"github.com/philippseith/signalr"
+)
+
+type ConnectionInfo struct {
+	Transport    string    `json:"transport"`
+	Protocol     string    `json:"protocol"`
+	URL          string    `json:"url"`
+	RemoteAddr   string    `json:"remoteAddr"`
+	ConnectionID string    `json:"connectionId"`
+	ConnectedAt  time.Time `json:"connectedAt,omitempty"`
+	InstanceID   string    `json:"instanceId,omitempty"`
+	// websocket drops bridged by stateful reconnect on this connection
+	Resumes int `json:"resumes,omitempty"`
+}
+
+func newConnectionInfo(address string, conn signalr.Connection, transport Transport, protocol HubProtocol) ConnectionInfo {
+	info := ConnectionInfo{
+		Transport:    transport.String(),
+		Protocol:     protocol.String(),
+		URL:          address,
+		ConnectionID: conn.ConnectionID(),
+	}
+
+	if u, err := url.Parse(address); err == nil {
+		info.RemoteAddr = u.Host
+	}
+	return info
+}
+
+// zero value when the client has never connected
+func (c *Client) ConnectionInfo() ConnectionInfo {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.connInfo
+}
@@ -0,0 +1,170 @@
+package hub
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+)
+
+const maxDriftEntries = 100
+
+var errDecode = errors.New("failed to unmarshal result")
+
+// Invoke hands its decode step to invokeOnce through the context, so a
+// result that does not decode is recorded as a failed call rather than a
+// successful one, and is not cached
+type resultDecoderKey struct{}
+
+type resultDecoder struct {
+	decode func(json.RawMessage) error
+	// the result decoded, to tell it from one an interceptor replaced
+	raw json.RawMessage
+}
+
+func withResultDecoder(ctx context.Context, d *resultDecoder) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return context.WithValue(ctx, resultDecoderKey{}, d)
+}
+
+// decodeResult runs the caller's decode step, if any, on a fresh result
+func decodeResult(ctx context.Context, raw json.RawMessage) error {
+	d, ok := ctx.Value(resultDecoderKey{}).(*resultDecoder)
+	if !ok {
+		return nil
+	}
+	if err := d.decode(raw); err != nil {
+		return err
+	}
+	d.raw = raw
+	return nil
+}
+
+// decoded reports whether raw is the very result decodeResult decoded;
+// cached and coalesced results and ones an interceptor swapped are not
+func (d *resultDecoder) decoded(raw json.RawMessage) bool {
+	return len(raw) > 0 && len(raw) == len(d.raw) && &raw[0] == &d.raw[0]
+}
+
+type DecodeProblem struct {
+	Method  string    `json:"method"`
+	Problem string    `json:"problem"`
+	At      time.Time `json:"at"`
+}
+
+// filled in by the client when a lenient fallback decode succeeds
+type DecodeReport struct {
+	mu       sync.Mutex
+	problems []DecodeProblem
+}
+
+func (r *DecodeReport) add(p DecodeProblem) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.problems = append(r.problems, p)
+}
+
+func (r *DecodeReport) Problems() []DecodeProblem {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]DecodeProblem(nil), r.problems...)
+}
+
+type decodeReportKey struct{}
+
+func WithDecodeReport(ctx context.Context, r *DecodeReport) context.Context {
+	return context.WithValue(ctx, decodeReportKey{}, r)
+}
+
+type driftLog struct {
+	mu       sync.Mutex
+	problems []DecodeProblem
+	total    int64
+}
+
+func (d *driftLog) add(p DecodeProblem) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.total++
+	d.problems = append(d.problems, p)
+	if len(d.problems) > maxDriftEntries {
+		d.problems = d.problems[len(d.problems)-maxDriftEntries:]
+	}
+}
+
+// most recent problems absorbed by lenient decoding, plus the running total
+func (c *Client) SchemaDrift() ([]DecodeProblem, int64) {
+	c.drift.mu.Lock()
+	defer c.drift.mu.Unlock()
+	return append([]DecodeProblem(nil), c.drift.problems...), c.drift.total
+}
+
+func (c *Client) unmarshalResult(ctx context.Context, method string, result json.RawMessage, target interface{}) error {
+	if c.protocol == ProtocolMessagePack {
+		if err := decodeMessagePack(result, target, c.strictDecoding); err == nil {
+			return nil
+		}
+
+		// shapes only the JSON decoders know, such as legacy objective maps,
+		// and drift reporting go through JSON
+		js, err := c.protocol.resultJSON(result)
+		if err != nil {
+			return fmt.Errorf("%w: %w", errDecode, err)
+		}
+		result = js
+		reflect.ValueOf(target).Elem().SetZero()
+	}
+
+	if !c.strictDecoding {
+		if err := json.Unmarshal(result, target); err != nil {
+			return fmt.Errorf("%w: %w", errDecode, err)
+		}
+		return nil
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(result))
+	dec.DisallowUnknownFields()
+	strictErr := dec.Decode(target)
+	if strictErr == nil {
+		return nil
+	}
+	if !c.decodeFallback {
+		return fmt.Errorf("%w: %w", errDecode, strictErr)
+	}
+
+	problems := []string{strictErr.Error()}
+
+	// a type mismatch still decodes the remaining fields, so it is tolerated
+	var typeErr *json.UnmarshalTypeError
+	if err := json.Unmarshal(result, target); err != nil {
+		if !errors.As(err, &typeErr) {
+			return fmt.Errorf("%w: %w", errDecode, err)
+		}
+		if err.Error() != strictErr.Error() {
+			problems = append(problems, err.Error())
+		}
+	}
+
+	var report *DecodeReport
+	if ctx != nil {
+		report, _ = ctx.Value(decodeReportKey{}).(*DecodeReport)
+	}
+
+	now := time.Now()
+	for _, p := range problems {
+		problem := DecodeProblem{Method: method, Problem: p, At: now}
+		c.drift.add(problem)
+		c.metrics.schemaDrift(method)
+		if report != nil {
+			report.add(problem)
+		}
+		c.logger.Warn("Schema drift in %s result: %s", method, p)
+	}
+	return nil
+}
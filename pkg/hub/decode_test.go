@@ -0,0 +1,44 @@
+package hub
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func TestSchemaDriftMetric(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	c := NewClient("http://localhost", WithStrictDecoding(true), WithMetrics(reg), WithSlog(slog.New(slog.DiscardHandler)))
+
+	var q BaseQuest
+	if err := c.unmarshalResult(context.Background(), "GetDailyQuest", []byte(`{"count": 3, "newField": true}`), &q); err != nil {
+		t.Fatal(err)
+	}
+	if q.Count != 3 {
+		t.Errorf("count = %d, want 3", q.Count)
+	}
+	if _, total := c.SchemaDrift(); total != 1 {
+		t.Errorf("SchemaDrift total = %d, want 1", total)
+	}
+
+	families, err := reg.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got float64
+	for _, f := range families {
+		if f.GetName() != "questhub_client_schema_drift_total" {
+			continue
+		}
+		for _, m := range f.GetMetric() {
+			if m.GetLabel()[0].GetValue() == "GetDailyQuest" {
+				got += m.GetCounter().GetValue()
+			}
+		}
+	}
+	if got != 1 {
+		t.Errorf("schema_drift_total{method=GetDailyQuest} = %v, want 1", got)
+	}
+}
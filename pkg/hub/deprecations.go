@@ -0,0 +1,70 @@
+package hub
+
+import (
+	"sort"
+	"time"
+)
+
+// pushed by the server through the Deprecated receiver method
+type DeprecationNotice struct {
+	Method         string    `json:"method"`
+	Field          string    `json:"field,omitempty"`
+	Message        string    `json:"message"`
+	RemovalVersion string    `json:"removalVersion,omitempty"`
+	FirstSeen      time.Time `json:"-"`
+}
+
+type deprecationKey struct {
+	method string
+	field  string
+}
+
+func (r *hubReceiver) Deprecated(notice DeprecationNotice) {
+	r.client.recordDeprecation(notice)
+}
+
+func (c *Client) recordDeprecation(notice DeprecationNotice) {
+	key := deprecationKey{method: notice.Method, field: notice.Field}
+
+	c.mu.Lock()
+	if c.deprecations == nil {
+		c.deprecations = make(map[deprecationKey]DeprecationNotice)
+	}
+	_, seen := c.deprecations[key]
+	if !seen {
+		notice.FirstSeen = time.Now()
+		c.deprecations[key] = notice
+	}
+	c.mu.Unlock()
+
+	if seen {
+		return
+	}
+
+	target := notice.Method
+	if notice.Field != "" {
+		target += "." + notice.Field
+	}
+	if notice.RemovalVersion != "" {
+		c.logger.Warn("Deprecated: %s (removal in %s): %s", target, notice.RemovalVersion, notice.Message)
+	} else {
+		c.logger.Warn("Deprecated: %s: %s", target, notice.Message)
+	}
+}
+
+func (c *Client) Deprecations() []DeprecationNotice {
+	c.mu.RLock()
+	out := make([]DeprecationNotice, 0, len(c.deprecations))
+	for _, n := range c.deprecations {
+		out = append(out, n)
+	}
+	c.mu.RUnlock()
+
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Method != out[j].Method {
+			return out[i].Method < out[j].Method
+		}
+		return out[i].Field < out[j].Field
+	})
+	return out
+}
@@ -0,0 +1,196 @@
+package hub
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+)
+
+type ChangeKind string
+
+const (
+	ChangeAdded    ChangeKind = "added"
+	ChangeRemoved  ChangeKind = "removed"
+	ChangeModified ChangeKind = "modified"
+)
+
+// Old is nil for additions and New is nil for removals. Fields lists the JSON
+// fields that differ for modifications.
+type QuestDelta struct {
+	ID     string     `json:"id"`
+	Kind   ChangeKind `json:"kind"`
+	Old    *BaseQuest `json:"old,omitempty"`
+	New    *BaseQuest `json:"new,omitempty"`
+	Fields []string   `json:"fields,omitempty"`
+}
+
+type BundleDelta struct {
+	TemplateID string                 `json:"templateId"`
+	Kind       ChangeKind             `json:"kind"`
+	Old        *AthenaChallengeBundle `json:"old,omitempty"`
+	New        *AthenaChallengeBundle `json:"new,omitempty"`
+	Fields     []string               `json:"fields,omitempty"`
+}
+
+type ScheduleDelta struct {
+	TemplateID string                   `json:"templateId"`
+	Kind       ChangeKind               `json:"kind"`
+	Old        *ChallengeBundleSchedule `json:"old,omitempty"`
+	New        *ChallengeBundleSchedule `json:"new,omitempty"`
+	Fields     []string                 `json:"fields,omitempty"`
+}
+
+// deltas are sorted by ID within each kind of content
+type ChangeSet struct {
+	Quests    []QuestDelta    `json:"quests,omitempty"`
+	Bundles   []BundleDelta   `json:"bundles,omitempty"`
+	Schedules []ScheduleDelta `json:"schedules,omitempty"`
+	// set by SeasonDiff in place of the deltas when a new season replaced
+	// everything
+	Rollover *Rollover `json:"rollover,omitempty"`
+
+	// where the compared snapshots came from; nil for snapshots without one
+	From *Provenance `json:"from,omitempty"`
+	To   *Provenance `json:"to,omitempty"`
+}
+
+func diffProvenance(p Provenance) *Provenance {
+	if p.IsZero() {
+		return nil
+	}
+	p = p.With(TransformDiff)
+	return &p
+}
+
+func (cs ChangeSet) Len() int {
+	return len(cs.Quests) + len(cs.Bundles) + len(cs.Schedules)
+}
+
+func (cs ChangeSet) Empty() bool {
+	return cs.Len() == 0 && cs.Rollover == nil
+}
+
+// Diff compares two pulls. A nil snapshot counts as empty, so diffing against
+// nil reports everything as added or removed.
+func Diff(oldSnap, newSnap *Snapshot) ChangeSet {
+	if oldSnap == nil {
+		oldSnap = &Snapshot{}
+	}
+	if newSnap == nil {
+		newSnap = &Snapshot{}
+	}
+
+	cs := ChangeSet{
+		From: diffProvenance(oldSnap.Provenance),
+		To:   diffProvenance(newSnap.Provenance),
+	}
+
+	for _, d := range diffKeyed(oldSnap.DailyQuests, newSnap.DailyQuests) {
+		cs.Quests = append(cs.Quests, QuestDelta{ID: d.key, Kind: d.kind, Old: d.old, New: d.new, Fields: d.fields})
+	}
+
+	bundleID := func(b AthenaChallengeBundle) string { return b.TemplateID }
+	for _, d := range diffKeyed(indexBy(oldSnap.Bundles, bundleID), indexBy(newSnap.Bundles, bundleID)) {
+		cs.Bundles = append(cs.Bundles, BundleDelta{TemplateID: d.key, Kind: d.kind, Old: d.old, New: d.new, Fields: d.fields})
+	}
+
+	scheduleID := func(s ChallengeBundleSchedule) string { return s.TemplateID }
+	for _, d := range diffKeyed(indexBy(oldSnap.Schedules, scheduleID), indexBy(newSnap.Schedules, scheduleID)) {
+		cs.Schedules = append(cs.Schedules, ScheduleDelta{TemplateID: d.key, Kind: d.kind, Old: d.old, New: d.new, Fields: d.fields})
+	}
+
+	return cs
+}
+
+type delta[T any] struct {
+	key    string
+	kind   ChangeKind
+	old    *T
+	new    *T
+	fields []string
+}
+
+func indexBy[T any](items []T, key func(T) string) map[string]T {
+	out := make(map[string]T, len(items))
+	for _, item := range items {
+		out[key(item)] = item
+	}
+	return out
+}
+
+func diffKeyed[T any](oldItems, newItems map[string]T) []delta[T] {
+	keys := make(map[string]struct{}, len(oldItems)+len(newItems))
+	for k := range oldItems {
+		keys[k] = struct{}{}
+	}
+	for k := range newItems {
+		keys[k] = struct{}{}
+	}
+
+	sorted := make([]string, 0, len(keys))
+	for k := range keys {
+		sorted = append(sorted, k)
+	}
+	sort.Strings(sorted)
+
+	var out []delta[T]
+	for _, k := range sorted {
+		o, inOld := oldItems[k]
+		n, inNew := newItems[k]
+
+		switch {
+		case !inOld:
+			out = append(out, delta[T]{key: k, kind: ChangeAdded, new: &n})
+		case !inNew:
+			out = append(out, delta[T]{key: k, kind: ChangeRemoved, old: &o})
+		default:
+			if fields := changedFields(o, n); len(fields) > 0 {
+				out = append(out, delta[T]{key: k, kind: ChangeModified, old: &o, new: &n, fields: fields})
+			}
+		}
+	}
+	return out
+}
+
+// compares the JSON encodings field by field; an empty top-level collection
+// equals a missing one, as the hub does not distinguish them either
+func changedFields(a, b interface{}) []string {
+	am, aok := jsonFields(a)
+	bm, bok := jsonFields(b)
+	if !aok || !bok {
+		return []string{"*"}
+	}
+
+	var fields []string
+	for k, av := range am {
+		if bv, ok := bm[k]; !ok || !bytes.Equal(av, bv) {
+			fields = append(fields, k)
+		}
+	}
+	for k := range bm {
+		if _, ok := am[k]; !ok {
+			fields = append(fields, k)
+		}
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+func jsonFields(v interface{}) (map[string]json.RawMessage, bool) {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil, false
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return nil, false
+	}
+
+	for k, f := range fields {
+		if string(f) == "[]" || string(f) == "{}" {
+			fields[k] = json.RawMessage("null")
+		}
+	}
+	return fields, true
+}
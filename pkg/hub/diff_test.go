@@ -0,0 +1,63 @@
+package hub
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	oldSnap := &Snapshot{
+		DailyQuests: map[string]BaseQuest{
+			"Quest_Kept":    {Count: 1},
+			"Quest_Changed": {Count: 1},
+			"Quest_Gone":    {Count: 1},
+		},
+		Bundles: []AthenaChallengeBundle{
+			{TemplateID: "ChallengeBundle:B", Rarity: "common"},
+		},
+	}
+	newSnap := &Snapshot{
+		DailyQuests: map[string]BaseQuest{
+			"Quest_Kept":    {Count: 1},
+			"Quest_Changed": {Count: 2},
+			"Quest_New":     {Count: 1},
+		},
+		Bundles: []AthenaChallengeBundle{
+			// an empty list equals a missing one
+			{TemplateID: "ChallengeBundle:B", Rarity: "common", Objects: []ChallengeBundleObject{}},
+		},
+		Schedules: []ChallengeBundleSchedule{{TemplateID: "ChallengeBundleSchedule:S"}},
+	}
+
+	cs := Diff(oldSnap, newSnap)
+	var got []string
+	for _, d := range cs.Quests {
+		got = append(got, d.ID+" "+string(d.Kind))
+	}
+	want := []string{"Quest_Changed modified", "Quest_Gone removed", "Quest_New added"}
+	if !slices.Equal(got, want) {
+		t.Errorf("quests = %v, want %v", got, want)
+	}
+	if d := cs.Quests[0]; !slices.Equal(d.Fields, []string{"count"}) || d.Old.Count != 1 || d.New.Count != 2 {
+		t.Errorf("modified quest = %+v", d)
+	}
+	if d := cs.Quests[1]; d.New != nil || d.Old == nil {
+		t.Errorf("removed quest = %+v", d)
+	}
+	if len(cs.Bundles) != 0 {
+		t.Errorf("bundles = %+v, want no change", cs.Bundles)
+	}
+	if len(cs.Schedules) != 1 || cs.Schedules[0].Kind != ChangeAdded {
+		t.Errorf("schedules = %+v", cs.Schedules)
+	}
+	if cs.Len() != 4 || cs.From != nil || cs.To != nil {
+		t.Errorf("len %d, from %v, to %v", cs.Len(), cs.From, cs.To)
+	}
+
+	if !Diff(newSnap, newSnap).Empty() {
+		t.Error("a snapshot differs from itself")
+	}
+	if cs := Diff(nil, oldSnap); len(cs.Quests) != 3 || cs.Quests[0].Kind != ChangeAdded {
+		t.Errorf("diff against nil = %+v", cs.Quests)
+	}
+}
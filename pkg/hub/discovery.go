@@ -0,0 +1,33 @@
+package hub
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// RawInvoke calls any hub method and returns its result as JSON, for
+// server methods the typed wrappers do not cover yet. Get* methods are
+// retried and cached like their typed counterparts.
+func (c *Client) RawInvoke(ctx context.Context, method string, args ...interface{}) (json.RawMessage, error) {
+	raw, err := c.invoke(ctx, method, args...)
+	if err != nil {
+		return nil, err
+	}
+	if raw, err = c.protocol.resultJSON(raw); err != nil {
+		return nil, fmt.Errorf("%w: %w", errDecode, err)
+	}
+	return raw, nil
+}
+
+type HubMethod struct {
+	Name       string   `json:"name"`
+	Parameters []string `json:"parameters,omitempty"`
+	Returns    string   `json:"returns,omitempty"`
+}
+
+// ListHubMethods asks the hub which methods it serves. Hubs without
+// discovery fail with an error matching ErrMethodNotFound.
+func (c *Client) ListHubMethods(ctx context.Context, opts ...CallOption) ([]HubMethod, error) {
+	return Invoke[[]HubMethod](ContextWithCallOptions(ctx, opts...), c, "ListHubMethods")
+}
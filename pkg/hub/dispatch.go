@@ -0,0 +1,407 @@
+package hub
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"slices"
+	"sync"
+
+	"github.com/philippseith/signalr"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// signalr v0.8.0 allocates invocation IDs with an increment and a separate
+// load, so two goroutines invoking at once can both get the same ID and one
+// receives the other's result. The dispatcher lets one invocation at a time
+// through signalr's ID allocation and holds it until the frame carrying that
+// invocation has been written, which also tells the caller its wire ID.
+// Calls still run concurrently once sent.
+type dispatcher struct {
+	mu   sync.Mutex
+	sent chan wireInvocation
+
+	// caps calls in flight; nil means no cap
+	slots *invokeSlots
+
+	// wire size of the completion of each call in flight, for usage
+	received wireSizes
+}
+
+type wireInvocation struct {
+	id     string
+	target string
+}
+
+func newDispatcher() dispatcher {
+	return dispatcher{
+		sent:     make(chan wireInvocation, 1),
+		received: wireSizes{sizes: make(map[string]int)},
+	}
+}
+
+// wireSizes only keeps the invocations sent and not yet taken, so
+// completions nobody waits for any more are not remembered
+type wireSizes struct {
+	mu    sync.Mutex
+	sizes map[string]int
+}
+
+func (w *wireSizes) expect(id string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.sizes[id] = 0
+}
+
+func (w *wireSizes) observe(id string, n int) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if _, ok := w.sizes[id]; ok {
+		w.sizes[id] = n
+	}
+}
+
+// take forgets id; false when its completion was not seen
+func (w *wireSizes) take(id string) (int, bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	n, ok := w.sizes[id]
+	delete(w.sizes, id)
+	return n, ok && n > 0
+}
+
+// dispatch starts the invocation and returns its wire ID. When the frame is
+// not written before ctx ends, the lock is held in the background until it
+// is, so the next caller cannot race the pending allocation.
+func (c *Client) dispatch(ctx context.Context, conn signalr.Client, method string, args ...interface{}) (<-chan signalr.InvokeResult, string) {
+	c.dispatcher.mu.Lock()
+
+	ch := conn.Invoke(method, args...)
+	out := make(chan signalr.InvokeResult, 1)
+
+	release := func(ctx context.Context) (string, bool) {
+		select {
+		case w := <-c.dispatcher.sent:
+			if w.target != method {
+				c.logger.Error("Invocation %s sent for %s while dispatching %s", w.id, w.target, method)
+			}
+			c.dispatcher.mu.Unlock()
+			go forwardResult(ch, out)
+			return w.id, true
+		case res, ok := <-ch:
+			// failed before anything was written
+			c.dispatcher.mu.Unlock()
+			if ok {
+				out <- res
+			}
+			close(out)
+			return "", true
+		case <-conn.Context().Done():
+			c.dispatcher.mu.Unlock()
+			go forwardResult(ch, out)
+			return "", true
+		case <-ctx.Done():
+			return "", false
+		}
+	}
+
+	id, done := release(ctx)
+	if !done {
+		go func() {
+			if id, _ := release(context.Background()); id != "" {
+				c.dispatcher.received.take(id)
+			}
+		}()
+	}
+	return out, id
+}
+
+func forwardResult(in <-chan signalr.InvokeResult, out chan<- signalr.InvokeResult) {
+	for res := range in {
+		out <- res
+	}
+	close(out)
+}
+
+func (c *Client) acquireInvokeSlot(ctx context.Context, p Priority) error {
+	if c.dispatcher.slots == nil {
+		return nil
+	}
+	return c.dispatcher.slots.acquire(ctx, p)
+}
+
+func (c *Client) releaseInvokeSlot() {
+	if c.dispatcher.slots != nil {
+		c.dispatcher.slots.release()
+	}
+}
+
+// invokeSlots hands a freed slot to the longest waiting interactive call,
+// and to a background call only when no interactive one waits, so a batch
+// of background refreshes cannot hold up what a user is waiting for
+type invokeSlots struct {
+	mu    sync.Mutex
+	size  int
+	inUse int
+	// closed when the waiter is handed a slot
+	waiting [PriorityBackground + 1][]chan struct{}
+}
+
+func newInvokeSlots(n int) *invokeSlots {
+	return &invokeSlots{size: n}
+}
+
+func (s *invokeSlots) acquire(ctx context.Context, p Priority) error {
+	if p != PriorityInteractive {
+		p = PriorityBackground
+	}
+
+	s.mu.Lock()
+	if s.inUse < s.size && s.queued(p) == 0 {
+		s.inUse++
+		s.mu.Unlock()
+		return nil
+	}
+	ready := make(chan struct{})
+	s.waiting[p] = append(s.waiting[p], ready)
+	s.mu.Unlock()
+
+	select {
+	case <-ready:
+		return nil
+	case <-ctx.Done():
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if i := slices.Index(s.waiting[p], ready); i >= 0 {
+			s.waiting[p] = slices.Delete(s.waiting[p], i, i+1)
+			return ctx.Err()
+		}
+		// handed a slot while giving up; pass it on
+		s.releaseLocked()
+		return ctx.Err()
+	}
+}
+
+// queued counts the waiters a call of priority p would have to let go first
+func (s *invokeSlots) queued(p Priority) int {
+	n := len(s.waiting[PriorityInteractive])
+	if p == PriorityBackground {
+		n += len(s.waiting[PriorityBackground])
+	}
+	return n
+}
+
+func (s *invokeSlots) release() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.releaseLocked()
+}
+
+func (s *invokeSlots) releaseLocked() {
+	for p := range s.waiting {
+		if len(s.waiting[p]) > 0 {
+			close(s.waiting[p][0])
+			s.waiting[p] = s.waiting[p][1:]
+			return
+		}
+	}
+	s.inUse--
+}
+
+// tracedConnection reports the invocation frames written to the hub and
+// the size of the completions read back
+type tracedConnection struct {
+	signalr.Connection
+	binary    bool
+	onSend    func(wireInvocation)
+	onReceive func(id string, size int)
+
+	// only signalr's read loop reads, so these need no lock: the part of a
+	// message not read yet, and whether the handshake response has passed
+	unread     []byte
+	handshaken bool
+}
+
+func (c *Client) traceInvocations(conn signalr.Connection) signalr.Connection {
+	return &tracedConnection{
+		Connection: conn,
+		binary:     c.protocol == ProtocolMessagePack,
+		onSend: func(w wireInvocation) {
+			c.dispatcher.received.expect(w.id)
+			select {
+			case c.dispatcher.sent <- w:
+			default:
+			}
+		},
+		onReceive: c.dispatcher.received.observe,
+	}
+}
+
+func (t *tracedConnection) Write(p []byte) (int, error) {
+	n, err := t.Connection.Write(p)
+	if err == nil {
+		if w, ok := parseInvocation(p, t.binary); ok {
+			t.onSend(w)
+		}
+	}
+	return n, err
+}
+
+func (t *tracedConnection) Read(p []byte) (int, error) {
+	n, err := t.Connection.Read(p)
+	if n > 0 {
+		t.scanCompletions(p[:n])
+	}
+	return n, err
+}
+
+// scanCompletions splits what was read into messages and reports each
+// completion's size. The handshake response is JSON with either protocol.
+func (t *tracedConnection) scanCompletions(p []byte) {
+	buf := append(t.unread, p...)
+	for len(buf) > 0 {
+		if !t.binary || !t.handshaken {
+			end := bytes.IndexByte(buf, 0x1e)
+			if end < 0 {
+				break
+			}
+			if t.handshaken {
+				if id, ok := parseCompletion(buf[:end], false); ok {
+					t.onReceive(id, end+1)
+				}
+			}
+			t.handshaken = true
+			buf = buf[end+1:]
+			continue
+		}
+
+		size, n := binary.Uvarint(buf)
+		if n <= 0 || uint64(len(buf)-n) < size {
+			break
+		}
+		end := n + int(size)
+		if id, ok := parseCompletion(buf[n:end], true); ok {
+			t.onReceive(id, end)
+		}
+		buf = buf[end:]
+	}
+	t.unread = bytes.Clone(buf)
+}
+
+// parseCompletion returns the invocation ID of a completion (type 3)
+// message, without its framing. Only the header is decoded; the result,
+// which can be megabytes, is left for signalr.
+func parseCompletion(msg []byte, binaryMsg bool) (string, bool) {
+	if !binaryMsg {
+		return completionIDJSON(msg)
+	}
+
+	// [type, headers, invocationId, ...]
+	dec := msgpack.NewDecoder(bytes.NewReader(msg))
+	if l, err := dec.DecodeArrayLen(); err != nil || l < 3 {
+		return "", false
+	}
+	if typ, err := dec.DecodeInt(); err != nil || typ != 3 {
+		return "", false
+	}
+	if err := dec.Skip(); err != nil {
+		return "", false
+	}
+	id, err := dec.DecodeString()
+	return id, err == nil && id != ""
+}
+
+// completionIDJSON reads keys until it has type and invocationId. signalr
+// writes both before the result, so the result is normally never scanned;
+// keys before them are skipped as raw values.
+func completionIDJSON(msg []byte) (string, bool) {
+	dec := json.NewDecoder(bytes.NewReader(msg))
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+		return "", false
+	}
+
+	var (
+		id      string
+		hasType bool
+	)
+	for dec.More() && (!hasType || id == "") {
+		key, err := dec.Token()
+		if err != nil {
+			return "", false
+		}
+		switch key {
+		case "type":
+			var typ int
+			if dec.Decode(&typ) != nil || typ != 3 {
+				return "", false
+			}
+			hasType = true
+		case "invocationId":
+			if dec.Decode(&id) != nil {
+				return "", false
+			}
+		default:
+			var skip json.RawMessage
+			if dec.Decode(&skip) != nil {
+				return "", false
+			}
+		}
+	}
+	return id, hasType && id != ""
+}
+
+// signalr sets the transfer mode on connections that support one
+func (t *tracedConnection) TransferMode() signalr.TransferMode {
+	if m, ok := t.Connection.(signalr.ConnectionWithTransferMode); ok {
+		return m.TransferMode()
+	}
+	return signalr.TextTransferMode
+}
+
+func (t *tracedConnection) SetTransferMode(mode signalr.TransferMode) {
+	if m, ok := t.Connection.(signalr.ConnectionWithTransferMode); ok {
+		m.SetTransferMode(mode)
+	}
+}
+
+// signalr writes one message per Write; only invocations that expect a
+// completion (type 1 with an ID) are reported
+func parseInvocation(p []byte, binaryFrame bool) (wireInvocation, bool) {
+	if !binaryFrame {
+		var msg struct {
+			Type         int    `json:"type"`
+			InvocationID string `json:"invocationId"`
+			Target       string `json:"target"`
+		}
+		if json.Unmarshal(bytes.TrimSuffix(p, []byte{0x1e}), &msg) != nil || msg.Type != 1 || msg.InvocationID == "" {
+			return wireInvocation{}, false
+		}
+		return wireInvocation{id: msg.InvocationID, target: msg.Target}, true
+	}
+
+	// varint length, then [type, headers, invocationId, target, ...]
+	_, n := binary.Uvarint(p)
+	if n <= 0 {
+		return wireInvocation{}, false
+	}
+	dec := msgpack.NewDecoder(bytes.NewReader(p[n:]))
+	if l, err := dec.DecodeArrayLen(); err != nil || l < 4 {
+		return wireInvocation{}, false
+	}
+	if typ, err := dec.DecodeInt(); err != nil || typ != 1 {
+		return wireInvocation{}, false
+	}
+	if err := dec.Skip(); err != nil {
+		return wireInvocation{}, false
+	}
+	id, err := dec.DecodeString()
+	if err != nil || id == "" {
+		return wireInvocation{}, false
+	}
+	target, err := dec.DecodeString()
+	if err != nil {
+		return wireInvocation{}, false
+	}
+	return wireInvocation{id: id, target: target}, true
+}
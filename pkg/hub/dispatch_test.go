@@ -0,0 +1,147 @@
+package hub
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+func TestParseCompletion(t *testing.T) {
+	cases := []struct {
+		name, msg string
+		id        string
+		ok        bool
+	}{
+		{"completion", `{"type":3,"invocationId":"7","result":{"count":3}}`, "7", true},
+		{"id first", `{"invocationId":"8","type":3,"result":null}`, "8", true},
+		{"other keys first", `{"headers":{"a":"b"},"type":3,"invocationId":"9"}`, "9", true},
+		{"invocation", `{"type":1,"invocationId":"7","target":"GetDailyQuests","arguments":[]}`, "", false},
+		{"ping", `{"type":6}`, "", false},
+		{"no id", `{"type":3,"result":1}`, "", false},
+		// the result is not scanned once the header is known
+		{"result unread", `{"type":3,"invocationId":"10","result":{"count":`, "10", true},
+		{"not an object", `[3,"7"]`, "", false},
+	}
+	for _, tc := range cases {
+		id, ok := parseCompletion([]byte(tc.msg), false)
+		if id != tc.id || ok != tc.ok {
+			t.Errorf("%s: got %q, %v; want %q, %v", tc.name, id, ok, tc.id, tc.ok)
+		}
+	}
+
+	var buf bytes.Buffer
+	enc := msgpack.NewEncoder(&buf)
+	if err := enc.Encode([]interface{}{3, map[string]string{}, "11", 3, map[string]int{"count": 3}}); err != nil {
+		t.Fatal(err)
+	}
+	if id, ok := parseCompletion(buf.Bytes(), true); id != "11" || !ok {
+		t.Errorf("msgpack: got %q, %v; want 11", id, ok)
+	}
+}
+
+func BenchmarkParseCompletion(b *testing.B) {
+	msg := append([]byte(`{"type":3,"invocationId":"7","result":[`), bytes.Repeat([]byte(`{"templateId":"ChallengeBundle:Week_001","count":3},`), 20000)...)
+	msg = append(msg, `{}]}`...)
+	b.SetBytes(int64(len(msg)))
+	for b.Loop() {
+		parseCompletion(msg, false)
+	}
+}
+
+func TestInvokeSlotsPreferInteractive(t *testing.T) {
+	ctx := context.Background()
+	s := newInvokeSlots(1)
+	if err := s.acquire(ctx, PriorityBackground); err != nil {
+		t.Fatal(err)
+	}
+
+	order := make(chan string, 3)
+	wait := func(name string, p Priority, queued int) {
+		go func() {
+			if err := s.acquire(ctx, p); err != nil {
+				t.Error(err)
+				return
+			}
+			order <- name
+			s.release()
+		}()
+		// queue them in a known order
+		for deadline := time.Now().Add(time.Second); time.Now().Before(deadline); time.Sleep(time.Millisecond) {
+			s.mu.Lock()
+			n := len(s.waiting[PriorityInteractive]) + len(s.waiting[PriorityBackground])
+			s.mu.Unlock()
+			if n == queued {
+				return
+			}
+		}
+		t.Fatalf("%s never queued", name)
+	}
+	wait("b", PriorityBackground, 1)
+	wait("i1", PriorityInteractive, 2)
+	wait("i2", PriorityInteractive, 3)
+
+	// a waiter that gives up leaves no slot behind
+	gone, stop := context.WithCancel(ctx)
+	stop()
+	if err := s.acquire(gone, PriorityInteractive); !errors.Is(err, context.Canceled) {
+		t.Fatalf("cancelled acquire: %v", err)
+	}
+
+	s.release()
+	var got []string
+	for range 3 {
+		got = append(got, <-order)
+	}
+	if want := []string{"i1", "i2", "b"}; !slices.Equal(got, want) {
+		t.Errorf("served %v, want %v", got, want)
+	}
+	// the last release follows its send on order
+	quick, cancel := context.WithTimeout(ctx, time.Second)
+	defer cancel()
+	if err := s.acquire(quick, PriorityBackground); err != nil {
+		t.Errorf("slot not free after every release: %v", err)
+	}
+}
+
+func TestParseInvocation(t *testing.T) {
+	frame := []byte(`{"type":1,"invocationId":"4","target":"GetDailyQuests","arguments":[]}` + "\x1e")
+	if w, ok := parseInvocation(frame, false); !ok || w.id != "4" || w.target != "GetDailyQuests" {
+		t.Errorf("json invocation: %+v, %v", w, ok)
+	}
+	// a send without an ID expects no completion
+	if _, ok := parseInvocation([]byte(`{"type":1,"target":"Ack","arguments":[]}`+"\x1e"), false); ok {
+		t.Error("non-blocking send reported")
+	}
+
+	var buf bytes.Buffer
+	if err := msgpack.NewEncoder(&buf).Encode([]interface{}{1, map[string]string{}, "5", "GetDailyQuest", []string{"Quest_A"}}); err != nil {
+		t.Fatal(err)
+	}
+	binFrame := binary.AppendUvarint(nil, uint64(buf.Len()))
+	binFrame = append(binFrame, buf.Bytes()...)
+	if w, ok := parseInvocation(binFrame, true); !ok || w.id != "5" || w.target != "GetDailyQuest" {
+		t.Errorf("msgpack invocation: %+v, %v", w, ok)
+	}
+}
+
+// completions split across reads are still matched to their invocation
+func TestScanCompletions(t *testing.T) {
+	got := map[string]int{}
+	tc := &tracedConnection{onReceive: func(id string, n int) { got[id] = n }}
+
+	first := `{"type":3,"invocationId":"1","result":1}` + "\x1e"
+	second := `{"type":3,"invocationId":"2","result":[1,2,3]}` + "\x1e"
+	stream := "{}\x1e" + first + `{"type":6}` + "\x1e" + second
+	for i := 0; i < len(stream); i += 7 {
+		tc.scanCompletions([]byte(stream[i:min(i+7, len(stream))]))
+	}
+	if got["1"] != len(first) || got["2"] != len(second) || len(got) != 2 {
+		t.Errorf("completions = %v", got)
+	}
+}
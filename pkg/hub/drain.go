@@ -0,0 +1,95 @@
+package hub
+
+import (
+	"context"
+	"sync"
+)
+
+// inflightCalls counts invocations waiting on the hub so a graceful
+// disconnect can let them finish
+type inflightCalls struct {
+	mu       sync.Mutex
+	n        int
+	draining bool
+	idle     chan struct{}
+}
+
+// false once draining has started; the call must not go to the hub
+func (f *inflightCalls) enter() bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.draining {
+		return false
+	}
+	f.n++
+	return true
+}
+
+func (f *inflightCalls) leave() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.n--
+	if f.n == 0 && f.idle != nil {
+		close(f.idle)
+		f.idle = nil
+	}
+}
+
+// drain refuses new calls; the channel closes once the running ones finish
+func (f *inflightCalls) drain() <-chan struct{} {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.draining = true
+	if f.n == 0 {
+		done := make(chan struct{})
+		close(done)
+		return done
+	}
+	if f.idle == nil {
+		f.idle = make(chan struct{})
+	}
+	return f.idle
+}
+
+func (f *inflightCalls) reset() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.draining = false
+}
+
+func (f *inflightCalls) count() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.n
+}
+
+// InFlight reports how many invocations are waiting on the hub
+func (c *Client) InFlight() int {
+	return c.inflight.count()
+}
+
+// DisconnectGraceful stops accepting new calls, waits for the ones already
+// sent to complete and then disconnects. If ctx ends first the remaining
+// calls are cut off as with Disconnect and ctx's error is returned.
+func (c *Client) DisconnectGraceful(ctx context.Context) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	defer c.inflight.reset()
+
+	var err error
+	select {
+	case <-c.inflight.drain():
+	case <-ctx.Done():
+		err = ctx.Err()
+		c.logger.Warn("Disconnecting with %d calls still in flight: %v", c.InFlight(), err)
+	}
+
+	if derr := c.Disconnect(); derr != nil {
+		return derr
+	}
+	return err
+}
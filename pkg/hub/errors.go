@@ -1,6 +1,10 @@
 package hub
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
 
 var (
 	ErrNotConnected = errors.New("client is not connected")
@@ -11,6 +15,10 @@ var (
 
 	ErrInvalidTemplateID = errors.New("invalid template ID")
 
+	ErrInvalidWeek = errors.New("invalid week")
+
+	ErrInvalidPage = errors.New("invalid page request")
+
 	ErrConnectionTimeout = errors.New("connection timeout")
 
 	ErrInvokeFailed = errors.New("hub method invocation failed")
@@ -18,4 +26,72 @@ var (
 	ErrQuestNotFound = errors.New("quest not found")
 
 	ErrBundleNotFound = errors.New("bundle not found")
+
+	ErrCertMismatch = errors.New("hub certificate does not match pin")
+
+	ErrTransportUnsupported = errors.New("transport not supported")
+
+	ErrConnectionLost = errors.New("connection lost during call")
+
+	ErrBudgetExhausted = errors.New("latency budget exhausted")
+
+	ErrRetryBudgetExhausted = errors.New("retry budget exhausted")
+
+	ErrDraining = errors.New("client is draining for disconnect")
+
+	ErrMethodNotFound = errors.New("hub method not found")
+
+	ErrInvalidConfig = errors.New("invalid client config")
+
+	ErrUnhealthy = errors.New("hub client unhealthy")
+
+	ErrResponseTooLarge = errors.New("hub response too large")
+
+	ErrRateLimited = errors.New("rate limited")
+
+	ErrCircuitOpen = errors.New("circuit breaker open")
+
+	ErrInvalidCachePattern = errors.New("invalid cache pattern")
+
+	ErrInvalidJobID = errors.New("invalid refresh job ID")
+
+	ErrRefreshFailed = errors.New("cache refresh failed")
 )
+
+// collects independent failures from batch operations
+type MultiError struct {
+	Errors []error
+}
+
+func (m *MultiError) Add(err error) {
+	if err != nil {
+		m.Errors = append(m.Errors, err)
+	}
+}
+
+// ErrOrNil returns nil when no errors were added so callers can return it directly
+func (m *MultiError) ErrOrNil() error {
+	if m == nil || len(m.Errors) == 0 {
+		return nil
+	}
+	return m
+}
+
+func (m *MultiError) Error() string {
+	switch len(m.Errors) {
+	case 0:
+		return "no errors"
+	case 1:
+		return m.Errors[0].Error()
+	}
+
+	msgs := make([]string, len(m.Errors))
+	for i, err := range m.Errors {
+		msgs[i] = err.Error()
+	}
+	return fmt.Sprintf("%d errors: %s", len(m.Errors), strings.Join(msgs, "; "))
+}
+
+func (m *MultiError) Unwrap() []error {
+	return m.Errors
+}
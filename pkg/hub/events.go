@@ -0,0 +1,112 @@
+package hub
+
+import (
+	"fmt"
+	"slices"
+	"time"
+)
+
+// events a subscriber may fall behind by before it misses some
+const eventBuffer = 64
+
+// EventKind says what an Event reports and so which of its fields are set
+type EventKind int
+
+const (
+	// the hub sent Ready; Ready and Initial are set
+	EventReady EventKind = iota + 1
+	// the connection was lost; Err says why
+	EventDisconnected
+	// a new connection replaced a lost one or, with Resumed, a dropped
+	// websocket was resumed after Downtime, see WithStatefulReconnect
+	EventReconnected
+	// OldState and NewState are set
+	EventStateChanged
+	// a hub call failed after any retries; Method and Err are set
+	EventInvokeFailed
+	// the hub pushed a quest update; Quest is set
+	EventQuestUpdated
+	// the hub pushed a bundle update; Bundle is set
+	EventBundleUpdated
+	// the hub pushed new schedules; Schedule is set
+	EventScheduleChanged
+)
+
+func (k EventKind) String() string {
+	switch k {
+	case EventReady:
+		return "Ready"
+	case EventDisconnected:
+		return "Disconnected"
+	case EventReconnected:
+		return "Reconnected"
+	case EventStateChanged:
+		return "StateChanged"
+	case EventInvokeFailed:
+		return "InvokeFailed"
+	case EventQuestUpdated:
+		return "QuestUpdated"
+	case EventBundleUpdated:
+		return "BundleUpdated"
+	case EventScheduleChanged:
+		return "ScheduleChanged"
+	default:
+		return fmt.Sprintf("EventKind(%d)", int(k))
+	}
+}
+
+// Event is something that happened to the client, as delivered by Events
+type Event struct {
+	Kind EventKind
+	Time time.Time
+
+	Ready   ReadyStatus
+	Initial bool
+
+	OldState ConnectionState
+	NewState ConnectionState
+
+	Resumed  bool
+	Downtime time.Duration
+
+	Method string
+	Err    error
+
+	Quest    QuestUpdate
+	Bundle   BundleUpdate
+	Schedule ScheduleChange
+}
+
+// Events delivers the client's events on a channel, for applications built
+// around select rather than callbacks; with kinds given, only those. Call
+// the returned func to stop, which closes the channel. Events are sent
+// without waiting, so a receiver more than 64 behind misses some, where the
+// On* handlers see all.
+func (c *Client) Events(kinds ...EventKind) (<-chan Event, func()) {
+	var keep func(Event) bool
+	if len(kinds) > 0 {
+		keep = func(e Event) bool { return slices.Contains(kinds, e.Kind) }
+	}
+	return c.events.SubscribeFunc(keep)
+}
+
+// handleEvents runs fn through the handler queue for every event of kind,
+// which is how the On* handlers are driven. The returned func removes it.
+func (c *Client) handleEvents(kind EventKind, fn func(Event)) func() {
+	return c.events.Handle(func(e Event) {
+		if e.Kind == kind {
+			c.runHandler(func() { fn(e) })
+		}
+	})
+}
+
+// EventsDropped counts the events subscribers missed by falling behind
+func (c *Client) EventsDropped() uint64 {
+	return c.events.Dropped()
+}
+
+// emit publishes e to the Events subscribers. Safe to call with c.mu held.
+func (c *Client) emit(e Event) {
+	e.Time = time.Now()
+	c.events.Publish(e)
+}
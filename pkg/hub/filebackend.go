@@ -0,0 +1,223 @@
+package hub
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+)
+
+// ExportFormatVersion is the newest layout of the export package's snapshot
+// files, which FileBackend reads; export.FormatVersion is this constant
+const ExportFormatVersion = 1
+
+// FileBackend answers the Client read methods from a snapshot on disk, so CI
+// and local development can run against canned data without a live hub. It
+// reads files written by the export package as well as bare Snapshot JSON.
+type FileBackend struct {
+	path string
+
+	mu   sync.RWMutex
+	snap *Snapshot
+}
+
+// NewFileBackend loads path, or the newest questhub-*.json in it when path
+// is a directory
+func NewFileBackend(path string) (*FileBackend, error) {
+	b := &FileBackend{path: path}
+	if err := b.reload(); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// NewSnapshotBackend serves a snapshot already in memory
+func NewSnapshotBackend(snap *Snapshot) (*FileBackend, error) {
+	if snap == nil {
+		return nil, errors.New("nil snapshot")
+	}
+	return &FileBackend{snap: snap.Clone()}, nil
+}
+
+func (b *FileBackend) reload() error {
+	file, err := resolveSnapshotFile(b.path)
+	if err != nil {
+		return err
+	}
+
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return err
+	}
+
+	var doc struct {
+		FormatVersion int `json:"formatVersion"`
+		Snapshot
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return fmt.Errorf("snapshot %s: %w", file, err)
+	}
+	if doc.FormatVersion > ExportFormatVersion {
+		return fmt.Errorf("snapshot %s: unsupported format version %d", file, doc.FormatVersion)
+	}
+
+	b.mu.Lock()
+	b.snap = &doc.Snapshot
+	b.mu.Unlock()
+	return nil
+}
+
+func resolveSnapshotFile(path string) (string, error) {
+	fi, err := os.Stat(path)
+	if err != nil {
+		return "", err
+	}
+	if !fi.IsDir() {
+		return path, nil
+	}
+
+	// export file names embed the UTC time, so they sort chronologically
+	matches, err := filepath.Glob(filepath.Join(path, "questhub-*.json"))
+	if err != nil {
+		return "", err
+	}
+	if len(matches) == 0 {
+		return "", fmt.Errorf("no questhub-*.json snapshots in %s", path)
+	}
+	slices.Sort(matches)
+	return matches[len(matches)-1], nil
+}
+
+func (b *FileBackend) current(ctx context.Context) (*Snapshot, error) {
+	if ctx != nil && ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.snap, nil
+}
+
+func (b *FileBackend) GetServiceStatus(ctx context.Context, _ ...CallOption) (*ServiceStatus, error) {
+	snap, err := b.current(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if snap.Status == nil {
+		return nil, ErrNotInitialized
+	}
+	status := *snap.Status
+	return &status, nil
+}
+
+func (b *FileBackend) GetDailyQuests(ctx context.Context, _ ...CallOption) (map[string]BaseQuest, error) {
+	snap, err := b.current(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(map[string]BaseQuest, len(snap.DailyQuests))
+	for id, q := range snap.DailyQuests {
+		out[id] = q.Clone()
+	}
+	return out, nil
+}
+
+func (b *FileBackend) GetDailyQuest(ctx context.Context, questID string, _ ...CallOption) (*BaseQuest, error) {
+	if questID == "" {
+		return nil, ErrInvalidQuestID
+	}
+
+	snap, err := b.current(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	q, ok := snap.DailyQuests[questID]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrQuestNotFound, questID)
+	}
+	q = q.Clone()
+	return &q, nil
+}
+
+func (b *FileBackend) GetChallengeBundles(ctx context.Context, _ ...CallOption) ([]AthenaChallengeBundle, error) {
+	snap, err := b.current(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]AthenaChallengeBundle, len(snap.Bundles))
+	for i, bundle := range snap.Bundles {
+		out[i] = bundle.Clone()
+	}
+	return out, nil
+}
+
+func (b *FileBackend) GetChallengeBundle(ctx context.Context, templateID string, _ ...CallOption) (*AthenaChallengeBundle, error) {
+	if templateID == "" {
+		return nil, ErrInvalidTemplateID
+	}
+
+	snap, err := b.current(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, bundle := range snap.Bundles {
+		if bundle.TemplateID == templateID {
+			out := bundle.Clone()
+			return &out, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", ErrBundleNotFound, templateID)
+}
+
+func (b *FileBackend) GetChallengeBundleSchedules(ctx context.Context, _ ...CallOption) ([]ChallengeBundleSchedule, error) {
+	snap, err := b.current(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return slices.Clone(snap.Schedules), nil
+}
+
+// Snapshot returns a copy of the whole dataset
+func (b *FileBackend) Snapshot(ctx context.Context) (*Snapshot, error) {
+	snap, err := b.current(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return snap.Clone(), nil
+}
+
+// ClearCache has nothing to clear; it reports success like the hub would
+func (b *FileBackend) ClearCache(ctx context.Context, _ ...CallOption) (*CacheResult, error) {
+	snap, err := b.current(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &CacheResult{Success: true, Timestamp: time.Now().UTC()}
+	if snap.Status != nil {
+		res.Version = snap.Status.Version
+	}
+	return res, nil
+}
+
+// RefreshCache reloads the snapshot from disk, picking up a newer export
+// when the backend was opened on a directory. The reload is done by the time
+// it returns, so there is no job ID.
+func (b *FileBackend) RefreshCache(ctx context.Context, _ ...CallOption) (string, error) {
+	if ctx != nil && ctx.Err() != nil {
+		return "", ctx.Err()
+	}
+	if b.path == "" {
+		return "", nil
+	}
+	return "", b.reload()
+}
@@ -0,0 +1,125 @@
+package hub
+
+import (
+	"runtime/debug"
+	"sync"
+	"sync/atomic"
+)
+
+// calls that may wait for a worker by default, see WithHandlerQueue
+const defaultHandlerQueue = 1024
+
+// handlerRunner runs registered callbacks off the receive loop. With no
+// worker limit every call gets its own goroutine; with one, calls queue up
+// and at most that many run at once. A full queue drops the call.
+type handlerRunner struct {
+	workers  int
+	maxQueue int
+
+	mu      sync.Mutex
+	queue   []func()
+	running int
+	dropped atomic.Uint64
+}
+
+// submit reports false when the queue was full and fn was dropped
+func (r *handlerRunner) submit(fn func()) bool {
+	if r.workers <= 0 {
+		go fn()
+		return true
+	}
+
+	r.mu.Lock()
+	limit := r.maxQueue
+	if limit <= 0 {
+		limit = defaultHandlerQueue
+	}
+	if len(r.queue) >= limit {
+		r.mu.Unlock()
+		r.dropped.Add(1)
+		return false
+	}
+
+	r.queue = append(r.queue, fn)
+	if r.running >= r.workers {
+		r.mu.Unlock()
+		return true
+	}
+	r.running++
+	r.mu.Unlock()
+
+	go r.work()
+	return true
+}
+
+// workers exit once the queue is empty, so an idle client holds none
+func (r *handlerRunner) work() {
+	for {
+		r.mu.Lock()
+		if len(r.queue) == 0 {
+			r.running--
+			r.mu.Unlock()
+			return
+		}
+		fn := r.queue[0]
+		r.queue[0] = nil
+		r.queue = r.queue[1:]
+		r.mu.Unlock()
+
+		fn()
+	}
+}
+
+// runHandler calls fn in the background. A panic in fn is recovered and
+// passed to the OnHandlerError hooks rather than crashing the process.
+// Safe to call with c.mu held.
+func (c *Client) runHandler(fn func()) {
+	ok := c.handlers.submit(func() {
+		defer c.recoverHandler()
+		fn()
+	})
+	if !ok {
+		c.metrics.handlerDropped()
+		c.logger.Warn("Handler queue full, dropped a handler call (%d so far)", c.handlers.dropped.Load())
+	}
+}
+
+// HandlerDrops counts handler calls dropped because the queue of
+// WithHandlerWorkers was full
+func (c *Client) HandlerDrops() uint64 {
+	return c.handlers.dropped.Load()
+}
+
+func (c *Client) recoverHandler() {
+	r := recover()
+	if r == nil {
+		return
+	}
+	c.logger.Error("Handler panicked: %v\n%s", r, debug.Stack())
+
+	c.mu.RLock()
+	hooks := append([]func(interface{}){}, c.handlerErrorHandlers...)
+	c.mu.RUnlock()
+
+	for _, h := range hooks {
+		c.reportHandlerError(h, r)
+	}
+}
+
+func (c *Client) reportHandlerError(h func(interface{}), value interface{}) {
+	defer func() {
+		if r := recover(); r != nil {
+			c.logger.Error("Handler error hook panicked: %v", r)
+		}
+	}()
+	h(value)
+}
+
+// OnHandlerError is called with the recovered value whenever a handler
+// registered on the client panics. It runs on the panicking handler's
+// goroutine, after the panic has been logged.
+func (c *Client) OnHandlerError(handler func(interface{})) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.handlerErrorHandlers = append(c.handlerErrorHandlers, handler)
+}
@@ -0,0 +1,112 @@
+package hub
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// HealthChecker is anything HealthHandler can probe, e.g. a Client or a Pool
+type HealthChecker interface {
+	Healthz(ctx context.Context) error
+}
+
+var (
+	_ HealthChecker = (*Client)(nil)
+	_ HealthChecker = (*Pool)(nil)
+)
+
+type healthCache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	checked time.Time
+	err     error
+}
+
+// Healthz reports whether the client can serve reads: it is connected, the
+// hub answers GetServiceStatus and has finished initializing. Results are
+// reused for the WithHealthCacheTTL window, 2s by default, so frequent
+// probes do not each cost a round trip.
+func (c *Client) Healthz(ctx context.Context) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	c.health.mu.Lock()
+	if c.health.ttl > 0 && !c.health.checked.IsZero() && time.Since(c.health.checked) < c.health.ttl {
+		err := c.health.err
+		c.health.mu.Unlock()
+		return err
+	}
+	c.health.mu.Unlock()
+
+	err := c.checkHealth(ctx)
+
+	// a probe that gave up says nothing about the hub
+	if ctx.Err() == nil {
+		c.health.mu.Lock()
+		c.health.checked = time.Now()
+		c.health.err = err
+		c.health.mu.Unlock()
+	}
+	return err
+}
+
+func (c *Client) checkHealth(ctx context.Context) error {
+	if !c.IsConnected() {
+		return fmt.Errorf("%w: %w", ErrUnhealthy, ErrNotConnected)
+	}
+
+	status, err := c.GetServiceStatus(withoutCache(ctx), CallPriority(PriorityInteractive))
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrUnhealthy, err)
+	}
+	if !status.Initialized {
+		return fmt.Errorf("%w: %w - version %s", ErrUnhealthy, ErrNotInitialized, status.Version)
+	}
+	return nil
+}
+
+// Healthz passes while at least one client passed its last health check
+func (p *Pool) Healthz(ctx context.Context) error {
+	if len(p.Healthy()) == 0 {
+		return fmt.Errorf("%w: %w", ErrUnhealthy, ErrNoHealthyClient)
+	}
+	return nil
+}
+
+// HealthHandler serves hc as a probe endpoint such as /healthz: 200 "ok"
+// while healthy, 503 with the error otherwise. The probe is bounded by the
+// request's context, so a probe timeout cancels the check.
+func HealthHandler(hc HealthChecker) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.Header().Set("Cache-Control", "no-store")
+
+		if err := hc.Healthz(r.Context()); err != nil {
+			if errors.Is(err, context.Canceled) && r.Context().Err() != nil {
+				// the prober went away; nobody reads the answer
+				return
+			}
+			w.WriteHeader(http.StatusServiceUnavailable)
+			if r.Method == http.MethodGet {
+				fmt.Fprintln(w, err)
+			}
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		if r.Method == http.MethodGet {
+			fmt.Fprintln(w, "ok")
+		}
+	})
+}
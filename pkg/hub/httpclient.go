@@ -0,0 +1,62 @@
+package hub
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net/http"
+)
+
+// the connection needs our own dialing whenever the caller configured the
+// http client, its TLS settings or certificate pins
+func (c *Client) customHTTP() bool {
+	return c.httpClient != nil || c.tlsConfig != nil || len(c.pinnedCerts) > 0
+}
+
+// dialHTTPClient derives the client used for negotiate and the websocket
+// handshake. The caller's client is never modified; TLS settings and pins
+// are applied to a clone of its transport.
+func (c *Client) dialHTTPClient() (*http.Client, error) {
+	base := c.httpClient
+	if base == nil {
+		base = &http.Client{}
+	}
+	if c.tlsConfig == nil && len(c.pinnedCerts) == 0 {
+		return base, nil
+	}
+
+	var transport *http.Transport
+	switch rt := base.Transport.(type) {
+	case nil:
+		transport = http.DefaultTransport.(*http.Transport).Clone()
+	case *http.Transport:
+		transport = rt.Clone()
+	default:
+		return nil, fmt.Errorf("TLS settings need an *http.Transport, got %T", rt)
+	}
+
+	cfg := &tls.Config{MinVersion: tls.VersionTLS12}
+	if c.tlsConfig != nil {
+		cfg = c.tlsConfig.Clone()
+	} else if transport.TLSClientConfig != nil {
+		cfg = transport.TLSClientConfig.Clone()
+	}
+
+	if len(c.pinnedCerts) > 0 {
+		pinned := verifyPinned(c.pinnedCerts)
+		if prev := cfg.VerifyConnection; prev != nil {
+			cfg.VerifyConnection = func(cs tls.ConnectionState) error {
+				if err := prev(cs); err != nil {
+					return err
+				}
+				return pinned(cs)
+			}
+		} else {
+			cfg.VerifyConnection = pinned
+		}
+	}
+	transport.TLSClientConfig = cfg
+
+	out := *base
+	out.Transport = transport
+	return &out, nil
+}
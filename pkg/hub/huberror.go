@@ -0,0 +1,149 @@
+package hub
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// error codes a hub may send in a structured HubException payload:
+//
+//	{"code": "...", "message": "...",
+//	 "messages": {"de": "...", "fr": "..."}, "details": {...}}
+//
+// messages and details are optional.
+const (
+	CodeQuestNotFound     = "QUEST_NOT_FOUND"
+	CodeBundleNotFound    = "BUNDLE_NOT_FOUND"
+	CodeNotInitialized    = "NOT_INITIALIZED"
+	CodeInvalidQuestID    = "INVALID_QUEST_ID"
+	CodeInvalidTemplateID = "INVALID_TEMPLATE_ID"
+	CodeMethodNotFound    = "METHOD_NOT_FOUND"
+	CodeRateLimited       = "RATE_LIMITED"
+)
+
+// HubError is a failure reported by the hub itself. It matches
+// ErrInvokeFailed and, when the code or message is recognised, the sentinel
+// for it, e.g. ErrQuestNotFound:
+//
+//	var herr *hub.HubError
+//	if errors.As(err, &herr) { ... herr.Code ... }
+type HubError struct {
+	// empty when the hub sent neither a code nor a message we recognise
+	Code    string
+	Method  string
+	Message string
+	// the language of Message when the hub localized it, see WithLocale
+	Locale string
+	// anything else the payload carried, e.g. the quest ID not found
+	Details map[string]interface{}
+
+	sentinel error
+}
+
+func (e *HubError) Error() string {
+	return fmt.Sprintf("%v: %s - %s", ErrInvokeFailed, e.Method, e.Message)
+}
+
+func (e *HubError) Unwrap() []error {
+	if e.sentinel == nil {
+		return []error{ErrInvokeFailed}
+	}
+	return []error{ErrInvokeFailed, e.sentinel}
+}
+
+var hubErrorCodes = []struct {
+	code     string
+	sentinel error
+	// lower case phrases servers without codes use
+	phrases []string
+}{
+	{CodeQuestNotFound, ErrQuestNotFound, []string{"quest not found"}},
+	{CodeBundleNotFound, ErrBundleNotFound, []string{"bundle not found"}},
+	{CodeNotInitialized, ErrNotInitialized, []string{"not initialized", "not initialised"}},
+	{CodeInvalidQuestID, ErrInvalidQuestID, []string{"invalid quest id"}},
+	{CodeInvalidTemplateID, ErrInvalidTemplateID, []string{"invalid template id"}},
+	{CodeMethodNotFound, ErrMethodNotFound, []string{"unknown method", "method does not exist"}},
+	{CodeRateLimited, ErrRateLimited, []string{"rate limit", "too many requests", "throttled"}},
+}
+
+// ASP.NET Core prefixes HubException messages with this
+const hubExceptionMarker = "HubException: "
+
+// locale picks the message from a localized payload; "" takes the default
+func parseHubError(method, locale string, err error) *HubError {
+	msg := err.Error()
+	if i := strings.Index(msg, hubExceptionMarker); i >= 0 {
+		msg = msg[i+len(hubExceptionMarker):]
+	}
+
+	herr := &HubError{Method: method, Message: msg}
+
+	var payload struct {
+		Code     string                 `json:"code"`
+		Message  string                 `json:"message"`
+		Messages map[string]string      `json:"messages"`
+		Details  map[string]interface{} `json:"details"`
+	}
+	// phrases are only matched in the default message, which is English
+	matchText := msg
+	if strings.HasPrefix(strings.TrimSpace(msg), "{") && json.Unmarshal([]byte(msg), &payload) == nil {
+		herr.Code = strings.ToUpper(payload.Code)
+		herr.Details = payload.Details
+		if payload.Message != "" {
+			herr.Message = payload.Message
+		}
+		matchText = herr.Message
+		if tag, text, ok := localized(payload.Messages, locale); ok {
+			herr.Locale, herr.Message = tag, text
+		}
+	}
+
+	lower := strings.ToLower(matchText)
+	for _, known := range hubErrorCodes {
+		if herr.Code == known.code {
+			herr.sentinel = known.sentinel
+			return herr
+		}
+		if herr.Code != "" {
+			continue
+		}
+		for _, phrase := range known.phrases {
+			if strings.Contains(lower, phrase) {
+				herr.Code = known.code
+				herr.sentinel = known.sentinel
+				return herr
+			}
+		}
+	}
+	return herr
+}
+
+// localized finds the message for locale in messages, keyed by language
+// tag: "de-AT" takes "de-AT", then "de". Tags match ignoring case and
+// either separator.
+func localized(messages map[string]string, locale string) (string, string, bool) {
+	if locale == "" || len(messages) == 0 {
+		return "", "", false
+	}
+
+	normalize := func(tag string) string {
+		return strings.ToLower(strings.ReplaceAll(tag, "_", "-"))
+	}
+	want := normalize(locale)
+	base, _, _ := strings.Cut(want, "-")
+
+	var baseTag, baseText string
+	for tag, text := range messages {
+		if text == "" {
+			continue
+		}
+		switch normalize(tag) {
+		case want:
+			return tag, text, true
+		case base:
+			baseTag, baseText = tag, text
+		}
+	}
+	return baseTag, baseText, baseTag != ""
+}
@@ -0,0 +1,103 @@
+package hub
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// sent on negotiate and the websocket handshake when the client has an
+// instance ID
+const InstanceIDHeader = "X-QuestHub-Instance-Id"
+
+// LoadOrCreateInstanceID returns the ID stored at path, generating and
+// persisting a new one the first time so it survives restarts
+func LoadOrCreateInstanceID(path string) (string, error) {
+	b, err := os.ReadFile(path)
+	if err == nil {
+		if id := strings.TrimSpace(string(b)); id != "" {
+			return id, nil
+		}
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return "", fmt.Errorf("read instance id: %w", err)
+	}
+
+	id, err := newInstanceID()
+	if err != nil {
+		return "", err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return "", fmt.Errorf("write instance id: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".instance-*")
+	if err != nil {
+		return "", fmt.Errorf("write instance id: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.WriteString(id + "\n"); err != nil {
+		tmp.Close()
+		return "", fmt.Errorf("write instance id: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return "", fmt.Errorf("write instance id: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return "", fmt.Errorf("write instance id: %w", err)
+	}
+	return id, nil
+}
+
+// random version 4 UUID
+func newInstanceID() (string, error) {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", fmt.Errorf("generate instance id: %w", err)
+	}
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+
+	h := hex.EncodeToString(b[:])
+	return h[0:8] + "-" + h[8:12] + "-" + h[12:16] + "-" + h[16:20] + "-" + h[20:], nil
+}
+
+// resolves WithInstanceIDFile once the options are applied, falling back to
+// an ID for this process only when the file cannot be used
+func (c *Client) resolveInstanceID() {
+	if c.instanceID != "" || c.instanceIDFile == "" {
+		return
+	}
+
+	id, err := LoadOrCreateInstanceID(c.instanceIDFile)
+	if err != nil {
+		c.logger.Warn("Instance ID not persisted, using a temporary one: %v", err)
+		if id, err = newInstanceID(); err != nil {
+			return
+		}
+	}
+	c.instanceID = id
+}
+
+// InstanceID is empty unless WithInstanceID or WithInstanceIDFile was used
+func (c *Client) InstanceID() string {
+	return c.instanceID
+}
+
+// sent with the negotiate request and the connection itself
+func (c *Client) identityHeaders() http.Header {
+	h := c.headers.Clone()
+	if h == nil {
+		h = make(http.Header)
+	}
+	if c.instanceID != "" {
+		h.Set(InstanceIDHeader, c.instanceID)
+	}
+	return h
+}
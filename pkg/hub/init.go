@@ -0,0 +1,207 @@
+package hub
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+type InitializationState int
+
+const (
+	// the hub has not said whether its data is loaded yet
+	InitUnknown InitializationState = iota
+	// the hub reported Initialized=false; the client polls until it flips
+	InitPending
+	InitReady
+)
+
+func (s InitializationState) String() string {
+	switch s {
+	case InitUnknown:
+		return "Unknown"
+	case InitPending:
+		return "Pending"
+	case InitReady:
+		return "Ready"
+	default:
+		return fmt.Sprintf("InitializationState(%d)", int(s))
+	}
+}
+
+// InitPolicy decides what reads do while the hub is still initializing
+type InitPolicy int
+
+const (
+	// fail reads at once with ErrNotInitialized
+	InitReject InitPolicy = iota
+	// hold reads until the hub is initialized or their context ends
+	InitQueue
+)
+
+// InitProgress is reported on every readiness poll and state change
+type InitProgress struct {
+	State   InitializationState
+	Attempt int
+	Version string
+	// zero once the hub is ready
+	NextPoll time.Duration
+	// the poll's error, if it failed
+	Err error
+}
+
+type initTracker struct {
+	state    InitializationState
+	ready    chan struct{}
+	polling  bool
+	handlers []func(InitProgress)
+
+	policy     InitPolicy
+	minBackoff time.Duration
+	maxBackoff time.Duration
+}
+
+func newInitTracker() initTracker {
+	return initTracker{
+		ready:      make(chan struct{}),
+		minBackoff: 500 * time.Millisecond,
+		maxBackoff: 30 * time.Second,
+	}
+}
+
+// InitializationState reports whether the hub has finished loading its data
+func (c *Client) InitializationState() InitializationState {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.init.state
+}
+
+// OnInitProgress is called while the client waits for an uninitialized hub
+func (c *Client) OnInitProgress(handler func(InitProgress)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.init.handlers = append(c.init.handlers, handler)
+}
+
+// WaitInitialized blocks until the hub reports Initialized=true or ctx is done
+func (c *Client) WaitInitialized(ctx context.Context) error {
+	c.mu.RLock()
+	ready := c.init.ready
+	c.mu.RUnlock()
+
+	select {
+	case <-ready:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("%w: %w", ErrNotInitialized, ctx.Err())
+	}
+}
+
+// observeInitialized records the hub's own view of its data, from Ready or
+// GetServiceStatus
+func (c *Client) observeInitialized(initialized bool, version string) {
+	c.mu.Lock()
+	prev := c.init.state
+
+	if initialized {
+		c.init.state = InitReady
+		if prev != InitReady {
+			close(c.init.ready)
+		}
+		handlers := append([]func(InitProgress){}, c.init.handlers...)
+		c.mu.Unlock()
+
+		if prev == InitPending {
+			c.logger.Info("Hub initialized - Version: %s", version)
+			c.notifyInit(handlers, InitProgress{State: InitReady, Version: version})
+		}
+		return
+	}
+
+	if prev == InitReady {
+		c.init.ready = make(chan struct{})
+	}
+	c.init.state = InitPending
+	start := !c.init.polling
+	c.init.polling = true
+	c.mu.Unlock()
+
+	if start {
+		c.logger.Warn("Hub is not initialized yet, polling until it is")
+		go c.pollInitialized()
+	}
+}
+
+func (c *Client) pollInitialized() {
+	delay := c.init.minBackoff
+	for attempt := 1; ; attempt++ {
+		// the exit check and clearing polling share the lock, so a hub that
+		// drops back to uninitialized always finds a poller running
+		c.mu.Lock()
+		if c.init.state != InitPending || c.state == StateClosed || c.ctx.Err() != nil {
+			c.init.polling = false
+			c.mu.Unlock()
+			return
+		}
+		handlers := append([]func(InitProgress){}, c.init.handlers...)
+		c.mu.Unlock()
+
+		ctx, cancel := context.WithTimeout(c.ctx, c.timeout)
+		status, err := c.GetServiceStatus(withoutCache(ctx))
+		cancel()
+
+		p := InitProgress{State: InitPending, Attempt: attempt, NextPoll: delay, Err: err}
+		if err == nil {
+			if status.Initialized {
+				// GetServiceStatus already moved the client to ready
+				continue
+			}
+			p.Version = status.Version
+		}
+
+		c.logger.Debug("Hub still initializing (attempt %d), next poll in %s", attempt, delay)
+		c.notifyInit(handlers, p)
+
+		select {
+		case <-time.After(delay):
+		case <-c.ctx.Done():
+		}
+		delay = min(delay*2, c.init.maxBackoff)
+	}
+}
+
+func (c *Client) notifyInit(handlers []func(InitProgress), p InitProgress) {
+	for _, h := range handlers {
+		c.runHandler(func() { h(p) })
+	}
+}
+
+// gates reads while the hub is initializing; GetServiceStatus always goes
+// through so readiness can still be polled
+func (c *Client) awaitInitialized(ctx context.Context, method string) error {
+	if !strings.HasPrefix(method, "Get") || method == "GetServiceStatus" {
+		return nil
+	}
+
+	c.mu.RLock()
+	state, policy, ready := c.init.state, c.init.policy, c.init.ready
+	c.mu.RUnlock()
+
+	if state != InitPending {
+		return nil
+	}
+	if policy == InitReject {
+		return fmt.Errorf("%w: %s", ErrNotInitialized, method)
+	}
+
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	select {
+	case <-ready:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("%w: %s - %v", ErrNotInitialized, method, ctx.Err())
+	}
+}
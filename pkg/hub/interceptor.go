@@ -0,0 +1,35 @@
+package hub
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// Invoker sends one invocation to the hub and returns the raw result: JSON,
+// or MessagePack for a client using ProtocolMessagePack
+type Invoker func(ctx context.Context, method string, args ...interface{}) (json.RawMessage, error)
+
+// Interceptor wraps every invocation attempt, retries included. It may change
+// ctx or args, short-circuit with its own result, or call next and inspect
+// what comes back.
+type Interceptor func(ctx context.Context, method string, args []interface{}, next Invoker) (json.RawMessage, error)
+
+// the first interceptor is the outermost
+func chainInterceptors(interceptors []Interceptor, final Invoker) Invoker {
+	next := final
+	for i := len(interceptors) - 1; i >= 0; i-- {
+		ic, inner := interceptors[i], next
+		next = func(ctx context.Context, method string, args ...interface{}) (json.RawMessage, error) {
+			return ic(ctx, method, args, inner)
+		}
+	}
+	return next
+}
+
+// invokeAttempt runs one attempt through the interceptor chain
+func (c *Client) invokeAttempt(ctx context.Context, method string, args ...interface{}) (json.RawMessage, error) {
+	if c.invoker == nil {
+		return c.invokeOnce(ctx, method, args...)
+	}
+	return c.invoker(ctx, method, args...)
+}
@@ -0,0 +1,26 @@
+package hub
+
+import (
+	"math/rand/v2"
+	"time"
+)
+
+// startupDelay holds back the first Connect by a random duration below the
+// WithStartupJitter limit. Concurrent first calls all wait for the same delay.
+func (c *Client) startupDelay() {
+	if c.startupJitter <= 0 {
+		return
+	}
+
+	c.startupOnce.Do(func() {
+		d := rand.N(c.startupJitter)
+		c.logger.Info("Delaying first connect by %s", d.Round(time.Millisecond))
+
+		timer := time.NewTimer(d)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+		case <-c.ctx.Done():
+		}
+	})
+}
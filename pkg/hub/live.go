@@ -0,0 +1,148 @@
+package hub
+
+import (
+	"encoding/json"
+	"sort"
+	"sync/atomic"
+	"time"
+)
+
+// LiveSnapshot holds the newest complete snapshot. Stores swap in a new view,
+// so readers holding an older view keep a consistent picture.
+type LiveSnapshot struct {
+	view atomic.Pointer[SnapshotView]
+}
+
+// Store copies s once; later changes to s do not reach readers
+func (l *LiveSnapshot) Store(s *Snapshot) {
+	l.swap(s)
+}
+
+// swap stores s and returns the change in estimated resident bytes
+func (l *LiveSnapshot) swap(s *Snapshot) (delta int64) {
+	v := newSnapshotView(s.Clone())
+	if old := l.view.Swap(v); old != nil {
+		return v.size - old.size
+	}
+	return v.size
+}
+
+// nil until the first Store
+func (l *LiveSnapshot) Load() *SnapshotView {
+	return l.view.Load()
+}
+
+// SnapshotView is a read-only facade over one snapshot and safe for concurrent
+// use. Its data is never modified after construction, and every accessor
+// returns copies, so callers may change what they get back freely.
+type SnapshotView struct {
+	snap    *Snapshot
+	bundles map[string]int
+	size    int64
+}
+
+func newSnapshotView(s *Snapshot) *SnapshotView {
+	v := &SnapshotView{snap: s, bundles: make(map[string]int, len(s.Bundles))}
+	for i, b := range s.Bundles {
+		v.bundles[b.TemplateID] = i
+	}
+
+	// the encoded size is a fair proxy for what the decoded data holds
+	if raw, err := json.Marshal(s); err == nil {
+		v.size = int64(len(raw))
+	}
+	return v
+}
+
+func (v *SnapshotView) TakenAt() time.Time {
+	return v.snap.TakenAt
+}
+
+func (v *SnapshotView) Provenance() Provenance {
+	return v.snap.Provenance.clone()
+}
+
+func (v *SnapshotView) Status() (ServiceStatus, bool) {
+	if v.snap.Status == nil {
+		return ServiceStatus{}, false
+	}
+	return *v.snap.Status, true
+}
+
+func (v *SnapshotView) Quest(id string) (BaseQuest, bool) {
+	q, ok := v.snap.DailyQuests[id]
+	if !ok {
+		return BaseQuest{}, false
+	}
+	return q.Clone(), true
+}
+
+// sorted
+func (v *SnapshotView) QuestIDs() []string {
+	ids := make([]string, 0, len(v.snap.DailyQuests))
+	for id := range v.snap.DailyQuests {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+func (v *SnapshotView) Bundle(templateID string) (AthenaChallengeBundle, bool) {
+	i, ok := v.bundles[templateID]
+	if !ok {
+		return AthenaChallengeBundle{}, false
+	}
+	return v.snap.Bundles[i].Clone(), true
+}
+
+func (v *SnapshotView) Bundles() []AthenaChallengeBundle {
+	return v.filterBundles(func(*AthenaChallengeBundle) bool { return true })
+}
+
+// BundlesForWeek matches the week encoded in bundle template IDs, see
+// ParseWeek. season 0 matches any season.
+func (v *SnapshotView) BundlesForWeek(season, week int) []AthenaChallengeBundle {
+	return v.filterBundles(func(b *AthenaChallengeBundle) bool {
+		s, w, ok := bundleWeek(b)
+		return ok && w == week && (season == 0 || s == season)
+	})
+}
+
+// ActiveNow returns the bundles a current schedule points at. The hub only
+// lists schedules that are running, so this is the content players can work
+// on right now.
+func (v *SnapshotView) ActiveNow() []AthenaChallengeBundle {
+	scheduled := make(map[string]bool, len(v.snap.Schedules))
+	for _, s := range v.snap.Schedules {
+		scheduled[s.QuestBundle] = true
+		scheduled[s.TemplateID] = true
+	}
+	return v.filterBundles(func(b *AthenaChallengeBundle) bool {
+		return scheduled[b.TemplateID] || scheduled[b.ChallengeBundleSchedule]
+	})
+}
+
+func (v *SnapshotView) Schedules() []ChallengeBundleSchedule {
+	return append([]ChallengeBundleSchedule(nil), v.snap.Schedules...)
+}
+
+// Snapshot returns a deep copy of the underlying snapshot
+func (v *SnapshotView) Snapshot() *Snapshot {
+	return v.snap.Clone()
+}
+
+func (v *SnapshotView) filterBundles(keep func(*AthenaChallengeBundle) bool) []AthenaChallengeBundle {
+	var out []AthenaChallengeBundle
+	for i := range v.snap.Bundles {
+		if keep(&v.snap.Bundles[i]) {
+			out = append(out, v.snap.Bundles[i].Clone())
+		}
+	}
+	return out
+}
+
+// Live returns a view of the newest complete snapshot the client has taken,
+// or nil before the first one
+func (c *Client) Live() *SnapshotView {
+	return c.live.Load()
+}
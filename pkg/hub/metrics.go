@@ -0,0 +1,262 @@
+package hub
+
+import (
+	"errors"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type metrics struct {
+	invocations *prometheus.CounterVec
+	latency     *prometheus.HistogramVec
+	errors      *prometheus.CounterVec
+	reconnects  prometheus.Counter
+	bytes       *prometheus.CounterVec
+	evictions   *prometheus.CounterVec
+	lookups     *prometheus.CounterVec
+	refresh     *prometheus.HistogramVec
+	cacheSize   prometheus.Gauge
+	liveSize    prometheus.Gauge
+	retryDenied *prometheus.CounterVec
+	handlerDrop prometheus.Counter
+	drift       *prometheus.CounterVec
+}
+
+// with an instance ID every collector carries it as the client_instance label
+func newMetrics(reg prometheus.Registerer, instanceID string) *metrics {
+	if instanceID != "" {
+		reg = prometheus.WrapRegistererWith(prometheus.Labels{"client_instance": instanceID}, reg)
+	}
+
+	m := &metrics{
+		invocations: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "questhub",
+			Subsystem: "client",
+			Name:      "invocations_total",
+			Help:      "Hub invocations by method and result.",
+		}, []string{"method", "result"}),
+		latency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: "questhub",
+			Subsystem: "client",
+			Name:      "invoke_duration_seconds",
+			Help:      "Latency of hub invocations.",
+			Buckets:   prometheus.ExponentialBuckets(0.005, 2, 14),
+		}, []string{"method"}),
+		errors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "questhub",
+			Subsystem: "client",
+			Name:      "errors_total",
+			Help:      "Failed hub invocations by method and error type.",
+		}, []string{"method", "type"}),
+		reconnects: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: "questhub",
+			Subsystem: "client",
+			Name:      "reconnect_attempts_total",
+			Help:      "Connect calls made after a previous connection, and websocket redials while resuming a session.",
+		}),
+		bytes: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "questhub",
+			Subsystem: "client",
+			Name:      "received_bytes_total",
+			Help:      "Bytes of hub results received by method.",
+		}, []string{"method"}),
+		evictions: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "questhub",
+			Subsystem: "client",
+			Name:      "cache_evictions_total",
+			Help:      "Response cache entries evicted to stay within the byte budget, by method.",
+		}, []string{"method"}),
+		lookups: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "questhub",
+			Subsystem: "client",
+			Name:      "cache_lookups_total",
+			Help:      "Response cache lookups by method and result: hit, miss, or stale when an expired entry answered a failed call.",
+		}, []string{"method", "result"}),
+		refresh: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: "questhub",
+			Subsystem: "client",
+			Name:      "cache_refresh_duration_seconds",
+			Help:      "Latency of hub calls that refilled a missed response cache entry.",
+			Buckets:   prometheus.ExponentialBuckets(0.005, 2, 14),
+		}, []string{"method"}),
+		cacheSize: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: "questhub",
+			Subsystem: "client",
+			Name:      "cache_resident_bytes",
+			Help:      "Estimated bytes held by the response cache.",
+		}),
+		liveSize: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: "questhub",
+			Subsystem: "client",
+			Name:      "live_snapshot_bytes",
+			Help:      "Estimated bytes held by the live snapshot.",
+		}),
+		retryDenied: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "questhub",
+			Subsystem: "client",
+			Name:      "retry_budget_exhausted_total",
+			Help:      "Retries skipped because the retry budget was empty.",
+		}, []string{"method"}),
+		handlerDrop: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: "questhub",
+			Subsystem: "client",
+			Name:      "handler_calls_dropped_total",
+			Help:      "Handler calls dropped because the handler queue was full.",
+		}),
+		drift: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "questhub",
+			Subsystem: "client",
+			Name:      "schema_drift_total",
+			Help:      "Problems found decoding hub results that still decoded, by method; see Client.SchemaDrift.",
+		}, []string{"method"}),
+	}
+
+	m.invocations = register(reg, m.invocations)
+	m.latency = register(reg, m.latency)
+	m.errors = register(reg, m.errors)
+	m.reconnects = register(reg, m.reconnects)
+	m.bytes = register(reg, m.bytes)
+	m.evictions = register(reg, m.evictions)
+	m.lookups = register(reg, m.lookups)
+	m.refresh = register(reg, m.refresh)
+	m.cacheSize = register(reg, m.cacheSize)
+	m.liveSize = register(reg, m.liveSize)
+	m.retryDenied = register(reg, m.retryDenied)
+	m.handlerDrop = register(reg, m.handlerDrop)
+	m.drift = register(reg, m.drift)
+	return m
+}
+
+// clients sharing a registerer share the already registered collectors
+func register[T prometheus.Collector](reg prometheus.Registerer, c T) T {
+	if err := reg.Register(c); err != nil {
+		var are prometheus.AlreadyRegisteredError
+		if errors.As(err, &are) {
+			if existing, ok := are.ExistingCollector.(T); ok {
+				return existing
+			}
+		}
+	}
+	return c
+}
+
+func (m *metrics) observe(method string, start time.Time, bytes int, err error) {
+	if m == nil {
+		return
+	}
+
+	m.latency.WithLabelValues(method).Observe(time.Since(start).Seconds())
+	if err != nil {
+		m.invocations.WithLabelValues(method, "error").Inc()
+		m.errors.WithLabelValues(method, errorType(err)).Inc()
+		return
+	}
+
+	m.invocations.WithLabelValues(method, "ok").Inc()
+	m.bytes.WithLabelValues(method).Add(float64(bytes))
+}
+
+func (m *metrics) cacheHit(method string) {
+	if m == nil {
+		return
+	}
+	m.invocations.WithLabelValues(method, "cached").Inc()
+}
+
+func (m *metrics) coalesced(method string) {
+	if m == nil {
+		return
+	}
+	m.invocations.WithLabelValues(method, "coalesced").Inc()
+}
+
+func (m *metrics) decodeError(method string) {
+	if m == nil {
+		return
+	}
+	m.errors.WithLabelValues(method, "decode").Inc()
+}
+
+func (m *metrics) schemaDrift(method string) {
+	if m == nil {
+		return
+	}
+	m.drift.WithLabelValues(method).Inc()
+}
+
+func (m *metrics) reconnect() {
+	if m == nil {
+		return
+	}
+	m.reconnects.Inc()
+}
+
+func errorType(err error) string {
+	switch {
+	case errors.Is(err, ErrNotConnected):
+		return "not_connected"
+	case errors.Is(err, ErrDraining):
+		return "draining"
+	case errors.Is(err, ErrBudgetExhausted):
+		return "budget"
+	case errors.Is(err, ErrConnectionTimeout):
+		return "timeout"
+	case errors.Is(err, ErrInvokeFailed):
+		return "invoke_failed"
+	case errors.Is(err, errDecode):
+		return "decode"
+	default:
+		return "other"
+	}
+}
+
+func (m *metrics) cacheEviction(method string) {
+	if m == nil {
+		return
+	}
+	m.evictions.WithLabelValues(method).Inc()
+}
+
+func (m *metrics) cacheLookup(method, result string) {
+	if m == nil {
+		return
+	}
+	m.lookups.WithLabelValues(method, result).Inc()
+}
+
+func (m *metrics) cacheRefresh(method string, took time.Duration) {
+	if m == nil {
+		return
+	}
+	m.refresh.WithLabelValues(method).Observe(took.Seconds())
+}
+
+// gauges move by deltas so clients sharing a registerer add up
+func (m *metrics) cacheBytes(delta int64) {
+	if m == nil {
+		return
+	}
+	m.cacheSize.Add(float64(delta))
+}
+
+func (m *metrics) liveBytes(delta int64) {
+	if m == nil {
+		return
+	}
+	m.liveSize.Add(float64(delta))
+}
+
+func (m *metrics) retryBudgetExhausted(method string) {
+	if m == nil {
+		return
+	}
+	m.retryDenied.WithLabelValues(method).Inc()
+}
+
+func (m *metrics) handlerDropped() {
+	if m == nil {
+		return
+	}
+	m.handlerDrop.Inc()
+}
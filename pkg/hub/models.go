@@ -23,9 +23,20 @@ type CacheResult struct {
 }
 
 type BaseQuest struct {
-	Objectives map[string]interface{} `json:"objectives"`
-	Rewards    map[string]interface{} `json:"rewards"`
-	Count      int                    `json:"count"`
+	Objectives QuestObjectives `json:"objectives"`
+	Rewards    QuestRewards    `json:"rewards"`
+	Count      int             `json:"count"`
+}
+
+type QuestObjective struct {
+	BackendName string `json:"backendName"`
+	Count       int    `json:"count"`
+	Stage       int    `json:"stage,omitempty"`
+}
+
+type QuestReward struct {
+	TemplateID string `json:"templateId"`
+	Quantity   int    `json:"quantity"`
 }
 
 type AthenaChallengeBundle struct {
@@ -71,7 +82,70 @@ type BundleCompletionReward struct {
 	Quantity   int    `json:"quantity"`
 }
 
+// a zero ActiveFrom or ActiveUntil leaves that end of the window open; hubs
+// that predate the time window send neither
 type ChallengeBundleSchedule struct {
-	TemplateID  string `json:"templateId"`
-	QuestBundle string `json:"questBundle"`
+	TemplateID  string             `json:"templateId"`
+	QuestBundle string             `json:"questBundle"`
+	ActiveFrom  time.Time          `json:"activeFrom,omitzero"`
+	ActiveUntil time.Time          `json:"activeUntil,omitzero"`
+	Visibility  ScheduleVisibility `json:"visibility,omitempty"`
+}
+
+type ScheduleVisibility string
+
+const (
+	VisibilityPublic ScheduleVisibility = "public"
+	// hidden schedules run but are not shown to players
+	VisibilityHidden ScheduleVisibility = "hidden"
+)
+
+type PageRequest struct {
+	Offset int `json:"offset"`
+	Limit  int `json:"limit"`
+}
+
+// BundlePage is one slice of the bundle list; Total counts all bundles
+type BundlePage struct {
+	Bundles []AthenaChallengeBundle `json:"bundles"`
+	Offset  int                     `json:"offset"`
+	Total   int                     `json:"total"`
+}
+
+// SeasonInfo describes the running season; Weeks is ordered by week number
+type SeasonInfo struct {
+	Season int        `json:"season"`
+	Start  time.Time  `json:"start"`
+	End    time.Time  `json:"end"`
+	Weeks  []WeekInfo `json:"weeks"`
+}
+
+type WeekInfo struct {
+	Week     int       `json:"week"`
+	UnlockAt time.Time `json:"unlockAt"`
+}
+
+// WeeklyChallenges are the bundles that unlock in one week of the season
+type WeeklyChallenges struct {
+	Season   int                     `json:"season"`
+	Week     int                     `json:"week"`
+	UnlockAt time.Time               `json:"unlockAt"`
+	Bundles  []AthenaChallengeBundle `json:"bundles"`
+}
+
+type QuestUpdate struct {
+	QuestID string     `json:"questId"`
+	Quest   *BaseQuest `json:"quest,omitempty"`
+	Removed bool       `json:"removed,omitempty"`
+}
+
+type BundleUpdate struct {
+	TemplateID string                 `json:"templateId"`
+	Bundle     *AthenaChallengeBundle `json:"bundle,omitempty"`
+	Removed    bool                   `json:"removed,omitempty"`
+}
+
+type ScheduleChange struct {
+	Schedules []ChallengeBundleSchedule `json:"schedules"`
+	Timestamp time.Time                 `json:"timestamp"`
 }
@@ -1,7 +1,13 @@
 package hub
 
 import (
+	"crypto/tls"
+	"log/slog"
+	"net/http"
 	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/sync/singleflight"
 )
 
 type ClientOption func(*Client)
@@ -12,12 +18,426 @@ func WithTimeout(timeout time.Duration) ClientOption {
 	}
 }
 
+// WithKeepAliveInterval sends a ping after d without other traffic, so
+// proxies that drop idle connections keep quiet ones open. The signalr
+// default is 15s.
+func WithKeepAliveInterval(d time.Duration) ClientOption {
+	return func(c *Client) {
+		c.keepAlive = d
+	}
+}
+
+// WithServerTimeout closes the connection when nothing, not even a ping,
+// arrives from the hub for d. It should be about twice the hub's own
+// keepalive interval; the signalr default is 30s. signalr also counts a
+// ping of ours that writes successfully, so it only fires when d is
+// shorter than the keepalive interval or the connection stops writing.
+func WithServerTimeout(d time.Duration) ClientOption {
+	return func(c *Client) {
+		c.serverTimeout = d
+	}
+}
+
+// WithMaxResponseSize caps a single hub response at n bytes, 10 MB by
+// default. A larger frame fails the read, and a larger decoded result
+// fails the call with ErrResponseTooLarge. Non-positive values are ignored.
+func WithMaxResponseSize(n int) ClientOption {
+	return func(c *Client) {
+		if n > 0 {
+			c.maxResponseSize = n
+		}
+	}
+}
+
+// WithStatefulReconnect asks the hub for stateful reconnect, so a dropped
+// websocket is dialed again under the same connection for up to window
+// (10s when window is not positive) and nothing sent either way is lost;
+// see OnSessionResumed. Hubs that do not offer it, and transports other
+// than websockets, connect as usual. The window should stay below the
+// server timeout, which ends the connection regardless.
+func WithStatefulReconnect(window time.Duration) ClientOption {
+	return func(c *Client) {
+		if window <= 0 {
+			window = defaultResumeWindow
+		}
+		c.resumeWindow = window
+	}
+}
+
+// WithSlowCallThreshold logs a warning for every hub call that takes d or
+// longer, with its size, so pathological responses show up before they
+// hurt. Zero, the default, disables it.
+func WithSlowCallThreshold(d time.Duration) ClientOption {
+	return func(c *Client) {
+		c.slowCallThreshold = d
+	}
+}
+
+// WithStartupJitter delays the first Connect by a random duration up to d,
+// so a fleet deployed at once does not connect and fetch in the same instant
+func WithStartupJitter(d time.Duration) ClientOption {
+	return func(c *Client) {
+		c.startupJitter = d
+	}
+}
+
+// WithHandlerWorkers runs registered handlers on at most n goroutines,
+// queueing the rest, so a burst of updates cannot start a goroutine per
+// handler per event. With n of 1 handlers run one at a time in the order
+// events arrived. By default each call gets its own goroutine.
+//
+// The queue holds 1024 calls, see WithHandlerQueue; once it is full further
+// calls are dropped, logged and counted by HandlerDrops.
+func WithHandlerWorkers(n int) ClientOption {
+	return func(c *Client) {
+		c.handlers.workers = n
+	}
+}
+
+// WithHandlerQueue sets how many handler calls may wait for one of the
+// WithHandlerWorkers goroutines; zero or less keeps the default of 1024
+func WithHandlerQueue(n int) ClientOption {
+	return func(c *Client) {
+		c.handlers.maxQueue = n
+	}
+}
+
 func WithLogger(logger Logger) ClientOption {
 	return func(c *Client) {
 		c.logger = logger
 	}
 }
 
+// WithSlog sends the client's logs to l as structured records, adds one
+// record per invocation and bridges the signalr library's own logging. A
+// later WithLogger replaces the printf-style adapter but keeps the rest.
+func WithSlog(l *slog.Logger) ClientOption {
+	return func(c *Client) {
+		if l == nil {
+			return
+		}
+		c.slog = l
+		c.logger = slogLogger{l: l}
+	}
+}
+
+// WithHTTPClient negotiates and dials the websocket with hc, for proxies
+// and connection pool tuning. Connections then use websockets only, since
+// the signalr SSE transport cannot be given a client.
+func WithHTTPClient(hc *http.Client) ClientOption {
+	return func(c *Client) {
+		c.httpClient = hc
+	}
+}
+
+// WithTLSConfig sets client certificates, root CAs and other TLS settings.
+// It applies to a clone of the WithHTTPClient transport, which must be an
+// *http.Transport, and like it limits connections to websockets.
+func WithTLSConfig(cfg *tls.Config) ClientOption {
+	return func(c *Client) {
+		c.tlsConfig = cfg
+	}
+}
+
+// WithInstanceID tags logs, metrics and hub requests with a fixed ID for
+// this collector instance
+func WithInstanceID(id string) ClientOption {
+	return func(c *Client) {
+		c.instanceID = id
+	}
+}
+
+// WithInstanceIDFile is WithInstanceID with an ID generated on first use and
+// kept in path across restarts
+func WithInstanceIDFile(path string) ClientOption {
+	return func(c *Client) {
+		c.instanceIDFile = path
+	}
+}
+
+// WithUsageFile keeps the Usage report in path across restarts, so it
+// covers earlier runs too. It is saved on Disconnect and at most once a
+// minute while calls are made; clients running at once need a file each.
+func WithUsageFile(path string) ClientOption {
+	return func(c *Client) {
+		c.usageFile = path
+	}
+}
+
+// WithLocale picks the language of HubError messages when the hub sends
+// them localized, e.g. "de" or "pt-BR", falling back to the hub's default
+// message. It also sends the locale as Accept-Language, for hubs that
+// localize on their own.
+func WithLocale(locale string) ClientOption {
+	return func(c *Client) {
+		c.locale = locale
+		if locale == "" {
+			return
+		}
+		if c.headers == nil {
+			c.headers = make(http.Header)
+		}
+		c.headers.Set("Accept-Language", locale)
+	}
+}
+
+// WithHeader adds a header to the negotiate request and the connection,
+// e.g. for auth the hub checks at connect time
+func WithHeader(key, value string) ClientOption {
+	return func(c *Client) {
+		if c.headers == nil {
+			c.headers = make(http.Header)
+		}
+		c.headers.Add(key, value)
+	}
+}
+
+func WithBearerToken(token string) ClientOption {
+	return func(c *Client) {
+		if c.headers == nil {
+			c.headers = make(http.Header)
+		}
+		c.headers.Set("Authorization", "Bearer "+token)
+	}
+}
+
+func WithPinnedCert(sha256 string) ClientOption {
+	return func(c *Client) {
+		c.pinnedCerts = append(c.pinnedCerts, normalizePin(sha256))
+	}
+}
+
+// interactive and background calls draw from separate buckets so bulk
+// background work cannot starve latency-sensitive callers
+func WithPriorityBuckets(interactive, background BucketConfig) ClientOption {
+	return func(c *Client) {
+		c.buckets = map[Priority]*tokenBucket{
+			PriorityInteractive: newTokenBucket(interactive),
+			PriorityBackground:  newTokenBucket(background),
+		}
+	}
+}
+
+// WithRateLimit throttles outgoing hub calls to rps a second, with bursts of
+// up to burst calls. A call that cannot get a token before its deadline
+// fails at once with ErrRateLimited. Cache hits are not counted.
+func WithRateLimit(rps float64, burst int) ClientOption {
+	return func(c *Client) {
+		c.rateLimit = newRateLimit(rps, burst)
+	}
+}
+
+// WithMethodRateLimit gives one hub method its own limit in place of the
+// one set by WithRateLimit; a rps of zero leaves the method unthrottled
+func WithMethodRateLimit(method string, rps float64, burst int) ClientOption {
+	return func(c *Client) {
+		if c.methodLimits == nil {
+			c.methodLimits = make(map[string]*tokenBucket)
+		}
+		c.methodLimits[method] = newRateLimit(rps, burst)
+	}
+}
+
+// WithCircuitBreaker opens the circuit after threshold hub calls in a row
+// fail with a timeout, a lost connection or a hub error. While it is open,
+// calls fail at once with ErrCircuitOpen. Once cooldown has passed, a single
+// probe call goes through: success closes the circuit and failure reopens
+// it. Not-found and invalid-argument errors do not count as failures.
+func WithCircuitBreaker(threshold int, cooldown time.Duration) ClientOption {
+	return func(c *Client) {
+		if threshold <= 0 {
+			c.breaker = nil
+			return
+		}
+		c.breaker = &circuitBreaker{threshold: threshold, cooldown: cooldown}
+	}
+}
+
+// strict decoding rejects unknown fields; with fallback enabled the result is
+// decoded leniently instead and the problems are reported as schema drift
+func WithStrictDecoding(fallback bool) ClientOption {
+	return func(c *Client) {
+		c.strictDecoding = true
+		c.decodeFallback = fallback
+	}
+}
+
+// transports can be combined, e.g. TransportWebSockets|TransportServerSentEvents,
+// and are tried in that order until one connects
+func WithTransport(t Transport) ClientOption {
+	return func(c *Client) {
+		c.transports = t
+	}
+}
+
+// MessagePack needs websockets; server-sent events only carry text
+func WithHubProtocol(p HubProtocol) ClientOption {
+	return func(c *Client) {
+		c.protocol = p
+	}
+}
+
+// WithCompression compresses hub messages over websockets with
+// permessage-deflate and asks for gzip or brotli encoded HTTP responses.
+// The SSE transport keeps signalr's own HTTP handling.
+func WithCompression(c Compression) ClientOption {
+	return func(cl *Client) {
+		cl.compression = c
+	}
+}
+
+// takes a snapshot as soon as the client connects so it warms up without
+// waiting for the first caller; see OnWarm
+func WithPrefetch() ClientOption {
+	return func(c *Client) {
+		c.prefetch = true
+	}
+}
+
+// WithCallCoalescing lets concurrent identical reads (Get* with the same
+// arguments) share one hub call and its result instead of each fetching it.
+// Calls that bypass the response cache are never coalesced.
+func WithCallCoalescing() ClientOption {
+	return func(c *Client) {
+		c.coalesce = &singleflight.Group{}
+	}
+}
+
+func WithResponseCache(ttl time.Duration) ClientOption {
+	return func(c *Client) {
+		c.cache = newResponseCache(ttl)
+	}
+}
+
+// caps the estimated bytes held by the response cache and the live snapshot;
+// least recently used cache entries are evicted to stay under it
+func WithCacheBudget(maxBytes int64) ClientOption {
+	return func(c *Client) {
+		c.cacheBudget = maxBytes
+	}
+}
+
+// WithStaleIfError keeps response cache entries for maxStale past their
+// TTL, to answer calls that fail because the hub cannot be reached. Errors
+// the hub itself returns are passed on. Needs WithResponseCache.
+func WithStaleIfError(maxStale time.Duration) ClientOption {
+	return func(c *Client) {
+		c.cacheStale = maxStale
+	}
+}
+
+func WithMetrics(reg prometheus.Registerer) ClientOption {
+	return func(c *Client) {
+		c.metricsReg = reg
+	}
+}
+
+// overrides the timeout for one hub method, e.g. a longer one for
+// GetChallengeBundles; CallTimeout on a single call still wins
+func WithMethodTimeout(method string, d time.Duration) ClientOption {
+	return func(c *Client) {
+		if c.methodTimeouts == nil {
+			c.methodTimeouts = make(map[string]time.Duration)
+		}
+		c.methodTimeouts[method] = d
+	}
+}
+
+// pages fetched by ChallengeBundleIter hold up to n bundles; the default is 100
+func WithPageSize(n int) ClientOption {
+	return func(c *Client) {
+		if n > 0 {
+			c.pageSize = n
+		}
+	}
+}
+
+func WithDefaultCallOptions(opts ...CallOption) ClientOption {
+	return func(c *Client) {
+		c.defaultCallOptions = append(c.defaultCallOptions, opts...)
+	}
+}
+
+// failed reads are retried after reconnecting; zero fields fall back to
+// DefaultRetryPolicy
+func WithRetryPolicy(p RetryPolicy) ClientOption {
+	return func(c *Client) {
+		def := DefaultRetryPolicy()
+		if p.MaxAttempts <= 0 {
+			p.MaxAttempts = def.MaxAttempts
+		}
+		if p.InitialBackoff <= 0 {
+			p.InitialBackoff = def.InitialBackoff
+		}
+		if p.MaxBackoff < p.InitialBackoff {
+			p.MaxBackoff = max(def.MaxBackoff, p.InitialBackoff)
+		}
+		if p.Multiplier < 1 {
+			p.Multiplier = def.Multiplier
+		}
+		c.retry = &p
+	}
+}
+
+// shares b between every method of the client; pass the same budget to
+// several clients to cap their retries together
+func WithRetryBudget(b RetryBudget) ClientOption {
+	return func(c *Client) {
+		c.retryBudget = b
+	}
+}
+
+// WithInterceptor wraps every hub invocation with i, for logging, auth,
+// caching or fault injection. Interceptors run in the order they are added.
+func WithInterceptor(i Interceptor) ClientOption {
+	return func(c *Client) {
+		c.interceptors = append(c.interceptors, i)
+	}
+}
+
+// WithMaxConcurrentInvokes caps the calls in flight on the connection;
+// further calls queue until a slot frees or their context ends. Waiting
+// interactive calls get a freed slot before background ones, each in
+// arrival order; see WithPriority. n <= 0 means no cap.
+func WithMaxConcurrentInvokes(n int) ClientOption {
+	return func(c *Client) {
+		c.dispatcher.slots = nil
+		if n > 0 {
+			c.dispatcher.slots = newInvokeSlots(n)
+		}
+	}
+}
+
+// WithHealthCacheTTL sets how long a Healthz result is reused; zero checks
+// the hub on every call
+func WithHealthCacheTTL(d time.Duration) ClientOption {
+	return func(c *Client) {
+		c.health.ttl = d
+	}
+}
+
+// WithInitPolicy sets what reads do while the hub reports Initialized=false;
+// the default, InitReject, fails them with ErrNotInitialized
+func WithInitPolicy(p InitPolicy) ClientOption {
+	return func(c *Client) {
+		c.init.policy = p
+	}
+}
+
+// WithInitBackoff bounds how often an uninitialized hub is polled; the wait
+// doubles from initial up to maximum
+func WithInitBackoff(initial, maximum time.Duration) ClientOption {
+	return func(c *Client) {
+		if initial > 0 {
+			c.init.minBackoff = initial
+		}
+		c.init.maxBackoff = maximum
+		if c.init.maxBackoff < c.init.minBackoff {
+			c.init.maxBackoff = c.init.minBackoff
+		}
+	}
+}
+
 type Logger interface {
 	Debug(msg string, args ...interface{})
 	Info(msg string, args ...interface{})
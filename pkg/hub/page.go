@@ -0,0 +1,95 @@
+package hub
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"iter"
+	"slices"
+)
+
+func (p *BundlePage) HasMore() bool {
+	return len(p.Bundles) > 0 && p.Offset+len(p.Bundles) < p.Total
+}
+
+// Next asks for the page after this one, the same size as this one
+func (p *BundlePage) Next() PageRequest {
+	return PageRequest{Offset: p.Offset + len(p.Bundles), Limit: len(p.Bundles)}
+}
+
+// GetChallengeBundlesPage fetches one page of bundles. Hubs without paging
+// are served from GetChallengeBundles, which still transfers the whole list.
+func (c *Client) GetChallengeBundlesPage(ctx context.Context, req PageRequest, opts ...CallOption) (*BundlePage, error) {
+	if req.Offset < 0 || req.Limit <= 0 {
+		return nil, fmt.Errorf("%w: offset %d, limit %d", ErrInvalidPage, req.Offset, req.Limit)
+	}
+
+	ctx = ContextWithCallOptions(ctx, opts...)
+	out, err := Invoke[BundlePage](ctx, c, "GetChallengeBundlesPage", req)
+	if err == nil {
+		return &out, nil
+	}
+	if !errors.Is(err, ErrMethodNotFound) {
+		return nil, err
+	}
+
+	bundles, err := c.GetChallengeBundles(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return pageOf(bundles, req), nil
+}
+
+// pageOf copies the page out so it does not keep the whole list alive
+func pageOf(bundles []AthenaChallengeBundle, req PageRequest) *BundlePage {
+	start := min(req.Offset, len(bundles))
+	end := min(start+req.Limit, len(bundles))
+	return &BundlePage{
+		Bundles: slices.Clone(bundles[start:end]),
+		Offset:  req.Offset,
+		Total:   len(bundles),
+	}
+}
+
+// ChallengeBundleIter yields the bundle list page by page, see WithPageSize.
+// It stops after the first error. On hubs without paging the whole list is
+// fetched once and the pages are cut from it.
+//
+//	for page, err := range client.ChallengeBundleIter(ctx) {
+//		if err != nil { ... }
+//	}
+func (c *Client) ChallengeBundleIter(ctx context.Context, opts ...CallOption) iter.Seq2[*BundlePage, error] {
+	return func(yield func(*BundlePage, error) bool) {
+		req := PageRequest{Limit: c.pageSize}
+		ctx := ContextWithCallOptions(ctx, opts...)
+
+		var all []AthenaChallengeBundle
+		paged := true
+		for {
+			var page *BundlePage
+			if paged {
+				out, err := Invoke[BundlePage](ctx, c, "GetChallengeBundlesPage", req)
+				switch {
+				case err == nil:
+					page = &out
+				case errors.Is(err, ErrMethodNotFound):
+					paged = false
+					if all, err = c.GetChallengeBundles(ctx); err != nil {
+						yield(nil, err)
+						return
+					}
+				default:
+					yield(nil, err)
+					return
+				}
+			}
+			if !paged {
+				page = pageOf(all, req)
+			}
+			if !yield(page, nil) || !page.HasMore() {
+				return
+			}
+			req.Offset = page.Offset + len(page.Bundles)
+		}
+	}
+}
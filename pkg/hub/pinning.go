@@ -0,0 +1,36 @@
+package hub
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// pins are hex sha256 digests of either the leaf certificate (DER) or its
+// SubjectPublicKeyInfo; colons and case are ignored
+func normalizePin(pin string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(pin), ":", ""))
+}
+
+func verifyPinned(pins []string) func(tls.ConnectionState) error {
+	return func(cs tls.ConnectionState) error {
+		if len(cs.PeerCertificates) == 0 {
+			return fmt.Errorf("%w: no peer certificate", ErrCertMismatch)
+		}
+
+		leaf := cs.PeerCertificates[0]
+		certSum := sha256.Sum256(leaf.Raw)
+		keySum := sha256.Sum256(leaf.RawSubjectPublicKeyInfo)
+		certHex := hex.EncodeToString(certSum[:])
+		keyHex := hex.EncodeToString(keySum[:])
+
+		for _, pin := range pins {
+			if pin == certHex || pin == keyHex {
+				return nil
+			}
+		}
+		return fmt.Errorf("%w: got %s", ErrCertMismatch, certHex)
+	}
+}
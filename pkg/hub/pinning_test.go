@@ -0,0 +1,44 @@
+package hub
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestVerifyPinned(t *testing.T) {
+	srv := httptest.NewTLSServer(nil)
+	defer srv.Close()
+	cert := srv.Certificate()
+	cs := tls.ConnectionState{PeerCertificates: []*x509.Certificate{cert}}
+
+	certSum := sha256.Sum256(cert.Raw)
+	keySum := sha256.Sum256(cert.RawSubjectPublicKeyInfo)
+	// colon separated and upper case, as browsers show it
+	var colons []string
+	for _, b := range certSum {
+		colons = append(colons, strings.ToUpper(hex.EncodeToString([]byte{b})))
+	}
+
+	for name, pin := range map[string]string{
+		"certificate": strings.Join(colons, ":"),
+		"public key":  hex.EncodeToString(keySum[:]),
+	} {
+		if err := verifyPinned([]string{normalizePin(pin)})(cs); err != nil {
+			t.Errorf("%s pin: %v", name, err)
+		}
+	}
+
+	other := sha256.Sum256([]byte("another certificate"))
+	if err := verifyPinned([]string{hex.EncodeToString(other[:])})(cs); !errors.Is(err, ErrCertMismatch) {
+		t.Errorf("wrong pin: err = %v", err)
+	}
+	if err := verifyPinned([]string{hex.EncodeToString(certSum[:])})(tls.ConnectionState{}); !errors.Is(err, ErrCertMismatch) {
+		t.Errorf("no certificate: err = %v", err)
+	}
+}
@@ -0,0 +1,345 @@
+package hub
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+type PoolStrategy int
+
+const (
+	// spread reads across every healthy client
+	RoundRobin PoolStrategy = iota
+	// read from the first healthy client in the order given to NewPool
+	PrimaryFallback
+)
+
+var (
+	ErrNoHealthyClient = errors.New("no healthy hub client")
+	ErrPoolStarted     = errors.New("pool already started")
+)
+
+type PoolOption func(*Pool)
+
+func WithPoolStrategy(s PoolStrategy) PoolOption {
+	return func(p *Pool) {
+		p.strategy = s
+	}
+}
+
+// how often every client is checked with GetServiceStatus
+func WithHealthInterval(d time.Duration) PoolOption {
+	return func(p *Pool) {
+		p.interval = d
+	}
+}
+
+func WithHealthTimeout(d time.Duration) PoolOption {
+	return func(p *Pool) {
+		p.checkTimeout = d
+	}
+}
+
+// Pool spreads reads over several hubs, e.g. one per region or season. A
+// client counts as healthy while it is connected and its last health check
+// passed; reads fail over to the next healthy client when one drops.
+type Pool struct {
+	clients      []*Client
+	strategy     PoolStrategy
+	interval     time.Duration
+	checkTimeout time.Duration
+	next         atomic.Uint64
+
+	mu            sync.RWMutex
+	failed        map[*Client]bool
+	readyHandlers []func(*Client, ReadyStatus)
+
+	startOnce sync.Once
+	started   atomic.Bool
+	stopOnce  sync.Once
+	stop      chan struct{}
+	done      chan struct{}
+}
+
+func NewPool(clients []*Client, opts ...PoolOption) *Pool {
+	p := &Pool{
+		clients:      append([]*Client(nil), clients...),
+		interval:     15 * time.Second,
+		checkTimeout: 5 * time.Second,
+		failed:       make(map[*Client]bool),
+		stop:         make(chan struct{}),
+		done:         make(chan struct{}),
+	}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	for _, c := range p.clients {
+		c.OnReady(func(s ReadyStatus, _ bool) { p.ready(c, s) })
+	}
+	return p
+}
+
+// Start connects every client and runs health checks until ctx is cancelled
+// or Close is called. Clients that fail to connect are retried by the checks.
+func (p *Pool) Start(ctx context.Context) error {
+	err := ErrPoolStarted
+	p.startOnce.Do(func() {
+		err = nil
+		for _, c := range p.clients {
+			if cerr := c.Connect(); cerr != nil {
+				p.setFailed(c, true)
+			}
+		}
+		p.started.Store(true)
+		go p.run(ctx)
+	})
+	return err
+}
+
+// Close stops the health checks and disconnects every client. It waits for
+// a check in progress first, so the check cannot reconnect a client Close
+// has disconnected.
+func (p *Pool) Close() error {
+	p.stopOnce.Do(func() {
+		close(p.stop)
+	})
+	if p.started.Load() {
+		<-p.done
+	}
+
+	var errs MultiError
+	for _, c := range p.clients {
+		errs.Add(c.Disconnect())
+	}
+	return errs.ErrOrNil()
+}
+
+func (p *Pool) Clients() []*Client {
+	return append([]*Client(nil), p.clients...)
+}
+
+// Healthy lists the healthy clients in the order given to NewPool
+func (p *Pool) Healthy() []*Client {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	var out []*Client
+	for _, c := range p.clients {
+		if c.IsConnected() && !p.failed[c] {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// OnReady is called with the client that received Ready, for every client
+// in the pool
+func (p *Pool) OnReady(handler func(*Client, ReadyStatus)) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.readyHandlers = append(p.readyHandlers, handler)
+}
+
+// Do runs fn against healthy clients in strategy order until one succeeds.
+// Connection errors and timeouts mark the client unhealthy and move on to
+// the next; any other error is returned as is. When every client fails
+// over, the *MultiError holds ErrNoHealthyClient followed by their errors.
+func (p *Pool) Do(ctx context.Context, fn func(*Client) error) error {
+	candidates := p.ordered()
+	if len(candidates) == 0 {
+		return ErrNoHealthyClient
+	}
+
+	errs := MultiError{Errors: []error{ErrNoHealthyClient}}
+	for _, c := range candidates {
+		err := fn(c)
+		if err == nil || !failsOver(err) {
+			return err
+		}
+		p.setFailed(c, true)
+		errs.Add(err)
+
+		if ctx != nil && ctx.Err() != nil {
+			break
+		}
+	}
+	return &errs
+}
+
+func (p *Pool) ordered() []*Client {
+	healthy := p.Healthy()
+	if p.strategy != RoundRobin || len(healthy) < 2 {
+		return healthy
+	}
+
+	start := int(p.next.Add(1)-1) % len(healthy)
+	out := make([]*Client, 0, len(healthy))
+	out = append(out, healthy[start:]...)
+	return append(out, healthy[:start]...)
+}
+
+func failsOver(err error) bool {
+	return errors.Is(err, ErrNotConnected) ||
+		errors.Is(err, ErrConnectionLost) ||
+		errors.Is(err, ErrConnectionTimeout) ||
+		errors.Is(err, ErrDraining) ||
+		errors.Is(err, ErrCircuitOpen)
+}
+
+func (p *Pool) setFailed(c *Client, failed bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.failed[c] = failed
+}
+
+func (p *Pool) ready(c *Client, s ReadyStatus) {
+	p.setFailed(c, false)
+
+	p.mu.RLock()
+	handlers := append([]func(*Client, ReadyStatus){}, p.readyHandlers...)
+	p.mu.RUnlock()
+
+	for _, h := range handlers {
+		c.runHandler(func() { h(c, s) })
+	}
+}
+
+func (p *Pool) run(ctx context.Context) {
+	defer close(p.done)
+
+	// Close cuts short the checks in progress
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		select {
+		case <-p.stop:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			p.check(ctx)
+		case <-p.stop:
+			return
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// check probes every client concurrently, reconnecting the dropped ones
+func (p *Pool) check(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, c := range p.clients {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			p.setFailed(c, !p.probe(ctx, c))
+		}()
+	}
+	wg.Wait()
+}
+
+func (p *Pool) probe(ctx context.Context, c *Client) bool {
+	switch c.State() {
+	case StateConnected:
+	case StateDisconnected:
+		if ctx.Err() == nil {
+			_ = c.Connect()
+		}
+		return false
+	default:
+		return false
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, p.checkTimeout)
+	defer cancel()
+
+	_, err := c.GetServiceStatus(ctx)
+	return err == nil
+}
+
+// the Get* reads, routed through Do
+
+func (p *Pool) GetServiceStatus(ctx context.Context, opts ...CallOption) (out *ServiceStatus, err error) {
+	err = p.Do(ctx, func(c *Client) (err error) {
+		out, err = c.GetServiceStatus(ctx, opts...)
+		return err
+	})
+	return out, err
+}
+
+func (p *Pool) GetDailyQuests(ctx context.Context, opts ...CallOption) (out map[string]BaseQuest, err error) {
+	err = p.Do(ctx, func(c *Client) (err error) {
+		out, err = c.GetDailyQuests(ctx, opts...)
+		return err
+	})
+	return out, err
+}
+
+func (p *Pool) GetDailyQuest(ctx context.Context, questID string, opts ...CallOption) (out *BaseQuest, err error) {
+	err = p.Do(ctx, func(c *Client) (err error) {
+		out, err = c.GetDailyQuest(ctx, questID, opts...)
+		return err
+	})
+	return out, err
+}
+
+func (p *Pool) GetChallengeBundles(ctx context.Context, opts ...CallOption) (out []AthenaChallengeBundle, err error) {
+	err = p.Do(ctx, func(c *Client) (err error) {
+		out, err = c.GetChallengeBundles(ctx, opts...)
+		return err
+	})
+	return out, err
+}
+
+func (p *Pool) GetChallengeBundle(ctx context.Context, templateID string, opts ...CallOption) (out *AthenaChallengeBundle, err error) {
+	err = p.Do(ctx, func(c *Client) (err error) {
+		out, err = c.GetChallengeBundle(ctx, templateID, opts...)
+		return err
+	})
+	return out, err
+}
+
+func (p *Pool) GetChallengeBundleSchedules(ctx context.Context, opts ...CallOption) (out []ChallengeBundleSchedule, err error) {
+	err = p.Do(ctx, func(c *Client) (err error) {
+		out, err = c.GetChallengeBundleSchedules(ctx, opts...)
+		return err
+	})
+	return out, err
+}
+
+func (p *Pool) GetSeasonInfo(ctx context.Context, opts ...CallOption) (out *SeasonInfo, err error) {
+	err = p.Do(ctx, func(c *Client) (err error) {
+		out, err = c.GetSeasonInfo(ctx, opts...)
+		return err
+	})
+	return out, err
+}
+
+func (p *Pool) GetWeeklyChallenges(ctx context.Context, week int, opts ...CallOption) (out *WeeklyChallenges, err error) {
+	err = p.Do(ctx, func(c *Client) (err error) {
+		out, err = c.GetWeeklyChallenges(ctx, week, opts...)
+		return err
+	})
+	return out, err
+}
+
+func (p *Pool) GetChallengeBundlesPage(ctx context.Context, req PageRequest, opts ...CallOption) (out *BundlePage, err error) {
+	err = p.Do(ctx, func(c *Client) (err error) {
+		out, err = c.GetChallengeBundlesPage(ctx, req, opts...)
+		return err
+	})
+	return out, err
+}
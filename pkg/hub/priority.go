@@ -0,0 +1,47 @@
+package hub
+
+import "context"
+
+type Priority int
+
+const (
+	PriorityInteractive Priority = iota
+	PriorityBackground
+)
+
+func (p Priority) String() string {
+	switch p {
+	case PriorityInteractive:
+		return "interactive"
+	case PriorityBackground:
+		return "background"
+	default:
+		return "unknown"
+	}
+}
+
+type priorityKey struct{}
+
+// calls default to PriorityInteractive when no priority is set
+func WithPriority(ctx context.Context, p Priority) context.Context {
+	return context.WithValue(ctx, priorityKey{}, p)
+}
+
+func PriorityFromContext(ctx context.Context) Priority {
+	if p, ok := ctx.Value(priorityKey{}).(Priority); ok {
+		return p
+	}
+	return PriorityInteractive
+}
+
+func (c *Client) waitForSlot(ctx context.Context, p Priority) error {
+	if c.buckets == nil {
+		return nil
+	}
+
+	bucket, ok := c.buckets[p]
+	if !ok {
+		return nil
+	}
+	return bucket.wait(ctx)
+}
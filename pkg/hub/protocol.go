@@ -0,0 +1,100 @@
+package hub
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/philippseith/signalr"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+type HubProtocol int
+
+const (
+	ProtocolJSON HubProtocol = iota
+	ProtocolMessagePack
+)
+
+func (p HubProtocol) String() string {
+	switch p {
+	case ProtocolJSON:
+		return "json"
+	case ProtocolMessagePack:
+		return "messagepack"
+	default:
+		return fmt.Sprintf("HubProtocol(%d)", int(p))
+	}
+}
+
+// name used for the protocol in negotiate responses
+func (p HubProtocol) transferFormat() signalr.TransferFormatType {
+	if p == ProtocolMessagePack {
+		return "Binary"
+	}
+	return "Text"
+}
+
+// encodeResult turns a result signalr decoded back into bytes the client can
+// cache and hand around. MessagePack results stay MessagePack, with sorted
+// keys so that equal results encode the same.
+func (p HubProtocol) encodeResult(v interface{}) ([]byte, error) {
+	if p != ProtocolMessagePack {
+		return json.Marshal(v)
+	}
+
+	var buf bytes.Buffer
+	enc := msgpack.NewEncoder(&buf)
+	enc.SetSortMapKeys(true)
+	if err := enc.Encode(stringKeys(v)); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// resultJSON converts a result from encodeResult to JSON
+func (p HubProtocol) resultJSON(raw []byte) (json.RawMessage, error) {
+	if p != ProtocolMessagePack {
+		return raw, nil
+	}
+
+	var v interface{}
+	if err := decodeMessagePack(raw, &v, false); err != nil {
+		return nil, err
+	}
+	return json.Marshal(stringKeys(v))
+}
+
+// decodeMessagePack decodes into the json field names, as signalr does for
+// arguments
+func decodeMessagePack(raw []byte, target interface{}, strict bool) error {
+	dec := msgpack.NewDecoder(bytes.NewReader(raw))
+	dec.SetCustomStructTag("json")
+	dec.DisallowUnknownFields(strict)
+	return dec.Decode(target)
+}
+
+// signalr decodes MessagePack results into generic values whose maps are
+// keyed by interface{}, which neither encoder sorts and JSON cannot encode
+func stringKeys(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for k, e := range v {
+			m[fmt.Sprint(k)] = stringKeys(e)
+		}
+		return m
+	case map[string]interface{}:
+		for k, e := range v {
+			v[k] = stringKeys(e)
+		}
+		return v
+	case []interface{}:
+		for i, e := range v {
+			v[i] = stringKeys(e)
+		}
+		return v
+	default:
+		return v
+	}
+}
@@ -0,0 +1,76 @@
+package hub
+
+import (
+	"slices"
+	"time"
+)
+
+// transform steps recorded on Provenance by this package
+const (
+	TransformMessagePackToJSON = "messagepack-to-json"
+	TransformDiff              = "diff"
+)
+
+// Provenance ties data back to the hub fetch that produced it. Transforms
+// lists the processing steps applied since, oldest first, and Overrides the
+// values that did not come from the hub at all.
+type Provenance struct {
+	Source        string     `json:"source"`
+	ServerVersion string     `json:"serverVersion,omitempty"`
+	ConnectionID  string     `json:"connectionId,omitempty"`
+	Protocol      string     `json:"protocol,omitempty"`
+	FetchedAt     time.Time  `json:"fetchedAt"`
+	Transforms    []string   `json:"transforms,omitempty"`
+	Overrides     []Override `json:"overrides,omitempty"`
+}
+
+// Override records an operator correction applied on top of hub data.
+// Fields are JSON paths, e.g. "objectives.0.count"; an excluded entry was
+// dropped altogether.
+type Override struct {
+	Table    string   `json:"table"`
+	ID       string   `json:"id"`
+	Fields   []string `json:"fields,omitempty"`
+	Excluded bool     `json:"excluded,omitempty"`
+	Reason   string   `json:"reason,omitempty"`
+}
+
+// With returns a copy with one more transform step appended
+func (p Provenance) With(transform string) Provenance {
+	p.Transforms = append(slices.Clip(p.Transforms), transform)
+	return p
+}
+
+func (p Provenance) IsZero() bool {
+	return p.Source == "" && p.FetchedAt.IsZero()
+}
+
+func (p Provenance) clone() Provenance {
+	p.Transforms = slices.Clone(p.Transforms)
+	if p.Overrides != nil {
+		overrides := make([]Override, len(p.Overrides))
+		for i, o := range p.Overrides {
+			o.Fields = slices.Clone(o.Fields)
+			overrides[i] = o
+		}
+		p.Overrides = overrides
+	}
+	return p
+}
+
+func (c *Client) provenance(fetchedAt time.Time, status *ServiceStatus) Provenance {
+	info := c.ConnectionInfo()
+	p := Provenance{
+		Source:       c.url,
+		ConnectionID: info.ConnectionID,
+		Protocol:     info.Protocol,
+		FetchedAt:    fetchedAt,
+	}
+	if status != nil {
+		p.ServerVersion = status.Version
+	}
+	if c.protocol == ProtocolMessagePack {
+		p = p.With(TransformMessagePackToJSON)
+	}
+	return p
+}
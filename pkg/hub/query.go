@@ -0,0 +1,94 @@
+package hub
+
+import (
+	"slices"
+	"strings"
+)
+
+// QuestSet adds filters to the daily quest map. Filters return new sets but
+// share the quests' objective and reward slices with the original.
+type QuestSet map[string]BaseQuest
+
+func (s QuestSet) Filter(keep func(id string, q BaseQuest) bool) QuestSet {
+	out := make(QuestSet)
+	for id, q := range s {
+		if keep(id, q) {
+			out[id] = q
+		}
+	}
+	return out
+}
+
+// quests granting the reward template
+func (s QuestSet) ByReward(templateID string) QuestSet {
+	return s.Filter(func(_ string, q BaseQuest) bool {
+		return slices.ContainsFunc(q.Rewards, func(r QuestReward) bool {
+			return r.TemplateID == templateID
+		})
+	})
+}
+
+// quests with at least n objectives
+func (s QuestSet) ByObjectiveCountAtLeast(n int) QuestSet {
+	return s.Filter(func(_ string, q BaseQuest) bool {
+		return len(q.Objectives) >= n
+	})
+}
+
+// sorted quest IDs
+func (s QuestSet) IDs() []string {
+	ids := make([]string, 0, len(s))
+	for id := range s {
+		ids = append(ids, id)
+	}
+	slices.Sort(ids)
+	return ids
+}
+
+// BundleSet adds filters to a bundle list. Like QuestSet, filtered sets share
+// nested slices with the original.
+type BundleSet []AthenaChallengeBundle
+
+func (s BundleSet) Filter(keep func(b *AthenaChallengeBundle) bool) BundleSet {
+	var out BundleSet
+	for i := range s {
+		if keep(&s[i]) {
+			out = append(out, s[i])
+		}
+	}
+	return out
+}
+
+// bundles with at least one battle pass challenge
+func (s BundleSet) BattlePassOnly() BundleSet {
+	return s.Filter(func(b *AthenaChallengeBundle) bool {
+		return slices.ContainsFunc(b.Objects, func(o ChallengeBundleObject) bool {
+			return o.Options.IsBattlePass
+		})
+	})
+}
+
+func (s BundleSet) BySchedule(scheduleID string) BundleSet {
+	return s.Filter(func(b *AthenaChallengeBundle) bool {
+		return b.ChallengeBundleSchedule == scheduleID
+	})
+}
+
+// rarity is still a free-form string on the wire, so matching ignores case
+func (s BundleSet) ByRarity(rarities ...string) BundleSet {
+	return s.Filter(func(b *AthenaChallengeBundle) bool {
+		return slices.ContainsFunc(rarities, func(r string) bool {
+			return strings.EqualFold(b.Rarity, r)
+		})
+	})
+}
+
+func (s BundleSet) Get(templateID string) (AthenaChallengeBundle, bool) {
+	i := slices.IndexFunc(s, func(b AthenaChallengeBundle) bool {
+		return b.TemplateID == templateID
+	})
+	if i < 0 {
+		return AthenaChallengeBundle{}, false
+	}
+	return s[i], true
+}
@@ -0,0 +1,124 @@
+package hub
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// objectives and rewards arrive either as arrays of objects or, from older
+// servers, as maps keyed by backend name / template ID; both decode here
+type QuestObjectives []QuestObjective
+
+type QuestRewards []QuestReward
+
+func (o *QuestObjectives) UnmarshalJSON(b []byte) error {
+	b = bytes.TrimSpace(b)
+	if bytes.Equal(b, []byte("null")) {
+		*o = nil
+		return nil
+	}
+
+	if len(b) > 0 && b[0] == '[' {
+		var list []QuestObjective
+		if err := json.Unmarshal(b, &list); err != nil {
+			return err
+		}
+		*o = list
+		return nil
+	}
+
+	var legacy map[string]json.RawMessage
+	if err := json.Unmarshal(b, &legacy); err != nil {
+		return fmt.Errorf("objectives: %w", err)
+	}
+
+	out := make(QuestObjectives, 0, len(legacy))
+	for _, name := range sortedKeys(legacy) {
+		obj := QuestObjective{BackendName: name}
+		if err := decodeLegacyEntry(legacy[name], &obj.Count, &obj); err != nil {
+			return fmt.Errorf("objective %s: %w", name, err)
+		}
+		obj.BackendName = name
+		out = append(out, obj)
+	}
+	*o = out
+	return nil
+}
+
+func (r *QuestRewards) UnmarshalJSON(b []byte) error {
+	b = bytes.TrimSpace(b)
+	if bytes.Equal(b, []byte("null")) {
+		*r = nil
+		return nil
+	}
+
+	if len(b) > 0 && b[0] == '[' {
+		var list []QuestReward
+		if err := json.Unmarshal(b, &list); err != nil {
+			return err
+		}
+		*r = list
+		return nil
+	}
+
+	var legacy map[string]json.RawMessage
+	if err := json.Unmarshal(b, &legacy); err != nil {
+		return fmt.Errorf("rewards: %w", err)
+	}
+
+	out := make(QuestRewards, 0, len(legacy))
+	for _, id := range sortedKeys(legacy) {
+		reward := QuestReward{TemplateID: id}
+		if err := decodeLegacyEntry(legacy[id], &reward.Quantity, &reward); err != nil {
+			return fmt.Errorf("reward %s: %w", id, err)
+		}
+		reward.TemplateID = id
+		out = append(out, reward)
+	}
+	*r = out
+	return nil
+}
+
+// a legacy map value is either a bare number or an object
+func decodeLegacyEntry(raw json.RawMessage, number *int, object interface{}) error {
+	raw = bytes.TrimSpace(raw)
+	if len(raw) > 0 && raw[0] == '{' {
+		return json.Unmarshal(raw, object)
+	}
+
+	var f float64
+	if err := json.Unmarshal(raw, &f); err != nil {
+		return err
+	}
+	*number = int(f)
+	return nil
+}
+
+func sortedKeys(m map[string]json.RawMessage) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func (o QuestObjectives) Get(backendName string) (QuestObjective, bool) {
+	for _, obj := range o {
+		if obj.BackendName == backendName {
+			return obj, true
+		}
+	}
+	return QuestObjective{}, false
+}
+
+func (r QuestRewards) Get(templateID string) (QuestReward, bool) {
+	for _, reward := range r {
+		if reward.TemplateID == templateID {
+			return reward, true
+		}
+	}
+	return QuestReward{}, false
+}
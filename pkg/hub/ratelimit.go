@@ -0,0 +1,98 @@
+package hub
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+type BucketConfig struct {
+	Rate  float64 // tokens per second
+	Burst int
+}
+
+type tokenBucket struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+
+	// give up at once when the token would arrive after ctx's deadline
+	failFast bool
+}
+
+func newTokenBucket(cfg BucketConfig) *tokenBucket {
+	burst := float64(cfg.Burst)
+	if burst < 1 {
+		burst = 1
+	}
+	return &tokenBucket{
+		rate:   cfg.Rate,
+		burst:  burst,
+		tokens: burst,
+		last:   time.Now(),
+	}
+}
+
+func (b *tokenBucket) refill(now time.Time) {
+	b.tokens += now.Sub(b.last).Seconds() * b.rate
+	if b.tokens > b.burst {
+		b.tokens = b.burst
+	}
+	b.last = now
+}
+
+// wait reserves a token, blocking until it is available or ctx is done
+func (b *tokenBucket) wait(ctx context.Context) error {
+	if b.rate <= 0 {
+		return nil
+	}
+
+	b.mu.Lock()
+	b.refill(time.Now())
+	b.tokens--
+	if b.tokens >= 0 {
+		b.mu.Unlock()
+		return nil
+	}
+	delay := time.Duration(-b.tokens / b.rate * float64(time.Second))
+	if deadline, ok := ctx.Deadline(); ok && b.failFast && time.Until(deadline) < delay {
+		b.tokens++
+		b.mu.Unlock()
+		return context.DeadlineExceeded
+	}
+	b.mu.Unlock()
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		b.mu.Lock()
+		b.tokens++
+		b.mu.Unlock()
+		return ctx.Err()
+	}
+}
+
+// waitForRateLimit takes a token from the method's own bucket if it has
+// one, otherwise from the client-wide one
+func (c *Client) waitForRateLimit(ctx context.Context, method string) error {
+	bucket, ok := c.methodLimits[method]
+	if !ok {
+		bucket = c.rateLimit
+	}
+	if bucket == nil {
+		return nil
+	}
+	return bucket.wait(ctx)
+}
+
+func newRateLimit(rps float64, burst int) *tokenBucket {
+	b := newTokenBucket(BucketConfig{Rate: rps, Burst: burst})
+	b.failFast = true
+	return b
+}
@@ -0,0 +1,115 @@
+package hub
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestTokenBucketBurstThenRate(t *testing.T) {
+	b := newTokenBucket(BucketConfig{Rate: 20, Burst: 3})
+	ctx := context.Background()
+
+	start := time.Now()
+	for range 3 {
+		if err := b.wait(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if took := time.Since(start); took > 20*time.Millisecond {
+		t.Errorf("burst took %s, want no wait", took)
+	}
+
+	// the fourth waits for a token at 20/s
+	start = time.Now()
+	if err := b.wait(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if took := time.Since(start); took < 30*time.Millisecond {
+		t.Errorf("over burst took %s, want about 50ms", took)
+	}
+}
+
+func TestTokenBucketCancelReturnsToken(t *testing.T) {
+	b := newTokenBucket(BucketConfig{Rate: 1, Burst: 1})
+	if err := b.wait(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := b.wait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("err = %v, want deadline exceeded", err)
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.tokens < -0.1 {
+		t.Errorf("tokens = %.2f after giving up, want the reservation returned", b.tokens)
+	}
+}
+
+// WithRateLimit fails at once when the token comes after the deadline
+func TestRateLimitFailsFast(t *testing.T) {
+	c := &Client{rateLimit: newRateLimit(1, 1)}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second/2)
+	defer cancel()
+
+	if err := c.waitForRateLimit(ctx, "GetDailyQuests"); err != nil {
+		t.Fatal(err)
+	}
+	start := time.Now()
+	if err := c.waitForRateLimit(ctx, "GetDailyQuests"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("err = %v, want deadline exceeded", err)
+	}
+	if took := time.Since(start); took > 100*time.Millisecond {
+		t.Errorf("failing took %s, want no wait", took)
+	}
+}
+
+func TestMethodRateLimit(t *testing.T) {
+	c := &Client{}
+	WithRateLimit(1, 1)(c)
+	WithMethodRateLimit("GetSeasonInfo", 0, 0)(c)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second/2)
+	defer cancel()
+
+	// an unthrottled method leaves the client-wide bucket alone
+	for range 5 {
+		if err := c.waitForRateLimit(ctx, "GetSeasonInfo"); err != nil {
+			t.Fatalf("GetSeasonInfo: %v", err)
+		}
+	}
+	if err := c.waitForRateLimit(ctx, "GetDailyQuests"); err != nil {
+		t.Fatalf("GetDailyQuests: %v", err)
+	}
+	if err := c.waitForRateLimit(ctx, "GetDailyQuests"); err == nil {
+		t.Error("second GetDailyQuests was not limited")
+	}
+}
+
+// background calls spending their bucket leave the interactive one full
+func TestPriorityBuckets(t *testing.T) {
+	c := &Client{}
+	WithPriorityBuckets(BucketConfig{Rate: 1, Burst: 2}, BucketConfig{Rate: 1, Burst: 1})(c)
+
+	bg, cancel := context.WithTimeout(WithPriority(context.Background(), PriorityBackground), 20*time.Millisecond)
+	defer cancel()
+	if err := c.waitForSlot(bg, PriorityFromContext(bg)); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.waitForSlot(bg, PriorityFromContext(bg)); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("second background call: %v, want it held back", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if p := PriorityFromContext(ctx); p != PriorityInteractive {
+		t.Fatalf("default priority = %s", p)
+	}
+	for range 2 {
+		if err := c.waitForSlot(ctx, PriorityInteractive); err != nil {
+			t.Fatalf("interactive call: %v", err)
+		}
+	}
+}
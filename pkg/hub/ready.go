@@ -0,0 +1,50 @@
+package hub
+
+import "context"
+
+// the hub sends Ready on every connection; only the first one is initial
+type readyState struct {
+	done      chan struct{}
+	seen      bool
+	status    ReadyStatus
+	refreshed []func(ReadyStatus)
+}
+
+// markReady records a Ready and reports whether it was the first
+func (c *Client) markReady(status ReadyStatus) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.ready.seen {
+		return false
+	}
+	c.ready.seen = true
+	c.ready.status = status
+	close(c.ready.done)
+	return true
+}
+
+// WaitForReady blocks until the hub's first Ready and returns it. Later
+// Ready notifications, after a reconnect, do not affect it; see OnRefreshed.
+func (c *Client) WaitForReady(ctx context.Context) (ReadyStatus, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	select {
+	case <-c.ready.done:
+		c.mu.RLock()
+		defer c.mu.RUnlock()
+		return c.ready.status, nil
+	case <-ctx.Done():
+		return ReadyStatus{}, ctx.Err()
+	}
+}
+
+// OnRefreshed is called for every Ready after the first, which the hub
+// sends again whenever the client reconnects
+func (c *Client) OnRefreshed(handler func(ReadyStatus)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.ready.refreshed = append(c.ready.refreshed, handler)
+}
@@ -0,0 +1,153 @@
+package hub
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// pushed by the hub through the CacheRefreshProgress receiver method while
+// a refresh started by RefreshCache runs. The last report for a job has
+// Done set, and Error when the refresh failed.
+type RefreshProgress struct {
+	JobID     string    `json:"jobId"`
+	Stage     string    `json:"stage,omitempty"`
+	Completed int       `json:"completed"`
+	Total     int       `json:"total"`
+	Done      bool      `json:"done"`
+	Error     string    `json:"error,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// Percent runs from 0 to 100; 0 until the hub reports a total
+func (p RefreshProgress) Percent() float64 {
+	if p.Done && p.Error == "" {
+		return 100
+	}
+	if p.Total <= 0 {
+		return 0
+	}
+	return min(float64(p.Completed)/float64(p.Total), 1) * 100
+}
+
+// finished jobs kept for WaitForRefresh calls that start after the final
+// report arrived
+const maxFinishedRefreshes = 64
+
+type refreshJobs struct {
+	mu       sync.Mutex
+	waiters  map[string][]chan RefreshProgress
+	finished map[string]RefreshProgress
+	order    []string
+}
+
+func (r *hubReceiver) CacheRefreshProgress(p RefreshProgress) {
+	r.client.recordRefresh(p)
+
+	r.client.mu.RLock()
+	handlers := append([]func(RefreshProgress){}, r.client.refreshHandlers...)
+	r.client.mu.RUnlock()
+
+	for _, h := range handlers {
+		r.client.runHandler(func() { h(p) })
+	}
+}
+
+func (c *Client) recordRefresh(p RefreshProgress) {
+	if !p.Done {
+		c.logger.Debug("Cache refresh %s: %s %d/%d", p.JobID, p.Stage, p.Completed, p.Total)
+		return
+	}
+
+	// the hub's data changed under whatever was cached while it ran
+	c.InvalidateLocal()
+	if p.Error != "" {
+		c.logger.Warn("Cache refresh %s failed: %s", p.JobID, p.Error)
+	} else {
+		c.logger.Info("Cache refresh %s finished", p.JobID)
+	}
+
+	jobs := &c.refreshes
+	jobs.mu.Lock()
+	defer jobs.mu.Unlock()
+
+	if jobs.finished == nil {
+		jobs.finished = make(map[string]RefreshProgress)
+	}
+	if _, ok := jobs.finished[p.JobID]; !ok {
+		jobs.order = append(jobs.order, p.JobID)
+	}
+	jobs.finished[p.JobID] = p
+	if len(jobs.order) > maxFinishedRefreshes {
+		delete(jobs.finished, jobs.order[0])
+		jobs.order = jobs.order[1:]
+	}
+
+	for _, ch := range jobs.waiters[p.JobID] {
+		ch <- p
+	}
+	delete(jobs.waiters, p.JobID)
+}
+
+// OnCacheRefreshProgress is called for every progress report the hub sends
+// on a cache refresh, the final one included
+func (c *Client) OnCacheRefreshProgress(handler func(RefreshProgress)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.refreshHandlers = append(c.refreshHandlers, handler)
+}
+
+// WaitForRefresh blocks until the hub reports the refresh jobID, as returned
+// by RefreshCache, done and returns the final report. A failed refresh
+// returns ErrRefreshFailed along with it. Reports sent while the client was
+// disconnected are not replayed, so ctx should carry a deadline.
+func (c *Client) WaitForRefresh(ctx context.Context, jobID string) (RefreshProgress, error) {
+	if jobID == "" {
+		return RefreshProgress{}, ErrInvalidJobID
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	jobs := &c.refreshes
+	jobs.mu.Lock()
+	p, ok := jobs.finished[jobID]
+	if ok {
+		jobs.mu.Unlock()
+		return p, refreshErr(p)
+	}
+
+	ch := make(chan RefreshProgress, 1)
+	if jobs.waiters == nil {
+		jobs.waiters = make(map[string][]chan RefreshProgress)
+	}
+	jobs.waiters[jobID] = append(jobs.waiters[jobID], ch)
+	jobs.mu.Unlock()
+
+	select {
+	case p := <-ch:
+		return p, refreshErr(p)
+	case <-ctx.Done():
+		jobs.mu.Lock()
+		waiters := jobs.waiters[jobID]
+		for i, w := range waiters {
+			if w == ch {
+				jobs.waiters[jobID] = append(waiters[:i:i], waiters[i+1:]...)
+				break
+			}
+		}
+		if len(jobs.waiters[jobID]) == 0 {
+			delete(jobs.waiters, jobID)
+		}
+		jobs.mu.Unlock()
+		return RefreshProgress{}, ctx.Err()
+	}
+}
+
+func refreshErr(p RefreshProgress) error {
+	if p.Error == "" {
+		return nil
+	}
+	return fmt.Errorf("%w: %s - %s", ErrRefreshFailed, p.JobID, p.Error)
+}
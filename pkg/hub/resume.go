@@ -0,0 +1,388 @@
+package hub
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/coder/websocket"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// Stateful reconnect, version 2 of the hub protocol: both sides number the
+// messages they send, acknowledge what they received and keep the rest, so
+// after a dropped websocket the client dials again with the same connection
+// token and each side replays what the other missed. The handshake is not
+// repeated; each side starts the new websocket with a Sequence message
+// giving the ID of the first message it resends. signalr v0.8.0 knows
+// nothing of it; wsConnection does it underneath, so a blip looks like a
+// slow read to signalr and the hub keeps the connection's groups and state.
+//
+// https://github.com/dotnet/aspnetcore/blob/main/src/SignalR/docs/specs/HubProtocol.md
+
+const (
+	messageAck      = 8
+	messageSequence = 9
+)
+
+// how long a client keeps trying to resume, see WithStatefulReconnect
+const defaultResumeWindow = 10 * time.Second
+
+// how long received messages wait to be acknowledged, so one Ack covers a
+// burst of them
+const ackInterval = time.Second
+
+// only invocations, stream items, completions and cancellations are numbered
+func sequencedMessage(typ int) bool {
+	return typ >= 1 && typ <= 5
+}
+
+// resumeOptions turns on stateful reconnect for a websocket dial
+type resumeOptions struct {
+	window    time.Duration
+	onDropped func(err error)
+	onRedial  func()
+	onResumed func(downtime time.Duration)
+}
+
+type sentMessage struct {
+	seq  int64
+	data []byte
+}
+
+type wsSession struct {
+	opts   resumeOptions
+	binary bool
+	// dials the hub again with the connection token
+	redial func(ctx context.Context) (*websocket.Conn, error)
+
+	// signalr's handshake is the first write and the hub's response the
+	// first frame read, both only on the first websocket
+	handshakeSent     bool
+	awaitingHandshake bool
+
+	sent    int64
+	unacked []sentMessage
+
+	// highest message ID passed on to signalr, and the ID the next numbered
+	// message from the hub carries
+	received int64
+	next     int64
+	ackTimer *time.Timer
+}
+
+func newSession(opts resumeOptions, binary bool, redial func(context.Context) (*websocket.Conn, error)) *wsSession {
+	return &wsSession{opts: opts, binary: binary, redial: redial, awaitingHandshake: true, next: 1}
+}
+
+// outgoing numbers and keeps a message signalr writes; it returns the bytes
+// to send. callers hold w.mu
+func (s *wsSession) outgoing(p []byte) ([]byte, error) {
+	if !s.handshakeSent {
+		s.handshakeSent = true
+		return statefulHandshake(p)
+	}
+
+	typ, ok := messageType(p, s.binary)
+	if !ok || !sequencedMessage(typ) {
+		return p, nil
+	}
+	s.sent++
+	s.unacked = append(s.unacked, sentMessage{seq: s.sent, data: append([]byte(nil), p...)})
+	return p, nil
+}
+
+type handshakeRequest struct {
+	Protocol string `json:"protocol"`
+	Version  int    `json:"version"`
+}
+
+// statefulHandshake turns signalr's handshake, which asks for version 1,
+// into one asking for version 2. The handshake is JSON whatever the
+// protocol.
+func statefulHandshake(p []byte) ([]byte, error) {
+	var req handshakeRequest
+	if err := json.Unmarshal(bytes.TrimSuffix(p, []byte{0x1e}), &req); err != nil {
+		return nil, fmt.Errorf("invalid handshake request: %w", err)
+	}
+	req.Version = 2
+
+	b, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+	return append(b, 0x1e), nil
+}
+
+// incoming strips Ack and Sequence messages and duplicates replayed by the
+// hub from a frame, returning what signalr should see; fresh reports whether
+// it let numbered messages through, which are due an Ack. callers hold w.mu
+func (s *wsSession) incoming(data []byte) (out []byte, fresh bool) {
+	if s.awaitingHandshake {
+		s.awaitingHandshake = false
+		i := bytes.IndexByte(data, 0x1e)
+		if i < 0 {
+			return data, false
+		}
+		out = append(out, data[:i+1]...)
+		data = data[i+1:]
+	}
+
+	before := s.received
+	for _, msg := range splitMessages(data, s.binary) {
+		typ, id, ok := messageHeader(msg, s.binary)
+		switch {
+		case !ok:
+			out = append(out, msg...)
+		case typ == messageAck:
+			s.ack(id)
+		case typ == messageSequence:
+			s.next = id
+		case sequencedMessage(typ):
+			seq := s.next
+			s.next++
+			if seq <= s.received {
+				continue
+			}
+			s.received = seq
+			out = append(out, msg...)
+		default:
+			out = append(out, msg...)
+		}
+	}
+	return out, s.received > before
+}
+
+func (s *wsSession) ack(id int64) {
+	i := 0
+	for i < len(s.unacked) && s.unacked[i].seq <= id {
+		i++
+	}
+	s.unacked = s.unacked[i:]
+}
+
+// first message ID the client resends after a resume
+func (s *wsSession) resendFrom() int64 {
+	if len(s.unacked) > 0 {
+		return s.unacked[0].seq
+	}
+	return s.sent + 1
+}
+
+func (s *wsSession) control(typ int, id int64) []byte {
+	if !s.binary {
+		b, _ := json.Marshal(map[string]int64{"type": int64(typ), "sequenceId": id})
+		return append(b, 0x1e)
+	}
+	body, _ := msgpack.Marshal([]interface{}{typ, id})
+	return append(binary.AppendUvarint(nil, uint64(len(body))), body...)
+}
+
+// splitMessages cuts a frame into its messages, separators and length
+// prefixes included
+func splitMessages(data []byte, binaryFrame bool) [][]byte {
+	var msgs [][]byte
+	for len(data) > 0 {
+		var n int
+		if binaryFrame {
+			size, l := binary.Uvarint(data)
+			if l <= 0 || uint64(len(data)-l) < size {
+				return append(msgs, data)
+			}
+			n = l + int(size)
+		} else {
+			i := bytes.IndexByte(data, 0x1e)
+			if i < 0 {
+				return append(msgs, data)
+			}
+			n = i + 1
+		}
+		msgs = append(msgs, data[:n])
+		data = data[n:]
+	}
+	return msgs
+}
+
+func messageType(msg []byte, binaryFrame bool) (int, bool) {
+	typ, _, ok := messageHeader(msg, binaryFrame)
+	return typ, ok
+}
+
+// messageHeader reads the type of one message and, for Ack and Sequence,
+// its sequence ID
+func messageHeader(msg []byte, binaryFrame bool) (typ int, id int64, ok bool) {
+	if !binaryFrame {
+		var m struct {
+			Type       int   `json:"type"`
+			SequenceID int64 `json:"sequenceId"`
+		}
+		if json.Unmarshal(bytes.TrimSuffix(msg, []byte{0x1e}), &m) != nil {
+			return 0, 0, false
+		}
+		return m.Type, m.SequenceID, true
+	}
+
+	_, n := binary.Uvarint(msg)
+	if n <= 0 {
+		return 0, 0, false
+	}
+	dec := msgpack.NewDecoder(bytes.NewReader(msg[n:]))
+	l, err := dec.DecodeArrayLen()
+	if err != nil || l < 1 {
+		return 0, 0, false
+	}
+	if typ, err = dec.DecodeInt(); err != nil {
+		return 0, 0, false
+	}
+	if (typ == messageAck || typ == messageSequence) && l >= 2 {
+		if id, err = dec.DecodeInt64(); err != nil {
+			return 0, 0, false
+		}
+	}
+	return typ, id, true
+}
+
+// scheduleAck acknowledges what was received once ackInterval has passed.
+// callers hold w.mu
+func (w *wsConnection) scheduleAck() {
+	s := w.session
+	if s.ackTimer != nil {
+		return
+	}
+	s.ackTimer = time.AfterFunc(ackInterval, func() {
+		w.mu.Lock()
+		s.ackTimer = nil
+		conn, frame := w.conn, s.control(messageAck, s.received)
+		w.mu.Unlock()
+
+		if w.Context().Err() == nil {
+			_ = conn.Write(w.Context(), w.messageType(), frame)
+		}
+	})
+}
+
+// resume dials the hub again after the websocket failed with cause and
+// replays what it may have missed. It gives up after the resume window, or
+// at once when the hub no longer knows the connection.
+func (w *wsConnection) resume(cause error) error {
+	s := w.session
+	start := time.Now()
+	if s.opts.onDropped != nil {
+		s.opts.onDropped(cause)
+	}
+
+	ctx, cancel := context.WithTimeout(w.Context(), s.opts.window)
+	defer cancel()
+
+	backoff := 100 * time.Millisecond
+	for {
+		if s.opts.onRedial != nil {
+			s.opts.onRedial()
+		}
+		ws, err := s.redial(ctx)
+		if err == nil {
+			if err = w.reattach(ctx, ws); err == nil {
+				if s.opts.onResumed != nil {
+					s.opts.onResumed(time.Since(start))
+				}
+				return nil
+			}
+			ws.CloseNow()
+		}
+		if errors.Is(err, errSessionGone) {
+			return fmt.Errorf("%w: %v", cause, err)
+		}
+
+		select {
+		case <-time.After(backoff):
+			backoff = min(backoff*2, 2*time.Second)
+		case <-ctx.Done():
+			return cause
+		}
+	}
+}
+
+var errSessionGone = errors.New("hub no longer knows the connection")
+
+// reattach starts the redialed websocket with a Sequence message and the
+// messages the hub has not acknowledged. The hub does the same; its
+// Sequence message is read like any other.
+func (w *wsConnection) reattach(ctx context.Context, ws *websocket.Conn) error {
+	s := w.session
+	ws.SetReadLimit(int64(w.readLimit) + 1)
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	frames := [][]byte{s.control(messageSequence, s.resendFrom())}
+	for _, m := range s.unacked {
+		frames = append(frames, m.data)
+	}
+	for _, f := range frames {
+		if err := ws.Write(ctx, w.messageType(), f); err != nil {
+			return err
+		}
+	}
+
+	old := w.conn
+	w.conn = ws
+	old.CloseNow()
+	return nil
+}
+
+// redialer dials the websocket again with the same URL, which carries the
+// connection token
+func redialer(u string, opts *websocket.DialOptions) func(context.Context) (*websocket.Conn, error) {
+	return func(ctx context.Context) (*websocket.Conn, error) {
+		ws, resp, err := websocket.Dial(ctx, u, opts)
+		if err != nil && resp != nil && resp.StatusCode == http.StatusNotFound {
+			return nil, fmt.Errorf("%w: %v", errSessionGone, err)
+		}
+		return ws, err
+	}
+}
+
+// ConnectionID is the hub's ID for the current connection; it survives a
+// resumed session but not a reconnect. Empty before the first connect.
+func (c *Client) ConnectionID() string {
+	return c.ConnectionInfo().ConnectionID
+}
+
+// OnSessionResumed is called when a dropped websocket was resumed under the
+// same connection, see WithStatefulReconnect. Nothing sent either way was
+// lost and the hub kept the connection's state, unlike a reconnect, which
+// starts a new connection and is reported through OnDisconnect and
+// OnReady with initial false.
+func (c *Client) OnSessionResumed(handler func(downtime time.Duration)) {
+	c.handleEvents(EventReconnected, func(e Event) {
+		if e.Resumed {
+			handler(e.Downtime)
+		}
+	})
+}
+
+func (c *Client) resumeOptions() *resumeOptions {
+	if c.resumeWindow <= 0 {
+		return nil
+	}
+	return &resumeOptions{
+		window: c.resumeWindow,
+		onDropped: func(err error) {
+			c.logger.Warn("Connection %s dropped, resuming: %v", c.ConnectionID(), err)
+		},
+		onRedial: c.metrics.reconnect,
+		onResumed: func(downtime time.Duration) {
+			c.mu.Lock()
+			c.connInfo.Resumes++
+			c.mu.Unlock()
+
+			c.logger.Info("Connection %s resumed after %s", c.ConnectionID(), downtime.Round(time.Millisecond))
+			c.emit(Event{Kind: EventReconnected, Resumed: true, Downtime: downtime})
+		},
+	}
+}
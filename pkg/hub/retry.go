@@ -0,0 +1,186 @@
+package hub
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/philippseith/signalr"
+)
+
+// RetryPolicy controls how read methods (Get*) are retried when the
+// connection drops underneath them. Writes are never retried.
+type RetryPolicy struct {
+	MaxAttempts    int
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	Multiplier     float64
+
+	// methods that are never retried even though they are reads
+	Exclude []string
+}
+
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:    3,
+		InitialBackoff: 200 * time.Millisecond,
+		MaxBackoff:     5 * time.Second,
+		Multiplier:     2,
+	}
+}
+
+func (p *RetryPolicy) retries(method string) bool {
+	if p == nil || p.MaxAttempts < 2 || !strings.HasPrefix(method, "Get") {
+		return false
+	}
+	for _, m := range p.Exclude {
+		if m == method {
+			return false
+		}
+	}
+	return true
+}
+
+func (p *RetryPolicy) backoff(attempt int) time.Duration {
+	d := float64(p.InitialBackoff)
+	for i := 1; i < attempt; i++ {
+		d *= p.Multiplier
+	}
+	return min(time.Duration(d), p.MaxBackoff)
+}
+
+func (c *Client) invoke(ctx context.Context, method string, args ...interface{}) (json.RawMessage, error) {
+	raw, err := c.invokeCoalesced(ctx, method, args)
+	if err != nil {
+		if stale, ok := c.serveStale(ctx, method, args, err); ok {
+			return stale, nil
+		}
+	}
+	return raw, err
+}
+
+func (c *Client) invokeCoalesced(ctx context.Context, method string, args []interface{}) (json.RawMessage, error) {
+	if c.coalesce != nil && !cacheBypassed(ctx) {
+		if key, ok := coalesceKey(method, args); ok {
+			return c.invokeShared(ctx, key, method, args)
+		}
+	}
+
+	raw, err := c.invokeWithRetry(ctx, method, args...)
+	c.invokeFailed(ctx, method, err)
+	return raw, err
+}
+
+// invokeFailed publishes EventInvokeFailed for a call that failed after any
+// retries. It runs once per call sent, not once per caller sharing it, and
+// a caller cancelling is not a failure.
+func (c *Client) invokeFailed(ctx context.Context, method string, err error) {
+	if err == nil || (ctx != nil && errors.Is(ctx.Err(), context.Canceled)) {
+		return
+	}
+	c.emit(Event{Kind: EventInvokeFailed, Method: method, Err: err})
+}
+
+func (c *Client) invokeWithRetry(ctx context.Context, method string, args ...interface{}) (json.RawMessage, error) {
+	if err := c.awaitInitialized(ctx, method); err != nil {
+		return nil, err
+	}
+
+	if !c.retry.retries(method) {
+		return c.invokeAttempt(ctx, method, args...)
+	}
+
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	for attempt := 1; ; attempt++ {
+		start := time.Now()
+		raw, err := c.invokeAttempt(ctx, method, args...)
+		if err == nil || attempt >= c.retry.MaxAttempts || !c.shouldRetry(err) {
+			return raw, err
+		}
+
+		delay := c.retry.backoff(attempt)
+		if !retryFitsBudget(ctx, delay, time.Since(start)) {
+			return nil, fmt.Errorf("%w: %s - no room to retry: %w", ErrBudgetExhausted, method, err)
+		}
+		if !c.withdrawRetry(method) {
+			c.logger.Warn("Not retrying %s, retry budget exhausted: %v", method, err)
+			return nil, fmt.Errorf("%w: %s: %w", ErrRetryBudgetExhausted, method, err)
+		}
+
+		c.logger.Warn("Retrying %s in %s (attempt %d/%d): %v", method, delay, attempt+1, c.retry.MaxAttempts, err)
+
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return nil, err
+		}
+
+		if rerr := c.reconnect(ctx); rerr != nil {
+			c.logger.Warn("Reconnect before retrying %s failed: %v", method, rerr)
+		}
+	}
+}
+
+func (c *Client) shouldRetry(err error) bool {
+	if !errors.Is(err, ErrConnectionLost) && !errors.Is(err, ErrNotConnected) {
+		return false
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	// never connected, or closed on purpose by Disconnect
+	return c.connection != nil && c.state != StateClosed
+}
+
+// reconnect replaces a dropped connection and waits for the new one to come up
+func (c *Client) reconnect(ctx context.Context) error {
+	c.mu.RLock()
+	conn := c.connection
+	c.mu.RUnlock()
+
+	if conn != nil && conn.State() == signalr.ClientConnected {
+		return c.waitConnected(ctx, true)
+	}
+
+	// let the state watcher observe the close before connecting again,
+	// otherwise Connect still sees the old connection as up
+	if err := c.waitConnected(ctx, false); err != nil {
+		return err
+	}
+
+	if err := c.Connect(); err != nil {
+		return err
+	}
+	return c.waitConnected(ctx, true)
+}
+
+func (c *Client) waitConnected(ctx context.Context, want bool) error {
+	ticker := time.NewTicker(10 * time.Millisecond)
+	defer ticker.Stop()
+
+	for c.IsConnected() != want {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+	return nil
+}
+
+// signalr does not export its connection errors; these are the messages it
+// fails pending invocations with when the message loop stops
+func isConnectionLoss(err error) bool {
+	if err == nil {
+		return false
+	}
+	msg := err.Error()
+	return msg == "message loop ended" || strings.HasPrefix(msg, "client closed")
+}
@@ -0,0 +1,50 @@
+package hub
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRetryPolicy(t *testing.T) {
+	c := &Client{}
+	WithRetryPolicy(RetryPolicy{MaxAttempts: 4, InitialBackoff: 100 * time.Millisecond, MaxBackoff: 300 * time.Millisecond, Exclude: []string{"GetSeasonInfo"}})(c)
+	p := c.retry
+
+	for method, want := range map[string]bool{
+		"GetDailyQuests":     true,
+		"GetSeasonInfo":      false,
+		"SubscribeToUpdates": false,
+	} {
+		if got := p.retries(method); got != want {
+			t.Errorf("retries(%s) = %v, want %v", method, got, want)
+		}
+	}
+
+	// the zero multiplier falls back to the default of 2
+	for attempt, want := range []time.Duration{100, 200, 300, 300} {
+		if got := p.backoff(attempt + 1); got != want*time.Millisecond {
+			t.Errorf("backoff(%d) = %s, want %s", attempt+1, got, want*time.Millisecond)
+		}
+	}
+
+	var none *RetryPolicy
+	if none.retries("GetDailyQuests") {
+		t.Error("nil policy retries")
+	}
+}
+
+func TestIsConnectionLoss(t *testing.T) {
+	for msg, want := range map[string]bool{
+		"message loop ended":              true,
+		"client closed: context canceled": true,
+		"hub method invocation failed":    false,
+	} {
+		if got := isConnectionLoss(errors.New(msg)); got != want {
+			t.Errorf("isConnectionLoss(%q) = %v", msg, got)
+		}
+	}
+	if isConnectionLoss(nil) {
+		t.Error("nil is a connection loss")
+	}
+}
@@ -0,0 +1,74 @@
+package hub
+
+import (
+	"sync"
+)
+
+// RetryBudget caps retries across every method sharing it, so a burst of
+// correlated failures cannot turn into a retry storm against a struggling
+// hub. Withdraw is called before each retry and Deposit after each call the
+// hub answered successfully.
+type RetryBudget interface {
+	Withdraw() bool
+	Deposit()
+}
+
+// TokenBucketBudget starts full with capacity tokens. A retry costs one
+// token and every success refills refill tokens, up to capacity. With refill
+// 0.1 the hub has to answer ten calls for every retry once the initial
+// tokens are spent.
+type TokenBucketBudget struct {
+	mu     sync.Mutex
+	tokens float64
+	max    float64
+	refill float64
+}
+
+func NewTokenBucketBudget(capacity int, refill float64) *TokenBucketBudget {
+	return &TokenBucketBudget{
+		tokens: float64(capacity),
+		max:    float64(capacity),
+		refill: refill,
+	}
+}
+
+func (b *TokenBucketBudget) Withdraw() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+func (b *TokenBucketBudget) Deposit() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.tokens = min(b.tokens+b.refill, b.max)
+}
+
+func (b *TokenBucketBudget) Tokens() float64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.tokens
+}
+
+// without a budget every retry is allowed
+func (c *Client) withdrawRetry(method string) bool {
+	if c.retryBudget == nil {
+		return true
+	}
+	if c.retryBudget.Withdraw() {
+		return true
+	}
+	c.metrics.retryBudgetExhausted(method)
+	return false
+}
+
+func (c *Client) depositRetry() {
+	if c.retryBudget != nil {
+		c.retryBudget.Deposit()
+	}
+}
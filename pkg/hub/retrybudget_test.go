@@ -0,0 +1,82 @@
+package hub
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestTokenBucketBudget(t *testing.T) {
+	b := NewTokenBucketBudget(2, 0.5)
+	if !b.Withdraw() || !b.Withdraw() {
+		t.Fatal("a full budget refused a retry")
+	}
+	if b.Withdraw() {
+		t.Fatal("an empty budget allowed a retry")
+	}
+
+	b.Deposit()
+	if b.Withdraw() {
+		t.Error("half a token allowed a retry")
+	}
+	b.Deposit()
+	if !b.Withdraw() {
+		t.Error("two successes did not earn a retry")
+	}
+
+	for range 10 {
+		b.Deposit()
+	}
+	if got := b.Tokens(); got != 2 {
+		t.Errorf("tokens = %v, want capped at 2", got)
+	}
+}
+
+func TestLatencyBudget(t *testing.T) {
+	if _, ok := BudgetRemaining(context.Background()); ok {
+		t.Fatal("budget without WithLatencyBudget")
+	}
+
+	ctx := WithLatencyBudget(context.Background(), time.Second)
+	// a nested budget can shrink it but not grow it
+	if r, _ := BudgetRemaining(WithLatencyBudget(ctx, time.Hour)); r > time.Second {
+		t.Errorf("nested larger budget left %s", r)
+	}
+	if r, _ := BudgetRemaining(WithLatencyBudget(ctx, 10*time.Millisecond)); r > 10*time.Millisecond {
+		t.Errorf("nested smaller budget left %s", r)
+	}
+
+	if !retryFitsBudget(ctx, 100*time.Millisecond, 100*time.Millisecond) {
+		t.Error("a short retry did not fit")
+	}
+	if retryFitsBudget(ctx, 600*time.Millisecond, 500*time.Millisecond) {
+		t.Error("a retry past the deadline fit")
+	}
+	if !retryFitsBudget(context.Background(), time.Hour, time.Hour) {
+		t.Error("a retry without a budget did not fit")
+	}
+}
+
+func TestBudgetDeadline(t *testing.T) {
+	ctx := WithLatencyBudget(context.Background(), 20*time.Millisecond)
+	bounded, cancel, err := withBudgetDeadline(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cancel()
+
+	<-bounded.Done()
+	if err := timeoutKind(bounded); err != ErrBudgetExhausted {
+		t.Errorf("timeoutKind = %v, want ErrBudgetExhausted", err)
+	}
+	if _, _, err := withBudgetDeadline(ctx); err != ErrBudgetExhausted {
+		t.Errorf("spent budget: err = %v", err)
+	}
+
+	plain, cancel2 := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel2()
+	<-plain.Done()
+	if err := timeoutKind(plain); err != ErrConnectionTimeout {
+		t.Errorf("timeoutKind without budget = %v", err)
+	}
+}
@@ -0,0 +1,97 @@
+package rewards
+
+import (
+	"regexp"
+)
+
+type Category string
+
+const (
+	CategoryXP         Category = "xp"
+	CategoryCosmetic   Category = "cosmetic"
+	CategoryCurrency   Category = "currency"
+	CategoryConsumable Category = "consumable"
+	CategoryOther      Category = "other"
+)
+
+type Rule struct {
+	Category Category
+	Pattern  string
+}
+
+// first matching rule wins. Patterns are matched case-insensitively against
+// the parsed ID, so bare IDs like AthenaBattleStar are already
+// AccountResource:athenabattlestar here.
+var DefaultRules = []Rule{
+	{CategoryXP, `^accountresource:athena(seasonalxp|battlestar)`},
+	{CategoryXP, `xpboost`},
+	{CategoryCurrency, `^currency:`},
+	{CategoryCurrency, `^accountresource:`},
+	{CategoryConsumable, `^(token|consumableaccountitem|cardpack):`},
+	{CategoryCosmetic, `^athena(character|backpack|pickaxe|glider|dance|itemwrap|loadingscreen|musicpack|skydivecontrail|spray|emoji|toy|petcarrier|pet):`},
+	{CategoryCosmetic, `^(homebasebannericon|bannertoken):`},
+}
+
+type Classifier struct {
+	rules []compiledRule
+}
+
+type compiledRule struct {
+	category Category
+	re       *regexp.Regexp
+}
+
+func NewClassifier(rules []Rule) (*Classifier, error) {
+	c := &Classifier{rules: make([]compiledRule, 0, len(rules))}
+	for _, r := range rules {
+		re, err := regexp.Compile("(?i)" + r.Pattern)
+		if err != nil {
+			return nil, err
+		}
+		c.rules = append(c.rules, compiledRule{category: r.Category, re: re})
+	}
+	return c, nil
+}
+
+func (c *Classifier) Classify(templateID string) Category {
+	return c.classify(Parse(templateID))
+}
+
+func (c *Classifier) classify(r Reward) Category {
+	id := r.String()
+	for _, rule := range c.rules {
+		if rule.re.MatchString(id) {
+			return rule.category
+		}
+	}
+	return CategoryOther
+}
+
+// ByCategory adds up the quantities in t per category
+func (c *Classifier) ByCategory(t *Totals) map[Category]int {
+	out := make(map[Category]int)
+	for _, a := range t.amounts {
+		out[c.classify(a.Reward)] += a.Quantity
+	}
+	return out
+}
+
+var defaultClassifier = mustClassifier(DefaultRules)
+
+func mustClassifier(rules []Rule) *Classifier {
+	c, err := NewClassifier(rules)
+	if err != nil {
+		panic(err)
+	}
+	return c
+}
+
+// Classify sorts a template ID into a category with DefaultRules
+func Classify(templateID string) Category {
+	return defaultClassifier.Classify(templateID)
+}
+
+// ByCategory adds up the quantities per category, see Classify
+func (t *Totals) ByCategory() map[Category]int {
+	return defaultClassifier.ByCategory(t)
+}
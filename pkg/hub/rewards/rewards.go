@@ -0,0 +1,126 @@
+// Package rewards turns reward template IDs such as
+// AccountResource:athenaseasonalxp or AthenaCharacter:cid_028_athena_commando_f
+// into structured values, sorts them into categories and adds them up.
+package rewards
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+)
+
+// Reward is a parsed template ID, Type:Name[:Variant]
+type Reward struct {
+	Type    string `json:"type"`
+	Name    string `json:"name,omitempty"`
+	Variant string `json:"variant,omitempty"`
+}
+
+// bare IDs the hub sends for account resources
+var bareResources = map[string]bool{
+	"athenabattlestar": true,
+	"athenaseasonalxp": true,
+}
+
+// Parse splits a template ID. Bare resource IDs like AthenaBattleStar parse
+// as AccountResource:athenabattlestar, so both spellings add up together.
+// Other IDs without a colon become a Type with no Name.
+func Parse(templateID string) Reward {
+	typ, rest, found := strings.Cut(templateID, ":")
+	if !found {
+		if bareResources[strings.ToLower(templateID)] {
+			return Reward{Type: "AccountResource", Name: strings.ToLower(templateID)}
+		}
+		return Reward{Type: templateID}
+	}
+
+	name, variant, _ := strings.Cut(rest, ":")
+	return Reward{Type: typ, Name: name, Variant: variant}
+}
+
+func (r Reward) String() string {
+	s := r.Type
+	if r.Name != "" {
+		s += ":" + r.Name
+	}
+	if r.Variant != "" {
+		s += ":" + r.Variant
+	}
+	return s
+}
+
+// template IDs are case-insensitive
+func (r Reward) key() Reward {
+	return Reward{Type: strings.ToLower(r.Type), Name: strings.ToLower(r.Name), Variant: strings.ToLower(r.Variant)}
+}
+
+type Amount struct {
+	Reward
+	Quantity int `json:"quantity"`
+}
+
+// Totals sums quantities per reward. IDs that differ only in case count as
+// the same reward; the first spelling seen is kept.
+type Totals struct {
+	amounts map[Reward]*Amount
+}
+
+func NewTotals() *Totals {
+	return &Totals{amounts: make(map[Reward]*Amount)}
+}
+
+func (t *Totals) Add(templateID string, quantity int) {
+	t.AddReward(Parse(templateID), quantity)
+}
+
+func (t *Totals) AddReward(r Reward, quantity int) {
+	if a, ok := t.amounts[r.key()]; ok {
+		a.Quantity += quantity
+		return
+	}
+	t.amounts[r.key()] = &Amount{Reward: r, Quantity: quantity}
+}
+
+func (t *Totals) Merge(other *Totals) {
+	for _, a := range other.amounts {
+		t.AddReward(a.Reward, a.Quantity)
+	}
+}
+
+func (t *Totals) Len() int {
+	return len(t.amounts)
+}
+
+// Quantity of one reward, by template ID
+func (t *Totals) Quantity(templateID string) int {
+	if a, ok := t.amounts[Parse(templateID).key()]; ok {
+		return a.Quantity
+	}
+	return 0
+}
+
+// ByType adds up the quantities of each reward type
+func (t *Totals) ByType() map[string]int {
+	out := make(map[string]int)
+	for _, a := range t.amounts {
+		out[a.Type] += a.Quantity
+	}
+	return out
+}
+
+// Amounts lists every reward, ordered by type and name
+func (t *Totals) Amounts() []Amount {
+	out := make([]Amount, 0, len(t.amounts))
+	for _, a := range t.amounts {
+		out = append(out, *a)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].String() < out[j].String()
+	})
+	return out
+}
+
+// encodes as the Amounts list
+func (t *Totals) MarshalJSON() ([]byte, error) {
+	return json.Marshal(t.Amounts())
+}
@@ -0,0 +1,56 @@
+package hub
+
+import "github.com/ilyskies/QuestHub/pkg/hub/rewards"
+
+// TotalRewards adds up the rewards of every quest in the bundle and the
+// bundle's completion rewards
+func (b *AthenaChallengeBundle) TotalRewards() *rewards.Totals {
+	t := rewards.NewTotals()
+	for _, o := range b.Objects {
+		for _, r := range o.Rewards {
+			t.Add(r.TemplateID, r.Quantity)
+		}
+	}
+	for _, r := range b.CompletionRewards {
+		t.Add(r.TemplateID, r.Quantity)
+	}
+	return t
+}
+
+func (s BundleSet) TotalRewards() *rewards.Totals {
+	t := rewards.NewTotals()
+	for i := range s {
+		t.Merge(s[i].TotalRewards())
+	}
+	return t
+}
+
+// TotalRewardsBySchedule groups the totals by challenge bundle schedule
+func (s BundleSet) TotalRewardsBySchedule() map[string]*rewards.Totals {
+	out := make(map[string]*rewards.Totals)
+	for i := range s {
+		t, ok := out[s[i].ChallengeBundleSchedule]
+		if !ok {
+			t = rewards.NewTotals()
+			out[s[i].ChallengeBundleSchedule] = t
+		}
+		t.Merge(s[i].TotalRewards())
+	}
+	return out
+}
+
+func (s QuestSet) TotalRewards() *rewards.Totals {
+	t := rewards.NewTotals()
+	for _, q := range s {
+		for _, r := range q.Rewards {
+			t.Add(r.TemplateID, r.Quantity)
+		}
+	}
+	return t
+}
+
+// ClassifyReward sorts a reward template ID into a category, see
+// rewards.DefaultRules. For totals per category use TotalRewards().ByCategory().
+func ClassifyReward(templateID string) rewards.Category {
+	return rewards.Classify(templateID)
+}
@@ -0,0 +1,99 @@
+package hub
+
+// Rollover is a new season replacing everything the hub served: the hub
+// reports a new version and no quest, bundle or schedule carried over.
+// Watchers and SeasonDiff report it as one change instead of a removal and
+// an addition for every item.
+type Rollover struct {
+	FromVersion string `json:"fromVersion"`
+	ToVersion   string `json:"toVersion"`
+	// what the old season had and the new one has
+	Removed RolloverCounts `json:"removed"`
+	Added   RolloverCounts `json:"added"`
+
+	// the new season's data, for consumers that rebuild from it
+	Snapshot *Snapshot `json:"-"`
+}
+
+type RolloverCounts struct {
+	Quests    int `json:"quests"`
+	Bundles   int `json:"bundles"`
+	Schedules int `json:"schedules"`
+}
+
+func (c RolloverCounts) Total() int {
+	return c.Quests + c.Bundles + c.Schedules
+}
+
+// DetectRollover reports whether newSnap starts a new season on top of
+// oldSnap. Both need a version and data; a deployment that keeps any of the
+// data is an ordinary change.
+func DetectRollover(oldSnap, newSnap *Snapshot) (*Rollover, bool) {
+	if oldSnap == nil || newSnap == nil {
+		return nil, false
+	}
+	from, to := snapshotVersion(oldSnap), snapshotVersion(newSnap)
+	if from == "" || to == "" || from == to {
+		return nil, false
+	}
+
+	r := &Rollover{
+		FromVersion: from,
+		ToVersion:   to,
+		Removed:     countsOf(oldSnap),
+		Added:       countsOf(newSnap),
+		Snapshot:    newSnap,
+	}
+	if r.Removed.Total() == 0 || r.Added.Total() == 0 {
+		return nil, false
+	}
+
+	for id := range oldSnap.DailyQuests {
+		if _, ok := newSnap.DailyQuests[id]; ok {
+			return nil, false
+		}
+	}
+	bundleID := func(b AthenaChallengeBundle) string { return b.TemplateID }
+	if sharesKey(indexBy(oldSnap.Bundles, bundleID), indexBy(newSnap.Bundles, bundleID)) {
+		return nil, false
+	}
+	scheduleID := func(s ChallengeBundleSchedule) string { return s.TemplateID }
+	if sharesKey(indexBy(oldSnap.Schedules, scheduleID), indexBy(newSnap.Schedules, scheduleID)) {
+		return nil, false
+	}
+	return r, true
+}
+
+// SeasonDiff is Diff, except that a rollover comes back as a change set
+// holding only Rollover
+func SeasonDiff(oldSnap, newSnap *Snapshot) ChangeSet {
+	r, ok := DetectRollover(oldSnap, newSnap)
+	if !ok {
+		return Diff(oldSnap, newSnap)
+	}
+	return ChangeSet{
+		Rollover: r,
+		From:     diffProvenance(oldSnap.Provenance),
+		To:       diffProvenance(newSnap.Provenance),
+	}
+}
+
+func snapshotVersion(s *Snapshot) string {
+	if s.Status != nil && s.Status.Version != "" {
+		return s.Status.Version
+	}
+	return s.Provenance.ServerVersion
+}
+
+func countsOf(s *Snapshot) RolloverCounts {
+	return RolloverCounts{Quests: len(s.DailyQuests), Bundles: len(s.Bundles), Schedules: len(s.Schedules)}
+}
+
+func sharesKey[T any](a, b map[string]T) bool {
+	for k := range a {
+		if _, ok := b[k]; ok {
+			return true
+		}
+	}
+	return false
+}
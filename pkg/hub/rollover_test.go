@@ -0,0 +1,47 @@
+package hub
+
+import "testing"
+
+func TestDetectRollover(t *testing.T) {
+	season := func(version string, ids ...string) *Snapshot {
+		s := &Snapshot{Status: &ServiceStatus{Version: version}, DailyQuests: map[string]BaseQuest{}}
+		for _, id := range ids {
+			s.DailyQuests["Quest_"+id] = BaseQuest{Count: 1}
+			s.Bundles = append(s.Bundles, AthenaChallengeBundle{TemplateID: "ChallengeBundle:" + id})
+		}
+		return s
+	}
+
+	cases := []struct {
+		name     string
+		old, new *Snapshot
+		rollover bool
+	}{
+		{"new season", season("9.0", "a", "b"), season("10.0", "c"), true},
+		{"same version", season("9.0", "a"), season("9.0", "c"), false},
+		{"data kept", season("9.0", "a", "b"), season("10.0", "b", "c"), false},
+		{"no version", season("", "a"), season("10.0", "c"), false},
+		{"empty before", season("9.0"), season("10.0", "c"), false},
+		{"empty after", season("9.0", "a"), season("10.0"), false},
+	}
+	for _, tc := range cases {
+		r, ok := DetectRollover(tc.old, tc.new)
+		if ok != tc.rollover {
+			t.Errorf("%s: rollover %v, want %v", tc.name, ok, tc.rollover)
+			continue
+		}
+		if !ok {
+			if cs := SeasonDiff(tc.old, tc.new); cs.Rollover != nil {
+				t.Errorf("%s: SeasonDiff reported a rollover", tc.name)
+			}
+			continue
+		}
+		if r.FromVersion != "9.0" || r.ToVersion != "10.0" || r.Removed.Total() != 4 || r.Added.Total() != 2 || r.Snapshot != tc.new {
+			t.Errorf("%s: %+v", tc.name, r)
+		}
+		cs := SeasonDiff(tc.old, tc.new)
+		if cs.Rollover == nil || cs.Len() != 0 || cs.Empty() {
+			t.Errorf("%s: SeasonDiff = %+v, want only the rollover", tc.name, cs)
+		}
+	}
+}
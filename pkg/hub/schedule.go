@@ -0,0 +1,35 @@
+package hub
+
+import (
+	"context"
+	"time"
+)
+
+// IsActive reports whether t falls inside the schedule's window; ActiveUntil
+// is exclusive
+func (s ChallengeBundleSchedule) IsActive(t time.Time) bool {
+	if !s.ActiveFrom.IsZero() && t.Before(s.ActiveFrom) {
+		return false
+	}
+	if !s.ActiveUntil.IsZero() && !t.Before(s.ActiveUntil) {
+		return false
+	}
+	return true
+}
+
+// GetActiveSchedules returns the schedules active at the given time, hidden
+// ones included
+func (c *Client) GetActiveSchedules(ctx context.Context, at time.Time, opts ...CallOption) ([]ChallengeBundleSchedule, error) {
+	schedules, err := c.GetChallengeBundleSchedules(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]ChallengeBundleSchedule, 0, len(schedules))
+	for _, s := range schedules {
+		if s.IsActive(at) {
+			out = append(out, s)
+		}
+	}
+	return out, nil
+}
@@ -0,0 +1,75 @@
+package hub
+
+//go:generate go run github.com/ilyskies/QuestHub/cmd/hubgen -o season_gen.go season.yaml
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// GetWeeklyChallenges returns the bundles of one week. Hubs without the
+// method are served from GetChallengeBundles, grouped by the week in their
+// template IDs; UnlockAt is then zero.
+func (c *Client) GetWeeklyChallenges(ctx context.Context, week int, opts ...CallOption) (*WeeklyChallenges, error) {
+	if week < 1 {
+		return nil, fmt.Errorf("%w: %d", ErrInvalidWeek, week)
+	}
+
+	ctx = ContextWithCallOptions(ctx, opts...)
+	out, err := Invoke[WeeklyChallenges](ctx, c, "GetWeeklyChallenges", week)
+	if err == nil {
+		return &out, nil
+	}
+	if !errors.Is(err, ErrMethodNotFound) {
+		return nil, err
+	}
+
+	bundles, err := c.GetChallengeBundles(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return weeklyFromBundles(bundles, week), nil
+}
+
+func weeklyFromBundles(bundles []AthenaChallengeBundle, week int) *WeeklyChallenges {
+	out := &WeeklyChallenges{Week: week, Bundles: []AthenaChallengeBundle{}}
+	for i := range bundles {
+		season, w, ok := bundleWeek(&bundles[i])
+		if !ok || w != week {
+			continue
+		}
+		if out.Season == 0 {
+			out.Season = season
+		}
+		out.Bundles = append(out.Bundles, bundles[i])
+	}
+	return out
+}
+
+// Week returns the given week of the season, if the hub listed it
+func (s *SeasonInfo) Week(week int) (WeekInfo, bool) {
+	for _, w := range s.Weeks {
+		if w.Week == week {
+			return w, true
+		}
+	}
+	return WeekInfo{}, false
+}
+
+// CurrentWeek is the latest week unlocked at t, or 0 before the first one
+// and outside the season
+func (s *SeasonInfo) CurrentWeek(t time.Time) int {
+	if t.Before(s.Start) || (!s.End.IsZero() && !t.Before(s.End)) {
+		return 0
+	}
+
+	current := 0
+	for _, w := range s.Weeks {
+		if !t.Before(w.UnlockAt) && w.Week > current {
+			current = w.Week
+		}
+	}
+	return current
+}
@@ -0,0 +1,63 @@
+// Code generated by hubgen from season.yaml. DO NOT EDIT.
+
+package hub
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// SeasonService is implemented by Client and SeasonMock
+type SeasonService interface {
+	GetSeasonInfo(ctx context.Context, opts ...CallOption) (*SeasonInfo, error)
+}
+
+var (
+	_ SeasonService = (*Client)(nil)
+	_ SeasonService = (*SeasonMock)(nil)
+)
+
+// GetSeasonInfo returns the season number, its dates and when each week
+// unlocks. Hubs that predate it fail with an error matching ErrMethodNotFound.
+func (c *Client) GetSeasonInfo(ctx context.Context, opts ...CallOption) (*SeasonInfo, error) {
+	out, err := Invoke[SeasonInfo](ContextWithCallOptions(ctx, opts...), c, "GetSeasonInfo")
+	if err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// SeasonMock implements SeasonService with a func per method, for tests of code
+// built on the generated methods. A method whose func is nil fails with
+// ErrMethodNotFound.
+type SeasonMock struct {
+	GetSeasonInfoFunc func(ctx context.Context) (*SeasonInfo, error)
+
+	mu    sync.Mutex
+	calls map[string]int
+}
+
+// Calls reports how often method was called
+func (m *SeasonMock) Calls(method string) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.calls[method]
+}
+
+func (m *SeasonMock) record(method string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.calls == nil {
+		m.calls = make(map[string]int)
+	}
+	m.calls[method]++
+}
+
+func (m *SeasonMock) GetSeasonInfo(ctx context.Context, _ ...CallOption) (*SeasonInfo, error) {
+	m.record("GetSeasonInfo")
+	if m.GetSeasonInfoFunc == nil {
+		return nil, fmt.Errorf("%w: %s", ErrMethodNotFound, "GetSeasonInfo")
+	}
+	return m.GetSeasonInfoFunc(ctx)
+}
@@ -0,0 +1,22 @@
+package hub
+
+import "context"
+
+// Service is the hub API shared by Client, the clients handed out by
+// hubtest.Server and FileBackend. Depend on it to swap a live hub for canned
+// data in tests or offline runs.
+type Service interface {
+	GetServiceStatus(ctx context.Context, opts ...CallOption) (*ServiceStatus, error)
+	GetDailyQuests(ctx context.Context, opts ...CallOption) (map[string]BaseQuest, error)
+	GetDailyQuest(ctx context.Context, questID string, opts ...CallOption) (*BaseQuest, error)
+	GetChallengeBundles(ctx context.Context, opts ...CallOption) ([]AthenaChallengeBundle, error)
+	GetChallengeBundle(ctx context.Context, templateID string, opts ...CallOption) (*AthenaChallengeBundle, error)
+	GetChallengeBundleSchedules(ctx context.Context, opts ...CallOption) ([]ChallengeBundleSchedule, error)
+	ClearCache(ctx context.Context, opts ...CallOption) (*CacheResult, error)
+	RefreshCache(ctx context.Context, opts ...CallOption) (string, error)
+}
+
+var (
+	_ Service = (*Client)(nil)
+	_ Service = (*FileBackend)(nil)
+)
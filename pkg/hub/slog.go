@@ -0,0 +1,130 @@
+package hub
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/philippseith/signalr"
+)
+
+// slogLogger adapts the printf-style Logger calls to a slog.Logger
+type slogLogger struct {
+	l *slog.Logger
+}
+
+func (s slogLogger) Debug(msg string, args ...interface{}) { s.log(slog.LevelDebug, msg, args) }
+func (s slogLogger) Info(msg string, args ...interface{})  { s.log(slog.LevelInfo, msg, args) }
+func (s slogLogger) Warn(msg string, args ...interface{})  { s.log(slog.LevelWarn, msg, args) }
+func (s slogLogger) Error(msg string, args ...interface{}) { s.log(slog.LevelError, msg, args) }
+
+func (s slogLogger) log(level slog.Level, msg string, args []interface{}) {
+	ctx := context.Background()
+	if !s.l.Enabled(ctx, level) {
+		return
+	}
+	if len(args) > 0 {
+		msg = fmt.Sprintf(msg, args...)
+	}
+	s.l.Log(ctx, level, msg)
+}
+
+// signalrSlogLogger bridges the keyvals logger of the signalr library. Its
+// go-kit level key picks the slog level; the other pairs become attrs.
+type signalrSlogLogger struct {
+	l *slog.Logger
+}
+
+func (s signalrSlogLogger) Log(keyvals ...interface{}) error {
+	level := slog.LevelDebug
+	attrs := make([]slog.Attr, 0, len(keyvals)/2)
+	for i := 0; i+1 < len(keyvals); i += 2 {
+		switch key := fmt.Sprint(keyvals[i]); key {
+		case "level":
+			level = signalrLevel(fmt.Sprint(keyvals[i+1]))
+		case "ts":
+			// slog stamps its own time
+		default:
+			attrs = append(attrs, slog.Any(key, keyvals[i+1]))
+		}
+	}
+
+	s.l.LogAttrs(context.Background(), level, "signalr", attrs...)
+	return nil
+}
+
+// go-kit's level values; anything else is logged at info
+func signalrLevel(v string) slog.Level {
+	switch v {
+	case "debug":
+		return slog.LevelDebug
+	case "warn":
+		return slog.LevelWarn
+	case "error":
+		return slog.LevelError
+	default:
+		return slog.LevelInfo
+	}
+}
+
+func (c *Client) signalrLogger() (signalr.StructuredLogger, bool) {
+	if c.slog == nil {
+		return noopSignalRLogger{}, false
+	}
+	return signalrSlogLogger{l: c.slog}, c.slog.Enabled(context.Background(), slog.LevelDebug)
+}
+
+// logInvoke writes one structured record per invocation; failures are logged
+// at warn level, everything else at debug
+func (c *Client) logInvoke(ctx context.Context, method, id, wireID string, start time.Time, cached bool, err error) {
+	if c.slog == nil {
+		return
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	level := slog.LevelDebug
+	if err != nil {
+		level = slog.LevelWarn
+	}
+	if !c.slog.Enabled(ctx, level) {
+		return
+	}
+
+	attrs := []slog.Attr{
+		slog.String("method", method),
+		slog.Duration("duration", time.Since(start)),
+		slog.String("connection_id", c.ConnectionInfo().ConnectionID),
+	}
+	if id != "" {
+		attrs = append(attrs, slog.String("correlation_id", id))
+	}
+	if wireID != "" {
+		attrs = append(attrs, slog.String("invocation_id", wireID))
+	}
+	if cached {
+		attrs = append(attrs, slog.Bool("cached", true))
+	}
+	if err != nil {
+		attrs = append(attrs, slog.String("error", err.Error()))
+	}
+	c.slog.LogAttrs(ctx, level, "hub invoke", attrs...)
+}
+
+// logSlowCall warns about a call that reached the slow-call threshold
+func (c *Client) logSlowCall(method, id string, start time.Time, size int, err error) {
+	if c.slowCallThreshold <= 0 {
+		return
+	}
+	elapsed := time.Since(start)
+	if elapsed < c.slowCallThreshold {
+		return
+	}
+	if err != nil {
+		c.logger.Warn("Slow call %s [%s] failed after %s: %v", method, id, elapsed, err)
+		return
+	}
+	c.logger.Warn("Slow call %s [%s] took %s (%d bytes)", method, id, elapsed, size)
+}
@@ -0,0 +1,49 @@
+package hub
+
+import (
+	"context"
+	"time"
+)
+
+// Snapshot is the hub's full dataset at one point in time
+type Snapshot struct {
+	TakenAt     time.Time                 `json:"takenAt"`
+	Status      *ServiceStatus            `json:"status"`
+	DailyQuests map[string]BaseQuest      `json:"dailyQuests"`
+	Bundles     []AthenaChallengeBundle   `json:"bundles"`
+	Schedules   []ChallengeBundleSchedule `json:"schedules"`
+	Provenance  Provenance                `json:"provenance"`
+
+	// display names operators gave quests, bundles and schedules, by ID
+	Names map[string]string `json:"names,omitempty"`
+}
+
+// Snapshot fetches everything concurrently. Like Batch.Run it returns the
+// parts that succeeded alongside a *MultiError for the ones that did not.
+func (c *Client) Snapshot(ctx context.Context) (*Snapshot, error) {
+	res, err := c.Batch(ctx).
+		GetServiceStatus().
+		GetDailyQuests().
+		GetChallengeBundles().
+		GetChallengeBundleSchedules().
+		Run()
+
+	now := time.Now().UTC()
+	snap := &Snapshot{
+		TakenAt:     now,
+		Status:      res.Status,
+		DailyQuests: res.DailyQuests,
+		Bundles:     res.Bundles,
+		Schedules:   res.Schedules,
+		Provenance:  c.provenance(now, res.Status),
+	}
+	if err == nil && snap.complete() {
+		delta := c.live.swap(snap)
+		c.metrics.liveBytes(delta)
+		if c.cache != nil {
+			c.cache.reserve(c.live.Load().size)
+		}
+		c.markWarm(snap)
+	}
+	return snap, err
+}
@@ -0,0 +1,81 @@
+package hub
+
+import (
+	"fmt"
+	"time"
+)
+
+type ConnectionState int
+
+const (
+	// never connected, or the connection dropped
+	StateDisconnected ConnectionState = iota
+	StateConnecting
+	StateConnected
+	// connecting again after an earlier connection was lost
+	StateReconnecting
+	// stopped on purpose with Disconnect
+	StateClosed
+)
+
+func (s ConnectionState) String() string {
+	switch s {
+	case StateDisconnected:
+		return "Disconnected"
+	case StateConnecting:
+		return "Connecting"
+	case StateConnected:
+		return "Connected"
+	case StateReconnecting:
+		return "Reconnecting"
+	case StateClosed:
+		return "Closed"
+	default:
+		return fmt.Sprintf("ConnectionState(%d)", int(s))
+	}
+}
+
+func (c *Client) State() ConnectionState {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.state
+}
+
+// handlers run in their own goroutines, so under quick successive changes they
+// may observe transitions out of order; State() is always current
+func (c *Client) OnStateChange(handler func(old, new ConnectionState)) {
+	c.handleEvents(EventStateChanged, func(e Event) { handler(e.OldState, e.NewState) })
+}
+
+// callers hold c.mu
+func (c *Client) setStateLocked(next ConnectionState) {
+	prev := c.state
+	if prev == next {
+		return
+	}
+	c.state = next
+
+	if next == StateConnected {
+		c.connInfo.ConnectedAt = time.Now()
+	}
+
+	c.logger.Debug("Connection state %s -> %s", prev, next)
+
+	c.emit(Event{Kind: EventStateChanged, OldState: prev, NewState: next})
+	if prev == StateReconnecting && next == StateConnected {
+		c.emit(Event{Kind: EventReconnected})
+	}
+}
+
+// the hub can only call Ready over a working connection, so it may mark the
+// client connected before signalr's own state notification arrives
+func (c *Client) markConnected() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.state != StateConnecting && c.state != StateReconnecting {
+		return false
+	}
+	c.setStateLocked(StateConnected)
+	return true
+}
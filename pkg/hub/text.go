@@ -0,0 +1,74 @@
+package hub
+
+import (
+	"strconv"
+	"strings"
+)
+
+// ExpandText replaces {name} placeholders with vars[name]; unknown
+// placeholders are left untouched so missing data stays visible
+func ExpandText(template string, vars map[string]string) string {
+	var b strings.Builder
+	b.Grow(len(template))
+
+	for {
+		start := strings.IndexByte(template, '{')
+		if start < 0 {
+			break
+		}
+		end := strings.IndexByte(template[start:], '}')
+		if end < 0 {
+			break
+		}
+		end += start
+
+		name := template[start+1 : end]
+		b.WriteString(template[:start])
+		if v, ok := vars[name]; ok {
+			b.WriteString(v)
+		} else {
+			b.WriteString(template[start : end+1])
+		}
+		template = template[end+1:]
+	}
+
+	b.WriteString(template)
+	return b.String()
+}
+
+func ObjectiveTextVars(obj ChallengeBundleObjective) map[string]string {
+	return map[string]string{
+		"count":       strconv.Itoa(obj.Count),
+		"stage":       strconv.Itoa(obj.Stage),
+		"backendName": obj.BackendName,
+	}
+}
+
+// count is the first objective's count since most quests have one objective;
+// each objective is also exposed as count0, count1, ...
+func QuestTextVars(obj ChallengeBundleObject) map[string]string {
+	vars := map[string]string{
+		"quest":  obj.QuestDefinition,
+		"rarity": obj.Rarity,
+		"stage":  strconv.Itoa(objectStage(obj)),
+	}
+
+	for i, o := range obj.Objectives {
+		idx := strconv.Itoa(i)
+		vars["count"+idx] = strconv.Itoa(o.Count)
+		vars["backendName"+idx] = o.BackendName
+		if i == 0 {
+			vars["count"] = strconv.Itoa(o.Count)
+			vars["backendName"] = o.BackendName
+		}
+	}
+	return vars
+}
+
+func FormatObjectiveText(template string, obj ChallengeBundleObjective) string {
+	return ExpandText(template, ObjectiveTextVars(obj))
+}
+
+func FormatQuestText(template string, obj ChallengeBundleObject) string {
+	return ExpandText(template, QuestTextVars(obj))
+}
@@ -0,0 +1,97 @@
+package hub
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/philippseith/signalr"
+)
+
+type Transport int
+
+const (
+	TransportWebSockets Transport = 1 << iota
+	TransportServerSentEvents
+)
+
+const defaultTransports = TransportWebSockets | TransportServerSentEvents
+
+func (t Transport) String() string {
+	switch t {
+	case TransportWebSockets:
+		return string(signalr.TransportWebSockets)
+	case TransportServerSentEvents:
+		return string(signalr.TransportServerSentEvents)
+	default:
+		return fmt.Sprintf("Transport(%d)", int(t))
+	}
+}
+
+// fallback order, most capable first
+var transportOrder = []Transport{
+	TransportWebSockets,
+	TransportServerSentEvents,
+}
+
+func (c *Client) newConnection(ctx context.Context) (signalr.Connection, Transport, error) {
+	allowed := c.transports
+	if allowed == 0 {
+		allowed = defaultTransports
+	}
+
+	var errs []error
+	for _, t := range transportOrder {
+		if allowed&t == 0 {
+			continue
+		}
+
+		conn, err := c.dialTransport(ctx, t)
+		if err == nil {
+			return conn, t, nil
+		}
+
+		// a pin mismatch must not be retried over a weaker path
+		if errors.Is(err, ErrCertMismatch) {
+			return nil, 0, err
+		}
+
+		c.logger.Warn("Transport %s failed: %v", t, err)
+		errs = append(errs, fmt.Errorf("%s: %w", t, err))
+	}
+
+	if len(errs) == 0 {
+		return nil, 0, ErrTransportUnsupported
+	}
+	return nil, 0, errors.Join(errs...)
+}
+
+func (c *Client) dialTransport(ctx context.Context, t Transport) (signalr.Connection, error) {
+	switch t {
+	case TransportWebSockets:
+		// signalr's own dialer can neither negotiate compression nor limit
+		// reads on a connection wrapped by traceInvocations
+		httpClient, err := c.dialHTTPClient()
+		if err != nil {
+			return nil, err
+		}
+		return dialWebSocket(ctx, c.ctx, httpClient, c.url, c.protocol.transferFormat(), c.identityHeaders(), c.compression, c.maxResponseSize, c.resumeOptions())
+
+	case TransportServerSentEvents:
+		if c.protocol == ProtocolMessagePack {
+			return nil, fmt.Errorf("%w: server-sent events cannot carry MessagePack", ErrTransportUnsupported)
+		}
+		if c.customHTTP() {
+			// the SSE transport posts with its own http client, so neither a
+			// pin nor the caller's client or TLS settings could be enforced
+			return nil, fmt.Errorf("%w: custom HTTP or TLS settings require websockets", ErrTransportUnsupported)
+		}
+		return signalr.NewHTTPConnection(ctx, c.url,
+			signalr.WithTransports(signalr.TransportServerSentEvents),
+			signalr.WithHTTPHeaders(c.identityHeaders),
+		)
+
+	default:
+		return nil, ErrTransportUnsupported
+	}
+}
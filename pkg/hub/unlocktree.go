@@ -0,0 +1,130 @@
+package hub
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type UnlockNode struct {
+	QuestDefinition string                     `json:"questDefinition"`
+	Stage           int                        `json:"stage"`
+	Objectives      []ChallengeBundleObjective `json:"objectives"`
+	Children        []*UnlockNode              `json:"children,omitempty"`
+}
+
+type UnlockTree struct {
+	Bundle string        `json:"bundle"`
+	Roots  []*UnlockNode `json:"roots"`
+}
+
+// quests sharing a definition prefix (e.g. Quest_Foo_01, Quest_Foo_02) form
+// a chain ordered by objective stage; unstaged quests are standalone roots
+func BuildUnlockTree(bundle AthenaChallengeBundle) *UnlockTree {
+	tree := &UnlockTree{Bundle: bundle.TemplateID}
+
+	chains := make(map[string][]*UnlockNode)
+	var order []string
+
+	for _, obj := range bundle.Objects {
+		node := &UnlockNode{
+			QuestDefinition: obj.QuestDefinition,
+			Stage:           objectStage(obj),
+			Objectives:      obj.Objectives,
+		}
+
+		if node.Stage == 0 {
+			key := "\x00" + obj.QuestDefinition
+			chains[key] = append(chains[key], node)
+			order = append(order, key)
+			continue
+		}
+
+		key := chainKey(obj.QuestDefinition)
+		if _, ok := chains[key]; !ok {
+			order = append(order, key)
+		}
+		chains[key] = append(chains[key], node)
+	}
+
+	for _, key := range order {
+		chain := chains[key]
+		sort.SliceStable(chain, func(i, j int) bool {
+			return chain[i].Stage < chain[j].Stage
+		})
+		for i := 1; i < len(chain); i++ {
+			chain[i-1].Children = append(chain[i-1].Children, chain[i])
+		}
+		tree.Roots = append(tree.Roots, chain[0])
+	}
+
+	return tree
+}
+
+func objectStage(obj ChallengeBundleObject) int {
+	stage := 0
+	for _, o := range obj.Objectives {
+		if o.Stage > stage {
+			stage = o.Stage
+		}
+	}
+	return stage
+}
+
+func chainKey(questDefinition string) string {
+	i := strings.LastIndex(questDefinition, "_")
+	if i < 0 {
+		return questDefinition
+	}
+	if _, err := strconv.Atoi(questDefinition[i+1:]); err != nil {
+		return questDefinition
+	}
+	return questDefinition[:i]
+}
+
+func (t *UnlockTree) Walk(fn func(node *UnlockNode, depth int)) {
+	var walk func(n *UnlockNode, depth int)
+	walk = func(n *UnlockNode, depth int) {
+		fn(n, depth)
+		for _, child := range n.Children {
+			walk(child, depth+1)
+		}
+	}
+	for _, root := range t.Roots {
+		walk(root, 0)
+	}
+}
+
+func (t *UnlockTree) Mermaid() string {
+	var b strings.Builder
+	b.WriteString("graph TD\n")
+	fmt.Fprintf(&b, "    root[%s]\n", mermaidLabel(t.Bundle))
+
+	ids := make(map[*UnlockNode]string)
+	t.Walk(func(n *UnlockNode, _ int) {
+		id := fmt.Sprintf("q%d", len(ids))
+		ids[n] = id
+
+		label := n.QuestDefinition
+		if n.Stage > 0 {
+			label = fmt.Sprintf("%s (stage %d)", label, n.Stage)
+		}
+		fmt.Fprintf(&b, "    %s[%s]\n", id, mermaidLabel(label))
+	})
+
+	for _, root := range t.Roots {
+		fmt.Fprintf(&b, "    root --> %s\n", ids[root])
+	}
+	t.Walk(func(n *UnlockNode, _ int) {
+		for _, child := range n.Children {
+			fmt.Fprintf(&b, "    %s --> %s\n", ids[n], ids[child])
+		}
+	})
+
+	return b.String()
+}
+
+func mermaidLabel(s string) string {
+	return `"` + strings.ReplaceAll(s, `"`, "#quot;") + `"`
+}
@@ -0,0 +1,25 @@
+package hub
+
+func (r *hubReceiver) QuestUpdated(update QuestUpdate) {
+	r.client.emit(Event{Kind: EventQuestUpdated, Quest: update})
+}
+
+func (r *hubReceiver) BundleUpdated(update BundleUpdate) {
+	r.client.emit(Event{Kind: EventBundleUpdated, Bundle: update})
+}
+
+func (r *hubReceiver) ScheduleChanged(change ScheduleChange) {
+	r.client.emit(Event{Kind: EventScheduleChanged, Schedule: change})
+}
+
+func (c *Client) OnQuestUpdated(handler func(QuestUpdate)) {
+	c.handleEvents(EventQuestUpdated, func(e Event) { handler(e.Quest) })
+}
+
+func (c *Client) OnBundleUpdated(handler func(BundleUpdate)) {
+	c.handleEvents(EventBundleUpdated, func(e Event) { handler(e.Bundle) })
+}
+
+func (c *Client) OnScheduleChanged(handler func(ScheduleChange)) {
+	c.handleEvents(EventScheduleChanged, func(e Event) { handler(e.Schedule) })
+}
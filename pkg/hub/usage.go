@@ -0,0 +1,248 @@
+package hub
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// how often at most a call saves the usage of WithUsageFile; Disconnect
+// and SaveUsage save it at once
+const usageSaveInterval = time.Minute
+
+// usage is bucketed per method per UTC day. Bytes are the size of the
+// hub's completion messages as framed on the connection, before websocket
+// compression, so MessagePack clients count MessagePack.
+type MethodUsage struct {
+	Method string `json:"method"`
+	Day    string `json:"day"`
+	Calls  int64  `json:"calls"`
+	Errors int64  `json:"errors"`
+	Bytes  int64  `json:"bytes"`
+}
+
+type UsageReport struct {
+	Since   time.Time     `json:"since"`
+	Entries []MethodUsage `json:"entries"`
+}
+
+func (r UsageReport) TotalCalls() int64 {
+	var n int64
+	for _, e := range r.Entries {
+		n += e.Calls
+	}
+	return n
+}
+
+func (r UsageReport) TotalErrors() int64 {
+	var n int64
+	for _, e := range r.Entries {
+		n += e.Errors
+	}
+	return n
+}
+
+func (r UsageReport) TotalBytes() int64 {
+	var n int64
+	for _, e := range r.Entries {
+		n += e.Bytes
+	}
+	return n
+}
+
+type usageKey struct {
+	method string
+	day    string
+}
+
+type usageTracker struct {
+	mu      sync.Mutex
+	since   time.Time
+	entries map[usageKey]*MethodUsage
+
+	// WithUsageFile; saves are serialised by saveMu, not mu
+	path      string
+	saveMu    sync.Mutex
+	dirty     bool
+	lastSaved time.Time
+}
+
+func newUsageTracker() *usageTracker {
+	return &usageTracker{
+		since:   time.Now().UTC(),
+		entries: make(map[usageKey]*MethodUsage),
+	}
+}
+
+// LoadUsage reads a report saved by a client with WithUsageFile. A missing
+// file is an empty report.
+func LoadUsage(path string) (UsageReport, error) {
+	b, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return UsageReport{}, nil
+	}
+	if err != nil {
+		return UsageReport{}, fmt.Errorf("read usage: %w", err)
+	}
+
+	var r UsageReport
+	if err := json.Unmarshal(b, &r); err != nil {
+		return UsageReport{}, fmt.Errorf("read usage %s: %w", path, err)
+	}
+	return r, nil
+}
+
+// load continues the report saved at path
+func (u *usageTracker) load(path string) error {
+	r, err := LoadUsage(path)
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	u.path = path
+	u.lastSaved = time.Now()
+	if err != nil {
+		return err
+	}
+
+	if !r.Since.IsZero() {
+		u.since = r.Since
+	}
+	for _, e := range r.Entries {
+		u.entries[usageKey{method: e.Method, day: e.Day}] = &e
+	}
+	return nil
+}
+
+// save writes the report when it changed since the last save; with due
+// set only once usageSaveInterval has passed
+func (u *usageTracker) save(due bool) error {
+	u.saveMu.Lock()
+	defer u.saveMu.Unlock()
+
+	u.mu.Lock()
+	if u.path == "" || !u.dirty || (due && time.Since(u.lastSaved) < usageSaveInterval) {
+		u.mu.Unlock()
+		return nil
+	}
+	u.dirty = false
+	u.lastSaved = time.Now()
+	u.mu.Unlock()
+
+	b, err := json.MarshalIndent(u.report(), "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := writeFileAtomic(u.path, b); err != nil {
+		u.mu.Lock()
+		u.dirty = true
+		u.mu.Unlock()
+		return fmt.Errorf("save usage: %w", err)
+	}
+	return nil
+}
+
+func writeFileAtomic(path string, b []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(b); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+func (u *usageTracker) record(method string, bytes int, failed bool) {
+	day := time.Now().UTC().Format(time.DateOnly)
+	key := usageKey{method: method, day: day}
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	e, ok := u.entries[key]
+	if !ok {
+		e = &MethodUsage{Method: method, Day: day}
+		u.entries[key] = e
+	}
+
+	e.Calls++
+	e.Bytes += int64(bytes)
+	if failed {
+		e.Errors++
+	}
+	u.dirty = true
+}
+
+func (u *usageTracker) report() UsageReport {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	out := UsageReport{
+		Since:   u.since,
+		Entries: make([]MethodUsage, 0, len(u.entries)),
+	}
+	for _, e := range u.entries {
+		out.Entries = append(out.Entries, *e)
+	}
+
+	sort.Slice(out.Entries, func(i, j int) bool {
+		if out.Entries[i].Day != out.Entries[j].Day {
+			return out.Entries[i].Day < out.Entries[j].Day
+		}
+		return out.Entries[i].Method < out.Entries[j].Method
+	})
+	return out
+}
+
+func (u *usageTracker) reset() {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.since = time.Now().UTC()
+	u.entries = make(map[usageKey]*MethodUsage)
+	u.dirty = true
+}
+
+// recordUsage counts a call and, with WithUsageFile, saves the usage when a
+// save is due
+func (c *Client) recordUsage(method string, bytes int, failed bool) {
+	c.usage.record(method, bytes, failed)
+	if err := c.usage.save(true); err != nil {
+		c.logger.Warn("%v", err)
+	}
+}
+
+// Usage covers this client's calls, and with WithUsageFile those of earlier
+// clients using the same file
+func (c *Client) Usage() UsageReport {
+	return c.usage.report()
+}
+
+// ResetUsage starts the report over, in the WithUsageFile file too
+func (c *Client) ResetUsage() {
+	c.usage.reset()
+	if err := c.usage.save(false); err != nil {
+		c.logger.Warn("%v", err)
+	}
+}
+
+// SaveUsage writes the usage to the WithUsageFile file now rather than
+// waiting for Disconnect or the next periodic save
+func (c *Client) SaveUsage() error {
+	return c.usage.save(false)
+}
@@ -0,0 +1,152 @@
+package hub
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"sort"
+	"strings"
+)
+
+type DivergenceKind string
+
+const (
+	// the live result differs from the local one
+	DivergenceStale DivergenceKind = "stale"
+	// the hub no longer answers the cached call, or no longer has an entry
+	// a store holds, e.g. the quest was removed and the removal never
+	// reached the local state
+	DivergenceOrphaned DivergenceKind = "orphaned"
+	// the hub has an entry a store lacks
+	DivergenceMissing DivergenceKind = "missing"
+)
+
+type CacheDivergence struct {
+	Key        string         `json:"key"`
+	Method     string         `json:"method"`
+	Kind       DivergenceKind `json:"kind"`
+	CachedHash string         `json:"cachedHash"`
+	LiveHash   string         `json:"liveHash,omitempty"`
+	Detail     string         `json:"detail,omitempty"`
+	Repaired   bool           `json:"repaired"`
+}
+
+type VerifyReport struct {
+	Checked     int               `json:"checked"`
+	Divergences []CacheDivergence `json:"divergences"`
+}
+
+type noCacheKey struct{}
+
+// calls made with this context neither read nor fill the response cache
+func withoutCache(ctx context.Context) context.Context {
+	return context.WithValue(ctx, noCacheKey{}, true)
+}
+
+func cacheBypassed(ctx context.Context) bool {
+	if ctx == nil {
+		return false
+	}
+	v, _ := ctx.Value(noCacheKey{}).(bool)
+	return v
+}
+
+// VerifyCache refetches every live entry of the response cache and reports the
+// ones that no longer match the hub. With repair set, stale entries are
+// replaced by the live result and orphaned ones are dropped.
+func (c *Client) VerifyCache(ctx context.Context, repair bool) (*VerifyReport, error) {
+	report := &VerifyReport{}
+	if c.cache == nil {
+		return report, nil
+	}
+
+	entries := c.cache.snapshot()
+	keys := make([]string, 0, len(entries))
+	for key := range entries {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		cached := entries[key]
+
+		method, args, err := parseCacheKey(key)
+		if err != nil {
+			return report, err
+		}
+
+		report.Checked++
+
+		live, err := c.invoke(withoutCache(ctx), method, args...)
+		if err != nil {
+			// only hub-side failures say something about the entry itself
+			if !errors.Is(err, ErrInvokeFailed) || errors.Is(err, ErrConnectionLost) {
+				return report, err
+			}
+
+			d := CacheDivergence{
+				Key:        key,
+				Method:     method,
+				Kind:       DivergenceOrphaned,
+				CachedHash: payloadHash(cached),
+				Detail:     err.Error(),
+			}
+			if repair {
+				c.cache.remove(key)
+				d.Repaired = true
+			}
+			report.Divergences = append(report.Divergences, d)
+			continue
+		}
+
+		cachedHash, liveHash := payloadHash(cached), payloadHash(live)
+		if cachedHash == liveHash {
+			continue
+		}
+
+		d := CacheDivergence{
+			Key:        key,
+			Method:     method,
+			Kind:       DivergenceStale,
+			CachedHash: cachedHash,
+			LiveHash:   liveHash,
+		}
+		if repair {
+			c.cache.put(key, live)
+			d.Repaired = true
+		}
+		report.Divergences = append(report.Divergences, d)
+	}
+
+	if len(report.Divergences) > 0 {
+		c.logger.Warn("Cache verification found %d divergent entries out of %d", len(report.Divergences), report.Checked)
+	}
+	return report, nil
+}
+
+func parseCacheKey(key string) (string, []interface{}, error) {
+	method, rawArgs, found := strings.Cut(key, ":")
+	if !found {
+		return method, nil, nil
+	}
+
+	var args []interface{}
+	if err := json.Unmarshal([]byte(rawArgs), &args); err != nil {
+		return "", nil, err
+	}
+	return method, args, nil
+}
+
+// compacted so whitespace differences do not count as drift
+func payloadHash(raw json.RawMessage) string {
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, raw); err != nil {
+		buf.Reset()
+		buf.Write(raw)
+	}
+	sum := sha256.Sum256(buf.Bytes())
+	return hex.EncodeToString(sum[:])
+}
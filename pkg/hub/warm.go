@@ -0,0 +1,85 @@
+package hub
+
+import (
+	"context"
+	"sync"
+)
+
+// warm is closed the first time the client holds a complete dataset
+type warmState struct {
+	once     sync.Once
+	done     chan struct{}
+	snap     *Snapshot
+	handlers []func(Snapshot)
+}
+
+// a snapshot only counts once every call succeeded and the hub reports its
+// own data as initialized
+func (s *Snapshot) complete() bool {
+	return s != nil && s.Status != nil && s.Status.Initialized &&
+		s.DailyQuests != nil && s.Bundles != nil && s.Schedules != nil
+}
+
+func (c *Client) markWarm(snap *Snapshot) {
+	if !snap.complete() {
+		return
+	}
+
+	c.warm.once.Do(func() {
+		c.mu.Lock()
+		c.warm.snap = snap
+		handlers := append([]func(Snapshot){}, c.warm.handlers...)
+		c.mu.Unlock()
+
+		close(c.warm.done)
+		c.logger.Info("Client warm - %d daily quests, %d bundles", len(snap.DailyQuests), len(snap.Bundles))
+
+		for _, h := range handlers {
+			c.runHandler(func() { h(*snap) })
+		}
+	})
+}
+
+// OnWarm fires once, with the first complete snapshot. Unlike OnReady it says
+// nothing about the connection, only that there is data to serve. Handlers
+// registered after the client warmed up run immediately.
+func (c *Client) OnWarm(handler func(Snapshot)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.warm.snap != nil {
+		snap := *c.warm.snap
+		c.runHandler(func() { handler(snap) })
+		return
+	}
+	c.warm.handlers = append(c.warm.handlers, handler)
+}
+
+// WaitWarm blocks until the client is warm or ctx is done
+func (c *Client) WaitWarm(ctx context.Context) error {
+	select {
+	case <-c.warm.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (c *Client) IsWarm() bool {
+	select {
+	case <-c.warm.done:
+		return true
+	default:
+		return false
+	}
+}
+
+func (c *Client) prefetchSnapshot() {
+	ctx, cancel := context.WithTimeout(c.ctx, c.timeout)
+	defer cancel()
+
+	// Snapshot marks the client warm itself
+	if _, err := c.Snapshot(ctx); err != nil {
+		c.logger.Warn("Prefetch failed, client stays cold until the next snapshot: %v", err)
+	}
+}
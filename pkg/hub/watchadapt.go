@@ -0,0 +1,44 @@
+package hub
+
+import (
+	"math"
+	"time"
+)
+
+// weight of the latest poll in the watcher's activity average; at 0.5 a
+// single poll with changes moves the interval halfway to the minimum
+const adaptiveAlpha = 0.5
+
+// WatchAdaptive replaces the fixed interval with one between minInterval
+// and maxInterval that follows an exponential moving average of how often
+// polls find changes: close to minInterval while content is dropping,
+// backing off towards maxInterval when it is quiet. WatchJitter still
+// applies to it.
+func WatchAdaptive(minInterval, maxInterval time.Duration) WatcherOption {
+	return func(w *Watcher) {
+		if minInterval <= 0 || maxInterval < minInterval {
+			return
+		}
+		w.adaptive = &adaptiveInterval{min: minInterval, max: maxInterval}
+	}
+}
+
+// adaptiveInterval is only touched by the watcher's poll loop
+type adaptiveInterval struct {
+	min, max time.Duration
+	// share of recent polls that found changes, 0 to 1
+	activity float64
+}
+
+func (a *adaptiveInterval) observe(changed bool) {
+	var sample float64
+	if changed {
+		sample = 1
+	}
+	a.activity = adaptiveAlpha*sample + (1-adaptiveAlpha)*a.activity
+}
+
+func (a *adaptiveInterval) interval() time.Duration {
+	span := float64(a.max - a.min)
+	return a.min + time.Duration(math.Round(span*(1-a.activity)))
+}
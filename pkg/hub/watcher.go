@@ -0,0 +1,346 @@
+package hub
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+type EventType string
+
+const (
+	QuestAdded       EventType = "quest_added"
+	QuestRemoved     EventType = "quest_removed"
+	QuestModified    EventType = "quest_modified"
+	BundleAdded      EventType = "bundle_added"
+	BundleRemoved    EventType = "bundle_removed"
+	BundleModified   EventType = "bundle_modified"
+	ScheduleAdded    EventType = "schedule_added"
+	ScheduleRemoved  EventType = "schedule_removed"
+	ScheduleModified EventType = "schedule_modified"
+	// the hub moved to a new season; sent instead of an event per item
+	SeasonRollover EventType = "season_rollover"
+)
+
+// exactly one of Quest, Bundle, Schedule and Rollover is set, matching Type.
+// ID is the new version for a rollover.
+type ChangeEvent struct {
+	Type     EventType      `json:"type"`
+	ID       string         `json:"id"`
+	At       time.Time      `json:"at"`
+	Quest    *QuestDelta    `json:"quest,omitempty"`
+	Bundle   *BundleDelta   `json:"bundle,omitempty"`
+	Schedule *ScheduleDelta `json:"schedule,omitempty"`
+	Rollover *Rollover      `json:"rollover,omitempty"`
+
+	// the fetch that observed the change
+	Provenance *Provenance `json:"provenance,omitempty"`
+}
+
+var ErrWatcherStarted = errors.New("watcher already started")
+
+type WatcherOption func(*Watcher)
+
+func WatchInterval(d time.Duration) WatcherOption {
+	return func(w *Watcher) {
+		w.interval = d
+	}
+}
+
+// each wait is randomised by up to ±fraction of the interval so a fleet of
+// watchers does not poll in lockstep
+func WatchJitter(fraction float64) WatcherOption {
+	return func(w *Watcher) {
+		w.jitter = min(max(fraction, 0), 1)
+	}
+}
+
+func WatchBuffer(n int) WatcherOption {
+	return func(w *Watcher) {
+		w.buffer = n
+	}
+}
+
+// the first poll is reported as additions instead of silently becoming the
+// baseline
+func WatchEmitInitial() WatcherOption {
+	return func(w *Watcher) {
+		w.emitInitial = true
+	}
+}
+
+// WatchFrom makes snap the baseline of the first poll instead of nothing,
+// e.g. a store bootstrapped from an archive, and holds the first poll back
+// for an interval. Only what changed since snap is reported; it overrides
+// WatchEmitInitial.
+func WatchFrom(snap *Snapshot) WatcherOption {
+	return func(w *Watcher) {
+		w.from = snap
+	}
+}
+
+// WatchTransform rewrites every snapshot the watcher polls before diffing
+// it, e.g. with an override.Patch, so the changes describe the data as it
+// is served. A snapshot the transform fails on is skipped like a failed
+// poll.
+func WatchTransform(fn func(context.Context, *Snapshot) error) WatcherOption {
+	return func(w *Watcher) {
+		w.transform = fn
+	}
+}
+
+// Watcher polls the hub, diffs each snapshot against the previous one and
+// emits the changes. Quest, bundle and schedule pushes trigger an immediate
+// poll, so changes the hub announces arrive without waiting for the interval.
+// A new season is reported as one SeasonRollover event, see DetectRollover,
+// and drops the client's cached responses.
+//
+// Events has one reader and, once Events has been called, waits for it.
+// Any number of other consumers can Subscribe instead, each independently.
+type Watcher struct {
+	client      *Client
+	interval    time.Duration
+	jitter      float64
+	buffer      int
+	emitInitial bool
+	from        *Snapshot
+	transform   func(context.Context, *Snapshot) error
+	adaptive    *adaptiveInterval
+	// the wait before the next poll, for Interval
+	current atomic.Int64
+
+	events chan ChangeEvent
+	// set by Events; until then nobody reads events and emit skips it
+	reading atomic.Bool
+	changes *Bus[ChangeEvent]
+	poke    chan struct{}
+	stop    chan struct{}
+	done    chan struct{}
+	// removes the push handler Start added
+	unhandle func()
+
+	startOnce sync.Once
+	stopOnce  sync.Once
+	started   atomic.Bool
+}
+
+func NewWatcher(c *Client, opts ...WatcherOption) *Watcher {
+	w := &Watcher{
+		client:   c,
+		interval: time.Minute,
+		jitter:   0.1,
+		buffer:   64,
+		poke:     make(chan struct{}, 1),
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+
+	for _, opt := range opts {
+		opt(w)
+	}
+
+	w.events = make(chan ChangeEvent, w.buffer)
+	w.changes = NewBus[ChangeEvent](w.buffer)
+	if w.adaptive != nil {
+		w.interval = w.adaptive.max
+	}
+	w.current.Store(int64(w.interval))
+	return w
+}
+
+// Interval is the wait between polls before jitter; with WatchAdaptive it
+// changes as the watcher runs
+func (w *Watcher) Interval() time.Duration {
+	return time.Duration(w.current.Load())
+}
+
+// Events delivers every change from the first call on, and the watcher
+// waits for it to be read. It is closed once the watcher has stopped.
+func (w *Watcher) Events() <-chan ChangeEvent {
+	w.reading.Store(true)
+	return w.events
+}
+
+// Subscribe delivers the changes on a channel of its own; with types given,
+// only those. Unlike Events the watcher does not wait for it, so a
+// subscriber more than WatchBuffer behind misses some. The channel is
+// closed when the watcher stops or the returned func is called.
+func (w *Watcher) Subscribe(types ...EventType) (<-chan ChangeEvent, func()) {
+	var keep func(ChangeEvent) bool
+	if len(types) > 0 {
+		keep = func(e ChangeEvent) bool { return slices.Contains(types, e.Type) }
+	}
+	return w.changes.SubscribeFunc(keep)
+}
+
+// Start polls in the background until ctx is cancelled or Stop is called
+func (w *Watcher) Start(ctx context.Context) error {
+	err := ErrWatcherStarted
+	w.startOnce.Do(func() {
+		err = nil
+		w.started.Store(true)
+
+		w.unhandle = w.client.events.Handle(func(e Event) {
+			switch e.Kind {
+			case EventQuestUpdated, EventBundleUpdated, EventScheduleChanged:
+				w.trigger()
+			}
+		})
+
+		go w.run(ctx)
+	})
+	return err
+}
+
+// Stop lets an in-flight poll finish, then closes Events and the
+// subscriptions. It returns early with ctx's error if that takes longer than
+// ctx allows.
+func (w *Watcher) Stop(ctx context.Context) error {
+	w.stopOnce.Do(func() {
+		close(w.stop)
+	})
+
+	if !w.started.Load() {
+		return nil
+	}
+
+	select {
+	case <-w.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (w *Watcher) trigger() {
+	select {
+	case w.poke <- struct{}{}:
+	default:
+	}
+}
+
+func (w *Watcher) run(ctx context.Context) {
+	defer close(w.done)
+	defer close(w.events)
+	defer w.changes.Close()
+	defer w.unhandle()
+
+	var last *Snapshot
+	if w.emitInitial {
+		last = &Snapshot{}
+	}
+	// the initial additions say nothing about how busy the hub is
+	baseline := true
+	if w.from != nil {
+		last, baseline = w.from, false
+		if !w.wait(ctx) {
+			return
+		}
+	}
+
+	for {
+		snap, err := w.client.Snapshot(withoutCache(ctx))
+		if err == nil && w.transform != nil {
+			err = w.transform(ctx, snap)
+		}
+		if err != nil {
+			w.client.logger.Warn("Watcher poll failed: %v", err)
+		} else {
+			if last != nil {
+				cs := SeasonDiff(last, snap)
+				if r := cs.Rollover; r != nil {
+					w.client.logger.Info("Season rolled over from %s to %s, %d items replaced", r.FromVersion, r.ToVersion, r.Removed.Total())
+					w.client.InvalidateLocal()
+				}
+				if w.adaptive != nil && !baseline {
+					w.adaptive.observe(!cs.Empty())
+					w.current.Store(int64(w.adaptive.interval()))
+				}
+				if !w.emit(ctx, cs) {
+					return
+				}
+			}
+			last = snap
+			baseline = false
+		}
+
+		if !w.wait(ctx) {
+			return
+		}
+	}
+}
+
+// wait sleeps until the next poll is due or pushed; false when the watcher
+// stops instead
+func (w *Watcher) wait(ctx context.Context) bool {
+	timer := time.NewTimer(w.nextWait())
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+	case <-w.poke:
+	case <-w.stop:
+		return false
+	case <-ctx.Done():
+		return false
+	}
+	return true
+}
+
+func (w *Watcher) nextWait() time.Duration {
+	interval := w.Interval()
+	if w.jitter == 0 {
+		return interval
+	}
+	spread := float64(interval) * w.jitter
+	return time.Duration(float64(interval) + (rand.Float64()*2-1)*spread)
+}
+
+// false when the watcher was stopped while waiting for a slow consumer
+func (w *Watcher) emit(ctx context.Context, cs ChangeSet) bool {
+	for _, e := range cs.Events(time.Now()) {
+		w.changes.Publish(e)
+		if !w.reading.Load() {
+			continue
+		}
+		select {
+		case w.events <- e:
+		case <-w.stop:
+			return false
+		case <-ctx.Done():
+			return false
+		}
+	}
+	return true
+}
+
+// Events is cs as the watcher emits it, one event per delta, or the one
+// SeasonRollover event
+func (cs ChangeSet) Events(at time.Time) []ChangeEvent {
+	var events []ChangeEvent
+	if cs.Rollover != nil {
+		events = append(events, ChangeEvent{Type: SeasonRollover, ID: cs.Rollover.ToVersion, At: at, Rollover: cs.Rollover, Provenance: cs.To})
+	}
+	for i := range cs.Quests {
+		d := &cs.Quests[i]
+		events = append(events, ChangeEvent{Type: questEvent[d.Kind], ID: d.ID, At: at, Quest: d, Provenance: cs.To})
+	}
+	for i := range cs.Bundles {
+		d := &cs.Bundles[i]
+		events = append(events, ChangeEvent{Type: bundleEvent[d.Kind], ID: d.TemplateID, At: at, Bundle: d, Provenance: cs.To})
+	}
+	for i := range cs.Schedules {
+		d := &cs.Schedules[i]
+		events = append(events, ChangeEvent{Type: scheduleEvent[d.Kind], ID: d.TemplateID, At: at, Schedule: d, Provenance: cs.To})
+	}
+	return events
+}
+
+var (
+	questEvent    = map[ChangeKind]EventType{ChangeAdded: QuestAdded, ChangeRemoved: QuestRemoved, ChangeModified: QuestModified}
+	bundleEvent   = map[ChangeKind]EventType{ChangeAdded: BundleAdded, ChangeRemoved: BundleRemoved, ChangeModified: BundleModified}
+	scheduleEvent = map[ChangeKind]EventType{ChangeAdded: ScheduleAdded, ChangeRemoved: ScheduleRemoved, ChangeModified: ScheduleModified}
+)
@@ -0,0 +1,35 @@
+package hub
+
+import (
+	"regexp"
+	"strconv"
+)
+
+var (
+	seasonPattern = regexp.MustCompile(`(?i)(?:^|[_:])S(?:eason)?_?(\d+)(?:_|$)`)
+	weekPattern   = regexp.MustCompile(`(?i)week_?(\d+)`)
+)
+
+// ParseWeek reads the season and week encoded in a bundle or schedule template
+// ID, e.g. ChallengeBundle:QuestBundle_S10_Week_001 is season 10, week 1.
+// season is 0 when the ID does not name one.
+func ParseWeek(templateID string) (season, week int, ok bool) {
+	m := weekPattern.FindStringSubmatch(templateID)
+	if m == nil {
+		return 0, 0, false
+	}
+	week, _ = strconv.Atoi(m[1])
+
+	if m := seasonPattern.FindStringSubmatch(templateID); m != nil {
+		season, _ = strconv.Atoi(m[1])
+	}
+	return season, week, true
+}
+
+// the bundle's own ID wins over its schedule's
+func bundleWeek(b *AthenaChallengeBundle) (season, week int, ok bool) {
+	if season, week, ok = ParseWeek(b.TemplateID); ok {
+		return season, week, true
+	}
+	return ParseWeek(b.ChallengeBundleSchedule)
+}
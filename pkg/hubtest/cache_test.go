@@ -0,0 +1,85 @@
+package hubtest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/ilyskies/QuestHub/pkg/hub"
+)
+
+// A hub that stops answering is covered by expired entries; an error the
+// hub sends is not. Both show in CacheStats and the metrics.
+func TestCacheStatsAndStaleServes(t *testing.T) {
+	srv := NewServer(DefaultFixtures())
+	defer srv.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	reg := prometheus.NewRegistry()
+	c, err := srv.NewClient(ctx,
+		hub.WithResponseCache(50*time.Millisecond),
+		hub.WithStaleIfError(time.Minute),
+		hub.WithMetrics(reg),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Disconnect()
+
+	for range 2 {
+		if _, err := c.GetDailyQuests(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+	time.Sleep(60 * time.Millisecond)
+
+	srv.SetFault("GetDailyQuests", Fault{Delay: time.Second})
+	quests, err := c.GetDailyQuests(ctx, hub.CallTimeout(50*time.Millisecond))
+	if err != nil || len(quests) != 1 {
+		t.Fatalf("got %d quests, %v; want the stale quest", len(quests), err)
+	}
+
+	srv.SetFault("GetChallengeBundles", Fault{Err: "boom"})
+	if _, err := c.GetChallengeBundles(ctx); !errors.Is(err, hub.ErrInvokeFailed) {
+		t.Fatalf("got %v, want the hub's error", err)
+	}
+
+	stats := c.CacheStats().Methods["GetDailyQuests"]
+	if stats.Hits != 1 || stats.Misses != 2 || stats.StaleServes != 1 || stats.Refreshes != 1 {
+		t.Errorf("got %+v, want 1 hit, 2 misses, 1 stale serve, 1 refresh", stats)
+	}
+	if stats.RefreshLatency <= 0 || stats.MaxRefreshLatency < stats.RefreshLatency {
+		t.Errorf("refresh latency %v, max %v", stats.RefreshLatency, stats.MaxRefreshLatency)
+	}
+	if got := c.CacheStats().Methods["GetChallengeBundles"]; got.Misses != 1 || got.StaleServes != 0 {
+		t.Errorf("bundles: got %+v", got)
+	}
+
+	families, err := reg.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	lookups := make(map[string]float64)
+	for _, f := range families {
+		if f.GetName() != "questhub_client_cache_lookups_total" {
+			continue
+		}
+		for _, m := range f.GetMetric() {
+			labels := make(map[string]string)
+			for _, l := range m.GetLabel() {
+				labels[l.GetName()] = l.GetValue()
+			}
+			if labels["method"] == "GetDailyQuests" {
+				lookups[labels["result"]] = m.GetCounter().GetValue()
+			}
+		}
+	}
+	if lookups["hit"] != 1 || lookups["miss"] != 2 || lookups["stale"] != 1 {
+		t.Errorf("got lookups %v", lookups)
+	}
+}
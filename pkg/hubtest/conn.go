@@ -0,0 +1,424 @@
+package hubtest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/coder/websocket"
+	"github.com/ilyskies/QuestHub/pkg/hub"
+)
+
+const recordSeparator = 0x1e
+
+const (
+	messageInvocation = 1
+	messageCompletion = 3
+	messagePing       = 6
+	messageClose      = 7
+	messageAck        = 8
+	messageSequence   = 9
+)
+
+// how long a stateful connection waits for its client to resume after the
+// websocket dropped
+const sessionTimeout = 30 * time.Second
+
+type message struct {
+	Type         int               `json:"type"`
+	InvocationID string            `json:"invocationId,omitempty"`
+	Target       string            `json:"target,omitempty"`
+	Arguments    []json.RawMessage `json:"arguments,omitempty"`
+	SequenceID   int64             `json:"sequenceId,omitempty"`
+}
+
+type handshakeRequest struct {
+	Protocol string `json:"protocol"`
+	Version  int    `json:"version"`
+}
+
+type completion struct {
+	Type         int         `json:"type"`
+	InvocationID string      `json:"invocationId"`
+	Result       interface{} `json:"result,omitempty"`
+	Error        string      `json:"error,omitempty"`
+}
+
+type invocation struct {
+	Type      int           `json:"type"`
+	Target    string        `json:"target"`
+	Arguments []interface{} `json:"arguments"`
+}
+
+type sentFrame struct {
+	seq  int64
+	data []byte
+}
+
+type conn struct {
+	server *Server
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	// set by the handshake when the client asked for MessagePack
+	binary atomic.Bool
+
+	// guards ws, which a resumed session replaces, and the sent messages
+	writeMu   sync.Mutex
+	ws        *websocket.Conn
+	closeOnce sync.Once
+
+	// stateful reconnect: numbered messages the client has not acknowledged
+	// yet, and the websockets of clients resuming the connection. A client
+	// that handshakes with version 1 turns it off, as on the hub.
+	resumable atomic.Bool
+	resumed   chan *websocket.Conn
+	sent      int64
+	unacked   []sentFrame
+
+	// read side, owned by serve
+	received int64
+	next     int64
+}
+
+func (c *conn) close() {
+	c.closeOnce.Do(func() {
+		c.cancel()
+		c.writeMu.Lock()
+		ws := c.ws
+		c.writeMu.Unlock()
+		_ = ws.Close(websocket.StatusNormalClosure, "")
+	})
+}
+
+// drop closes the websocket; a stateful connection waits for its client to
+// resume, any other ends
+func (c *conn) drop() {
+	if !c.resumable.Load() {
+		c.close()
+		return
+	}
+	c.writeMu.Lock()
+	ws := c.ws
+	c.writeMu.Unlock()
+	ws.CloseNow()
+}
+
+// send writes a frame; sequenced frames of a stateful connection are
+// numbered and kept until acknowledged, so losing the websocket loses none
+func (c *conn) send(typ websocket.MessageType, b []byte, sequenced bool) error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+
+	if c.resumable.Load() && sequenced {
+		c.sent++
+		c.unacked = append(c.unacked, sentFrame{seq: c.sent, data: b})
+	}
+	err := c.ws.Write(c.ctx, typ, b)
+	if err != nil && c.resumable.Load() && c.ctx.Err() == nil {
+		return nil
+	}
+	return err
+}
+
+func (c *conn) write(v interface{}, sequenced bool) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return c.send(websocket.MessageText, append(b, recordSeparator), sequenced)
+}
+
+func (c *conn) writeBinary(frame []byte, sequenced bool) error {
+	return c.send(websocket.MessageBinary, frame, sequenced)
+}
+
+// control sends an Ack or Sequence message
+func (c *conn) control(typ int, sequenceID int64) error {
+	return c.send(c.controlFrame(typ, sequenceID))
+}
+
+func (c *conn) controlFrame(typ int, sequenceID int64) (websocket.MessageType, []byte, bool) {
+	if c.binary.Load() {
+		return websocket.MessageBinary, packControl(typ, sequenceID), false
+	}
+	b, _ := json.Marshal(map[string]int64{"type": int64(typ), "sequenceId": sequenceID})
+	return websocket.MessageText, append(b, recordSeparator), false
+}
+
+func (c *conn) invokeClient(target string, args ...interface{}) error {
+	if args == nil {
+		args = []interface{}{}
+	}
+	if c.binary.Load() {
+		frame, err := packInvocation(target, args)
+		if err != nil {
+			return err
+		}
+		return c.writeBinary(frame, true)
+	}
+	return c.write(invocation{Type: messageInvocation, Target: target, Arguments: args}, true)
+}
+
+func (c *conn) complete(invocationID string, result interface{}, errMsg string) error {
+	if c.binary.Load() {
+		frame, err := packCompletion(invocationID, result, errMsg)
+		if err != nil {
+			return err
+		}
+		return c.writeBinary(frame, true)
+	}
+	return c.write(completion{
+		Type:         messageCompletion,
+		InvocationID: invocationID,
+		Result:       result,
+		Error:        errMsg,
+	}, true)
+}
+
+func (c *conn) ping() error {
+	if c.binary.Load() {
+		return c.writeBinary(packPing(), false)
+	}
+	return c.write(map[string]int{"type": messagePing}, false)
+}
+
+func (c *conn) serve() {
+	c.writeMu.Lock()
+	ws := c.ws
+	c.writeMu.Unlock()
+
+	// only the first websocket carries a handshake; a resumed one starts
+	// with the Sequence messages
+	_, data, err := ws.Read(c.ctx)
+	if err != nil {
+		return
+	}
+	var req handshakeRequest
+	record, _, _ := bytes.Cut(data, []byte{recordSeparator})
+	if err := json.Unmarshal(record, &req); err != nil {
+		return
+	}
+	c.binary.Store(req.Protocol == "messagepack")
+	if req.Version < 2 {
+		c.resumable.Store(false)
+	}
+	if err := c.writeRaw([]byte("{}")); err != nil {
+		return
+	}
+
+	go c.keepAlive()
+	c.sendReady()
+
+	for {
+		_, data, err := ws.Read(c.ctx)
+		if err != nil {
+			if ws = c.awaitResume(); ws == nil {
+				return
+			}
+			c.replay()
+			continue
+		}
+
+		var msgs []message
+		if c.binary.Load() {
+			msgs, err = unpackMessages(data)
+		} else {
+			msgs, err = parseJSONMessages(data)
+		}
+		if err != nil {
+			return
+		}
+
+		fresh := false
+		for _, msg := range msgs {
+			if c.resumable.Load() && msg.Type >= messageInvocation && msg.Type <= 5 {
+				seq := c.next
+				c.next++
+				if seq <= c.received {
+					// replayed by the client after a resume
+					continue
+				}
+				c.received = seq
+				fresh = true
+			}
+
+			switch msg.Type {
+			case 0:
+				// not a hub message, such as a handshake sent again
+				return
+			case messageInvocation:
+				go c.handleInvocation(msg)
+			case messageAck:
+				c.ack(msg.SequenceID)
+			case messageSequence:
+				c.next = msg.SequenceID
+			case messageClose:
+				return
+			}
+		}
+		if fresh {
+			_ = c.control(messageAck, c.received)
+		}
+	}
+}
+
+// awaitResume waits for the client of a stateful connection to dial again
+// after its websocket failed; nil means the connection is over
+func (c *conn) awaitResume() *websocket.Conn {
+	if !c.resumable.Load() || c.ctx.Err() != nil {
+		return nil
+	}
+
+	timer := time.NewTimer(sessionTimeout)
+	defer timer.Stop()
+
+	select {
+	case ws := <-c.resumed:
+		c.writeMu.Lock()
+		c.ws = ws
+		c.writeMu.Unlock()
+		return ws
+	case <-timer.C:
+		return nil
+	case <-c.ctx.Done():
+		return nil
+	}
+}
+
+func (c *conn) ack(seq int64) {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+
+	i := 0
+	for i < len(c.unacked) && c.unacked[i].seq <= seq {
+		i++
+	}
+	c.unacked = c.unacked[i:]
+}
+
+// replay starts a resumed websocket with a Sequence message giving the
+// first ID it resends, then what the client has not acknowledged. Nothing
+// else is written in between, or the client would number it wrongly.
+func (c *conn) replay() {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+
+	first := c.sent + 1
+	if len(c.unacked) > 0 {
+		first = c.unacked[0].seq
+	}
+	typ, seq, _ := c.controlFrame(messageSequence, first)
+
+	frames := [][]byte{seq}
+	for _, f := range c.unacked {
+		frames = append(frames, f.data)
+	}
+	for _, f := range frames {
+		if err := c.ws.Write(c.ctx, typ, f); err != nil {
+			return
+		}
+	}
+}
+
+func parseJSONMessages(data []byte) ([]message, error) {
+	var msgs []message
+	for _, record := range bytes.Split(data, []byte{recordSeparator}) {
+		if len(bytes.TrimSpace(record)) == 0 {
+			continue
+		}
+
+		var msg message
+		if err := json.Unmarshal(record, &msg); err != nil {
+			return nil, err
+		}
+		msgs = append(msgs, msg)
+	}
+	return msgs, nil
+}
+
+func (c *conn) writeRaw(b []byte) error {
+	return c.send(websocket.MessageText, append(b, recordSeparator), false)
+}
+
+func (c *conn) keepAlive() {
+	ticker := time.NewTicker(5 * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.ctx.Done():
+			return
+		case <-ticker.C:
+			if err := c.ping(); err != nil {
+				return
+			}
+		}
+	}
+}
+
+func (c *conn) sendReady() {
+	c.server.mu.Lock()
+	send := c.server.sendReady
+	status := c.server.fixtures.Status
+	c.server.mu.Unlock()
+
+	if send {
+		_ = c.invokeClient("Ready", hub.ReadyStatus{
+			Initialized: status.Initialized,
+			Version:     status.Version,
+		})
+	}
+}
+
+func (c *conn) handleInvocation(msg message) {
+	fixtures, fault := c.server.enter(msg.Target)
+
+	if fault.Delay > 0 {
+		select {
+		case <-time.After(fault.Delay):
+		case <-c.ctx.Done():
+			return
+		}
+	}
+	if fault.Drop {
+		c.close()
+		return
+	}
+
+	var (
+		result interface{}
+		errMsg string
+	)
+	if fault.Err != "" {
+		errMsg = fault.Err
+	} else {
+		result, errMsg = dispatch(fixtures, msg.Target, msg.Arguments)
+	}
+
+	// refreshes finish at once; the caller still gets a job ID and its final
+	// progress report, sent right after the completion
+	var job string
+	if msg.Target == "RefreshCache" && errMsg == "" {
+		job = c.server.refreshJob()
+		result = job
+	}
+
+	// invocations sent without an id expect no completion
+	if msg.InvocationID != "" {
+		_ = c.complete(msg.InvocationID, result, errMsg)
+	}
+
+	if job != "" {
+		_ = c.invokeClient("CacheRefreshProgress", hub.RefreshProgress{
+			JobID:     job,
+			Completed: 1,
+			Total:     1,
+			Done:      true,
+			Timestamp: time.Now().UTC(),
+		})
+	}
+}
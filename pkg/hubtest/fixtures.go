@@ -0,0 +1,79 @@
+package hubtest
+
+import (
+	"time"
+
+	"github.com/ilyskies/QuestHub/pkg/hub"
+)
+
+type Fixtures struct {
+	Status      hub.ServiceStatus
+	DailyQuests map[string]hub.BaseQuest
+	Bundles     []hub.AthenaChallengeBundle
+	Schedules   []hub.ChallengeBundleSchedule
+	Season      hub.SeasonInfo
+}
+
+// small but complete data set, enough to exercise every client method
+func DefaultFixtures() Fixtures {
+	return Fixtures{
+		Status: hub.ServiceStatus{
+			Initialized: true,
+			Version:     "test",
+			Timestamp:   time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		},
+		DailyQuests: map[string]hub.BaseQuest{
+			"Quest_Daily_Eliminations": {
+				Objectives: hub.QuestObjectives{
+					{BackendName: "daily_athena_eliminations", Count: 3},
+				},
+				Rewards: hub.QuestRewards{
+					{TemplateID: "AccountResource:athenaseasonalxp", Quantity: 500},
+				},
+				Count: 3,
+			},
+		},
+		Bundles: []hub.AthenaChallengeBundle{
+			{
+				TemplateID:              "ChallengeBundle:QuestBundle_Week_001",
+				ChallengeBundleSchedule: "ChallengeBundleSchedule:Schedule_Week_001",
+				Amount:                  1,
+				Rarity:                  "Common",
+				Objects: []hub.ChallengeBundleObject{
+					{
+						QuestDefinition: "Quest_Week_001_Damage_01",
+						Rarity:          "Common",
+						Rewards: []hub.ChallengeBundleReward{
+							{TemplateID: "AccountResource:athenabattlestar", Quantity: 5},
+						},
+						Objectives: []hub.ChallengeBundleObjective{
+							{BackendName: "athena_damage", Count: 500, Stage: 1},
+						},
+						Options: hub.ChallengeBundleOptions{IsBattlePass: true},
+					},
+				},
+				CompletionRewards: []hub.BundleCompletionReward{
+					{TemplateID: "AthenaSpray:spid_test", Quantity: 1},
+				},
+			},
+		},
+		Schedules: []hub.ChallengeBundleSchedule{
+			{
+				TemplateID:  "ChallengeBundleSchedule:Schedule_Week_001",
+				QuestBundle: "ChallengeBundle:QuestBundle_Week_001",
+				ActiveFrom:  time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+				ActiveUntil: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+				Visibility:  hub.VisibilityPublic,
+			},
+		},
+		Season: hub.SeasonInfo{
+			Season: 1,
+			Start:  time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+			End:    time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+			Weeks: []hub.WeekInfo{
+				{Week: 1, UnlockAt: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)},
+				{Week: 2, UnlockAt: time.Date(2024, 1, 8, 0, 0, 0, 0, time.UTC)},
+			},
+		},
+	}
+}
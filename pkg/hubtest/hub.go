@@ -0,0 +1,177 @@
+package hubtest
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"time"
+
+	"github.com/ilyskies/QuestHub/pkg/hub"
+)
+
+// dispatch answers a hub method from the fixtures, returning either the
+// result or an error message for the completion
+func dispatch(f Fixtures, method string, args []json.RawMessage) (interface{}, string) {
+	switch method {
+	case "GetServiceStatus":
+		return f.Status, ""
+
+	case "GetDailyQuests":
+		return f.DailyQuests, ""
+
+	case "GetDailyQuest":
+		var id string
+		if err := stringArg(args, &id); err != nil {
+			return nil, err.Error()
+		}
+		q, ok := f.DailyQuests[id]
+		if !ok {
+			return nil, hub.ErrQuestNotFound.Error()
+		}
+		return q, ""
+
+	case "GetChallengeBundles":
+		return f.Bundles, ""
+
+	case "GetChallengeBundle":
+		var id string
+		if err := stringArg(args, &id); err != nil {
+			return nil, err.Error()
+		}
+		for _, b := range f.Bundles {
+			if b.TemplateID == id {
+				return b, ""
+			}
+		}
+		return nil, hub.ErrBundleNotFound.Error()
+
+	case "GetChallengeBundlesPage":
+		var req hub.PageRequest
+		if err := singleArg(args, &req); err != nil {
+			return nil, err.Error()
+		}
+		if req.Offset < 0 || req.Limit <= 0 {
+			return nil, hub.ErrInvalidPage.Error()
+		}
+		start := min(req.Offset, len(f.Bundles))
+		end := min(start+req.Limit, len(f.Bundles))
+		return hub.BundlePage{Bundles: f.Bundles[start:end], Offset: req.Offset, Total: len(f.Bundles)}, ""
+
+	case "GetChallengeBundleSchedules":
+		return f.Schedules, ""
+
+	case "GetSeasonInfo":
+		return f.Season, ""
+
+	case "GetWeeklyChallenges":
+		var week int
+		if err := singleArg(args, &week); err != nil {
+			return nil, err.Error()
+		}
+		return weeklyChallenges(f, week), ""
+
+	case "ClearCache":
+		return hub.CacheResult{
+			Success:   true,
+			Version:   f.Status.Version,
+			Timestamp: time.Now().UTC(),
+		}, ""
+
+	case "ClearCacheByPattern":
+		var patterns []string
+		if err := singleArg(args, &patterns); err != nil {
+			return nil, err.Error()
+		}
+		cleared, err := matchingKeys(f, patterns)
+		if err != nil {
+			return nil, err.Error()
+		}
+		return hub.CacheResult{
+			Success:     true,
+			Version:     f.Status.Version,
+			KeysCleared: cleared,
+			Patterns:    patterns,
+			Timestamp:   time.Now().UTC(),
+		}, ""
+
+	case "RefreshCache":
+		return nil, ""
+
+	case "ListHubMethods":
+		return methods, ""
+	}
+
+	return nil, fmt.Sprintf("Unknown method %s", method)
+}
+
+func stringArg(args []json.RawMessage, out *string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("expected 1 argument, got %d", len(args))
+	}
+	return json.Unmarshal(args[0], out)
+}
+
+func singleArg(args []json.RawMessage, out interface{}) error {
+	if len(args) != 1 {
+		return fmt.Errorf("expected 1 argument, got %d", len(args))
+	}
+	return json.Unmarshal(args[0], out)
+}
+
+// counts the cache keys the hub would hold for the fixtures, one per daily
+// quest and bundle, that match any of the patterns
+func matchingKeys(f Fixtures, patterns []string) (int, error) {
+	keys := make([]string, 0, len(f.DailyQuests)+len(f.Bundles))
+	for id := range f.DailyQuests {
+		keys = append(keys, "quests:"+id)
+	}
+	for _, b := range f.Bundles {
+		keys = append(keys, "bundles:"+b.TemplateID)
+	}
+
+	n := 0
+	for _, key := range keys {
+		for _, p := range patterns {
+			ok, err := path.Match(p, key)
+			if err != nil {
+				return 0, fmt.Errorf("invalid pattern %q", p)
+			}
+			if ok {
+				n++
+				break
+			}
+		}
+	}
+	return n, nil
+}
+
+// groups the fixture bundles by the week in their template IDs
+func weeklyChallenges(f Fixtures, week int) hub.WeeklyChallenges {
+	out := hub.WeeklyChallenges{Season: f.Season.Season, Week: week, Bundles: []hub.AthenaChallengeBundle{}}
+	if w, ok := f.Season.Week(week); ok {
+		out.UnlockAt = w.UnlockAt
+	}
+	for _, b := range f.Bundles {
+		if _, w, ok := hub.ParseWeek(b.TemplateID); ok && w == week {
+			out.Bundles = append(out.Bundles, b)
+		}
+	}
+	return out
+}
+
+// what ListHubMethods reports, matching the cases served by dispatch
+var methods = []hub.HubMethod{
+	{Name: "GetServiceStatus", Returns: "ServiceStatus"},
+	{Name: "GetDailyQuests", Returns: "map[string]BaseQuest"},
+	{Name: "GetDailyQuest", Parameters: []string{"questId"}, Returns: "BaseQuest"},
+	{Name: "GetChallengeBundles", Returns: "[]AthenaChallengeBundle"},
+	{Name: "GetChallengeBundle", Parameters: []string{"templateId"}, Returns: "AthenaChallengeBundle"},
+	{Name: "GetChallengeBundlesPage", Parameters: []string{"page"}, Returns: "BundlePage"},
+	{Name: "GetChallengeBundleSchedules", Returns: "[]ChallengeBundleSchedule"},
+	{Name: "GetSeasonInfo", Returns: "SeasonInfo"},
+	{Name: "GetWeeklyChallenges", Parameters: []string{"week"}, Returns: "WeeklyChallenges"},
+	{Name: "ClearCache", Returns: "CacheResult"},
+	{Name: "ClearCacheByPattern", Parameters: []string{"patterns"}, Returns: "CacheResult"},
+	{Name: "RefreshCache", Returns: "string"},
+	{Name: "ListHubMethods", Returns: "[]HubMethod"},
+}
@@ -0,0 +1,53 @@
+package hubtest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ilyskies/QuestHub/pkg/hub"
+)
+
+func TestLocalizedHubError(t *testing.T) {
+	srv := NewServer(DefaultFixtures())
+	defer srv.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	srv.SetFault("GetDailyQuests", Fault{Err: `{"code": "quest_not_found", "message": "Quest not found",
+		"messages": {"de": "Quest nicht gefunden", "pt-BR": "Missão não encontrada"},
+		"details": {"questId": "Quest_Missing"}}`})
+
+	cases := []struct {
+		locale, locale2, message string
+	}{
+		{"", "", "Quest not found"},
+		{"de-AT", "de", "Quest nicht gefunden"},
+		{"pt_br", "pt-BR", "Missão não encontrada"},
+		{"fr", "", "Quest not found"},
+	}
+	for _, tc := range cases {
+		c, err := srv.NewClient(ctx, hub.WithLocale(tc.locale))
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = c.GetDailyQuests(ctx)
+		c.Disconnect()
+
+		var herr *hub.HubError
+		if !errors.As(err, &herr) {
+			t.Fatalf("%q: got %v, want a HubError", tc.locale, err)
+		}
+		if !errors.Is(err, hub.ErrQuestNotFound) || herr.Code != hub.CodeQuestNotFound {
+			t.Errorf("%q: got code %q, %v", tc.locale, herr.Code, err)
+		}
+		if herr.Message != tc.message || herr.Locale != tc.locale2 {
+			t.Errorf("%q: got %q in %q, want %q in %q", tc.locale, herr.Message, herr.Locale, tc.message, tc.locale2)
+		}
+		if herr.Details["questId"] != "Quest_Missing" {
+			t.Errorf("%q: got details %v", tc.locale, herr.Details)
+		}
+	}
+}
@@ -0,0 +1,143 @@
+package hubtest
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// MessagePack hub protocol framing: every message is a varint length
+// prefix followed by a msgpack array, see
+// https://github.com/dotnet/aspnetcore/blob/main/src/SignalR/docs/specs/HubProtocol.md
+
+const (
+	resultError   = 1
+	resultVoid    = 2
+	resultNonVoid = 3
+)
+
+func frame(values ...interface{}) ([]byte, error) {
+	var body bytes.Buffer
+	enc := msgpack.NewEncoder(&body)
+	enc.SetCustomStructTag("json")
+	enc.UseCompactInts(true)
+
+	if err := enc.EncodeArrayLen(len(values)); err != nil {
+		return nil, err
+	}
+	for _, v := range values {
+		if err := enc.Encode(v); err != nil {
+			return nil, err
+		}
+	}
+
+	out := binary.AppendUvarint(nil, uint64(body.Len()))
+	return append(out, body.Bytes()...), nil
+}
+
+func packInvocation(target string, args []interface{}) ([]byte, error) {
+	return frame(messageInvocation, map[string]string{}, nil, target, args)
+}
+
+func packCompletion(invocationID string, result interface{}, errMsg string) ([]byte, error) {
+	switch {
+	case errMsg != "":
+		return frame(messageCompletion, map[string]string{}, invocationID, resultError, errMsg)
+	case result == nil:
+		return frame(messageCompletion, map[string]string{}, invocationID, resultVoid)
+	default:
+		return frame(messageCompletion, map[string]string{}, invocationID, resultNonVoid, result)
+	}
+}
+
+func packPing() []byte {
+	b, _ := frame(messagePing)
+	return b
+}
+
+func packControl(typ int, sequenceID int64) []byte {
+	b, _ := frame(typ, sequenceID)
+	return b
+}
+
+// unpackMessages decodes the client messages hubtest acts on; anything else
+// is returned with only its type set
+func unpackMessages(data []byte) ([]message, error) {
+	var msgs []message
+	for len(data) > 0 {
+		size, n := binary.Uvarint(data)
+		if n <= 0 || uint64(len(data)-n) < size {
+			return nil, errors.New("truncated messagepack frame")
+		}
+		body := data[n : n+int(size)]
+		data = data[n+int(size):]
+
+		msg, err := unpackMessage(body)
+		if err != nil {
+			return nil, err
+		}
+		msgs = append(msgs, msg)
+	}
+	return msgs, nil
+}
+
+func unpackMessage(body []byte) (message, error) {
+	dec := msgpack.NewDecoder(bytes.NewReader(body))
+
+	n, err := dec.DecodeArrayLen()
+	if err != nil {
+		return message{}, err
+	}
+	typ, err := dec.DecodeInt()
+	if err != nil {
+		return message{}, err
+	}
+
+	msg := message{Type: typ}
+	if (typ == messageAck || typ == messageSequence) && n >= 2 {
+		if msg.SequenceID, err = dec.DecodeInt64(); err != nil {
+			return message{}, err
+		}
+		return msg, nil
+	}
+	if typ != messageInvocation {
+		return msg, nil
+	}
+	if n < 5 {
+		return message{}, fmt.Errorf("invalid invocation length %d", n)
+	}
+
+	// headers
+	if err := dec.Skip(); err != nil {
+		return message{}, err
+	}
+
+	var id *string
+	if err := dec.Decode(&id); err != nil {
+		return message{}, err
+	}
+	if id != nil {
+		msg.InvocationID = *id
+	}
+
+	if msg.Target, err = dec.DecodeString(); err != nil {
+		return message{}, err
+	}
+
+	var args []interface{}
+	if err := dec.Decode(&args); err != nil {
+		return message{}, err
+	}
+	for _, a := range args {
+		raw, err := json.Marshal(a)
+		if err != nil {
+			return message{}, err
+		}
+		msg.Arguments = append(msg.Arguments, raw)
+	}
+	return msg, nil
+}
@@ -0,0 +1,46 @@
+package hubtest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/ilyskies/QuestHub/pkg/hub"
+)
+
+// every client failing over reports each failure, not just the last
+func TestPoolDoFailsOverToAll(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var clients []*hub.Client
+	for range 2 {
+		srv := NewServer(DefaultFixtures())
+		defer srv.Close()
+		c, err := srv.NewClient(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		clients = append(clients, c)
+	}
+	pool := hub.NewPool(clients, hub.WithPoolStrategy(hub.PrimaryFallback))
+	defer pool.Close()
+
+	calls := 0
+	err := pool.Do(ctx, func(*hub.Client) error {
+		calls++
+		return fmt.Errorf("call %d: %w", calls, hub.ErrConnectionLost)
+	})
+	var multi *hub.MultiError
+	if !errors.As(err, &multi) {
+		t.Fatalf("err = %v, want a MultiError", err)
+	}
+	if !errors.Is(err, hub.ErrNoHealthyClient) || !errors.Is(err, hub.ErrConnectionLost) || len(multi.Errors) != 3 {
+		t.Errorf("err = %v", err)
+	}
+	if calls != 2 || len(pool.Healthy()) != 0 {
+		t.Errorf("%d calls, %d healthy after failing over", calls, len(pool.Healthy()))
+	}
+}
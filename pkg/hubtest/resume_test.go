@@ -0,0 +1,69 @@
+package hubtest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ilyskies/QuestHub/pkg/hub"
+)
+
+// The server follows the hub's stateful reconnect protocol: a resumed
+// websocket starts with Sequence messages, and a handshake sent on it ends
+// the connection.
+func TestStatefulReconnectResumes(t *testing.T) {
+	for _, protocol := range []hub.HubProtocol{hub.ProtocolJSON, hub.ProtocolMessagePack} {
+		t.Run(protocol.String(), func(t *testing.T) {
+			srv := NewServer(DefaultFixtures())
+			defer srv.Close()
+			srv.SetStatefulReconnect(true)
+
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+
+			c, err := srv.NewClient(ctx, hub.WithHubProtocol(protocol), hub.WithStatefulReconnect(5*time.Second))
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer c.Disconnect()
+
+			resumed := make(chan struct{}, 1)
+			c.OnSessionResumed(func(time.Duration) { resumed <- struct{}{} })
+			id := c.ConnectionID()
+
+			// the completion is sent while the websocket is down and has to
+			// be replayed on the resumed one
+			srv.SetFault("GetDailyQuests", Fault{Delay: 200 * time.Millisecond, Times: 1})
+			done := make(chan error, 1)
+			go func() {
+				_, err := c.GetDailyQuests(ctx)
+				done <- err
+			}()
+
+			time.Sleep(50 * time.Millisecond)
+			srv.DropTransports()
+
+			if err := <-done; err != nil {
+				t.Fatalf("call across the drop: %v", err)
+			}
+			select {
+			case <-resumed:
+			case <-ctx.Done():
+				t.Fatal("session not resumed")
+			}
+
+			if _, err := c.GetChallengeBundles(ctx); err != nil {
+				t.Fatalf("call after resume: %v", err)
+			}
+			if got := c.ConnectionID(); got != id {
+				t.Errorf("connection ID changed from %s to %s", id, got)
+			}
+			if got := c.ConnectionInfo().Resumes; got != 1 {
+				t.Errorf("resumes = %d, want 1", got)
+			}
+			if got := srv.Calls("GetDailyQuests"); got != 1 {
+				t.Errorf("GetDailyQuests called %d times, want 1", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,138 @@
+package hubtest
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ilyskies/QuestHub/pkg/hub"
+)
+
+// a read the connection drops under reconnects and goes through
+func TestRetryReconnects(t *testing.T) {
+	srv := NewServer(DefaultFixtures())
+	defer srv.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	client, err := srv.NewClient(ctx, hub.WithRetryPolicy(hub.RetryPolicy{MaxAttempts: 3, InitialBackoff: 10 * time.Millisecond}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Disconnect()
+
+	srv.SetFault("GetDailyQuests", Fault{Drop: true, Times: 1})
+	quests, err := client.GetDailyQuests(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := quests["Quest_Daily_Eliminations"]; !ok {
+		t.Errorf("quests = %v", quests)
+	}
+	if n := srv.Calls("GetDailyQuests"); n != 2 {
+		t.Errorf("%d calls, want the dropped one and its retry", n)
+	}
+	if !client.IsConnected() {
+		t.Error("client did not reconnect")
+	}
+}
+
+// an empty retry budget stops the retry
+func TestRetryBudgetExhausted(t *testing.T) {
+	srv := NewServer(DefaultFixtures())
+	defer srv.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	client, err := srv.NewClient(ctx,
+		hub.WithRetryPolicy(hub.RetryPolicy{MaxAttempts: 3, InitialBackoff: 10 * time.Millisecond}),
+		hub.WithRetryBudget(hub.NewTokenBucketBudget(0, 0)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Disconnect()
+
+	srv.SetFault("GetDailyQuests", Fault{Drop: true, Times: 1})
+	if _, err := client.GetDailyQuests(ctx); !errors.Is(err, hub.ErrRetryBudgetExhausted) {
+		t.Errorf("err = %v, want ErrRetryBudgetExhausted", err)
+	}
+	if n := srv.Calls("GetDailyQuests"); n != 1 {
+		t.Errorf("%d calls, want no retry", n)
+	}
+}
+
+// hub errors open the circuit and an open circuit keeps calls off the hub
+func TestCircuitBreakerOpens(t *testing.T) {
+	srv := NewServer(DefaultFixtures())
+	defer srv.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	client, err := srv.NewClient(ctx, hub.WithCircuitBreaker(2, 100*time.Millisecond))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Disconnect()
+
+	srv.SetFault("GetSeasonInfo", Fault{Err: "internal error", Times: 2})
+	for range 2 {
+		if _, err := client.GetSeasonInfo(ctx); !errors.Is(err, hub.ErrInvokeFailed) {
+			t.Fatalf("err = %v, want the hub error", err)
+		}
+	}
+	if s := client.CircuitState(); s != hub.CircuitOpen {
+		t.Fatalf("circuit %s, want open", s)
+	}
+	if _, err := client.GetSeasonInfo(ctx); !errors.Is(err, hub.ErrCircuitOpen) {
+		t.Errorf("err = %v, want ErrCircuitOpen", err)
+	}
+	if n := srv.Calls("GetSeasonInfo"); n != 2 {
+		t.Errorf("%d calls reached the hub, want 2", n)
+	}
+
+	time.Sleep(150 * time.Millisecond)
+	if _, err := client.GetSeasonInfo(ctx); err != nil {
+		t.Fatalf("probe: %v", err)
+	}
+	if s := client.CircuitState(); s != hub.CircuitClosed {
+		t.Errorf("circuit %s after a good probe, want closed", s)
+	}
+}
+
+// concurrent calls each get their own completion
+func TestConcurrentCallsCorrelated(t *testing.T) {
+	srv := NewServer(DefaultFixtures())
+	defer srv.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	client, err := srv.NewClient(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Disconnect()
+
+	var wg sync.WaitGroup
+	for range 50 {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			if q, err := client.GetDailyQuest(ctx, "Quest_Daily_Eliminations"); err != nil || q == nil || q.Count != 3 || len(q.Objectives) != 1 {
+				t.Errorf("GetDailyQuest: %v, %v", q, err)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			if b, err := client.GetChallengeBundle(ctx, "ChallengeBundle:QuestBundle_Week_001"); err != nil || b.TemplateID != "ChallengeBundle:QuestBundle_Week_001" {
+				t.Errorf("GetChallengeBundle: %v, %v", b, err)
+			}
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,356 @@
+// Package hubtest runs an in-process SignalR hub that serves fixture data,
+// so code built on hub.Client can be tested without the .NET backend.
+//
+// The server speaks the JSON and MessagePack hub protocols over websockets
+// itself rather than using the signalr server, whose panic-based error path
+// sends a second completion that makes clients drop the connection.
+package hubtest
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/andybalholm/brotli"
+	"github.com/coder/websocket"
+	"github.com/ilyskies/QuestHub/pkg/hub"
+)
+
+// Err is sent back as the hub error and Drop closes the connection instead
+// of answering; Times limits the fault to the next N calls (0 means every call)
+type Fault struct {
+	Err   string
+	Delay time.Duration
+	Drop  bool
+	Times int
+}
+
+type Server struct {
+	URL string
+
+	mu        sync.Mutex
+	fixtures  Fixtures
+	faults    map[string]*Fault
+	calls     map[string]int
+	sendReady bool
+	conns     map[*conn]struct{}
+	nextID    int
+	nextJob   int
+
+	// stateful reconnect: whether it is offered, whether each negotiated
+	// token asked for it, and the connections clients may resume
+	stateful   bool
+	negotiated map[string]bool
+	sessions   map[string]*conn
+
+	http *httptest.Server
+}
+
+func NewServer(fixtures Fixtures) *Server {
+	s := &Server{
+		fixtures:  fixtures,
+		faults:    make(map[string]*Fault),
+		calls:     make(map[string]int),
+		sendReady: true,
+		conns:     make(map[*conn]struct{}),
+
+		negotiated: make(map[string]bool),
+		sessions:   make(map[string]*conn),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /hub/negotiate", s.negotiate)
+	mux.HandleFunc("GET /hub", s.serveWebSocket)
+
+	s.http = httptest.NewServer(mux)
+	s.URL = s.http.URL + "/hub"
+	return s
+}
+
+// NewClient connects a client to the server and waits until it is connected
+func (s *Server) NewClient(ctx context.Context, opts ...hub.ClientOption) (*hub.Client, error) {
+	c := hub.NewClient(s.URL, opts...)
+	if err := c.Connect(); err != nil {
+		return nil, err
+	}
+
+	ticker := time.NewTicker(10 * time.Millisecond)
+	defer ticker.Stop()
+
+	for !c.IsConnected() {
+		select {
+		case <-ctx.Done():
+			c.Disconnect()
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+	return c, nil
+}
+
+func (s *Server) Close() {
+	s.DisconnectAll()
+	s.http.CloseClientConnections()
+	s.http.Close()
+}
+
+// DisconnectAll drops every open client connection
+func (s *Server) DisconnectAll() {
+	s.mu.Lock()
+	conns := make([]*conn, 0, len(s.conns))
+	for c := range s.conns {
+		conns = append(conns, c)
+	}
+	s.mu.Unlock()
+
+	for _, c := range conns {
+		c.close()
+	}
+}
+
+// DropTransports closes every websocket as a network blip would. Clients
+// with stateful reconnect resume their connection; the others lose it, as
+// with DisconnectAll.
+func (s *Server) DropTransports() {
+	s.mu.Lock()
+	conns := make([]*conn, 0, len(s.conns))
+	for c := range s.conns {
+		conns = append(conns, c)
+	}
+	s.mu.Unlock()
+
+	for _, c := range conns {
+		c.drop()
+	}
+}
+
+func (s *Server) Connections() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.conns)
+}
+
+func (s *Server) SetFixtures(f Fixtures) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.fixtures = f
+}
+
+func (s *Server) Fixtures() Fixtures {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.fixtures
+}
+
+// SetSendReady controls whether new connections receive a Ready call
+func (s *Server) SetSendReady(send bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sendReady = send
+}
+
+// SetStatefulReconnect controls whether clients that ask for stateful
+// reconnect get it; off by default
+func (s *Server) SetStatefulReconnect(enabled bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.stateful = enabled
+}
+
+func (s *Server) SetFault(method string, f Fault) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults[method] = &f
+}
+
+func (s *Server) ClearFaults() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = make(map[string]*Fault)
+}
+
+func (s *Server) Calls(method string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.calls[method]
+}
+
+func (s *Server) PushReady(status hub.ReadyStatus) {
+	s.broadcast("Ready", status)
+}
+
+func (s *Server) PushQuestUpdate(u hub.QuestUpdate) {
+	s.broadcast("QuestUpdated", u)
+}
+
+func (s *Server) PushBundleUpdate(u hub.BundleUpdate) {
+	s.broadcast("BundleUpdated", u)
+}
+
+func (s *Server) PushScheduleChange(c hub.ScheduleChange) {
+	s.broadcast("ScheduleChanged", c)
+}
+
+func (s *Server) PushDeprecation(n hub.DeprecationNotice) {
+	s.broadcast("Deprecated", n)
+}
+
+func (s *Server) PushRefreshProgress(p hub.RefreshProgress) {
+	s.broadcast("CacheRefreshProgress", p)
+}
+
+// Push calls an arbitrary client method on every connection
+func (s *Server) Push(target string, args ...interface{}) {
+	s.broadcast(target, args...)
+}
+
+func (s *Server) broadcast(target string, args ...interface{}) {
+	s.mu.Lock()
+	conns := make([]*conn, 0, len(s.conns))
+	for c := range s.conns {
+		conns = append(conns, c)
+	}
+	s.mu.Unlock()
+
+	for _, c := range conns {
+		_ = c.invokeClient(target, args...)
+	}
+}
+
+func (s *Server) refreshJob() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.nextJob++
+	return fmt.Sprintf("refresh-%d", s.nextJob)
+}
+
+func (s *Server) negotiate(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	s.nextID++
+	id := fmt.Sprintf("conn-%d", s.nextID)
+	stateful := s.stateful && r.URL.Query().Get("useStatefulReconnect") == "true"
+	s.negotiated[id] = stateful
+	s.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+
+	// encode like a hub behind a compressing proxy would
+	var out io.Writer = w
+	switch accept := r.Header.Get("Accept-Encoding"); {
+	case strings.Contains(accept, "br"):
+		w.Header().Set("Content-Encoding", "br")
+		bw := brotli.NewWriter(w)
+		defer bw.Close()
+		out = bw
+	case strings.Contains(accept, "gzip"):
+		w.Header().Set("Content-Encoding", "gzip")
+		gw := gzip.NewWriter(w)
+		defer gw.Close()
+		out = gw
+	}
+
+	_ = json.NewEncoder(out).Encode(map[string]interface{}{
+		"connectionId":     id,
+		"connectionToken":  id,
+		"negotiateVersion": 1,
+		"availableTransports": []map[string]interface{}{
+			{"transport": "WebSockets", "transferFormats": []string{"Text", "Binary"}},
+		},
+		"useStatefulReconnect": stateful,
+	})
+}
+
+func (s *Server) serveWebSocket(w http.ResponseWriter, r *http.Request) {
+	token := r.URL.Query().Get("id")
+
+	s.mu.Lock()
+	session := s.sessions[token]
+	stateful, known := s.negotiated[token]
+	delete(s.negotiated, token)
+	s.mu.Unlock()
+
+	// like the hub, refuse tokens it never issued or whose connection ended
+	if session == nil && !known {
+		http.Error(w, "No Connection with that ID", http.StatusNotFound)
+		return
+	}
+
+	// compresses only when the client asks for permessage-deflate
+	ws, err := websocket.Accept(w, r, &websocket.AcceptOptions{
+		CompressionMode: websocket.CompressionContextTakeover,
+	})
+	if err != nil {
+		return
+	}
+
+	if session != nil {
+		s.resume(session, ws)
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	c := &conn{server: s, ws: ws, ctx: ctx, cancel: cancel, next: 1}
+	c.resumable.Store(stateful)
+	if stateful {
+		c.resumed = make(chan *websocket.Conn)
+	}
+
+	s.mu.Lock()
+	s.conns[c] = struct{}{}
+	if stateful {
+		s.sessions[token] = c
+	}
+	s.mu.Unlock()
+
+	defer func() {
+		s.mu.Lock()
+		delete(s.conns, c)
+		delete(s.sessions, token)
+		s.mu.Unlock()
+		c.close()
+	}()
+
+	c.serve()
+}
+
+// resume hands a resuming client's websocket to its connection, closing the
+// old one in case the server has not noticed it failed
+func (s *Server) resume(c *conn, ws *websocket.Conn) {
+	c.writeMu.Lock()
+	old := c.ws
+	c.writeMu.Unlock()
+	old.CloseNow()
+
+	select {
+	case c.resumed <- ws:
+	case <-c.ctx.Done():
+		_ = ws.Close(websocket.StatusGoingAway, "connection ended")
+	}
+}
+
+// enter records the call and returns the fault to apply, if any
+func (s *Server) enter(method string) (Fixtures, Fault) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.calls[method]++
+
+	var fault Fault
+	if f, ok := s.faults[method]; ok {
+		fault = *f
+		if f.Times > 0 {
+			f.Times--
+			if f.Times == 0 {
+				delete(s.faults, method)
+			}
+		}
+	}
+	return s.fixtures, fault
+}
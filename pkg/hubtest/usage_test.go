@@ -0,0 +1,52 @@
+package hubtest
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ilyskies/QuestHub/pkg/hub"
+)
+
+// Usage survives a restart through WithUsageFile, and bytes are counted
+// as the hub sent them, so the two protocols report different sizes.
+func TestUsageFileAndWireBytes(t *testing.T) {
+	srv := NewServer(DefaultFixtures())
+	defer srv.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	bytes := make(map[hub.HubProtocol]int64)
+	for _, protocol := range []hub.HubProtocol{hub.ProtocolJSON, hub.ProtocolMessagePack} {
+		path := filepath.Join(t.TempDir(), "usage.json")
+
+		for range 2 {
+			c, err := srv.NewClient(ctx, hub.WithHubProtocol(protocol), hub.WithUsageFile(path))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := c.GetChallengeBundles(ctx); err != nil {
+				t.Fatal(err)
+			}
+			c.Disconnect()
+		}
+
+		report, err := hub.LoadUsage(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(report.Entries) != 1 || report.Entries[0].Method != "GetChallengeBundles" || report.Entries[0].Calls != 2 {
+			t.Fatalf("%s: got %+v, want 2 GetChallengeBundles calls", protocol, report.Entries)
+		}
+		if report.TotalBytes() == 0 {
+			t.Fatalf("%s: no bytes counted", protocol)
+		}
+		bytes[protocol] = report.TotalBytes()
+	}
+
+	if bytes[hub.ProtocolJSON] == bytes[hub.ProtocolMessagePack] {
+		t.Errorf("JSON and MessagePack both counted %d bytes", bytes[hub.ProtocolJSON])
+	}
+}
@@ -0,0 +1,257 @@
+package hubtest
+
+import (
+	"context"
+	"maps"
+	"testing"
+	"time"
+
+	"github.com/ilyskies/QuestHub/pkg/hub"
+)
+
+// Every subscriber sees the change a push announces, and stopping the
+// watcher closes their channels.
+func TestWatcherPublishesToSubscribers(t *testing.T) {
+	srv := NewServer(DefaultFixtures())
+	defer srv.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	c, err := srv.NewClient(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Disconnect()
+
+	// only a push can make it poll again within the test
+	w := hub.NewWatcher(c, hub.WatchInterval(time.Hour), hub.WatchJitter(0))
+	first, _ := w.Subscribe()
+	second, _ := w.Subscribe(hub.QuestAdded)
+	events := w.Events()
+	if err := w.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+	for srv.Calls("GetChallengeBundleSchedules") == 0 {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	f := srv.Fixtures()
+	f.DailyQuests = maps.Clone(f.DailyQuests)
+	f.DailyQuests["Quest_Daily_Harvest"] = hub.BaseQuest{Count: 1}
+	srv.SetFixtures(f)
+	srv.PushQuestUpdate(hub.QuestUpdate{QuestID: "Quest_Daily_Harvest"})
+
+	for name, ch := range map[string]<-chan hub.ChangeEvent{"first": first, "second": second, "Events": events} {
+		select {
+		case e := <-ch:
+			if e.Type != hub.QuestAdded || e.ID != "Quest_Daily_Harvest" {
+				t.Errorf("%s: got %s %s, want quest_added Quest_Daily_Harvest", name, e.Type, e.ID)
+			}
+		case <-ctx.Done():
+			t.Fatalf("%s: no change delivered", name)
+		}
+	}
+
+	if err := w.Stop(ctx); err != nil {
+		t.Fatal(err)
+	}
+	for name, ch := range map[string]<-chan hub.ChangeEvent{"first": first, "second": second} {
+		if _, ok := <-ch; ok {
+			t.Errorf("%s: channel open after Stop", name)
+		}
+	}
+}
+
+// A poll that finds changes shortens the adaptive interval.
+func TestWatcherAdaptiveInterval(t *testing.T) {
+	srv := NewServer(DefaultFixtures())
+	defer srv.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	c, err := srv.NewClient(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Disconnect()
+
+	w := hub.NewWatcher(c, hub.WatchAdaptive(time.Second, time.Hour), hub.WatchJitter(0))
+	if got := w.Interval(); got != time.Hour {
+		t.Fatalf("starts at %s, want the maximum", got)
+	}
+	events := w.Events()
+	if err := w.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+	defer w.Stop(ctx)
+	for srv.Calls("GetChallengeBundleSchedules") == 0 {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	f := srv.Fixtures()
+	f.DailyQuests = maps.Clone(f.DailyQuests)
+	delete(f.DailyQuests, "Quest_Daily_Eliminations")
+	srv.SetFixtures(f)
+	srv.PushQuestUpdate(hub.QuestUpdate{QuestID: "Quest_Daily_Eliminations", Removed: true})
+
+	select {
+	case <-events:
+	case <-ctx.Done():
+		t.Fatal("no change delivered")
+	}
+	if got := w.Interval(); got >= time.Hour || got < time.Second {
+		t.Errorf("interval %s after a change, want between 1s and 1h", got)
+	}
+}
+
+// With nobody reading Events, more changes than WatchBuffer must not stall
+// the watcher for its subscribers.
+func TestWatcherWithoutEventsReader(t *testing.T) {
+	srv := NewServer(DefaultFixtures())
+	defer srv.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	c, err := srv.NewClient(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Disconnect()
+
+	// the initial additions alone are more than the buffer holds
+	w := hub.NewWatcher(c, hub.WatchInterval(time.Hour), hub.WatchJitter(0), hub.WatchBuffer(1), hub.WatchEmitInitial())
+	sub, _ := w.Subscribe()
+	if err := w.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+	defer w.Stop(ctx)
+	for srv.Calls("GetChallengeBundleSchedules") == 0 {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	f := srv.Fixtures()
+	f.DailyQuests = maps.Clone(f.DailyQuests)
+	f.DailyQuests["Quest_Daily_Harvest"] = hub.BaseQuest{Count: 1}
+	srv.SetFixtures(f)
+	srv.PushQuestUpdate(hub.QuestUpdate{QuestID: "Quest_Daily_Harvest"})
+
+	for {
+		select {
+		case e := <-sub:
+			if e.ID == "Quest_Daily_Harvest" {
+				return
+			}
+		case <-ctx.Done():
+			t.Fatal("watcher stalled on the unread Events channel")
+		}
+	}
+}
+
+// a new version replacing everything arrives as one event and empties the
+// response cache
+func TestWatcherSeasonRollover(t *testing.T) {
+	srv := NewServer(DefaultFixtures())
+	defer srv.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	c, err := srv.NewClient(ctx, hub.WithResponseCache(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Disconnect()
+	if _, err := c.GetDailyQuests(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	w := hub.NewWatcher(c, hub.WatchInterval(time.Hour))
+	events := w.Events()
+	if err := w.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+	defer w.Stop(ctx)
+	for srv.Calls("GetChallengeBundleSchedules") == 0 {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	f := srv.Fixtures()
+	f.Status.Version = "next"
+	f.DailyQuests = map[string]hub.BaseQuest{"Quest_Next_Season": {Count: 1}}
+	f.Bundles = []hub.AthenaChallengeBundle{{TemplateID: "ChallengeBundle:Next", ChallengeBundleSchedule: "ChallengeBundleSchedule:Next"}}
+	f.Schedules = []hub.ChallengeBundleSchedule{{TemplateID: "ChallengeBundleSchedule:Next"}}
+	srv.SetFixtures(f)
+	srv.PushQuestUpdate(hub.QuestUpdate{QuestID: "Quest_Next_Season"})
+
+	var e hub.ChangeEvent
+	select {
+	case e = <-events:
+	case <-ctx.Done():
+		t.Fatal("no rollover delivered")
+	}
+	if e.Type != hub.SeasonRollover || e.ID != "next" || e.Rollover.FromVersion != "test" || e.Rollover.Added.Total() != 3 {
+		t.Fatalf("event = %+v", e)
+	}
+	select {
+	case e := <-events:
+		t.Errorf("another event after the rollover: %+v", e)
+	case <-time.After(50 * time.Millisecond):
+	}
+	if entries := c.CacheStats().Entries; entries != 0 {
+		t.Errorf("%d cached responses survived the rollover", entries)
+	}
+}
+
+// a watcher started from a baseline waits an interval, or a push, and then
+// reports only what changed since
+func TestWatcherFrom(t *testing.T) {
+	srv := NewServer(DefaultFixtures())
+	defer srv.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	c, err := srv.NewClient(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Disconnect()
+
+	from, err := c.Snapshot(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	delete(from.DailyQuests, "Quest_Daily_Eliminations")
+	polled := srv.Calls("GetDailyQuests")
+
+	w := hub.NewWatcher(c, hub.WatchInterval(time.Hour), hub.WatchEmitInitial(), hub.WatchFrom(from))
+	events := w.Events()
+	if err := w.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+	defer w.Stop(ctx)
+
+	time.Sleep(50 * time.Millisecond)
+	if n := srv.Calls("GetDailyQuests") - polled; n != 0 {
+		t.Fatalf("%d polls before the first interval", n)
+	}
+
+	srv.PushQuestUpdate(hub.QuestUpdate{QuestID: "Quest_Daily_Eliminations"})
+	var e hub.ChangeEvent
+	select {
+	case e = <-events:
+	case <-ctx.Done():
+		t.Fatal("no change delivered")
+	}
+	if e.Type != hub.QuestAdded || e.ID != "Quest_Daily_Eliminations" {
+		t.Fatalf("event = %+v", e)
+	}
+	select {
+	case e := <-events:
+		t.Errorf("change the baseline already had: %+v", e)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
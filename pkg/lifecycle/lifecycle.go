@@ -0,0 +1,188 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"sync"
+	"time"
+
+	"github.com/ilyskies/QuestHub/pkg/hub"
+)
+
+var (
+	ErrStageTimeout = errors.New("shutdown stage timed out")
+
+	ErrAlreadyShutdown = errors.New("lifecycle already shut down")
+)
+
+const DefaultStageTimeout = 10 * time.Second
+
+// StopFunc releases a subsystem. ctx expires when the stage timeout elapses.
+type StopFunc func(ctx context.Context) error
+
+type StageResult struct {
+	Name     string
+	Duration time.Duration
+	Err      error
+}
+
+type stage struct {
+	name    string
+	timeout time.Duration
+	stop    StopFunc
+}
+
+// Manager stops registered subsystems one at a time, in registration order.
+// Register the subsystem that produces work first (watchers), then the ones
+// that consume it (outbox, store) and the hub client last.
+type Manager struct {
+	mu      sync.Mutex
+	stages  []stage
+	before  []func(name string)
+	after   []func(StageResult)
+	done    bool
+	results []StageResult
+	err     error
+}
+
+func New() *Manager {
+	return &Manager{}
+}
+
+// Register appends a stage. A timeout <= 0 uses DefaultStageTimeout.
+func (m *Manager) Register(name string, timeout time.Duration, stop StopFunc) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.done {
+		return ErrAlreadyShutdown
+	}
+	if timeout <= 0 {
+		timeout = DefaultStageTimeout
+	}
+	m.stages = append(m.stages, stage{name: name, timeout: timeout, stop: stop})
+	return nil
+}
+
+// RegisterClient adds a stage that lets in-flight calls finish, then
+// disconnects the hub client.
+func (m *Manager) RegisterClient(c *hub.Client, timeout time.Duration) error {
+	return m.Register("hub client", timeout, func(ctx context.Context) error {
+		return c.DisconnectGraceful(ctx)
+	})
+}
+
+func (m *Manager) OnStageStart(fn func(name string)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.before = append(m.before, fn)
+}
+
+func (m *Manager) OnStageDone(fn func(StageResult)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.after = append(m.after, fn)
+}
+
+// Shutdown runs every stage in order. A failing or timed out stage does not stop
+// the ones after it; their errors are combined in a hub.MultiError. When ctx is
+// cancelled the remaining stages still run, but each gets an already expired
+// context. Later calls return the first result.
+func (m *Manager) Shutdown(ctx context.Context) error {
+	m.mu.Lock()
+	if m.done {
+		err := m.err
+		m.mu.Unlock()
+		return err
+	}
+	m.done = true
+	stages := append([]stage(nil), m.stages...)
+	before := make([]func(string), len(m.before))
+	copy(before, m.before)
+	after := make([]func(StageResult), len(m.after))
+	copy(after, m.after)
+	m.mu.Unlock()
+
+	var errs hub.MultiError
+	results := make([]StageResult, 0, len(stages))
+
+	for _, s := range stages {
+		for _, h := range before {
+			h(s.name)
+		}
+
+		result := runStage(ctx, s)
+		results = append(results, result)
+		if result.Err != nil {
+			errs.Add(fmt.Errorf("%s: %w", s.name, result.Err))
+		}
+
+		for _, h := range after {
+			h(result)
+		}
+	}
+
+	m.mu.Lock()
+	m.results = results
+	m.err = errs.ErrOrNil()
+	m.mu.Unlock()
+
+	return m.err
+}
+
+func runStage(ctx context.Context, s stage) StageResult {
+	stageCtx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	start := time.Now()
+	done := make(chan error, 1)
+
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				done <- fmt.Errorf("panic: %v", r)
+			}
+		}()
+		done <- s.stop(stageCtx)
+	}()
+
+	var err error
+	select {
+	case err = <-done:
+	case <-stageCtx.Done():
+		err = fmt.Errorf("%w after %s", ErrStageTimeout, s.timeout)
+	}
+
+	return StageResult{Name: s.name, Duration: time.Since(start), Err: err}
+}
+
+// Results reports how each stage finished. Empty until Shutdown has returned.
+func (m *Manager) Results() []StageResult {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]StageResult(nil), m.results...)
+}
+
+// ShutdownOnSignal blocks until ctx is cancelled or one of sigs arrives and then
+// shuts down with the given overall deadline.
+func (m *Manager) ShutdownOnSignal(ctx context.Context, deadline time.Duration, sigs ...os.Signal) error {
+	if len(sigs) == 0 {
+		sigs = []os.Signal{os.Interrupt}
+	}
+
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, sigs...)
+	defer signal.Stop(ch)
+
+	select {
+	case <-ctx.Done():
+	case <-ch:
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), deadline)
+	defer cancel()
+	return m.Shutdown(shutdownCtx)
+}
@@ -0,0 +1,242 @@
+// Package loadtest drives a hub with a weighted mix of calls from many
+// workers and reports latency percentiles and error rates per method, for
+// capacity planning ahead of traffic spikes:
+//
+//	srv := hubtest.NewServer(hubtest.DefaultFixtures())
+//	c, _ := srv.NewClient(ctx)
+//	report, err := loadtest.Run(ctx, []hub.Service{c}, loadtest.Config{
+//		Mix:         loadtest.DefaultMix(),
+//		Concurrency: 32,
+//		Duration:    30 * time.Second,
+//	})
+//
+// Run against a hubtest.Server or a staging hub; it does not hold back for
+// a production one.
+package loadtest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"math/rand/v2"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ilyskies/QuestHub/pkg/hub"
+)
+
+var ErrUnknownMethod = errors.New("loadtest: unknown method")
+
+// Mix weighs the methods to call: with GetDailyQuests 3 and
+// GetServiceStatus 1, three calls in four fetch the quests
+type Mix map[string]int
+
+// DefaultMix is read traffic, mostly the two lists clients poll
+func DefaultMix() Mix {
+	return Mix{
+		"GetDailyQuests":              4,
+		"GetChallengeBundles":         4,
+		"GetChallengeBundleSchedules": 1,
+		"GetServiceStatus":            1,
+	}
+}
+
+// ParseMix reads "GetDailyQuests=3,GetServiceStatus" form; a method without
+// a weight weighs 1
+func ParseMix(s string) (Mix, error) {
+	mix := make(Mix)
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		method, weight, ok := strings.Cut(part, "=")
+		w := 1
+		if ok {
+			n, err := strconv.Atoi(weight)
+			if err != nil || n < 0 {
+				return nil, fmt.Errorf("loadtest: weight of %s: %q is not a count", method, weight)
+			}
+			w = n
+		}
+		if _, known := calls[method]; !known {
+			return nil, fmt.Errorf("%w: %s, want one of %s", ErrUnknownMethod, method, strings.Join(Methods(), ", "))
+		}
+		mix[method] += w
+	}
+	return mix, nil
+}
+
+type Config struct {
+	Mix Mix
+	// workers calling at once; 1 when unset
+	Concurrency int
+	// how long to keep calling; the run also ends when ctx does
+	Duration time.Duration
+}
+
+// call runs one method; ids holds what the warm-up found for methods
+// taking an ID
+type call func(ctx context.Context, svc hub.Service, ids *targets, rng *rand.Rand) error
+
+var calls = map[string]call{
+	"GetServiceStatus": func(ctx context.Context, svc hub.Service, _ *targets, _ *rand.Rand) error {
+		_, err := svc.GetServiceStatus(ctx)
+		return err
+	},
+	"GetDailyQuests": func(ctx context.Context, svc hub.Service, _ *targets, _ *rand.Rand) error {
+		_, err := svc.GetDailyQuests(ctx)
+		return err
+	},
+	"GetDailyQuest": func(ctx context.Context, svc hub.Service, ids *targets, rng *rand.Rand) error {
+		_, err := svc.GetDailyQuest(ctx, pick(ids.quests, rng))
+		return err
+	},
+	"GetChallengeBundles": func(ctx context.Context, svc hub.Service, _ *targets, _ *rand.Rand) error {
+		_, err := svc.GetChallengeBundles(ctx)
+		return err
+	},
+	"GetChallengeBundle": func(ctx context.Context, svc hub.Service, ids *targets, rng *rand.Rand) error {
+		_, err := svc.GetChallengeBundle(ctx, pick(ids.bundles, rng))
+		return err
+	},
+	"GetChallengeBundleSchedules": func(ctx context.Context, svc hub.Service, _ *targets, _ *rand.Rand) error {
+		_, err := svc.GetChallengeBundleSchedules(ctx)
+		return err
+	},
+	"ClearCache": func(ctx context.Context, svc hub.Service, _ *targets, _ *rand.Rand) error {
+		_, err := svc.ClearCache(ctx)
+		return err
+	},
+	"RefreshCache": func(ctx context.Context, svc hub.Service, _ *targets, _ *rand.Rand) error {
+		_, err := svc.RefreshCache(ctx)
+		return err
+	},
+}
+
+// Methods lists what a Mix may name
+func Methods() []string {
+	out := make([]string, 0, len(calls))
+	for m := range calls {
+		out = append(out, m)
+	}
+	sort.Strings(out)
+	return out
+}
+
+type targets struct {
+	quests, bundles []string
+}
+
+// an empty list asks for an ID the hub does not know, which still loads it
+func pick(ids []string, rng *rand.Rand) string {
+	if len(ids) == 0 {
+		return "loadtest-missing"
+	}
+	return ids[rng.IntN(len(ids))]
+}
+
+// Run calls until cfg.Duration passes or ctx is done, worker i using
+// services[i%len(services)]. Calls cut short by the end of the run are not
+// counted.
+func Run(ctx context.Context, services []hub.Service, cfg Config) (*Report, error) {
+	if len(services) == 0 {
+		return nil, errors.New("loadtest: no service to call")
+	}
+	if cfg.Duration <= 0 {
+		return nil, errors.New("loadtest: duration must be positive")
+	}
+	if cfg.Concurrency <= 0 {
+		cfg.Concurrency = 1
+	}
+
+	var methods []string
+	var cumulative []int
+	total := 0
+	for _, m := range slices.Sorted(maps.Keys(cfg.Mix)) {
+		if _, ok := calls[m]; !ok {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownMethod, m)
+		}
+		if cfg.Mix[m] == 0 {
+			continue
+		}
+		total += cfg.Mix[m]
+		methods = append(methods, m)
+		cumulative = append(cumulative, total)
+	}
+	if total == 0 {
+		return nil, errors.New("loadtest: the mix calls nothing")
+	}
+
+	ids, err := warmUp(ctx, services[0], cfg.Mix)
+	if err != nil {
+		return nil, err
+	}
+
+	runCtx, cancel := context.WithTimeout(ctx, cfg.Duration)
+	defer cancel()
+
+	results := make([]*recorder, cfg.Concurrency)
+	start := time.Now()
+	var wg sync.WaitGroup
+	for i := range cfg.Concurrency {
+		rec := newRecorder()
+		results[i] = rec
+		svc := services[i%len(services)]
+		rng := rand.New(rand.NewPCG(uint64(start.UnixNano()), uint64(i)))
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for runCtx.Err() == nil {
+				n := rng.IntN(total)
+				j := sort.SearchInts(cumulative, n+1)
+				method := methods[j]
+
+				began := time.Now()
+				err := calls[method](runCtx, svc, ids, rng)
+				took := time.Since(began)
+
+				if err != nil && runCtx.Err() != nil {
+					// cut short by the end of the run
+					return
+				}
+				rec.add(method, took, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	merged := newRecorder()
+	for _, rec := range results {
+		merged.merge(rec)
+	}
+	return merged.report(time.Since(start), cfg.Concurrency), nil
+}
+
+// warmUp finds IDs for the methods that take one
+func warmUp(ctx context.Context, svc hub.Service, mix Mix) (*targets, error) {
+	ids := &targets{}
+	if mix["GetDailyQuest"] > 0 {
+		quests, err := svc.GetDailyQuests(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("loadtest: listing quests: %w", err)
+		}
+		ids.quests = slices.Sorted(maps.Keys(quests))
+	}
+	if mix["GetChallengeBundle"] > 0 {
+		bundles, err := svc.GetChallengeBundles(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("loadtest: listing bundles: %w", err)
+		}
+		for _, b := range bundles {
+			ids.bundles = append(ids.bundles, b.TemplateID)
+		}
+	}
+	return ids, nil
+}
@@ -0,0 +1,77 @@
+package loadtest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ilyskies/QuestHub/pkg/hub"
+	"github.com/ilyskies/QuestHub/pkg/hubtest"
+)
+
+func TestRun(t *testing.T) {
+	srv := hubtest.NewServer(hubtest.DefaultFixtures())
+	defer srv.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	c, err := srv.NewClient(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Disconnect()
+
+	srv.SetFault("GetServiceStatus", hubtest.Fault{Err: `{"code": "RATE_LIMITED", "message": "slow down"}`})
+
+	mix, err := ParseMix("GetDailyQuest=2, GetServiceStatus")
+	if err != nil {
+		t.Fatal(err)
+	}
+	report, err := Run(ctx, []hub.Service{c}, Config{Mix: mix, Concurrency: 4, Duration: 300 * time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(report.Methods) != 2 || report.Total.Calls != report.Methods[0].Calls+report.Methods[1].Calls {
+		t.Fatalf("got %+v", report)
+	}
+	quest, status := report.Methods[0], report.Methods[1]
+	if quest.Method != "GetDailyQuest" || quest.Calls == 0 || quest.Errors != 0 {
+		t.Errorf("got %+v, want GetDailyQuest calls without errors", quest)
+	}
+	if status.Calls == 0 || status.ErrorRate != 1 || status.ErrorsByKind[hub.CodeRateLimited] != status.Errors {
+		t.Errorf("got %+v, want every GetServiceStatus rate limited", status)
+	}
+	if quest.P50 > quest.P99 || quest.P99 > quest.Max || quest.Max == 0 {
+		t.Errorf("percentiles out of order: %+v", quest)
+	}
+	if got := srv.Calls("GetDailyQuest"); got < quest.Calls {
+		t.Errorf("hub saw %d GetDailyQuest calls, report has %d", got, quest.Calls)
+	}
+}
+
+func TestParseMix(t *testing.T) {
+	if _, err := ParseMix("GetDailyQuests=x"); err == nil {
+		t.Error("bad weight accepted")
+	}
+	if _, err := ParseMix("SendQuestUpdate=1"); !errors.Is(err, ErrUnknownMethod) {
+		t.Errorf("got %v, want ErrUnknownMethod", err)
+	}
+}
+
+func TestPercentile(t *testing.T) {
+	var sorted []time.Duration
+	for i := 1; i <= 100; i++ {
+		sorted = append(sorted, time.Duration(i))
+	}
+	for p, want := range map[int]time.Duration{50: 50, 90: 90, 99: 99, 100: 100} {
+		if got := percentile(sorted, p); got != want {
+			t.Errorf("p%d: got %d, want %d", p, got, want)
+		}
+	}
+	if got := percentile(sorted[:1], 50); got != 1 {
+		t.Errorf("one sample: got %d", got)
+	}
+}
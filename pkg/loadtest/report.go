@@ -0,0 +1,146 @@
+package loadtest
+
+import (
+	"errors"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/ilyskies/QuestHub/pkg/hub"
+)
+
+type Report struct {
+	Duration    time.Duration `json:"duration"`
+	Concurrency int           `json:"concurrency"`
+	// by method name
+	Methods []MethodStats `json:"methods"`
+	// every method together
+	Total MethodStats `json:"total"`
+}
+
+// MethodStats covers the calls of one method; latencies include failed
+// calls
+type MethodStats struct {
+	Method    string  `json:"method"`
+	Calls     int     `json:"calls"`
+	Errors    int     `json:"errors"`
+	ErrorRate float64 `json:"errorRate"`
+	// calls per second over the run
+	Throughput float64 `json:"throughput"`
+
+	Mean time.Duration `json:"mean"`
+	P50  time.Duration `json:"p50"`
+	P90  time.Duration `json:"p90"`
+	P99  time.Duration `json:"p99"`
+	Max  time.Duration `json:"max"`
+
+	// error count by hub error code, or by message for other failures
+	ErrorsByKind map[string]int `json:"errorsByKind,omitempty"`
+}
+
+type samples struct {
+	latencies []time.Duration
+	errors    map[string]int
+}
+
+type recorder struct {
+	byMethod map[string]*samples
+}
+
+func newRecorder() *recorder {
+	return &recorder{byMethod: make(map[string]*samples)}
+}
+
+func (r *recorder) get(method string) *samples {
+	s, ok := r.byMethod[method]
+	if !ok {
+		s = &samples{errors: make(map[string]int)}
+		r.byMethod[method] = s
+	}
+	return s
+}
+
+func (r *recorder) add(method string, took time.Duration, err error) {
+	s := r.get(method)
+	s.latencies = append(s.latencies, took)
+	if err != nil {
+		s.errors[errorKind(err)]++
+	}
+}
+
+func (r *recorder) merge(other *recorder) {
+	for method, o := range other.byMethod {
+		s := r.get(method)
+		s.latencies = append(s.latencies, o.latencies...)
+		for kind, n := range o.errors {
+			s.errors[kind] += n
+		}
+	}
+}
+
+func (r *recorder) report(took time.Duration, concurrency int) *Report {
+	rep := &Report{Duration: took, Concurrency: concurrency}
+
+	all := &samples{errors: make(map[string]int)}
+	for _, method := range slices.Sorted(maps.Keys(r.byMethod)) {
+		s := r.byMethod[method]
+		rep.Methods = append(rep.Methods, s.stats(method, took))
+		all.latencies = append(all.latencies, s.latencies...)
+		for kind, n := range s.errors {
+			all.errors[kind] += n
+		}
+	}
+	rep.Total = all.stats("total", took)
+	return rep
+}
+
+func (s *samples) stats(method string, took time.Duration) MethodStats {
+	st := MethodStats{Method: method, Calls: len(s.latencies)}
+	if st.Calls == 0 {
+		return st
+	}
+
+	for kind, n := range s.errors {
+		st.Errors += n
+		if st.ErrorsByKind == nil {
+			st.ErrorsByKind = make(map[string]int)
+		}
+		st.ErrorsByKind[kind] = n
+	}
+	st.ErrorRate = float64(st.Errors) / float64(st.Calls)
+	if took > 0 {
+		st.Throughput = float64(st.Calls) / took.Seconds()
+	}
+
+	sorted := slices.Clone(s.latencies)
+	slices.Sort(sorted)
+	var sum time.Duration
+	for _, d := range sorted {
+		sum += d
+	}
+	st.Mean = sum / time.Duration(len(sorted))
+	st.P50 = percentile(sorted, 50)
+	st.P90 = percentile(sorted, 90)
+	st.P99 = percentile(sorted, 99)
+	st.Max = sorted[len(sorted)-1]
+	return st
+}
+
+// nearest rank of sorted, which is not empty
+func percentile(sorted []time.Duration, p int) time.Duration {
+	rank := (p*len(sorted) + 99) / 100
+	return sorted[max(rank, 1)-1]
+}
+
+// hub errors are grouped by code, so a hundred missing quests are one kind
+func errorKind(err error) string {
+	var herr *hub.HubError
+	if errors.As(err, &herr) && herr.Code != "" {
+		return herr.Code
+	}
+	msg := err.Error()
+	if len(msg) > 80 {
+		msg = msg[:80]
+	}
+	return msg
+}
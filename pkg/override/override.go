@@ -0,0 +1,368 @@
+// Package override lets operators correct hub data they know to be wrong,
+// give entries display names the hub does not have, and hide entries
+// altogether. A patch file lists rules per table:
+//
+//	quests:
+//	  - id: Quest_Daily_Eliminations
+//	    set: {count: 5}
+//	    reason: hub reports 3 since the 1.2 rollout
+//	bundles:
+//	  - id: ChallengeBundle:QuestBundle_Week_001
+//	    name: Week 1
+//	schedules:
+//	  - id: ChallengeBundleSchedule:Season_Old
+//	    exclude: true
+//
+// set is merged into the entry's JSON form as an RFC 7386 merge patch: objects
+// merge, anything else including arrays is replaced, and null removes a
+// field. Rules for IDs the hub does not serve are ignored.
+//
+// A Patch is a plugin.Transform, registered as "override", and can wrap a
+// hub.Service so served data is patched too. Every value it changes is
+// listed in the snapshot's Provenance.Overrides.
+package override
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/ilyskies/QuestHub/pkg/hub"
+	"github.com/ilyskies/QuestHub/pkg/plugin"
+)
+
+// recorded on the provenance of patched snapshots
+const TransformOverride = "override"
+
+// tables as named in Provenance.Overrides
+const (
+	TableQuests    = "quests"
+	TableBundles   = "bundles"
+	TableSchedules = "schedules"
+)
+
+// the field a display name is recorded under in Override.Fields
+const FieldName = "name"
+
+var ErrInvalidPatch = errors.New("invalid patch")
+
+func init() {
+	plugin.RegisterTransform("override", func(cfg plugin.Config) (plugin.Transform, error) {
+		if cfg["path"] == "" {
+			return nil, fmt.Errorf("override: path is required")
+		}
+		return Load(cfg["path"])
+	})
+}
+
+// Rule overrides one entry, by quest ID or template ID
+type Rule struct {
+	ID      string                 `json:"id" yaml:"id"`
+	Set     map[string]interface{} `json:"set,omitempty" yaml:"set,omitempty"`
+	Name    string                 `json:"name,omitempty" yaml:"name,omitempty"`
+	Exclude bool                   `json:"exclude,omitempty" yaml:"exclude,omitempty"`
+	// why the hub's data is overridden, kept in the provenance
+	Reason string `json:"reason,omitempty" yaml:"reason,omitempty"`
+}
+
+type Patch struct {
+	Quests    []Rule `json:"quests,omitempty" yaml:"quests,omitempty"`
+	Bundles   []Rule `json:"bundles,omitempty" yaml:"bundles,omitempty"`
+	Schedules []Rule `json:"schedules,omitempty" yaml:"schedules,omitempty"`
+
+	once      sync.Once
+	err       error
+	quests    map[string]*Rule
+	bundles   map[string]*Rule
+	schedules map[string]*Rule
+}
+
+var _ plugin.Transform = (*Patch)(nil)
+
+// Load reads a YAML or JSON patch file and validates it
+func Load(path string) (*Patch, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPatch, err)
+	}
+
+	p := &Patch{}
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".yaml", ".yml":
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+		// an empty file overrides nothing
+		if err = dec.Decode(p); errors.Is(err, io.EOF) {
+			err = nil
+		}
+	case ".json":
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+		err = dec.Decode(p)
+	default:
+		err = fmt.Errorf("unsupported patch format %q", ext)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s - %v", ErrInvalidPatch, path, err)
+	}
+
+	if err := p.Validate(); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return p, nil
+}
+
+// Validate checks every rule against the model it patches, so a misspelt
+// field fails here rather than on the first snapshot. The rules must not
+// be changed afterwards.
+func (p *Patch) Validate() error {
+	p.once.Do(func() {
+		var errs hub.MultiError
+		p.quests = index(TableQuests, p.Quests, "", validateSet[hub.BaseQuest], &errs)
+		p.bundles = index(TableBundles, p.Bundles, "templateId", validateSet[hub.AthenaChallengeBundle], &errs)
+		p.schedules = index(TableSchedules, p.Schedules, "templateId", validateSet[hub.ChallengeBundleSchedule], &errs)
+		p.err = errs.ErrOrNil()
+	})
+	return p.err
+}
+
+func index(table string, rules []Rule, idField string, validate func(map[string]interface{}) error, errs *hub.MultiError) map[string]*Rule {
+	out := make(map[string]*Rule, len(rules))
+	for i := range rules {
+		r := &rules[i]
+		fail := func(format string, args ...interface{}) {
+			errs.Add(fmt.Errorf("%w: %s[%d] %q: %s", ErrInvalidPatch, table, i, r.ID, fmt.Sprintf(format, args...)))
+		}
+
+		switch {
+		case r.ID == "":
+			fail("id is required")
+			continue
+		case out[r.ID] != nil:
+			fail("duplicate rule")
+			continue
+		case r.Exclude && (len(r.Set) > 0 || r.Name != ""):
+			fail("an excluded entry cannot also be set or named")
+		case idField != "" && hasKey(r.Set, idField):
+			fail("%s cannot be overridden", idField)
+		}
+		if err := validate(r.Set); err != nil {
+			fail("%v", err)
+		}
+		out[r.ID] = r
+	}
+	return out
+}
+
+func hasKey(m map[string]interface{}, k string) bool {
+	_, ok := m[k]
+	return ok
+}
+
+func validateSet[T any](set map[string]interface{}) error {
+	var zero T
+	_, err := merge(zero, set)
+	return err
+}
+
+// Apply patches snap in place and records what it changed
+func (p *Patch) Apply(_ context.Context, snap *hub.Snapshot) error {
+	if err := p.Validate(); err != nil {
+		return err
+	}
+
+	var applied []hub.Override
+	quests, o, err := p.patchQuests(snap.DailyQuests)
+	if err != nil {
+		return err
+	}
+	applied = append(applied, o...)
+	bundles, o, err := p.patchBundles(snap.Bundles)
+	if err != nil {
+		return err
+	}
+	applied = append(applied, o...)
+	schedules, o, err := p.patchSchedules(snap.Schedules)
+	if err != nil {
+		return err
+	}
+	applied = append(applied, o...)
+
+	snap.DailyQuests, snap.Bundles, snap.Schedules = quests, bundles, schedules
+	for _, a := range applied {
+		if !slices.Contains(a.Fields, FieldName) {
+			continue
+		}
+		if snap.Names == nil {
+			snap.Names = make(map[string]string)
+		}
+		snap.Names[a.ID] = p.rules(a.Table)[a.ID].Name
+	}
+
+	snap.Provenance = snap.Provenance.With(TransformOverride)
+	snap.Provenance.Overrides = append(slices.Clip(snap.Provenance.Overrides), applied...)
+	return nil
+}
+
+func (p *Patch) rules(table string) map[string]*Rule {
+	switch table {
+	case TableQuests:
+		return p.quests
+	case TableBundles:
+		return p.bundles
+	}
+	return p.schedules
+}
+
+// the patch* helpers leave their input alone and return patched copies
+
+func (p *Patch) patchQuests(in map[string]hub.BaseQuest) (map[string]hub.BaseQuest, []hub.Override, error) {
+	if in == nil {
+		return nil, nil, nil
+	}
+
+	out := make(map[string]hub.BaseQuest, len(in))
+	var applied []hub.Override
+	for id, q := range in {
+		q, o, keep, err := apply(p.quests[id], TableQuests, id, q)
+		if err != nil {
+			return nil, nil, err
+		}
+		if keep {
+			out[id] = q
+		}
+		if o != nil {
+			applied = append(applied, *o)
+		}
+	}
+	// map order is random; keep the provenance stable
+	sort.Slice(applied, func(i, j int) bool { return applied[i].ID < applied[j].ID })
+	return out, applied, nil
+}
+
+func (p *Patch) patchBundles(in []hub.AthenaChallengeBundle) ([]hub.AthenaChallengeBundle, []hub.Override, error) {
+	return patchList(p.bundles, TableBundles, in, func(b hub.AthenaChallengeBundle) string { return b.TemplateID })
+}
+
+func (p *Patch) patchSchedules(in []hub.ChallengeBundleSchedule) ([]hub.ChallengeBundleSchedule, []hub.Override, error) {
+	return patchList(p.schedules, TableSchedules, in, func(s hub.ChallengeBundleSchedule) string { return s.TemplateID })
+}
+
+func patchList[T any](rules map[string]*Rule, table string, in []T, id func(T) string) ([]T, []hub.Override, error) {
+	if in == nil {
+		return nil, nil, nil
+	}
+
+	out := make([]T, 0, len(in))
+	var applied []hub.Override
+	for _, v := range in {
+		v, o, keep, err := apply(rules[id(v)], table, id(v), v)
+		if err != nil {
+			return nil, nil, err
+		}
+		if keep {
+			out = append(out, v)
+		}
+		if o != nil {
+			applied = append(applied, *o)
+		}
+	}
+	return out, applied, nil
+}
+
+// apply returns v with r applied, the override to record, if any, and
+// whether v is kept at all
+func apply[T any](r *Rule, table, id string, v T) (T, *hub.Override, bool, error) {
+	if r == nil {
+		return v, nil, true, nil
+	}
+
+	o := &hub.Override{Table: table, ID: id, Reason: r.Reason}
+	if r.Exclude {
+		o.Excluded = true
+		return v, o, false, nil
+	}
+
+	if len(r.Set) > 0 {
+		patched, err := merge(v, r.Set)
+		if err != nil {
+			return v, nil, false, fmt.Errorf("override %s %q: %w", table, id, err)
+		}
+		v = patched
+		o.Fields = paths("", r.Set)
+	}
+	if r.Name != "" {
+		o.Fields = append(o.Fields, FieldName)
+	}
+	return v, o, true, nil
+}
+
+// merge applies set to the JSON form of v; fields v does not have are an
+// error rather than silently dropped
+func merge[T any](v T, set map[string]interface{}) (T, error) {
+	var out T
+
+	b, err := json.Marshal(v)
+	if err != nil {
+		return out, err
+	}
+	var doc interface{}
+	if err := json.Unmarshal(b, &doc); err != nil {
+		return out, err
+	}
+	if b, err = json.Marshal(mergePatch(doc, set)); err != nil {
+		return out, err
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&out); err != nil {
+		return out, err
+	}
+	return out, nil
+}
+
+// mergePatch is RFC 7386
+func mergePatch(target, patch interface{}) interface{} {
+	p, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+	t, ok := target.(map[string]interface{})
+	if !ok {
+		t = make(map[string]interface{}, len(p))
+	}
+	for k, v := range p {
+		if v == nil {
+			delete(t, k)
+			continue
+		}
+		t[k] = mergePatch(t[k], v)
+	}
+	return t
+}
+
+// paths lists the leaves of set as dotted field paths, sorted
+func paths(prefix string, set map[string]interface{}) []string {
+	var out []string
+	for k, v := range set {
+		if m, ok := v.(map[string]interface{}); ok && len(m) > 0 {
+			out = append(out, paths(prefix+k+".", m)...)
+			continue
+		}
+		out = append(out, prefix+k)
+	}
+	sort.Strings(out)
+	return out
+}
@@ -0,0 +1,154 @@
+package override
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/ilyskies/QuestHub/pkg/hub"
+	"github.com/ilyskies/QuestHub/pkg/hubtest"
+)
+
+const patchYAML = `
+quests:
+  - id: Quest_Daily_Eliminations
+    set:
+      count: 5
+      objectives: [{backendName: daily_athena_eliminations, count: 5}]
+    reason: hub reports 3
+  - id: Quest_Not_Served
+    set: {count: 1}
+bundles:
+  - id: ChallengeBundle:QuestBundle_Week_001
+    name: Week 1
+    set: {rarity: Rare}
+schedules:
+  - id: ChallengeBundleSchedule:Schedule_Week_001
+    exclude: true
+`
+
+func writePatch(t *testing.T, name, data string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestApply(t *testing.T) {
+	p, err := Load(writePatch(t, "patch.yaml", patchYAML))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	snap := &hub.Snapshot{
+		DailyQuests: map[string]hub.BaseQuest{
+			"Quest_Daily_Eliminations": {
+				Objectives: hub.QuestObjectives{{BackendName: "daily_athena_eliminations", Count: 3}},
+				Count:      3,
+			},
+		},
+		Bundles:    []hub.AthenaChallengeBundle{{TemplateID: "ChallengeBundle:QuestBundle_Week_001", Rarity: "Common", Amount: 1}},
+		Schedules:  []hub.ChallengeBundleSchedule{{TemplateID: "ChallengeBundleSchedule:Schedule_Week_001"}},
+		Provenance: hub.Provenance{Source: "test", Transforms: []string{hub.TransformMessagePackToJSON}},
+	}
+	orig := snap.Clone()
+	if err := p.Apply(context.Background(), snap); err != nil {
+		t.Fatal(err)
+	}
+
+	q := snap.DailyQuests["Quest_Daily_Eliminations"]
+	if q.Count != 5 || len(q.Objectives) != 1 || q.Objectives[0].Count != 5 {
+		t.Errorf("quest = %+v", q)
+	}
+	// fields the patch does not name are left alone
+	if b := snap.Bundles[0]; b.Rarity != "Rare" || b.Amount != 1 {
+		t.Errorf("bundle = %+v", b)
+	}
+	if len(snap.Schedules) != 0 {
+		t.Errorf("excluded schedule still there: %+v", snap.Schedules)
+	}
+	if snap.Names["ChallengeBundle:QuestBundle_Week_001"] != "Week 1" {
+		t.Errorf("names = %v", snap.Names)
+	}
+	if orig.DailyQuests["Quest_Daily_Eliminations"].Objectives[0].Count != 3 {
+		t.Error("Apply changed data shared with the original")
+	}
+
+	if !slices.Equal(snap.Provenance.Transforms, []string{hub.TransformMessagePackToJSON, TransformOverride}) {
+		t.Errorf("transforms = %v", snap.Provenance.Transforms)
+	}
+	want := []hub.Override{
+		{Table: TableQuests, ID: "Quest_Daily_Eliminations", Fields: []string{"count", "objectives"}, Reason: "hub reports 3"},
+		{Table: TableBundles, ID: "ChallengeBundle:QuestBundle_Week_001", Fields: []string{"rarity", FieldName}},
+		{Table: TableSchedules, ID: "ChallengeBundleSchedule:Schedule_Week_001", Excluded: true},
+	}
+	got := snap.Provenance.Overrides
+	if len(got) != len(want) {
+		t.Fatalf("overrides = %+v", got)
+	}
+	for i := range want {
+		if got[i].Table != want[i].Table || got[i].ID != want[i].ID || !slices.Equal(got[i].Fields, want[i].Fields) ||
+			got[i].Excluded != want[i].Excluded || got[i].Reason != want[i].Reason {
+			t.Errorf("override %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestLoadRejectsBadRules(t *testing.T) {
+	for name, data := range map[string]string{
+		"unknown field":   `{"quests": [{"id": "Q", "set": {"cont": 5}}]}`,
+		"wrong type":      `{"quests": [{"id": "Q", "set": {"count": "five"}}]}`,
+		"template id":     `{"bundles": [{"id": "B", "set": {"templateId": "C"}}]}`,
+		"exclude and set": `{"bundles": [{"id": "B", "exclude": true, "name": "x"}]}`,
+		"duplicate":       `{"schedules": [{"id": "S", "exclude": true}, {"id": "S", "exclude": true}]}`,
+		"missing id":      `{"quests": [{"set": {"count": 1}}]}`,
+	} {
+		_, err := Load(writePatch(t, "patch.json", data))
+		if !errors.Is(err, ErrInvalidPatch) {
+			t.Errorf("%s: err = %v, want ErrInvalidPatch", name, err)
+		}
+	}
+
+	_, err := Load(writePatch(t, "patch.json", `{"quests": [{"id": "Q", "set": {"cont": 5}}], "bundles": [{"set": {}}]}`))
+	var multi *hub.MultiError
+	if !errors.As(err, &multi) || len(multi.Errors) != 2 {
+		t.Errorf("two bad rules: err = %v, want a MultiError with both", err)
+	}
+}
+
+func TestService(t *testing.T) {
+	ctx := context.Background()
+	srv := hubtest.NewServer(hubtest.DefaultFixtures())
+	defer srv.Close()
+	client, err := srv.NewClient(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Disconnect()
+
+	p := &Patch{
+		Quests:  []Rule{{ID: "Quest_Daily_Eliminations", Exclude: true}},
+		Bundles: []Rule{{ID: "ChallengeBundle:QuestBundle_Week_001", Set: map[string]interface{}{"amount": 2}}},
+	}
+	svc, err := p.Service(client)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	quests, err := svc.GetDailyQuests(ctx)
+	if err != nil || len(quests) != 0 {
+		t.Errorf("GetDailyQuests = %v, %v", quests, err)
+	}
+	if _, err := svc.GetDailyQuest(ctx, "Quest_Daily_Eliminations"); !errors.Is(err, hub.ErrQuestNotFound) {
+		t.Errorf("GetDailyQuest err = %v, want ErrQuestNotFound", err)
+	}
+	b, err := svc.GetChallengeBundle(ctx, "ChallengeBundle:QuestBundle_Week_001")
+	if err != nil || b.Amount != 2 || b.Rarity != "Common" {
+		t.Errorf("GetChallengeBundle = %+v, %v", b, err)
+	}
+}
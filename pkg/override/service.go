@@ -0,0 +1,82 @@
+package override
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ilyskies/QuestHub/pkg/hub"
+)
+
+// Service wraps svc so every read is patched: corrected values are served
+// in place of the hub's and excluded entries are not found. Display names
+// have nowhere to go in single reads and only show up on snapshots.
+func (p *Patch) Service(svc hub.Service) (hub.Service, error) {
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+	return &service{Service: svc, patch: p}, nil
+}
+
+type service struct {
+	hub.Service
+	patch *Patch
+}
+
+func (s *service) GetDailyQuests(ctx context.Context, opts ...hub.CallOption) (map[string]hub.BaseQuest, error) {
+	quests, err := s.Service.GetDailyQuests(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+	quests, _, err = s.patch.patchQuests(quests)
+	return quests, err
+}
+
+func (s *service) GetDailyQuest(ctx context.Context, questID string, opts ...hub.CallOption) (*hub.BaseQuest, error) {
+	if r := s.patch.quests[questID]; r != nil && r.Exclude {
+		return nil, fmt.Errorf("%w: %s", hub.ErrQuestNotFound, questID)
+	}
+
+	q, err := s.Service.GetDailyQuest(ctx, questID, opts...)
+	if err != nil {
+		return nil, err
+	}
+	patched, _, _, err := apply(s.patch.quests[questID], TableQuests, questID, *q)
+	if err != nil {
+		return nil, err
+	}
+	return &patched, nil
+}
+
+func (s *service) GetChallengeBundles(ctx context.Context, opts ...hub.CallOption) ([]hub.AthenaChallengeBundle, error) {
+	bundles, err := s.Service.GetChallengeBundles(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+	bundles, _, err = s.patch.patchBundles(bundles)
+	return bundles, err
+}
+
+func (s *service) GetChallengeBundle(ctx context.Context, templateID string, opts ...hub.CallOption) (*hub.AthenaChallengeBundle, error) {
+	if r := s.patch.bundles[templateID]; r != nil && r.Exclude {
+		return nil, fmt.Errorf("%w: %s", hub.ErrBundleNotFound, templateID)
+	}
+
+	b, err := s.Service.GetChallengeBundle(ctx, templateID, opts...)
+	if err != nil {
+		return nil, err
+	}
+	patched, _, _, err := apply(s.patch.bundles[templateID], TableBundles, templateID, *b)
+	if err != nil {
+		return nil, err
+	}
+	return &patched, nil
+}
+
+func (s *service) GetChallengeBundleSchedules(ctx context.Context, opts ...hub.CallOption) ([]hub.ChallengeBundleSchedule, error) {
+	schedules, err := s.Service.GetChallengeBundleSchedules(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+	schedules, _, err = s.patch.patchSchedules(schedules)
+	return schedules, err
+}
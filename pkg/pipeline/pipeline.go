@@ -0,0 +1,120 @@
+// Package pipeline runs snapshots through composable stages that fetch,
+// transform, diff, persist and notify. It is for applications assembling
+// their own polling loop; hub.Watcher, Client.Snapshot and the exporters
+// do not run on it.
+//
+//	p := pipeline.New(client,
+//		pipeline.Apply(enrich),
+//		pipeline.Diff(),
+//		pipeline.SkipUnchanged(),
+//		pipeline.Persist(backend),
+//		pipeline.Publish(sink),
+//	)
+//	err := p.Poll(ctx, time.Minute, nil)
+//
+// Custom stages are plain functions of the item, see StageFunc.
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ilyskies/QuestHub/pkg/hub"
+)
+
+// Source produces snapshots; *hub.Client and *hub.FileBackend are sources
+type Source interface {
+	Snapshot(ctx context.Context) (*hub.Snapshot, error)
+}
+
+type SourceFunc func(ctx context.Context) (*hub.Snapshot, error)
+
+func (f SourceFunc) Snapshot(ctx context.Context) (*hub.Snapshot, error) {
+	return f(ctx)
+}
+
+// Item is what flows through the stages of one run
+type Item struct {
+	Seq      uint64
+	Snapshot *hub.Snapshot
+	// the last snapshot that passed every stage; nil on the first run
+	Previous *hub.Snapshot
+	// set by the Diff stage
+	Changes hub.ChangeSet
+}
+
+// Stage processes an item in place. Returning ErrSkip drops the item
+// without failing the run.
+type Stage interface {
+	Process(ctx context.Context, item *Item) error
+}
+
+type StageFunc func(ctx context.Context, item *Item) error
+
+func (f StageFunc) Process(ctx context.Context, item *Item) error {
+	return f(ctx, item)
+}
+
+var ErrSkip = errors.New("pipeline: item skipped")
+
+type Pipeline struct {
+	source Source
+	stages []Stage
+
+	mu   sync.Mutex
+	seq  uint64
+	last *hub.Snapshot
+}
+
+func New(source Source, stages ...Stage) *Pipeline {
+	return &Pipeline{source: source, stages: stages}
+}
+
+// Run fetches one snapshot and passes it through the stages in order. It
+// returns the item, or nil when a stage skipped it.
+func (p *Pipeline) Run(ctx context.Context) (*Item, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	snap, err := p.source.Snapshot(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("fetch: %w", err)
+	}
+
+	p.seq++
+	item := &Item{Seq: p.seq, Snapshot: snap, Previous: p.last}
+
+	for i, s := range p.stages {
+		if err := s.Process(ctx, item); err != nil {
+			if errors.Is(err, ErrSkip) {
+				return nil, nil
+			}
+			return nil, fmt.Errorf("stage %d: %w", i, err)
+		}
+	}
+
+	p.last = item.Snapshot
+	return item, nil
+}
+
+// Poll runs the pipeline every interval until ctx is done. Failed runs are
+// passed to onError, which may be nil, and do not stop polling.
+func (p *Pipeline) Poll(ctx context.Context, interval time.Duration, onError func(error)) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := p.Run(ctx); err != nil && onError != nil && ctx.Err() == nil {
+			onError(err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package pipeline
+
+import (
+	"context"
+
+	"github.com/ilyskies/QuestHub/pkg/hub"
+	"github.com/ilyskies/QuestHub/pkg/plugin"
+	"github.com/ilyskies/QuestHub/pkg/store"
+)
+
+// Transform rewrites the snapshot in place, e.g. to enrich or redact it
+func Transform(fn func(ctx context.Context, snap *hub.Snapshot) error) Stage {
+	return StageFunc(func(ctx context.Context, item *Item) error {
+		return fn(ctx, item.Snapshot)
+	})
+}
+
+// Apply runs a plugin transform
+func Apply(t plugin.Transform) Stage {
+	return Transform(t.Apply)
+}
+
+// Filter drops items keep rejects
+func Filter(keep func(*Item) bool) Stage {
+	return StageFunc(func(_ context.Context, item *Item) error {
+		if !keep(item) {
+			return ErrSkip
+		}
+		return nil
+	})
+}
+
+// Sample keeps every nth item, starting with the first
+func Sample(n int) Stage {
+	var count int
+	return StageFunc(func(_ context.Context, _ *Item) error {
+		count++
+		if n > 1 && (count-1)%n != 0 {
+			return ErrSkip
+		}
+		return nil
+	})
+}
+
+// Diff fills Item.Changes with the changes since the previous snapshot; the
+// first run reports everything as added, and a new season is one
+// hub.Rollover, see hub.SeasonDiff
+func Diff() Stage {
+	return StageFunc(func(_ context.Context, item *Item) error {
+		item.Changes = hub.SeasonDiff(item.Previous, item.Snapshot)
+		return nil
+	})
+}
+
+// SkipUnchanged drops items identical to the previous snapshot, so later
+// stages only see real changes. The first run always passes.
+func SkipUnchanged() Stage {
+	return StageFunc(func(_ context.Context, item *Item) error {
+		if item.Previous != nil && hub.Diff(item.Previous, item.Snapshot).Empty() {
+			return ErrSkip
+		}
+		return nil
+	})
+}
+
+// Persist replaces what backend holds with each snapshot
+func Persist(backend store.Backend) Stage {
+	return StageFunc(func(ctx context.Context, item *Item) error {
+		return backend.Write(ctx, store.ResetBatch(item.Snapshot))
+	})
+}
+
+// Publish sends Item.Changes to sink; put it after Diff
+func Publish(sink plugin.Sink) Stage {
+	return StageFunc(func(ctx context.Context, item *Item) error {
+		return sink.Publish(ctx, item.Changes)
+	})
+}
+
+// Notify calls fn with the item; it runs synchronously, so keep it short
+func Notify(fn func(Item)) Stage {
+	return StageFunc(func(_ context.Context, item *Item) error {
+		fn(*item)
+		return nil
+	})
+}
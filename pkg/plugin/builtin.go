@@ -0,0 +1,202 @@
+package plugin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ilyskies/QuestHub/pkg/hub"
+	"github.com/ilyskies/QuestHub/pkg/store"
+	"github.com/ilyskies/QuestHub/pkg/store/codec"
+)
+
+func init() {
+	RegisterSink("stdout", func(Config) (Sink, error) {
+		return &writerSink{w: os.Stdout}, nil
+	})
+	RegisterStore("file", newFileStore)
+	RegisterStore("mirror", newMirrorStore)
+}
+
+// writerSink writes each non-empty change set as one JSON line
+type writerSink struct {
+	w io.Writer
+}
+
+func (s *writerSink) Publish(_ context.Context, changes hub.ChangeSet) error {
+	if changes.Empty() {
+		return nil
+	}
+	return json.NewEncoder(s.w).Encode(changes)
+}
+
+// fileStore keeps the model as one JSON snapshot at cfg["path"], replacing
+// it atomically on every write. cfg["compression"] and cfg["blockSize"]
+// compress it, see package codec.
+type fileStore struct {
+	mu   sync.Mutex
+	path string
+	enc  *codec.Encoder
+}
+
+func newFileStore(cfg Config) (store.Backend, error) {
+	path := cfg["path"]
+	if path == "" {
+		return nil, fmt.Errorf("file store: path is required")
+	}
+
+	c, err := codec.ParseCodec(cfg["compression"])
+	if err != nil {
+		return nil, fmt.Errorf("file store: %w", err)
+	}
+	var blockSize int
+	if v := cfg["blockSize"]; v != "" {
+		if blockSize, err = strconv.Atoi(v); err != nil {
+			return nil, fmt.Errorf("file store: blockSize: %w", err)
+		}
+	}
+	enc, err := codec.New(c, blockSize)
+	if err != nil {
+		return nil, fmt.Errorf("file store: %w", err)
+	}
+	return &fileStore{path: path, enc: enc}, nil
+}
+
+// slugs are not kept, so a batch of them alone has nothing to write
+func (s *fileStore) Write(ctx context.Context, batch *store.Batch) error {
+	if batch.Len() == 0 && !batch.Reset {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	snap := &hub.Snapshot{}
+	if !batch.Reset {
+		var err error
+		if snap, err = s.Load(ctx); err != nil {
+			return err
+		}
+	}
+	batch.ApplyTo(snap)
+	snap.TakenAt = time.Now().UTC()
+	return s.save(snap)
+}
+
+func (s *fileStore) save(snap *hub.Snapshot) error {
+	b, err := json.Marshal(snap)
+	if err == nil {
+		b, err = s.enc.Encode(b)
+	}
+	if err != nil {
+		return err
+	}
+
+	dir := filepath.Dir(s.path)
+	tmp, err := os.CreateTemp(dir, ".snapshot-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(b); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), s.path)
+}
+
+func (s *fileStore) Load(context.Context) (*hub.Snapshot, error) {
+	b, err := os.ReadFile(s.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return &hub.Snapshot{DailyQuests: make(map[string]hub.BaseQuest)}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if b, err = codec.Decode(b); err != nil {
+		return nil, fmt.Errorf("file store: %w", err)
+	}
+	var snap hub.Snapshot
+	if err := json.Unmarshal(b, &snap); err != nil {
+		return nil, fmt.Errorf("file store: %w", err)
+	}
+	return &snap, nil
+}
+
+func (s *fileStore) Close() error {
+	return nil
+}
+
+// newMirrorStore double-writes to the stores named by cfg["primary"] and
+// cfg["secondary"], see store.Mirror. Their settings are given with a
+// "primary." or "secondary." prefix, e.g. "secondary.path"; divergences are
+// logged.
+func newMirrorStore(cfg Config) (store.Backend, error) {
+	primary, err := mirrorSide(cfg, "primary")
+	if err != nil {
+		return nil, err
+	}
+	secondary, err := mirrorSide(cfg, "secondary")
+	if err != nil {
+		primary.Close()
+		return nil, err
+	}
+
+	var opts []store.MirrorOption
+	if v := cfg["compareEvery"]; v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			primary.Close()
+			secondary.Close()
+			return nil, fmt.Errorf("mirror store: compareEvery: %w", err)
+		}
+		opts = append(opts, store.WithCompareEvery(n))
+	}
+	opts = append(opts, store.WithMirrorReport(func(r *store.VerifyReport) {
+		if len(r.Divergences) == 0 {
+			slog.Info("Mirrored stores agree again", "checked", r.Checked)
+			return
+		}
+		for _, d := range r.Divergences {
+			slog.Warn("Mirrored stores diverged", "table", d.Table, "id", d.ID, "kind", d.Kind, "fields", d.Fields)
+		}
+	}))
+	return store.NewMirror(primary, secondary, opts...), nil
+}
+
+func mirrorSide(cfg Config, side string) (store.Backend, error) {
+	name := cfg[side]
+	if name == "" {
+		return nil, fmt.Errorf("mirror store: %s is required", side)
+	}
+	if name == "mirror" {
+		return nil, fmt.Errorf("mirror store: %s cannot be a mirror", side)
+	}
+
+	sub := make(Config)
+	for k, v := range cfg {
+		if rest, ok := strings.CutPrefix(k, side+"."); ok {
+			sub[rest] = v
+		}
+	}
+	b, err := NewStore(name, sub)
+	if err != nil {
+		return nil, fmt.Errorf("mirror store: %s: %w", side, err)
+	}
+	return b, nil
+}
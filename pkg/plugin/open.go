@@ -0,0 +1,16 @@
+package plugin
+
+import (
+	"fmt"
+	goplugin "plugin"
+)
+
+// Open loads a Go plugin built with -buildmode=plugin; its init functions
+// register what it provides. Go plugins need cgo and the exact toolchain and
+// module versions the host binary was built with.
+func Open(path string) error {
+	if _, err := goplugin.Open(path); err != nil {
+		return fmt.Errorf("load plugin %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,149 @@
+// Package plugin lets integrations extend the SDK without forking it. A
+// plugin registers named factories for sinks, transforms or store backends
+// from an init function, either in a package that is blank-imported or in a
+// Go plugin loaded with Open:
+//
+//	func init() {
+//		plugin.RegisterSink("kafka", newKafkaSink)
+//	}
+package plugin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/ilyskies/QuestHub/pkg/hub"
+	"github.com/ilyskies/QuestHub/pkg/store"
+)
+
+// Sink receives the changes found between two snapshots
+type Sink interface {
+	Publish(ctx context.Context, changes hub.ChangeSet) error
+}
+
+// Transform rewrites a snapshot in place before it is diffed or stored
+type Transform interface {
+	Apply(ctx context.Context, snap *hub.Snapshot) error
+}
+
+// Config carries the plugin specific settings, e.g. from a config file
+type Config map[string]string
+
+type (
+	SinkFactory      func(Config) (Sink, error)
+	TransformFactory func(Config) (Transform, error)
+	StoreFactory     func(Config) (store.Backend, error)
+)
+
+type Kind string
+
+const (
+	KindSink      Kind = "sink"
+	KindTransform Kind = "transform"
+	KindStore     Kind = "store"
+)
+
+var ErrUnknownPlugin = errors.New("unknown plugin")
+
+type Info struct {
+	Kind Kind   `json:"kind"`
+	Name string `json:"name"`
+}
+
+var registry = struct {
+	sync.RWMutex
+	sinks      map[string]SinkFactory
+	transforms map[string]TransformFactory
+	stores     map[string]StoreFactory
+}{
+	sinks:      make(map[string]SinkFactory),
+	transforms: make(map[string]TransformFactory),
+	stores:     make(map[string]StoreFactory),
+}
+
+// registering the same kind and name twice panics, like database/sql drivers
+func RegisterSink(name string, f SinkFactory) {
+	register(registry.sinks, KindSink, name, f)
+}
+
+func RegisterTransform(name string, f TransformFactory) {
+	register(registry.transforms, KindTransform, name, f)
+}
+
+func RegisterStore(name string, f StoreFactory) {
+	register(registry.stores, KindStore, name, f)
+}
+
+func register[F any](m map[string]F, kind Kind, name string, f F) {
+	registry.Lock()
+	defer registry.Unlock()
+
+	if _, dup := m[name]; dup {
+		panic(fmt.Sprintf("plugin: %s %q registered twice", kind, name))
+	}
+	m[name] = f
+}
+
+func NewSink(name string, cfg Config) (Sink, error) {
+	f, err := lookup(registry.sinks, KindSink, name)
+	if err != nil {
+		return nil, err
+	}
+	return f(cfg)
+}
+
+func NewTransform(name string, cfg Config) (Transform, error) {
+	f, err := lookup(registry.transforms, KindTransform, name)
+	if err != nil {
+		return nil, err
+	}
+	return f(cfg)
+}
+
+func NewStore(name string, cfg Config) (store.Backend, error) {
+	f, err := lookup(registry.stores, KindStore, name)
+	if err != nil {
+		return nil, err
+	}
+	return f(cfg)
+}
+
+func lookup[F any](m map[string]F, kind Kind, name string) (F, error) {
+	registry.RLock()
+	defer registry.RUnlock()
+
+	f, ok := m[name]
+	if !ok {
+		var zero F
+		return zero, fmt.Errorf("%w: %s %q", ErrUnknownPlugin, kind, name)
+	}
+	return f, nil
+}
+
+// List returns every registered plugin sorted by kind and name
+func List() []Info {
+	registry.RLock()
+	defer registry.RUnlock()
+
+	var out []Info
+	for name := range registry.sinks {
+		out = append(out, Info{Kind: KindSink, Name: name})
+	}
+	for name := range registry.transforms {
+		out = append(out, Info{Kind: KindTransform, Name: name})
+	}
+	for name := range registry.stores {
+		out = append(out, Info{Kind: KindStore, Name: name})
+	}
+
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Kind != out[j].Kind {
+			return out[i].Kind < out[j].Kind
+		}
+		return out[i].Name < out[j].Name
+	})
+	return out
+}
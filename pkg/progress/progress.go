@@ -0,0 +1,195 @@
+// Package progress works out how far a player is through daily quests and
+// challenge bundles from their objective stats, and what that pays out.
+package progress
+
+import (
+	"slices"
+
+	"github.com/ilyskies/QuestHub/pkg/hub"
+	"github.com/ilyskies/QuestHub/pkg/hub/rewards"
+)
+
+// Stats holds a player's count for each objective, by backend name
+type Stats map[string]int
+
+type Objective struct {
+	BackendName string `json:"backendName"`
+	Stage       int    `json:"stage,omitempty"`
+	Count       int    `json:"count"`
+	Required    int    `json:"required"`
+	Complete    bool   `json:"complete"`
+}
+
+// Quest is the progress on one daily quest or one quest of a bundle. Percent
+// runs from 0 to 100, averaged over the objectives.
+type Quest struct {
+	ID    string `json:"id"`
+	Stage int    `json:"stage,omitempty"`
+	// an earlier stage of the same chain is not complete yet; a locked quest
+	// makes no progress until it is
+	Locked     bool        `json:"locked,omitempty"`
+	Objectives []Objective `json:"objectives"`
+	Percent    float64     `json:"percent"`
+	Complete   bool        `json:"complete"`
+	// paid out when the quest completes
+	Rewards *rewards.Totals `json:"rewards"`
+}
+
+// Bundle is the progress on a challenge bundle, which completes once
+// Required of its quests do. Percent counts the quests closest to done.
+type Bundle struct {
+	TemplateID string  `json:"templateId"`
+	Quests     []Quest `json:"quests"`
+	Completed  int     `json:"completed"`
+	Required   int     `json:"required"`
+	// staged quests not complete yet, locked ones included
+	RemainingStages int     `json:"remainingStages"`
+	Percent         float64 `json:"percent"`
+	Complete        bool    `json:"complete"`
+	// rewards of the completed quests, and the completion rewards once the
+	// bundle is complete; Remaining holds the rest
+	Earned    *rewards.Totals `json:"earned"`
+	Remaining *rewards.Totals `json:"remaining"`
+}
+
+func (s Stats) objectives(objectives []hub.ChallengeBundleObjective, locked bool) ([]Objective, float64, bool) {
+	out := make([]Objective, 0, len(objectives))
+	var sum float64
+	complete := len(objectives) > 0
+
+	for _, o := range objectives {
+		obj := Objective{
+			BackendName: o.BackendName,
+			Stage:       o.Stage,
+			Required:    o.Count,
+		}
+		if !locked {
+			obj.Count = max(s[o.BackendName], 0)
+		}
+
+		fraction := 1.0
+		if obj.Required > 0 {
+			fraction = min(float64(obj.Count)/float64(obj.Required), 1)
+		}
+		obj.Complete = !locked && fraction >= 1
+		if locked {
+			fraction = 0
+		}
+
+		sum += fraction
+		complete = complete && obj.Complete
+		out = append(out, obj)
+	}
+
+	var percent float64
+	if len(objectives) > 0 {
+		percent = sum / float64(len(objectives)) * 100
+	}
+	return out, percent, complete
+}
+
+// Quest reports progress on a daily quest
+func (s Stats) Quest(id string, q hub.BaseQuest) Quest {
+	objectives := make([]hub.ChallengeBundleObjective, len(q.Objectives))
+	for i, o := range q.Objectives {
+		objectives[i] = hub.ChallengeBundleObjective(o)
+	}
+
+	out := Quest{ID: id, Rewards: rewards.NewTotals()}
+	out.Objectives, out.Percent, out.Complete = s.objectives(objectives, false)
+	for _, r := range q.Rewards {
+		out.Rewards.Add(r.TemplateID, r.Quantity)
+	}
+	return out
+}
+
+// Bundle reports progress on a challenge bundle. Staged quests are chained
+// the way hub.BuildUnlockTree chains them.
+func (s Stats) Bundle(b hub.AthenaChallengeBundle) Bundle {
+	quests := make(map[string]Quest, len(b.Objects))
+
+	var visit func(n *hub.UnlockNode, locked bool)
+	visit = func(n *hub.UnlockNode, locked bool) {
+		q := Quest{ID: n.QuestDefinition, Stage: n.Stage, Locked: locked}
+		q.Objectives, q.Percent, q.Complete = s.objectives(n.Objectives, locked)
+		quests[n.QuestDefinition] = q
+
+		for _, child := range n.Children {
+			visit(child, !q.Complete)
+		}
+	}
+	for _, root := range hub.BuildUnlockTree(b).Roots {
+		visit(root, false)
+	}
+
+	out := Bundle{
+		TemplateID: b.TemplateID,
+		Quests:     make([]Quest, 0, len(b.Objects)),
+		Required:   len(b.Objects),
+		Earned:     rewards.NewTotals(),
+		Remaining:  rewards.NewTotals(),
+	}
+	if b.Amount > 0 && b.Amount < out.Required {
+		out.Required = b.Amount
+	}
+
+	percents := make([]float64, 0, len(b.Objects))
+	for _, obj := range b.Objects {
+		q := quests[obj.QuestDefinition]
+		q.Rewards = rewards.NewTotals()
+		for _, r := range obj.Rewards {
+			q.Rewards.Add(r.TemplateID, r.Quantity)
+		}
+
+		if q.Complete {
+			out.Completed++
+			out.Earned.Merge(q.Rewards)
+		} else {
+			out.Remaining.Merge(q.Rewards)
+			if q.Stage > 0 {
+				out.RemainingStages++
+			}
+		}
+		percents = append(percents, q.Percent)
+		out.Quests = append(out.Quests, q)
+	}
+
+	out.Complete = out.Completed >= out.Required
+	completion := out.Remaining
+	if out.Complete {
+		completion = out.Earned
+	}
+	for _, r := range b.CompletionRewards {
+		completion.Add(r.TemplateID, r.Quantity)
+	}
+
+	if out.Required == 0 {
+		out.Percent = 100
+	} else {
+		slices.Sort(percents)
+		var sum float64
+		for _, p := range percents[len(percents)-out.Required:] {
+			sum += p
+		}
+		out.Percent = sum / float64(out.Required)
+	}
+	return out
+}
+
+// Bundles reports progress on every bundle, in order
+func (s Stats) Bundles(bundles []hub.AthenaChallengeBundle) []Bundle {
+	out := make([]Bundle, len(bundles))
+	for i := range bundles {
+		out[i] = s.Bundle(bundles[i])
+	}
+	return out
+}
+
+// Earned adds up what the bundles have paid out so far
+func Earned(bundles []Bundle) *rewards.Totals {
+	t := rewards.NewTotals()
+	for _, b := range bundles {
+		t.Merge(b.Earned)
+	}
+	return t
+}
@@ -0,0 +1,378 @@
+// Package boltstore persists a store.Store in a bbolt file, one bucket each
+// for quests, bundles and schedules with JSON values, one for when each
+// quest and bundle was first and last seen, and one for public slugs. A
+// reset batch with Archive moves the quests, bundles and schedules under
+// that name in the archive bucket, see LoadArchive:
+//
+//	b, err := boltstore.Open("questhub.db")
+//	s, err := store.Open(ctx, b)
+//
+// WithCompression compresses the values, see package codec. Values are read
+// whatever codec wrote them, so the setting can change between runs.
+//
+// Importing the package also registers it as the "bolt" store plugin, with
+// the file in the "path" setting and the optional "compression" and
+// "blockSize" settings.
+package boltstore
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"go.etcd.io/bbolt"
+
+	"github.com/ilyskies/QuestHub/pkg/hub"
+	"github.com/ilyskies/QuestHub/pkg/plugin"
+	"github.com/ilyskies/QuestHub/pkg/store"
+	"github.com/ilyskies/QuestHub/pkg/store/codec"
+)
+
+var (
+	bucketQuests    = []byte("quests")
+	bucketBundles   = []byte("bundles")
+	bucketSchedules = []byte("schedules")
+	bucketLifetimes = []byte("lifetimes")
+	bucketSlugs     = []byte("slugs")
+	bucketMeta      = []byte("meta")
+	bucketArchive   = []byte("archive")
+
+	keyUpdatedAt = []byte("updatedAt")
+)
+
+type Backend struct {
+	db *bbolt.DB
+
+	codec     codec.Codec
+	blockSize int
+	enc       *codec.Encoder
+}
+
+type Option func(*Backend)
+
+// WithCompression compresses quests, bundles, schedules and lifetimes with
+// c in blocks of blockSize bytes; 0 means codec.DefaultBlockSize
+func WithCompression(c codec.Codec, blockSize int) Option {
+	return func(b *Backend) {
+		b.codec = c
+		b.blockSize = blockSize
+	}
+}
+
+var (
+	_ store.LifetimeBackend = (*Backend)(nil)
+	_ store.SlugBackend     = (*Backend)(nil)
+)
+
+func init() {
+	plugin.RegisterStore("bolt", func(cfg plugin.Config) (store.Backend, error) {
+		path := cfg["path"]
+		if path == "" {
+			return nil, fmt.Errorf("boltstore: path is required")
+		}
+		c, err := codec.ParseCodec(cfg["compression"])
+		if err != nil {
+			return nil, fmt.Errorf("boltstore: %w", err)
+		}
+		var blockSize int
+		if v := cfg["blockSize"]; v != "" {
+			if blockSize, err = strconv.Atoi(v); err != nil {
+				return nil, fmt.Errorf("boltstore: blockSize: %w", err)
+			}
+		}
+		b, err := Open(path, WithCompression(c, blockSize))
+		if err != nil {
+			return nil, err
+		}
+		return b, nil
+	})
+}
+
+// Open creates the file if needed. Another process holding it makes Open
+// wait up to a second before failing.
+func Open(path string, opts ...Option) (*Backend, error) {
+	b := &Backend{codec: codec.None}
+	for _, opt := range opts {
+		opt(b)
+	}
+	enc, err := codec.New(b.codec, b.blockSize)
+	if err != nil {
+		return nil, fmt.Errorf("boltstore: %w", err)
+	}
+	b.enc = enc
+
+	db, err := bbolt.Open(path, 0o600, &bbolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("boltstore: open %s: %w", path, err)
+	}
+
+	err = db.Update(func(tx *bbolt.Tx) error {
+		for _, name := range [][]byte{bucketQuests, bucketBundles, bucketSchedules, bucketLifetimes, bucketSlugs, bucketMeta, bucketArchive} {
+			if _, err := tx.CreateBucketIfNotExists(name); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("boltstore: init %s: %w", path, err)
+	}
+	b.db = db
+	return b, nil
+}
+
+func (b *Backend) Close() error {
+	return b.db.Close()
+}
+
+func (b *Backend) Load(ctx context.Context) (*hub.Snapshot, error) {
+	var snap *hub.Snapshot
+	err := b.db.View(func(tx *bbolt.Tx) (err error) {
+		snap, err = load(tx)
+		if err != nil {
+			return err
+		}
+		if v := tx.Bucket(bucketMeta).Get(keyUpdatedAt); v != nil {
+			if err := snap.TakenAt.UnmarshalText(v); err != nil {
+				return fmt.Errorf("updated at: %w", err)
+			}
+			snap.Provenance.FetchedAt = snap.TakenAt
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("boltstore: load: %w", err)
+	}
+	return snap, nil
+}
+
+// Archives lists the names reset batches archived under, in order
+func (b *Backend) Archives(ctx context.Context) ([]string, error) {
+	var names []string
+	err := b.db.View(func(tx *bbolt.Tx) error {
+		return tx.Bucket(bucketArchive).ForEachBucket(func(k []byte) error {
+			names = append(names, string(k))
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("boltstore: archives: %w", err)
+	}
+	return names, nil
+}
+
+// LoadArchive reads the model as it was when it was archived under name
+func (b *Backend) LoadArchive(ctx context.Context, name string) (*hub.Snapshot, error) {
+	var snap *hub.Snapshot
+	err := b.db.View(func(tx *bbolt.Tx) (err error) {
+		archived := tx.Bucket(bucketArchive).Bucket([]byte(name))
+		if archived == nil {
+			return fmt.Errorf("no archive %q", name)
+		}
+		snap, err = load(archived)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("boltstore: load archive: %w", err)
+	}
+	return snap, nil
+}
+
+// a transaction or an archive bucket
+type buckets interface {
+	Bucket(name []byte) *bbolt.Bucket
+}
+
+func load(from buckets) (*hub.Snapshot, error) {
+	snap := &hub.Snapshot{
+		DailyQuests: make(map[string]hub.BaseQuest),
+		Provenance:  hub.Provenance{Source: "boltstore"},
+	}
+
+	err := from.Bucket(bucketQuests).ForEach(func(k, v []byte) error {
+		var q hub.BaseQuest
+		if err := decode(v, &q); err != nil {
+			return fmt.Errorf("quest %s: %w", k, err)
+		}
+		snap.DailyQuests[string(k)] = q
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// bolt iterates in key order, so both lists come out sorted
+	err = from.Bucket(bucketBundles).ForEach(func(k, v []byte) error {
+		var bundle hub.AthenaChallengeBundle
+		if err := decode(v, &bundle); err != nil {
+			return fmt.Errorf("bundle %s: %w", k, err)
+		}
+		snap.Bundles = append(snap.Bundles, bundle)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = from.Bucket(bucketSchedules).ForEach(func(k, v []byte) error {
+		var sched hub.ChallengeBundleSchedule
+		if err := decode(v, &sched); err != nil {
+			return fmt.Errorf("schedule %s: %w", k, err)
+		}
+		snap.Schedules = append(snap.Schedules, sched)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return snap, nil
+}
+
+// LoadLifetimes reads what Write kept of Batch.Lifetimes
+func (b *Backend) LoadLifetimes(ctx context.Context) (map[string]store.Lifetime, error) {
+	out := make(map[string]store.Lifetime)
+	err := b.db.View(func(tx *bbolt.Tx) error {
+		return tx.Bucket(bucketLifetimes).ForEach(func(k, v []byte) error {
+			var lt store.Lifetime
+			if err := decode(v, &lt); err != nil {
+				return fmt.Errorf("lifetime %s: %w", k, err)
+			}
+			out[string(k)] = lt
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("boltstore: load lifetimes: %w", err)
+	}
+	return out, nil
+}
+
+// LoadSlugs reads what Write kept of Batch.Slugs
+func (b *Backend) LoadSlugs(ctx context.Context) (map[string]string, error) {
+	out := make(map[string]string)
+	err := b.db.View(func(tx *bbolt.Tx) error {
+		return tx.Bucket(bucketSlugs).ForEach(func(k, v []byte) error {
+			out[string(k)] = string(v)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("boltstore: load slugs: %w", err)
+	}
+	return out, nil
+}
+
+// Write applies the batch in one transaction
+func (b *Backend) Write(ctx context.Context, batch *store.Batch) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	err := b.db.Update(func(tx *bbolt.Tx) error {
+		if batch.Reset {
+			if err := reset(tx, batch.Archive); err != nil {
+				return err
+			}
+		}
+
+		if err := putAll(tx.Bucket(bucketQuests), b.enc, batch.Quests); err != nil {
+			return err
+		}
+		if err := putAll(tx.Bucket(bucketBundles), b.enc, batch.Bundles); err != nil {
+			return err
+		}
+		if err := putAll(tx.Bucket(bucketSchedules), b.enc, batch.Schedules); err != nil {
+			return err
+		}
+		lifetimes := make(map[string]*store.Lifetime, len(batch.Lifetimes))
+		for id, lt := range batch.Lifetimes {
+			lifetimes[id] = &lt
+		}
+		if err := putAll(tx.Bucket(bucketLifetimes), b.enc, lifetimes); err != nil {
+			return err
+		}
+		for id, slug := range batch.Slugs {
+			if err := tx.Bucket(bucketSlugs).Put([]byte(id), []byte(slug)); err != nil {
+				return err
+			}
+		}
+
+		// a batch of slugs alone leaves the model as it was
+		if batch.Len() == 0 && !batch.Reset {
+			return nil
+		}
+		now, _ := time.Now().UTC().MarshalText()
+		return tx.Bucket(bucketMeta).Put(keyUpdatedAt, now)
+	})
+	if err != nil {
+		return fmt.Errorf("boltstore: write: %w", err)
+	}
+	return nil
+}
+
+// reset empties the model's buckets, moving them under archive first when
+// it is set; archiving under a name again replaces what was there
+func reset(tx *bbolt.Tx, archive string) error {
+	var dst *bbolt.Bucket
+	if archive != "" {
+		root := tx.Bucket(bucketArchive)
+		if root.Bucket([]byte(archive)) != nil {
+			if err := root.DeleteBucket([]byte(archive)); err != nil {
+				return err
+			}
+		}
+		var err error
+		if dst, err = root.CreateBucket([]byte(archive)); err != nil {
+			return fmt.Errorf("archive %s: %w", archive, err)
+		}
+	}
+
+	for _, name := range [][]byte{bucketQuests, bucketBundles, bucketSchedules} {
+		if dst != nil {
+			if err := tx.MoveBucket(name, nil, dst); err != nil {
+				return fmt.Errorf("archive %s: %w", archive, err)
+			}
+		} else if err := tx.DeleteBucket(name); err != nil {
+			return err
+		}
+		if _, err := tx.CreateBucket(name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// nil values delete their key
+func putAll[T any](bucket *bbolt.Bucket, enc *codec.Encoder, items map[string]*T) error {
+	for id, v := range items {
+		if v == nil {
+			if err := bucket.Delete([]byte(id)); err != nil {
+				return err
+			}
+			continue
+		}
+
+		data, err := json.Marshal(v)
+		if err == nil {
+			data, err = enc.Encode(data)
+		}
+		if err != nil {
+			return fmt.Errorf("%s: %w", id, err)
+		}
+		if err := bucket.Put([]byte(id), data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func decode(data []byte, v interface{}) error {
+	data, err := codec.Decode(data)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
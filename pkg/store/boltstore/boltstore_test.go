@@ -0,0 +1,70 @@
+package boltstore
+
+import (
+	"context"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/ilyskies/QuestHub/pkg/hub"
+	"github.com/ilyskies/QuestHub/pkg/store"
+)
+
+// a rollover keeps the old season under its version, across reopening
+func TestRolloverArchives(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "qh.db")
+
+	b, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := store.Open(ctx, b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	season := func(id string) *hub.Snapshot {
+		return &hub.Snapshot{
+			DailyQuests: map[string]hub.BaseQuest{"Quest_" + id: {Count: 1}},
+			Bundles:     []hub.AthenaChallengeBundle{{TemplateID: "ChallengeBundle:" + id}},
+		}
+	}
+	if err := s.Reset(ctx, season("9")); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Rollover(ctx, &hub.Rollover{FromVersion: "9.0", ToVersion: "10.0", Snapshot: season("10")}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	b, err = Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer b.Close()
+
+	live, err := b.Load(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := live.DailyQuests["Quest_10"]; !ok || len(live.DailyQuests) != 1 || len(live.Bundles) != 1 {
+		t.Errorf("live = %+v", live)
+	}
+
+	names, err := b.Archives(ctx)
+	if err != nil || !slices.Equal(names, []string{"9.0"}) {
+		t.Fatalf("archives = %v, %v", names, err)
+	}
+	archived, err := b.LoadArchive(ctx, "9.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := archived.DailyQuests["Quest_9"]; !ok || len(archived.Bundles) != 1 || archived.Bundles[0].TemplateID != "ChallengeBundle:9" {
+		t.Errorf("archived = %+v", archived)
+	}
+	if _, err := b.LoadArchive(ctx, "8.0"); err == nil {
+		t.Error("loaded an archive never written")
+	}
+}
@@ -0,0 +1,95 @@
+package store
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// FetchJournal downloads a journal archive, a FileJournal's file published
+// at url, e.g. copied to a bucket by a cron job, gzipped if url ends in .gz.
+// A nil client is http.DefaultClient.
+func FetchJournal(ctx context.Context, client *http.Client, url string) (*MemoryJournal, error) {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("store: archive: %w", err)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("store: archive: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("store: archive: GET %s -> %s", url, resp.Status)
+	}
+
+	var r io.Reader = resp.Body
+	if strings.HasSuffix(req.URL.Path, ".gz") {
+		gz, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			return nil, fmt.Errorf("store: archive %s: %w", url, err)
+		}
+		defer gz.Close()
+		r = gz
+	}
+
+	j := NewMemoryJournal()
+	dec := json.NewDecoder(r)
+	for {
+		var e JournalEntry
+		err := dec.Decode(&e)
+		if errors.Is(err, io.EOF) {
+			return j, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("store: archive %s: offset %d: %w", url, j.Next(), err)
+		}
+		if e.Offset != j.Next() {
+			return nil, fmt.Errorf("store: archive %s: offset %d where %d was due", url, e.Offset, j.Next())
+		}
+		if err := j.Append(ctx, &e); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// Bootstrap fills an empty store from j, e.g. a FetchJournal archive, so a
+// new deployment starts with the history j records instead of nothing.
+// Every entry is applied as ApplyJournal would, lifetimes, backend and the
+// store's own journal included. It returns false without reading j when
+// the store or its journal already holds data.
+func (s *Store) Bootstrap(ctx context.Context, j Journal) (bool, error) {
+	const readSize = 256
+
+	s.mu.RLock()
+	empty := len(s.quests)+len(s.bundles)+len(s.schedules) == 0 && (s.journal == nil || s.journal.Next() == 0)
+	s.mu.RUnlock()
+	if !empty {
+		return false, nil
+	}
+
+	for from := uint64(0); ; {
+		entries, err := j.Read(ctx, from, readSize)
+		if err != nil {
+			return true, err
+		}
+		if len(entries) == 0 {
+			return true, nil
+		}
+		for _, e := range entries {
+			if err := s.ApplyJournal(ctx, e); err != nil {
+				return true, err
+			}
+			from = e.Offset + 1
+		}
+	}
+}
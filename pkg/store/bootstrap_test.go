@@ -0,0 +1,90 @@
+package store
+
+import (
+	"compress/gzip"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ilyskies/QuestHub/pkg/hub"
+)
+
+func TestBootstrap(t *testing.T) {
+	ctx := context.Background()
+	start := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	// the archive is what a deployment's FileJournal wrote
+	path := filepath.Join(t.TempDir(), "qh.journal")
+	fj, err := OpenFileJournal(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	src := New(WithJournal(fj))
+	if err := src.Reset(ctx, &hub.Snapshot{TakenAt: start, DailyQuests: map[string]hub.BaseQuest{"Quest_A": {Count: 1}}}); err != nil {
+		t.Fatal(err)
+	}
+	err = src.ApplyChanges(ctx, hub.ChangeSet{
+		Quests: []hub.QuestDelta{{ID: "Quest_B", Kind: hub.ChangeAdded, New: &hub.BaseQuest{Count: 2}}},
+		To:     &hub.Provenance{FetchedAt: start.Add(time.Hour)},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	src.Close()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/qh.journal", func(w http.ResponseWriter, r *http.Request) {
+		http.ServeFile(w, r, path)
+	})
+	mux.HandleFunc("/qh.journal.gz", func(w http.ResponseWriter, r *http.Request) {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Error(err)
+		}
+		gz := gzip.NewWriter(w)
+		_, _ = gz.Write(data)
+		gz.Close()
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	for _, name := range []string{"qh.journal", "qh.journal.gz"} {
+		archive, err := FetchJournal(ctx, srv.Client(), srv.URL+"/"+name)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if archive.Next() != 2 {
+			t.Fatalf("%s: %d entries, want 2", name, archive.Next())
+		}
+
+		j := NewMemoryJournal()
+		s := New(WithJournal(j))
+		filled, err := s.Bootstrap(ctx, archive)
+		if err != nil || !filled {
+			t.Fatalf("%s: filled = %v, err = %v", name, filled, err)
+		}
+		if _, ok := s.Quest("Quest_B"); !ok || len(s.Quests()) != 2 {
+			t.Errorf("%s: model = %+v", name, s.Snapshot())
+		}
+		// the history comes along
+		if l, ok := s.Lifetime("Quest_A"); !ok || !l.FirstSeen.Equal(start) {
+			t.Errorf("%s: lifetime = %+v, want first seen %v", name, l, start)
+		}
+		if j.Next() != 2 {
+			t.Errorf("%s: journal has %d entries, want the archive's 2", name, j.Next())
+		}
+
+		// a store with data is left alone
+		if filled, err := s.Bootstrap(ctx, archive); err != nil || filled {
+			t.Errorf("%s: second bootstrap filled = %v, err = %v", name, filled, err)
+		}
+	}
+
+	if _, err := FetchJournal(ctx, srv.Client(), srv.URL+"/missing"); err == nil {
+		t.Error("missing archive fetched")
+	}
+}
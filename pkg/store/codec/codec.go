@@ -0,0 +1,220 @@
+// Package codec compresses the values store backends keep. Which codec
+// trades CPU for space best depends on the machine: snappy for a Raspberry
+// Pi collector, zstd where cores are cheap and disks are not.
+//
+//	enc, err := codec.New(codec.Zstd, codec.DefaultBlockSize)
+//	data, err := enc.Encode(value)
+//	value, err := codec.Decode(data)
+//
+// Encoded values name their codec, so Decode reads anything Encode wrote
+// whatever the current setting, and values written before compression was
+// turned on, which are returned as they are. Input is compressed in blocks
+// of at most the block size, each on its own, which bounds the memory a
+// decoder needs.
+package codec
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/klauspost/compress/snappy"
+	"github.com/klauspost/compress/zstd"
+)
+
+type Codec string
+
+const (
+	None   Codec = "none"
+	Gzip   Codec = "gzip"
+	Zstd   Codec = "zstd"
+	Snappy Codec = "snappy"
+)
+
+const DefaultBlockSize = 64 << 10
+
+// values are never this large; guards allocations against corrupt lengths
+const maxBlockSize = 64 << 20
+
+var (
+	ErrUnknownCodec = errors.New("unknown compression codec")
+	ErrCorrupt      = errors.New("corrupt compressed value")
+)
+
+// ids written after the marker byte; never reuse one
+var ids = map[Codec]byte{Gzip: 1, Zstd: 2, Snappy: 3}
+
+// marker starts every compressed value. JSON, which uncompressed values
+// are, never starts with it.
+const marker = 0
+
+func Codecs() []Codec {
+	return []Codec{None, Gzip, Zstd, Snappy}
+}
+
+// ParseCodec accepts a codec name; empty means None
+func ParseCodec(s string) (Codec, error) {
+	c := Codec(strings.ToLower(strings.TrimSpace(s)))
+	if c == "" {
+		return None, nil
+	}
+	if _, ok := ids[c]; !ok && c != None {
+		return "", fmt.Errorf("%w: %q", ErrUnknownCodec, s)
+	}
+	return c, nil
+}
+
+type Encoder struct {
+	codec     Codec
+	blockSize int
+}
+
+// New returns an encoder for c. blockSize 0 means DefaultBlockSize.
+func New(c Codec, blockSize int) (*Encoder, error) {
+	if _, ok := ids[c]; !ok && c != None {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownCodec, c)
+	}
+	if blockSize == 0 {
+		blockSize = DefaultBlockSize
+	}
+	if blockSize < 0 || blockSize > maxBlockSize {
+		return nil, fmt.Errorf("codec: block size %d out of range", blockSize)
+	}
+	return &Encoder{codec: c, blockSize: blockSize}, nil
+}
+
+func (e *Encoder) Codec() Codec {
+	if e == nil {
+		return None
+	}
+	return e.codec
+}
+
+// Encode compresses src. A nil Encoder, like None, returns src unchanged.
+func (e *Encoder) Encode(src []byte) ([]byte, error) {
+	if e == nil || e.codec == None || len(src) == 0 {
+		return src, nil
+	}
+
+	out := []byte{marker, ids[e.codec]}
+	for start := 0; start < len(src); start += e.blockSize {
+		block := src[start:min(start+e.blockSize, len(src))]
+		compressed, err := compress(e.codec, block)
+		if err != nil {
+			return nil, fmt.Errorf("codec: %s: %w", e.codec, err)
+		}
+		out = binary.AppendUvarint(out, uint64(len(block)))
+		out = binary.AppendUvarint(out, uint64(len(compressed)))
+		out = append(out, compressed...)
+	}
+	return out, nil
+}
+
+// Decode undoes Encode with any codec
+func Decode(data []byte) ([]byte, error) {
+	if len(data) == 0 || data[0] != marker {
+		return data, nil
+	}
+	if len(data) < 2 {
+		return nil, ErrCorrupt
+	}
+
+	var c Codec
+	for name, id := range ids {
+		if id == data[1] {
+			c = name
+		}
+	}
+	if c == "" {
+		return nil, fmt.Errorf("%w: id %d", ErrUnknownCodec, data[1])
+	}
+
+	var out []byte
+	for rest := data[2:]; len(rest) > 0; {
+		size, n := binary.Uvarint(rest)
+		if n <= 0 || size > maxBlockSize {
+			return nil, ErrCorrupt
+		}
+		rest = rest[n:]
+		clen, n := binary.Uvarint(rest)
+		if n <= 0 || clen > uint64(len(rest)-n) {
+			return nil, ErrCorrupt
+		}
+		rest = rest[n:]
+
+		block, err := decompress(c, rest[:clen], int(size))
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s: %v", ErrCorrupt, c, err)
+		}
+		if len(block) != int(size) {
+			return nil, fmt.Errorf("%w: block is %d bytes, want %d", ErrCorrupt, len(block), size)
+		}
+		out = append(out, block...)
+		rest = rest[clen:]
+	}
+	return out, nil
+}
+
+// zstd encoders and decoders are expensive to set up and safe to share for
+// EncodeAll and DecodeAll
+var (
+	zstdEncoder = sync.OnceValues(func() (*zstd.Encoder, error) {
+		return zstd.NewWriter(nil)
+	})
+	zstdDecoder = sync.OnceValues(func() (*zstd.Decoder, error) {
+		return zstd.NewReader(nil, zstd.WithDecoderConcurrency(0))
+	})
+)
+
+func compress(c Codec, block []byte) ([]byte, error) {
+	switch c {
+	case Gzip:
+		var buf bytes.Buffer
+		w := gzip.NewWriter(&buf)
+		if _, err := w.Write(block); err != nil {
+			return nil, err
+		}
+		if err := w.Close(); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	case Zstd:
+		enc, err := zstdEncoder()
+		if err != nil {
+			return nil, err
+		}
+		return enc.EncodeAll(block, nil), nil
+	case Snappy:
+		return snappy.Encode(nil, block), nil
+	}
+	return nil, fmt.Errorf("%w: %q", ErrUnknownCodec, c)
+}
+
+func decompress(c Codec, block []byte, size int) ([]byte, error) {
+	switch c {
+	case Gzip:
+		r, err := gzip.NewReader(bytes.NewReader(block))
+		if err != nil {
+			return nil, err
+		}
+		// one byte past size tells an oversized block apart
+		return io.ReadAll(io.LimitReader(r, int64(size)+1))
+	case Zstd:
+		dec, err := zstdDecoder()
+		if err != nil {
+			return nil, err
+		}
+		return dec.DecodeAll(block, make([]byte, 0, size))
+	case Snappy:
+		if n, err := snappy.DecodedLen(block); err != nil || n != size {
+			return nil, fmt.Errorf("block is %d bytes, want %d", n, size)
+		}
+		return snappy.Decode(nil, block)
+	}
+	return nil, fmt.Errorf("%w: %q", ErrUnknownCodec, c)
+}
@@ -0,0 +1,122 @@
+package codec
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/ilyskies/QuestHub/pkg/hub"
+)
+
+// a store value about the size of a real bundle with many objects
+func bundleJSON(tb testing.TB, objects int) []byte {
+	tb.Helper()
+	b := hub.AthenaChallengeBundle{
+		TemplateID:              "ChallengeBundle:QuestBundle_Week_001",
+		ChallengeBundleSchedule: "ChallengeBundleSchedule:Schedule_Week_001",
+		Rarity:                  "Common",
+	}
+	for i := range objects {
+		b.Objects = append(b.Objects, hub.ChallengeBundleObject{
+			QuestDefinition: fmt.Sprintf("Quest_Week_001_Damage_%02d", i),
+			Rarity:          "Common",
+			Rewards:         []hub.ChallengeBundleReward{{TemplateID: "AccountResource:athenabattlestar", Quantity: 5}},
+		})
+	}
+	data, err := json.Marshal(b)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	return data
+}
+
+func TestRoundTrip(t *testing.T) {
+	value := bundleJSON(t, 200)
+
+	for _, c := range Codecs() {
+		for _, blockSize := range []int{0, 1000, len(value)} {
+			enc, err := New(c, blockSize)
+			if err != nil {
+				t.Fatal(err)
+			}
+			data, err := enc.Encode(value)
+			if err != nil {
+				t.Fatalf("%s/%d: %v", c, blockSize, err)
+			}
+			if c != None && len(data) >= len(value) {
+				t.Errorf("%s/%d: %d bytes compressed to %d", c, blockSize, len(value), len(data))
+			}
+
+			got, err := Decode(data)
+			if err != nil {
+				t.Fatalf("%s/%d: %v", c, blockSize, err)
+			}
+			if !bytes.Equal(got, value) {
+				t.Errorf("%s/%d: round trip changed the value", c, blockSize)
+			}
+		}
+	}
+}
+
+func TestDecodeUncompressed(t *testing.T) {
+	value := []byte(`{"count":3}`)
+	got, err := Decode(value)
+	if err != nil || !bytes.Equal(got, value) {
+		t.Errorf("Decode = %q, %v", got, err)
+	}
+}
+
+func TestDecodeCorrupt(t *testing.T) {
+	enc, _ := New(Zstd, 0)
+	data, _ := enc.Encode(bundleJSON(t, 10))
+
+	if _, err := Decode(data[:len(data)-5]); !errors.Is(err, ErrCorrupt) {
+		t.Errorf("truncated: err = %v, want ErrCorrupt", err)
+	}
+	if _, err := Decode([]byte{marker, 99, 1, 1, 0}); !errors.Is(err, ErrUnknownCodec) {
+		t.Errorf("unknown id: err = %v, want ErrUnknownCodec", err)
+	}
+	if _, err := ParseCodec("lz4"); !errors.Is(err, ErrUnknownCodec) {
+		t.Errorf("ParseCodec err = %v, want ErrUnknownCodec", err)
+	}
+}
+
+// go test -bench . -benchmem ./pkg/store/codec reports the compressed size
+// as bytes/value alongside the time per operation
+func BenchmarkCodecs(b *testing.B) {
+	value := bundleJSON(b, 200)
+
+	for _, c := range Codecs() {
+		for _, blockSize := range []int{4 << 10, DefaultBlockSize} {
+			enc, err := New(c, blockSize)
+			if err != nil {
+				b.Fatal(err)
+			}
+			data, err := enc.Encode(value)
+			if err != nil {
+				b.Fatal(err)
+			}
+
+			name := fmt.Sprintf("%s/block=%dk", c, blockSize>>10)
+			b.Run(name+"/encode", func(b *testing.B) {
+				b.SetBytes(int64(len(value)))
+				for b.Loop() {
+					if _, err := enc.Encode(value); err != nil {
+						b.Fatal(err)
+					}
+				}
+				b.ReportMetric(float64(len(data)), "bytes/value")
+			})
+			b.Run(name+"/decode", func(b *testing.B) {
+				b.SetBytes(int64(len(value)))
+				for b.Loop() {
+					if _, err := Decode(data); err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+		}
+	}
+}
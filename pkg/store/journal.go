@@ -0,0 +1,317 @@
+package store
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/ilyskies/QuestHub/pkg/hub"
+)
+
+var ErrNoJournal = errors.New("store: no journal")
+
+// JournalEntry is one write to the model, as Batch is to a Backend. A nil
+// value deletes its key; a Reset entry replaces everything, so replaying a
+// journal from any Reset rebuilds the model.
+type JournalEntry struct {
+	Offset    uint64                                  `json:"offset"`
+	At        time.Time                               `json:"at,omitzero"`
+	Reset     bool                                    `json:"reset,omitempty"`
+	Archive   string                                  `json:"archive,omitempty"`
+	Season    string                                  `json:"season,omitempty"`
+	Quests    map[string]*hub.BaseQuest               `json:"quests,omitempty"`
+	Bundles   map[string]*hub.AthenaChallengeBundle   `json:"bundles,omitempty"`
+	Schedules map[string]*hub.ChallengeBundleSchedule `json:"schedules,omitempty"`
+}
+
+// Batch is the entry as a write to a Backend, for replicas keeping one
+func (e *JournalEntry) Batch() *Batch {
+	b := newBatch()
+	b.Reset, b.Archive, b.Season = e.Reset, e.Archive, e.Season
+	for id, q := range e.Quests {
+		b.Quests[id] = q
+	}
+	for id, bundle := range e.Bundles {
+		b.Bundles[id] = bundle
+	}
+	for id, sched := range e.Schedules {
+		b.Schedules[id] = sched
+	}
+	return b
+}
+
+// clone copies e deep enough that changing either copy leaves the other
+func (e JournalEntry) clone() JournalEntry {
+	out := e
+	out.Quests = cloneMap(e.Quests, hub.BaseQuest.Clone)
+	out.Bundles = cloneMap(e.Bundles, hub.AthenaChallengeBundle.Clone)
+	out.Schedules = cloneMap(e.Schedules, func(s hub.ChallengeBundleSchedule) hub.ChallengeBundleSchedule { return s })
+	return out
+}
+
+func cloneMap[T any](m map[string]*T, clone func(T) T) map[string]*T {
+	if m == nil {
+		return nil
+	}
+	out := make(map[string]*T, len(m))
+	for id, v := range m {
+		if v == nil {
+			out[id] = nil
+			continue
+		}
+		c := clone(*v)
+		out[id] = &c
+	}
+	return out
+}
+
+// Journal keeps the store's writes in order, addressed by offset, for
+// replicas that catch up from the last offset they applied. Offsets count
+// from 0 without gaps.
+type Journal interface {
+	// Append sets e.Offset to Next and stores e
+	Append(ctx context.Context, e *JournalEntry) error
+	// Read returns up to max entries from offset from on, fewer or none
+	// at the end
+	Read(ctx context.Context, from uint64, max int) ([]JournalEntry, error)
+	Next() uint64
+	Close() error
+}
+
+type Option func(*Store)
+
+// WithJournal writes every change to j before the backend and the model
+// see it. A failed backend write leaves its entry in the journal; the
+// change came from the hub, so replicas applying it are ahead of the store,
+// not wrong. An empty journal on a store with data starts with a Reset.
+func WithJournal(j Journal) Option {
+	return func(s *Store) {
+		s.journal = j
+	}
+}
+
+// journalLocked appends what b changes in the model; callers hold s.mu
+func (s *Store) journalLocked(ctx context.Context, b *Batch, at time.Time) error {
+	if s.journal == nil || (b.Len() == 0 && !b.Reset) {
+		return nil
+	}
+
+	e := &JournalEntry{At: at, Reset: b.Reset, Archive: b.Archive, Season: b.Season, Quests: b.Quests, Bundles: b.Bundles, Schedules: b.Schedules}
+	if err := s.journal.Append(ctx, e); err != nil {
+		return fmt.Errorf("store: journal: %w", err)
+	}
+	close(s.journaled)
+	s.journaled = make(chan struct{})
+	return nil
+}
+
+// startJournal gives an empty journal the model it starts from
+func (s *Store) startJournal(ctx context.Context, snap *hub.Snapshot) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.journal.Next() > 0 {
+		return nil
+	}
+	b := ResetBatch(snap)
+	if b.Len() == 0 {
+		return nil
+	}
+	return s.journalLocked(ctx, b, snap.TakenAt)
+}
+
+// Tail calls fn with every journal entry from offset from on, then with
+// each new one as it is written, until ctx is done, fn fails or the store
+// closes
+func (s *Store) Tail(ctx context.Context, from uint64, fn func(JournalEntry) error) error {
+	const readSize = 256
+
+	for {
+		s.mu.RLock()
+		j, written, closed := s.journal, s.journaled, s.closed
+		s.mu.RUnlock()
+
+		if j == nil {
+			return ErrNoJournal
+		}
+		if closed {
+			return ErrClosed
+		}
+
+		entries, err := j.Read(ctx, from, readSize)
+		if err != nil {
+			return err
+		}
+		for _, e := range entries {
+			if err := fn(e); err != nil {
+				return err
+			}
+			from = e.Offset + 1
+		}
+		if len(entries) > 0 {
+			continue
+		}
+
+		select {
+		case <-written:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// ApplyJournal applies an entry from another store's journal, making this
+// store a replica of it
+func (s *Store) ApplyJournal(ctx context.Context, e JournalEntry) error {
+	return s.write(ctx, e.Batch(), e.At)
+}
+
+// MemoryJournal keeps entries in memory, e.g. for replicas in the same
+// process. Entries go in and come out as copies.
+type MemoryJournal struct {
+	mu      sync.RWMutex
+	entries []JournalEntry
+}
+
+func NewMemoryJournal() *MemoryJournal {
+	return &MemoryJournal{}
+}
+
+func (j *MemoryJournal) Append(_ context.Context, e *JournalEntry) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	e.Offset = uint64(len(j.entries))
+	j.entries = append(j.entries, e.clone())
+	return nil
+}
+
+func (j *MemoryJournal) Read(_ context.Context, from uint64, max int) ([]JournalEntry, error) {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+
+	if from >= uint64(len(j.entries)) {
+		return nil, nil
+	}
+	end := min(from+uint64(max), uint64(len(j.entries)))
+	out := make([]JournalEntry, 0, end-from)
+	for _, e := range j.entries[from:end] {
+		out = append(out, e.clone())
+	}
+	return out, nil
+}
+
+func (j *MemoryJournal) Next() uint64 {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+	return uint64(len(j.entries))
+}
+
+func (j *MemoryJournal) Close() error {
+	return nil
+}
+
+// FileJournal appends entries to a file as JSON lines and syncs after
+// each, so an entry Append returned for survives a crash
+type FileJournal struct {
+	mu   sync.Mutex
+	f    *os.File
+	size int64
+	// offset -> where its line starts
+	starts []int64
+}
+
+// OpenFileJournal continues the journal at path, creating it if needed. A
+// torn last line, from a crash mid-append, is cut off.
+func OpenFileJournal(path string) (*FileJournal, error) {
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("store: journal: %w", err)
+	}
+
+	j := &FileJournal{f: f}
+	r := bufio.NewReader(f)
+	for {
+		line, err := r.ReadBytes('\n')
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			f.Close()
+			return nil, fmt.Errorf("store: journal: %w", err)
+		}
+		j.starts = append(j.starts, j.size)
+		j.size += int64(len(line))
+	}
+	if err := f.Truncate(j.size); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("store: journal: %w", err)
+	}
+	return j, nil
+}
+
+func (j *FileJournal) Append(_ context.Context, e *JournalEntry) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	e.Offset = uint64(len(j.starts))
+	line, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+
+	if _, err := j.f.WriteAt(line, j.size); err != nil {
+		return err
+	}
+	if err := j.f.Sync(); err != nil {
+		return err
+	}
+	j.starts = append(j.starts, j.size)
+	j.size += int64(len(line))
+	return nil
+}
+
+func (j *FileJournal) Read(_ context.Context, from uint64, max int) ([]JournalEntry, error) {
+	j.mu.Lock()
+	if from >= uint64(len(j.starts)) {
+		j.mu.Unlock()
+		return nil, nil
+	}
+	end := min(from+uint64(max), uint64(len(j.starts)))
+	start, stop := j.starts[from], j.size
+	if end < uint64(len(j.starts)) {
+		stop = j.starts[end]
+	}
+	j.mu.Unlock()
+
+	// appends only add past stop, so the section is safe to read unlocked
+	dec := json.NewDecoder(io.NewSectionReader(j.f, start, stop-start))
+	out := make([]JournalEntry, 0, end-from)
+	for range end - from {
+		var e JournalEntry
+		if err := dec.Decode(&e); err != nil {
+			return nil, fmt.Errorf("store: journal: offset %d: %w", from+uint64(len(out)), err)
+		}
+		out = append(out, e)
+	}
+	return out, nil
+}
+
+func (j *FileJournal) Next() uint64 {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return uint64(len(j.starts))
+}
+
+func (j *FileJournal) Close() error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.f.Close()
+}
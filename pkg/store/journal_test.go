@@ -0,0 +1,100 @@
+package store
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ilyskies/QuestHub/pkg/hub"
+)
+
+// a replica tailing the journal ends up with the primary's model, and the
+// journal carries on where it was after reopening
+func TestJournalReplication(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	path := filepath.Join(t.TempDir(), "journal")
+	j, err := OpenFileJournal(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	primary := New(WithJournal(j))
+
+	replica := New()
+	applied := make(chan uint64, 16)
+	tailed := make(chan error, 1)
+	go func() {
+		tailed <- primary.Tail(ctx, 0, func(e JournalEntry) error {
+			if err := replica.ApplyJournal(ctx, e); err != nil {
+				return err
+			}
+			applied <- e.Offset
+			return nil
+		})
+	}()
+
+	first := &hub.Snapshot{
+		DailyQuests: map[string]hub.BaseQuest{"Quest_A": {Count: 1}, "Quest_B": {Count: 2}},
+		Bundles:     []hub.AthenaChallengeBundle{{TemplateID: "ChallengeBundle:Week_001"}},
+	}
+	second := &hub.Snapshot{
+		DailyQuests: map[string]hub.BaseQuest{"Quest_A": {Count: 5}},
+		Bundles:     first.Bundles,
+	}
+	if err := primary.Reset(ctx, first); err != nil {
+		t.Fatal(err)
+	}
+	if err := primary.ApplyChanges(ctx, hub.Diff(first, second)); err != nil {
+		t.Fatal(err)
+	}
+
+	for want := range uint64(2) {
+		select {
+		case got := <-applied:
+			if got != want {
+				t.Fatalf("applied offset %d, want %d", got, want)
+			}
+		case err := <-tailed:
+			t.Fatalf("tail stopped: %v", err)
+		case <-ctx.Done():
+			t.Fatal("replica never caught up")
+		}
+	}
+	if cs := hub.Diff(primary.Snapshot(), replica.Snapshot()); !cs.Empty() {
+		t.Errorf("replica diverged: %+v", cs)
+	}
+
+	if err := primary.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := <-tailed; err != ErrClosed {
+		t.Errorf("tail ended with %v, want ErrClosed", err)
+	}
+
+	// a crash mid-append leaves a torn line, which reopening drops
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.WriteString(`{"offset":2,"quests":{"Quest_`)
+	f.Close()
+
+	j, err = OpenFileJournal(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer j.Close()
+	if j.Next() != 2 {
+		t.Fatalf("reopened at offset %d, want 2", j.Next())
+	}
+	entries, err := j.Read(ctx, 1, 10)
+	if err != nil || len(entries) != 1 {
+		t.Fatalf("got %+v, %v", entries, err)
+	}
+	if e := entries[0]; e.Offset != 1 || e.Reset || e.Quests["Quest_A"].Count != 5 || e.Quests["Quest_B"] != nil {
+		t.Errorf("got %+v", e)
+	}
+}
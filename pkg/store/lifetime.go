@@ -0,0 +1,105 @@
+package store
+
+import (
+	"context"
+	"maps"
+	"time"
+)
+
+// Lifetime is when the store saw a quest or bundle come and go. An entry
+// that was removed and came back keeps its FirstSeen.
+type Lifetime struct {
+	FirstSeen time.Time `json:"firstSeen"`
+	// when the entry was last observed at the hub: for one still served,
+	// the latest write; for a removed one, when its removal was seen
+	LastSeen time.Time `json:"lastSeen"`
+	Active   bool      `json:"active"`
+}
+
+// LifetimeBackend is a Backend that also persists Batch.Lifetimes, so first
+// and last seen survive a reopened store. Backends without it lose them on
+// restart.
+type LifetimeBackend interface {
+	Backend
+	LoadLifetimes(ctx context.Context) (map[string]Lifetime, error)
+}
+
+// Lifetime looks up a quest ID or bundle template ID
+func (s *Store) Lifetime(templateID string) (Lifetime, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	lt, ok := s.lifetimes[templateID]
+	if !ok {
+		return Lifetime{}, false
+	}
+	return s.currentLocked(lt), true
+}
+
+// Lifetimes of every quest and bundle seen, including removed ones
+func (s *Store) Lifetimes() map[string]Lifetime {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	out := make(map[string]Lifetime, len(s.lifetimes))
+	for id, lt := range s.lifetimes {
+		out[id] = s.currentLocked(lt)
+	}
+	return out
+}
+
+// an entry still served was seen by the latest write, even one that did
+// not touch it
+func (s *Store) currentLocked(lt Lifetime) Lifetime {
+	if lt.Active && s.updated.After(lt.LastSeen) {
+		lt.LastSeen = s.updated
+	}
+	return lt
+}
+
+// lifetimesFor works out what b changes about the lifetimes; it runs
+// before b is applied
+func (s *Store) lifetimesFor(b *Batch, at time.Time) map[string]Lifetime {
+	out := make(map[string]Lifetime)
+	seen := func(id string, present bool) {
+		lt, known := s.lifetimes[id]
+		switch {
+		case present:
+			if !known {
+				lt.FirstSeen = at
+			}
+			lt.LastSeen, lt.Active = at, true
+		case known && lt.Active:
+			lt.LastSeen, lt.Active = at, false
+		default:
+			return
+		}
+		out[id] = lt
+	}
+
+	for id, q := range b.Quests {
+		seen(id, q != nil)
+	}
+	for id, bundle := range b.Bundles {
+		seen(id, bundle != nil)
+	}
+
+	// a reset removes whatever it does not name
+	if b.Reset {
+		for id, lt := range s.lifetimes {
+			_, quest := b.Quests[id]
+			_, bundle := b.Bundles[id]
+			if lt.Active && !quest && !bundle {
+				seen(id, false)
+			}
+		}
+	}
+	return out
+}
+
+func (s *Store) applyLifetimesLocked(lifetimes map[string]Lifetime) {
+	if s.lifetimes == nil {
+		s.lifetimes = make(map[string]Lifetime, len(lifetimes))
+	}
+	maps.Copy(s.lifetimes, lifetimes)
+}
@@ -0,0 +1,67 @@
+package store
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ilyskies/QuestHub/pkg/hub"
+)
+
+func TestLifetime(t *testing.T) {
+	ctx := context.Background()
+	day := func(d int) time.Time { return time.Date(2024, 1, d, 0, 0, 0, 0, time.UTC) }
+	snap := func(d int, quests ...string) *hub.Snapshot {
+		s := &hub.Snapshot{
+			TakenAt:     day(d),
+			DailyQuests: make(map[string]hub.BaseQuest),
+			Bundles:     []hub.AthenaChallengeBundle{{TemplateID: "ChallengeBundle:Week_001"}},
+		}
+		for _, id := range quests {
+			s.DailyQuests[id] = hub.BaseQuest{Count: 1}
+		}
+		return s
+	}
+
+	s := New()
+	if err := s.Reset(ctx, snap(1, "Quest_A")); err != nil {
+		t.Fatal(err)
+	}
+	// Quest_A goes away on day 2 and is back on day 4, after a write on
+	// day 3 that leaves the bundle alone
+	steps := []*hub.Snapshot{snap(2, "Quest_B"), snap(3, "Quest_B", "Quest_C"), snap(4, "Quest_A", "Quest_B", "Quest_C")}
+	prev := s.Snapshot()
+	for _, next := range steps {
+		cs := hub.Diff(prev, next)
+		cs.To = &hub.Provenance{FetchedAt: next.TakenAt}
+		if err := s.ApplyChanges(ctx, cs); err != nil {
+			t.Fatal(err)
+		}
+		prev = next
+	}
+
+	want := map[string]Lifetime{
+		"Quest_A":                  {FirstSeen: day(1), LastSeen: day(4), Active: true},
+		"Quest_B":                  {FirstSeen: day(2), LastSeen: day(4), Active: true},
+		"ChallengeBundle:Week_001": {FirstSeen: day(1), LastSeen: day(4), Active: true},
+	}
+	for id, w := range want {
+		if got, ok := s.Lifetime(id); !ok || got != w {
+			t.Errorf("%s: got %+v, %v; want %+v", id, got, ok, w)
+		}
+	}
+
+	// a reset without Quest_B ends its lifetime
+	if err := s.Reset(ctx, snap(5, "Quest_A", "Quest_C")); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := s.Lifetime("Quest_B"); got != (Lifetime{FirstSeen: day(2), LastSeen: day(5)}) {
+		t.Errorf("Quest_B after reset: got %+v", got)
+	}
+	if _, ok := s.Lifetime("Quest_Never"); ok {
+		t.Error("unknown quest has a lifetime")
+	}
+	if n := len(s.Lifetimes()); n != 4 {
+		t.Errorf("got %d lifetimes, want 4", n)
+	}
+}
@@ -0,0 +1,212 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/ilyskies/QuestHub/pkg/hub"
+)
+
+// Mirror is a Backend that writes to two, for validating a new backend in
+// production before cutting over to it. The primary stays authoritative:
+// reads come from it and only its failures fail a write. Every write also
+// goes to the secondary, and the two are read back and compared, with
+// divergences reported as in Verify, the primary standing in for the hub.
+//
+//	m := store.NewMirror(oldBackend, newBackend, store.WithMirrorReport(func(r *store.VerifyReport) {
+//		slog.Warn("Backends diverged", "divergences", len(r.Divergences))
+//	}))
+//	s, err := store.Open(ctx, m)
+//
+// A secondary that is still empty when the mirror is loaded is seeded with
+// a copy of the primary, lifetimes and slugs included. Only the model is
+// compared afterwards; lifetimes and slugs are read from the primary alone.
+type Mirror struct {
+	primary   Backend
+	secondary Backend
+
+	compareEvery int
+	report       func(*VerifyReport)
+
+	mu     sync.Mutex
+	writes int
+	stats  MirrorStats
+}
+
+var (
+	_ LifetimeBackend = (*Mirror)(nil)
+	_ SlugBackend     = (*Mirror)(nil)
+)
+
+type MirrorStats struct {
+	Writes int `json:"writes"`
+	// secondary writes and reads that failed; the primary carried on
+	SecondaryErrors int `json:"secondaryErrors"`
+	Compares        int `json:"compares"`
+	// compares that found the backends apart
+	Diverged  int           `json:"diverged"`
+	LastError string        `json:"lastError,omitempty"`
+	Last      *VerifyReport `json:"last,omitempty"`
+}
+
+type MirrorOption func(*Mirror)
+
+// WithCompareEvery reads both backends back after every n writes rather
+// than after each one, as comparing loads everything twice. 0 compares
+// only on Load and Compare.
+func WithCompareEvery(n int) MirrorOption {
+	return func(m *Mirror) {
+		m.compareEvery = n
+	}
+}
+
+// WithMirrorReport is called with every compare that finds divergences, and
+// with a report of none once they agree again
+func WithMirrorReport(fn func(*VerifyReport)) MirrorOption {
+	return func(m *Mirror) {
+		m.report = fn
+	}
+}
+
+func NewMirror(primary, secondary Backend, opts ...MirrorOption) *Mirror {
+	m := &Mirror{primary: primary, secondary: secondary, compareEvery: 1}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
+}
+
+// Load reads the primary, seeds an empty secondary and compares the two
+func (m *Mirror) Load(ctx context.Context) (*hub.Snapshot, error) {
+	snap, err := m.primary.Load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := m.seed(ctx, snap); err != nil {
+		m.secondaryFailed(err)
+		return snap, nil
+	}
+	if _, err := m.compareWith(ctx, snap); err != nil {
+		m.secondaryFailed(err)
+	}
+	return snap, nil
+}
+
+func (m *Mirror) seed(ctx context.Context, primary *hub.Snapshot) error {
+	secondary, err := m.secondary.Load(ctx)
+	if err != nil {
+		return fmt.Errorf("store: mirror: secondary: %w", err)
+	}
+	if entries(secondary) > 0 || entries(primary) == 0 {
+		return nil
+	}
+
+	b := snapshotBatch(primary)
+	b.Reset = true
+	if b.Lifetimes, err = m.LoadLifetimes(ctx); err != nil {
+		return fmt.Errorf("store: mirror: primary: %w", err)
+	}
+	if b.Slugs, err = m.LoadSlugs(ctx); err != nil {
+		return fmt.Errorf("store: mirror: primary: %w", err)
+	}
+	if err := m.secondary.Write(ctx, b); err != nil {
+		return fmt.Errorf("store: mirror: seed secondary: %w", err)
+	}
+	return nil
+}
+
+func entries(snap *hub.Snapshot) int {
+	if snap == nil {
+		return 0
+	}
+	return len(snap.DailyQuests) + len(snap.Bundles) + len(snap.Schedules)
+}
+
+func (m *Mirror) Write(ctx context.Context, b *Batch) error {
+	if err := m.primary.Write(ctx, b); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	m.writes++
+	m.stats.Writes++
+	compare := m.compareEvery > 0 && m.writes%m.compareEvery == 0
+	m.mu.Unlock()
+
+	if err := m.secondary.Write(ctx, b); err != nil {
+		m.secondaryFailed(err)
+		return nil
+	}
+	if compare {
+		if _, err := m.Compare(ctx); err != nil {
+			m.secondaryFailed(err)
+		}
+	}
+	return nil
+}
+
+// Compare reads both backends back and reports how the secondary differs
+func (m *Mirror) Compare(ctx context.Context) (*VerifyReport, error) {
+	snap, err := m.primary.Load(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("store: mirror: primary: %w", err)
+	}
+	return m.compareWith(ctx, snap)
+}
+
+func (m *Mirror) compareWith(ctx context.Context, primary *hub.Snapshot) (*VerifyReport, error) {
+	secondary, err := m.secondary.Load(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("store: mirror: secondary: %w", err)
+	}
+	report := diverge(secondary, primary)
+
+	m.mu.Lock()
+	wasDiverged := m.stats.Last != nil && len(m.stats.Last.Divergences) > 0
+	m.stats.Compares++
+	if len(report.Divergences) > 0 {
+		m.stats.Diverged++
+	}
+	m.stats.Last = report
+	m.mu.Unlock()
+
+	if m.report != nil && (len(report.Divergences) > 0 || wasDiverged) {
+		m.report(report)
+	}
+	return report, nil
+}
+
+func (m *Mirror) secondaryFailed(err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.stats.SecondaryErrors++
+	m.stats.LastError = err.Error()
+}
+
+func (m *Mirror) Stats() MirrorStats {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.stats
+}
+
+// LoadLifetimes reads the primary's, if it keeps them
+func (m *Mirror) LoadLifetimes(ctx context.Context) (map[string]Lifetime, error) {
+	if lb, ok := m.primary.(LifetimeBackend); ok {
+		return lb.LoadLifetimes(ctx)
+	}
+	return nil, nil
+}
+
+// LoadSlugs reads the primary's, if it keeps them
+func (m *Mirror) LoadSlugs(ctx context.Context) (map[string]string, error) {
+	if sb, ok := m.primary.(SlugBackend); ok {
+		return sb.LoadSlugs(ctx)
+	}
+	return nil, nil
+}
+
+func (m *Mirror) Close() error {
+	return errors.Join(m.primary.Close(), m.secondary.Close())
+}
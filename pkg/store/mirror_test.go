@@ -0,0 +1,111 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/ilyskies/QuestHub/pkg/hub"
+)
+
+// memBackend keeps the model in memory; dropQuests makes it lose quest
+// writes, failWrites fails them
+type memBackend struct {
+	mu         sync.Mutex
+	snap       hub.Snapshot
+	dropQuests bool
+	failWrites bool
+}
+
+func (b *memBackend) Load(context.Context) (*hub.Snapshot, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.snap.Clone(), nil
+}
+
+func (b *memBackend) Write(_ context.Context, batch *Batch) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.failWrites {
+		return errors.New("disk full")
+	}
+	if b.dropQuests {
+		copied := *batch
+		copied.Quests = nil
+		batch = &copied
+	}
+	batch.ApplyTo(&b.snap)
+	return nil
+}
+
+func (b *memBackend) Close() error { return nil }
+
+func TestMirror(t *testing.T) {
+	ctx := context.Background()
+	snap := func(quests ...string) *hub.Snapshot {
+		s := &hub.Snapshot{
+			DailyQuests: make(map[string]hub.BaseQuest),
+			Bundles:     []hub.AthenaChallengeBundle{{TemplateID: "ChallengeBundle:Week_001"}},
+		}
+		for _, id := range quests {
+			s.DailyQuests[id] = hub.BaseQuest{Count: 1}
+		}
+		return s
+	}
+
+	primary, secondary := &memBackend{}, &memBackend{}
+	seedBatch := snapshotBatch(snap("Quest_A"))
+	seedBatch.Reset = true
+	if err := primary.Write(ctx, seedBatch); err != nil {
+		t.Fatal(err)
+	}
+
+	var reports []*VerifyReport
+	m := NewMirror(primary, secondary, WithMirrorReport(func(r *VerifyReport) { reports = append(reports, r) }))
+	s, err := Open(ctx, m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	// the empty secondary starts as a copy
+	if got, _ := secondary.Load(ctx); len(got.DailyQuests) != 1 || len(got.Bundles) != 1 {
+		t.Fatalf("secondary not seeded: %+v", got)
+	}
+	if st := m.Stats(); st.Compares != 1 || st.Diverged != 0 || len(reports) != 0 {
+		t.Fatalf("after open: %+v, %d reports", st, len(reports))
+	}
+
+	secondary.dropQuests = true
+	if err := s.ApplyChanges(ctx, hub.Diff(snap("Quest_A"), snap("Quest_A", "Quest_B"))); err != nil {
+		t.Fatal(err)
+	}
+	if len(reports) != 1 || len(reports[0].Divergences) != 1 {
+		t.Fatalf("reports = %+v", reports)
+	}
+	if d := reports[0].Divergences[0]; d.ID != "Quest_B" || d.Kind != hub.DivergenceMissing {
+		t.Errorf("divergence = %+v", d)
+	}
+
+	// the primary alone decides whether a write fails
+	secondary.dropQuests, secondary.failWrites = false, true
+	if err := s.Reset(ctx, snap("Quest_B")); err != nil {
+		t.Fatalf("write failed with the secondary: %v", err)
+	}
+	if st := m.Stats(); st.SecondaryErrors != 1 || st.LastError == "" {
+		t.Errorf("stats = %+v", st)
+	}
+
+	// agreeing again is reported once
+	secondary.failWrites = false
+	if err := s.Reset(ctx, snap("Quest_B")); err != nil {
+		t.Fatal(err)
+	}
+	if len(reports) != 2 || len(reports[1].Divergences) != 0 {
+		t.Errorf("reports = %+v", reports)
+	}
+	if st := m.Stats(); st.Writes != 3 || st.Diverged != 1 {
+		t.Errorf("stats = %+v", st)
+	}
+}
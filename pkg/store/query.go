@@ -0,0 +1,177 @@
+package store
+
+import (
+	"iter"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/ilyskies/QuestHub/pkg/hub"
+)
+
+func (s *Store) Quest(id string) (hub.BaseQuest, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	q, ok := s.quests[id]
+	if !ok {
+		return hub.BaseQuest{}, false
+	}
+	return q.Clone(), true
+}
+
+func (s *Store) Bundle(templateID string) (hub.AthenaChallengeBundle, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	b, ok := s.bundles[templateID]
+	if !ok {
+		return hub.AthenaChallengeBundle{}, false
+	}
+	return b.Clone(), true
+}
+
+func (s *Store) Schedule(templateID string) (hub.ChallengeBundleSchedule, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	sched, ok := s.schedules[templateID]
+	return sched, ok
+}
+
+func (s *Store) Quests() hub.QuestSet {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	out := make(hub.QuestSet, len(s.quests))
+	for id, q := range s.quests {
+		out[id] = q.Clone()
+	}
+	return out
+}
+
+// bundles are sorted by template ID
+func (s *Store) Bundles() hub.BundleSet {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.bundlesLocked(maps.Keys(s.bundles))
+}
+
+// schedules are sorted by template ID
+func (s *Store) Schedules() []hub.ChallengeBundleSchedule {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	out := make([]hub.ChallengeBundleSchedule, 0, len(s.schedules))
+	for _, sched := range s.schedules {
+		out = append(out, sched)
+	}
+	slices.SortFunc(out, func(a, b hub.ChallengeBundleSchedule) int {
+		return strings.Compare(a.TemplateID, b.TemplateID)
+	})
+	return out
+}
+
+// quests granting the reward; template IDs match the way the rewards
+// package parses them, ignoring case
+func (s *Store) QuestsByReward(templateID string) hub.QuestSet {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	ids := s.questsByReward[rewardKey(templateID)]
+	out := make(hub.QuestSet, len(ids))
+	for id := range ids {
+		out[id] = s.quests[id].Clone()
+	}
+	return out
+}
+
+// bundles granting the reward from any of their quests or on completion
+func (s *Store) BundlesByReward(templateID string) hub.BundleSet {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.bundlesLocked(maps.Keys(s.bundlesByReward[rewardKey(templateID)]))
+}
+
+// like BundleSet.ByRarity, matching ignores case
+func (s *Store) BundlesByRarity(rarities ...string) hub.BundleSet {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	ids := make(set)
+	for _, r := range rarities {
+		for id := range s.bundlesByRarity[strings.ToLower(r)] {
+			ids[id] = struct{}{}
+		}
+	}
+	return s.bundlesLocked(maps.Keys(ids))
+}
+
+func (s *Store) BundlesBySchedule(scheduleID string) hub.BundleSet {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.bundlesLocked(maps.Keys(s.bundlesBySchedule[scheduleID]))
+}
+
+// Rarities lists the bundle rarities present, in lower case
+func (s *Store) Rarities() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	out := make([]string, 0, len(s.bundlesByRarity))
+	for r := range s.bundlesByRarity {
+		out = append(out, r)
+	}
+	slices.Sort(out)
+	return out
+}
+
+// Snapshot copies the whole model; Status is nil since events do not
+// carry it
+func (s *Store) Snapshot() *hub.Snapshot {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	snap := &hub.Snapshot{
+		TakenAt:     s.updated,
+		DailyQuests: make(map[string]hub.BaseQuest, len(s.quests)),
+		Bundles:     s.bundlesLocked(maps.Keys(s.bundles)),
+		Provenance:  hub.Provenance{Source: "store", FetchedAt: s.updated},
+	}
+	for id, q := range s.quests {
+		snap.DailyQuests[id] = q.Clone()
+	}
+	for _, sched := range s.schedules {
+		snap.Schedules = append(snap.Schedules, sched)
+	}
+	slices.SortFunc(snap.Schedules, func(a, b hub.ChallengeBundleSchedule) int {
+		return strings.Compare(a.TemplateID, b.TemplateID)
+	})
+	return snap
+}
+
+// Version counts the writes applied since the store was created or opened
+func (s *Store) Version() uint64 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.version
+}
+
+// UpdatedAt is when the last applied change was observed at the hub
+func (s *Store) UpdatedAt() time.Time {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.updated
+}
+
+func (s *Store) bundlesLocked(ids iter.Seq[string]) hub.BundleSet {
+	var out hub.BundleSet
+	for id := range ids {
+		out = append(out, s.bundles[id].Clone())
+	}
+	slices.SortFunc(out, func(a, b hub.AthenaChallengeBundle) int {
+		return strings.Compare(a.TemplateID, b.TemplateID)
+	})
+	return out
+}
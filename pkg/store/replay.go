@@ -0,0 +1,127 @@
+package store
+
+import (
+	"context"
+	"time"
+
+	"github.com/ilyskies/QuestHub/pkg/hub"
+)
+
+type replayer struct {
+	from, to time.Time
+	speed    float64
+}
+
+type ReplayOption func(*replayer)
+
+// ReplayFrom skips changes written before t
+func ReplayFrom(t time.Time) ReplayOption {
+	return func(r *replayer) {
+		r.from = t
+	}
+}
+
+// ReplayTo stops at the first change written at or after t
+func ReplayTo(t time.Time) ReplayOption {
+	return func(r *replayer) {
+		r.to = t
+	}
+}
+
+// ReplaySpeed waits between changes for the time that passed between them
+// divided by x, so 10 replays an hour in six minutes. Without it changes
+// follow each other at once.
+func ReplaySpeed(x float64) ReplayOption {
+	return func(r *replayer) {
+		r.speed = x
+	}
+}
+
+// Replay reads j from the start and calls fn with what each entry in the
+// window changed, oldest first. Entries before the window only rebuild the
+// model, so the first change is relative to the model as it was then. A
+// rollover entry comes as a change set holding only its hub.Rollover.
+func Replay(ctx context.Context, j Journal, fn func(hub.ChangeSet) error, opts ...ReplayOption) error {
+	const readSize = 256
+
+	r := &replayer{}
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	model := &hub.Snapshot{}
+	var last time.Time
+	for from := uint64(0); ; {
+		entries, err := j.Read(ctx, from, readSize)
+		if err != nil {
+			return err
+		}
+		if len(entries) == 0 {
+			return nil
+		}
+
+		for _, e := range entries {
+			from = e.Offset + 1
+			if !r.to.IsZero() && !e.At.Before(r.to) {
+				return nil
+			}
+			if e.At.Before(r.from) {
+				e.Batch().ApplyTo(model)
+				continue
+			}
+
+			before := model.Clone()
+			e.Batch().ApplyTo(model)
+			cs := replayChanges(&e, before, model)
+			if cs.Empty() {
+				continue
+			}
+
+			if err := r.wait(ctx, last, e.At); err != nil {
+				return err
+			}
+			last = e.At
+			if err := fn(cs); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func replayChanges(e *JournalEntry, before, after *hub.Snapshot) hub.ChangeSet {
+	to := &hub.Provenance{Source: "journal", FetchedAt: e.At}
+	if e.Archive == "" {
+		cs := hub.Diff(before, after)
+		cs.To = to
+		return cs
+	}
+
+	counts := func(s *hub.Snapshot) hub.RolloverCounts {
+		return hub.RolloverCounts{Quests: len(s.DailyQuests), Bundles: len(s.Bundles), Schedules: len(s.Schedules)}
+	}
+	return hub.ChangeSet{
+		Rollover: &hub.Rollover{
+			FromVersion: e.Archive,
+			ToVersion:   e.Season,
+			Removed:     counts(before),
+			Added:       counts(after),
+			Snapshot:    after.Clone(),
+		},
+		To: to,
+	}
+}
+
+func (r *replayer) wait(ctx context.Context, last, at time.Time) error {
+	if r.speed <= 0 || last.IsZero() || !at.After(last) {
+		return nil
+	}
+
+	timer := time.NewTimer(time.Duration(float64(at.Sub(last)) / r.speed))
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
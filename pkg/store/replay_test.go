@@ -0,0 +1,93 @@
+package store
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ilyskies/QuestHub/pkg/hub"
+)
+
+func TestReplay(t *testing.T) {
+	ctx := context.Background()
+	j := NewMemoryJournal()
+	start := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	appendAt := func(hours int, e *JournalEntry) {
+		t.Helper()
+		e.At = start.Add(time.Duration(hours) * time.Hour)
+		if err := j.Append(ctx, e); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	appendAt(0, &JournalEntry{Reset: true, Quests: map[string]*hub.BaseQuest{"Quest_A": {Count: 1}}})
+	appendAt(1, &JournalEntry{Quests: map[string]*hub.BaseQuest{"Quest_A": {Count: 2}}})
+	appendAt(2, &JournalEntry{Quests: map[string]*hub.BaseQuest{"Quest_B": {Count: 1}}})
+	// writes nothing new
+	appendAt(3, &JournalEntry{Quests: map[string]*hub.BaseQuest{"Quest_B": {Count: 1}}})
+	appendAt(4, &JournalEntry{Reset: true, Archive: "9.0", Season: "10.0", Quests: map[string]*hub.BaseQuest{"Quest_C": {Count: 1}}})
+	appendAt(5, &JournalEntry{Quests: map[string]*hub.BaseQuest{"Quest_C": nil}})
+
+	var got []hub.ChangeSet
+	collect := func(cs hub.ChangeSet) error {
+		got = append(got, cs)
+		return nil
+	}
+	err := Replay(ctx, j, collect, ReplayFrom(start.Add(time.Hour)), ReplayTo(start.Add(5*time.Hour)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(got) != 3 {
+		t.Fatalf("got %d change sets, want 3: %+v", len(got), got)
+	}
+	// the first change is relative to the entries before the window
+	if q := got[0].Quests; len(q) != 1 || q[0].ID != "Quest_A" || q[0].Kind != hub.ChangeModified {
+		t.Errorf("first change = %+v", got[0])
+	}
+	if q := got[1].Quests; len(q) != 1 || q[0].ID != "Quest_B" || q[0].Kind != hub.ChangeAdded {
+		t.Errorf("second change = %+v", got[1])
+	}
+	if !got[1].To.FetchedAt.Equal(start.Add(2 * time.Hour)) {
+		t.Errorf("change at %v, want when it was journaled", got[1].To.FetchedAt)
+	}
+
+	r := got[2].Rollover
+	if r == nil || len(got[2].Quests) != 0 {
+		t.Fatalf("third change = %+v, want only a rollover", got[2])
+	}
+	if r.FromVersion != "9.0" || r.ToVersion != "10.0" || r.Removed.Quests != 2 || r.Added.Quests != 1 {
+		t.Errorf("rollover = %+v", r)
+	}
+	if _, ok := r.Snapshot.DailyQuests["Quest_C"]; !ok {
+		t.Errorf("rollover snapshot = %+v", r.Snapshot)
+	}
+}
+
+func TestReplaySpeed(t *testing.T) {
+	ctx := context.Background()
+	j := NewMemoryJournal()
+	start := time.Now()
+	for i, id := range []string{"Quest_A", "Quest_B"} {
+		e := &JournalEntry{At: start.Add(time.Duration(i) * time.Second), Quests: map[string]*hub.BaseQuest{id: {Count: 1}}}
+		if err := j.Append(ctx, e); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	began := time.Now()
+	err := Replay(ctx, j, func(hub.ChangeSet) error { return nil }, ReplaySpeed(10))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if took := time.Since(began); took < 100*time.Millisecond || took > time.Second {
+		t.Errorf("replaying one second at 10x took %v", took)
+	}
+
+	// cancelling stops the wait
+	ctx, cancel := context.WithCancel(ctx)
+	cancel()
+	if err := Replay(ctx, j, func(hub.ChangeSet) error { return nil }, ReplaySpeed(0.001)); err == nil {
+		t.Error("cancelled replay finished")
+	}
+}
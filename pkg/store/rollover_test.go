@@ -0,0 +1,66 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ilyskies/QuestHub/pkg/hub"
+)
+
+// a rollover event resets the model, and the journal tells replicas which
+// season was archived
+func TestApplyRollover(t *testing.T) {
+	ctx := context.Background()
+	j := NewMemoryJournal()
+	s := New(WithJournal(j))
+
+	old := &hub.Snapshot{DailyQuests: map[string]hub.BaseQuest{"Quest_Old": {Count: 1}}}
+	if err := s.Reset(ctx, old); err != nil {
+		t.Fatal(err)
+	}
+
+	next := &hub.Snapshot{
+		TakenAt:     time.Now().UTC(),
+		DailyQuests: map[string]hub.BaseQuest{"Quest_New": {Count: 2}},
+		Bundles:     []hub.AthenaChallengeBundle{{TemplateID: "ChallengeBundle:New"}},
+	}
+	r := &hub.Rollover{FromVersion: "9.0", ToVersion: "10.0", Snapshot: next}
+	err := s.Apply(ctx,
+		hub.ChangeEvent{Type: hub.QuestAdded, ID: "Quest_Late", Quest: &hub.QuestDelta{ID: "Quest_Late", Kind: hub.ChangeAdded, New: &hub.BaseQuest{Count: 3}}},
+		hub.ChangeEvent{Type: hub.SeasonRollover, ID: "10.0", Rollover: r},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := s.Quest("Quest_Old"); ok {
+		t.Error("old season's quest survived")
+	}
+	if _, ok := s.Quest("Quest_Late"); ok {
+		t.Error("quest added before the rollover survived it")
+	}
+	if _, ok := s.Quest("Quest_New"); !ok || len(s.Bundles()) != 1 {
+		t.Errorf("new season not applied: %+v", s.Snapshot())
+	}
+	// the old quest's lifetime is kept
+	if _, ok := s.Lifetime("Quest_Old"); !ok {
+		t.Error("rollover dropped an earlier lifetime")
+	}
+
+	entries, err := j.Read(ctx, 0, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	last := entries[len(entries)-1]
+	if len(entries) != 3 || !last.Reset || last.Archive != "9.0" || last.Batch().Archive != "9.0" {
+		t.Errorf("journal = %+v", entries)
+	}
+
+	// a rollover without its data cannot be applied
+	r.Snapshot = nil
+	if err := s.ApplyChanges(ctx, hub.ChangeSet{Rollover: r}); !errors.Is(err, ErrInvalidEvent) {
+		t.Errorf("err = %v, want ErrInvalidEvent", err)
+	}
+}
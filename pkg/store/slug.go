@@ -0,0 +1,106 @@
+package store
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base32"
+	"fmt"
+	"strings"
+)
+
+// SlugBackend is a Backend that also persists Batch.Slugs. Slugs handed
+// out by a store on any other backend last until it closes.
+type SlugBackend interface {
+	Backend
+	LoadSlugs(ctx context.Context) (map[string]string, error)
+}
+
+var slugEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// Slug returns the short public name of a quest ID or template ID, making
+// one up the first time. Slugs are random, so they reveal nothing about
+// the ID, and never change: not even Reset drops them.
+func (s *Store) Slug(ctx context.Context, id string) (string, error) {
+	slugs, err := s.Slugs(ctx, id)
+	if err != nil {
+		return "", err
+	}
+	return slugs[id], nil
+}
+
+// Slugs is Slug for several IDs at once; the ones that are new are
+// persisted together in a single write
+func (s *Store) Slugs(ctx context.Context, ids ...string) (map[string]string, error) {
+	out := make(map[string]string, len(ids))
+	s.mu.RLock()
+	for _, id := range ids {
+		if slug, ok := s.slugs[id]; ok {
+			out[id] = slug
+		}
+	}
+	s.mu.RUnlock()
+	if len(out) == len(ids) {
+		return out, nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return nil, ErrClosed
+	}
+	// id to slug, and the slugs among them, for the write
+	made, fresh := make(map[string]string), make(map[string]bool)
+	for _, id := range ids {
+		if _, ok := out[id]; ok {
+			continue
+		}
+		if slug, ok := s.slugs[id]; ok {
+			out[id] = slug
+			continue
+		}
+		slug, err := s.newSlugLocked(fresh)
+		if err != nil {
+			return nil, err
+		}
+		made[id], out[id], fresh[slug] = slug, slug, true
+	}
+
+	if len(made) > 0 && s.backend != nil {
+		if err := s.backend.Write(ctx, &Batch{Slugs: made}); err != nil {
+			return nil, fmt.Errorf("store: write: %w", err)
+		}
+	}
+	s.addSlugsLocked(made)
+	return out, nil
+}
+
+// newSlugLocked makes up a slug neither taken nor in fresh
+func (s *Store) newSlugLocked(fresh map[string]bool) (string, error) {
+	for {
+		var b [5]byte
+		if _, err := rand.Read(b[:]); err != nil {
+			return "", fmt.Errorf("store: slug: %w", err)
+		}
+		slug := strings.ToLower(slugEncoding.EncodeToString(b[:]))
+		if _, taken := s.bySlug[slug]; !taken && !fresh[slug] {
+			return slug, nil
+		}
+	}
+}
+
+// ResolveSlug finds the ID a slug was made for
+func (s *Store) ResolveSlug(slug string) (string, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	id, ok := s.bySlug[slug]
+	return id, ok
+}
+
+func (s *Store) addSlugsLocked(slugs map[string]string) {
+	for id, slug := range slugs {
+		s.slugs[id] = slug
+		s.bySlug[slug] = id
+	}
+}
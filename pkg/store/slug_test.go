@@ -0,0 +1,82 @@
+package store
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ilyskies/QuestHub/pkg/hub"
+)
+
+func TestSlug(t *testing.T) {
+	ctx := context.Background()
+	s := New()
+
+	slug, err := s.Slug(ctx, "ChallengeBundle:QuestBundle_Week_001")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(slug) != 8 {
+		t.Errorf("got slug %q, want 8 characters", slug)
+	}
+	if again, _ := s.Slug(ctx, "ChallengeBundle:QuestBundle_Week_001"); again != slug {
+		t.Errorf("got %q, then %q", slug, again)
+	}
+	if other, _ := s.Slug(ctx, "Quest_Daily_Eliminations"); other == slug {
+		t.Error("two IDs share a slug")
+	}
+
+	// slugs outlive the model
+	if err := s.Reset(ctx, &hub.Snapshot{}); err != nil {
+		t.Fatal(err)
+	}
+	if id, ok := s.ResolveSlug(slug); !ok || id != "ChallengeBundle:QuestBundle_Week_001" {
+		t.Errorf("resolved %q to %q, %v", slug, id, ok)
+	}
+	if _, ok := s.ResolveSlug("unknown"); ok {
+		t.Error("resolved a slug never handed out")
+	}
+}
+
+type countingBackend struct {
+	memBackend
+	writes int
+}
+
+func (b *countingBackend) Write(ctx context.Context, batch *Batch) error {
+	b.writes++
+	return b.memBackend.Write(ctx, batch)
+}
+
+// the new slugs of one call are written together
+func TestSlugsWriteOnce(t *testing.T) {
+	ctx := context.Background()
+	b := &countingBackend{}
+	s, err := Open(ctx, b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	writes := b.writes
+
+	known, err := s.Slug(ctx, "Quest_A")
+	if err != nil {
+		t.Fatal(err)
+	}
+	slugs, err := s.Slugs(ctx, "Quest_A", "Quest_B", "Quest_C", "Quest_B")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(slugs) != 3 || slugs["Quest_A"] != known || slugs["Quest_B"] == slugs["Quest_C"] {
+		t.Errorf("slugs = %v", slugs)
+	}
+	if got := b.writes - writes; got != 2 {
+		t.Errorf("%d writes, want one for Slug and one for Slugs", got)
+	}
+
+	if _, err := s.Slugs(ctx, "Quest_A", "Quest_C"); err != nil {
+		t.Fatal(err)
+	}
+	if got := b.writes - writes; got != 2 {
+		t.Errorf("known slugs written again: %d writes", got)
+	}
+}
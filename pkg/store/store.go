@@ -0,0 +1,545 @@
+// Package store keeps a local read model of the hub's quests, bundles and
+// schedules, built from the watcher's change events, with indexes by
+// reward, rarity and schedule so consumers can query it instead of calling
+// the hub:
+//
+//	s := store.New()
+//	snap, _ := client.Snapshot(ctx)
+//	_ = s.Reset(ctx, snap)
+//	go s.Consume(ctx, watcher.Events())
+//	bundles := s.BundlesByRarity("rare")
+//
+// The store also remembers when each quest and bundle was first and last
+// seen, see Lifetime, including ones the hub no longer serves.
+//
+// WithJournal records every write in an offset-addressed Journal that
+// replicas follow with Tail from the last offset they applied:
+//
+//	j, _ := store.OpenFileJournal("questhub.journal")
+//	s := store.New(store.WithJournal(j))
+//	go s.Tail(ctx, lastOffset, func(e store.JournalEntry) error { ... })
+//
+// A journal file published somewhere is an archive: a new, empty store can
+// Bootstrap from it, see FetchJournal, and Replay sends its changes again.
+//
+// A Backend, such as boltstore, keeps the model across restarts. Events only
+// describe changes, so a reopened store should still be Reset from a fresh
+// snapshot to drop what was removed while it was down. A Mirror writes to
+// two backends and compares them, for migrating from one to the other.
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ilyskies/QuestHub/pkg/hub"
+	"github.com/ilyskies/QuestHub/pkg/hub/rewards"
+)
+
+var (
+	ErrInvalidEvent = errors.New("store: invalid change event")
+	ErrClosed       = errors.New("store: closed")
+	ErrNilSnapshot  = errors.New("store: nil snapshot")
+)
+
+// Batch is one atomic write to a Backend. A nil value deletes its key.
+type Batch struct {
+	// drop everything stored before applying the rest
+	Reset bool
+	// with Reset, the namespace to keep what was stored under instead of
+	// dropping it, e.g. the season a rollover ended; backends without
+	// namespaces drop it as for any reset
+	Archive string
+	// with Archive, the season replacing the archived one
+	Season string
+
+	Quests    map[string]*hub.BaseQuest
+	Bundles   map[string]*hub.AthenaChallengeBundle
+	Schedules map[string]*hub.ChallengeBundleSchedule
+
+	// first and last seen of the quests and bundles the batch touches,
+	// filled in by the store; a reset does not drop earlier lifetimes
+	Lifetimes map[string]Lifetime
+	// ID -> public slug handed out by Slug, written in batches of their own
+	Slugs map[string]string
+}
+
+func newBatch() *Batch {
+	return &Batch{
+		Quests:    make(map[string]*hub.BaseQuest),
+		Bundles:   make(map[string]*hub.AthenaChallengeBundle),
+		Schedules: make(map[string]*hub.ChallengeBundleSchedule),
+	}
+}
+
+func (b *Batch) Len() int {
+	return len(b.Quests) + len(b.Bundles) + len(b.Schedules)
+}
+
+// Backend persists the read model. Load returns an empty snapshot before
+// the first Write. Backends can be registered by name with
+// plugin.RegisterStore.
+type Backend interface {
+	Load(ctx context.Context) (*hub.Snapshot, error)
+	Write(ctx context.Context, b *Batch) error
+	Close() error
+}
+
+// Store is safe for concurrent use. Values it returns are copies.
+type Store struct {
+	mu      sync.RWMutex
+	backend Backend
+	closed  bool
+
+	journal Journal
+	// closed and replaced after every journal append, waking Tail
+	journaled chan struct{}
+
+	quests    map[string]hub.BaseQuest
+	bundles   map[string]hub.AthenaChallengeBundle
+	schedules map[string]hub.ChallengeBundleSchedule
+	// quest ID or bundle template ID -> when it was seen
+	lifetimes map[string]Lifetime
+	// ID -> public slug and back
+	slugs, bySlug map[string]string
+
+	// reward key -> quest IDs and bundle template IDs
+	questsByReward  index
+	bundlesByReward index
+	// lower case rarity -> bundle template IDs
+	bundlesByRarity index
+	// schedule template ID -> bundle template IDs
+	bundlesBySchedule index
+
+	version uint64
+	updated time.Time
+}
+
+// New returns an empty in-memory store
+func New(opts ...Option) *Store {
+	s := &Store{
+		quests:            make(map[string]hub.BaseQuest),
+		bundles:           make(map[string]hub.AthenaChallengeBundle),
+		schedules:         make(map[string]hub.ChallengeBundleSchedule),
+		lifetimes:         make(map[string]Lifetime),
+		slugs:             make(map[string]string),
+		bySlug:            make(map[string]string),
+		questsByReward:    make(index),
+		bundlesByReward:   make(index),
+		bundlesByRarity:   make(index),
+		bundlesBySchedule: make(index),
+		journaled:         make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Open loads what b holds and writes every later change through to it
+func Open(ctx context.Context, b Backend, opts ...Option) (*Store, error) {
+	snap, err := b.Load(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("store: load: %w", err)
+	}
+
+	s := New(opts...)
+	if lb, ok := b.(LifetimeBackend); ok {
+		lifetimes, err := lb.LoadLifetimes(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("store: load lifetimes: %w", err)
+		}
+		s.applyLifetimesLocked(lifetimes)
+	}
+	if sb, ok := b.(SlugBackend); ok {
+		slugs, err := sb.LoadSlugs(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("store: load slugs: %w", err)
+		}
+		s.addSlugsLocked(slugs)
+	}
+	s.applyLocked(snapshotBatch(snap), snap.TakenAt)
+	s.backend = b
+
+	if s.journal != nil {
+		if err := s.startJournal(ctx, snap); err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+// Close closes the backend and journal, if any; later writes fail with
+// ErrClosed
+func (s *Store) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return nil
+	}
+	s.closed = true
+	close(s.journaled)
+
+	var errs []error
+	if s.journal != nil {
+		errs = append(errs, s.journal.Close())
+	}
+	if s.backend != nil {
+		errs = append(errs, s.backend.Close())
+	}
+	return errors.Join(errs...)
+}
+
+// Reset replaces the whole model with snap
+func (s *Store) Reset(ctx context.Context, snap *hub.Snapshot) error {
+	if snap == nil {
+		return ErrNilSnapshot
+	}
+	return s.write(ctx, ResetBatch(snap), snap.TakenAt)
+}
+
+// Apply adds, replaces or removes what the events describe, as one write.
+// A SeasonRollover event is a Rollover of its own, after the events before
+// it.
+func (s *Store) Apply(ctx context.Context, events ...hub.ChangeEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	b := newBatch()
+	var at time.Time
+	for _, e := range events {
+		if e.Rollover != nil {
+			if b.Len() > 0 {
+				if err := s.write(ctx, b, at); err != nil {
+					return err
+				}
+			}
+			if err := s.Rollover(ctx, e.Rollover); err != nil {
+				return err
+			}
+			b = newBatch()
+			continue
+		}
+		if err := addEvent(b, e); err != nil {
+			return err
+		}
+		at = e.At
+	}
+	if b.Len() == 0 {
+		return nil
+	}
+	return s.write(ctx, b, at)
+}
+
+// Rollover replaces the model with the new season's snapshot. A backend
+// with namespaces keeps the old season under r.FromVersion.
+func (s *Store) Rollover(ctx context.Context, r *hub.Rollover) error {
+	if r.Snapshot == nil {
+		return fmt.Errorf("%w: rollover to %s has no snapshot", ErrInvalidEvent, r.ToVersion)
+	}
+	b := ResetBatch(r.Snapshot)
+	b.Archive, b.Season = r.FromVersion, r.ToVersion
+	return s.write(ctx, b, r.Snapshot.TakenAt)
+}
+
+// ApplyChanges applies a diff, e.g. the Changes of a pipeline item
+func (s *Store) ApplyChanges(ctx context.Context, cs hub.ChangeSet) error {
+	if cs.Empty() {
+		return nil
+	}
+	if cs.Rollover != nil {
+		return s.Rollover(ctx, cs.Rollover)
+	}
+
+	b := newBatch()
+	for _, d := range cs.Quests {
+		b.Quests[d.ID] = d.New
+	}
+	for _, d := range cs.Bundles {
+		b.Bundles[d.TemplateID] = d.New
+	}
+	for _, d := range cs.Schedules {
+		b.Schedules[d.TemplateID] = d.New
+	}
+
+	at := time.Now().UTC()
+	if cs.To != nil {
+		at = cs.To.FetchedAt
+	}
+	return s.write(ctx, b, at)
+}
+
+// Consume applies events until the channel closes or ctx ends. Events that
+// are already queued are written together.
+func (s *Store) Consume(ctx context.Context, events <-chan hub.ChangeEvent) error {
+	const maxBatch = 256
+
+	for {
+		var pending []hub.ChangeEvent
+		select {
+		case e, ok := <-events:
+			if !ok {
+				return nil
+			}
+			pending = append(pending, e)
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+
+	drain:
+		for len(pending) < maxBatch {
+			select {
+			case e, ok := <-events:
+				if !ok {
+					break drain
+				}
+				pending = append(pending, e)
+			default:
+				break drain
+			}
+		}
+
+		if err := s.Apply(ctx, pending...); err != nil {
+			return err
+		}
+	}
+}
+
+func addEvent(b *Batch, e hub.ChangeEvent) error {
+	switch {
+	case e.Quest != nil:
+		if e.Quest.Kind != hub.ChangeRemoved && e.Quest.New == nil {
+			return fmt.Errorf("%w: %s %s has no quest", ErrInvalidEvent, e.Type, e.ID)
+		}
+		b.Quests[e.ID] = e.Quest.New
+	case e.Bundle != nil:
+		if e.Bundle.Kind != hub.ChangeRemoved && e.Bundle.New == nil {
+			return fmt.Errorf("%w: %s %s has no bundle", ErrInvalidEvent, e.Type, e.ID)
+		}
+		b.Bundles[e.ID] = e.Bundle.New
+	case e.Schedule != nil:
+		if e.Schedule.Kind != hub.ChangeRemoved && e.Schedule.New == nil {
+			return fmt.Errorf("%w: %s %s has no schedule", ErrInvalidEvent, e.Type, e.ID)
+		}
+		b.Schedules[e.ID] = e.Schedule.New
+	default:
+		return fmt.Errorf("%w: %s %s carries no delta", ErrInvalidEvent, e.Type, e.ID)
+	}
+	return nil
+}
+
+// ResetBatch is a batch that replaces everything stored with snap
+func ResetBatch(snap *hub.Snapshot) *Batch {
+	b := snapshotBatch(snap)
+	b.Reset = true
+	return b
+}
+
+// ApplyTo applies the batch to snap, for backends that keep the whole
+// model as one snapshot. Bundles and schedules end up sorted by template ID.
+func (b *Batch) ApplyTo(snap *hub.Snapshot) {
+	if b.Reset {
+		snap.DailyQuests, snap.Bundles, snap.Schedules = nil, nil, nil
+	}
+
+	if snap.DailyQuests == nil {
+		snap.DailyQuests = make(map[string]hub.BaseQuest)
+	}
+	for id, q := range b.Quests {
+		if q == nil {
+			delete(snap.DailyQuests, id)
+			continue
+		}
+		snap.DailyQuests[id] = *q
+	}
+
+	snap.Bundles = applyList(snap.Bundles, b.Bundles, func(v *hub.AthenaChallengeBundle) string { return v.TemplateID })
+	snap.Schedules = applyList(snap.Schedules, b.Schedules, func(v *hub.ChallengeBundleSchedule) string { return v.TemplateID })
+}
+
+func applyList[T any](list []T, changes map[string]*T, key func(*T) string) []T {
+	if len(changes) == 0 {
+		return list
+	}
+
+	byID := make(map[string]T, len(list)+len(changes))
+	for i := range list {
+		byID[key(&list[i])] = list[i]
+	}
+	for id, v := range changes {
+		if v == nil {
+			delete(byID, id)
+			continue
+		}
+		byID[id] = *v
+	}
+
+	out := make([]T, 0, len(byID))
+	for _, id := range slices.Sorted(maps.Keys(byID)) {
+		out = append(out, byID[id])
+	}
+	return out
+}
+
+func snapshotBatch(snap *hub.Snapshot) *Batch {
+	b := newBatch()
+	if snap == nil {
+		return b
+	}
+	for id, q := range snap.DailyQuests {
+		b.Quests[id] = &q
+	}
+	for i := range snap.Bundles {
+		b.Bundles[snap.Bundles[i].TemplateID] = &snap.Bundles[i]
+	}
+	for i := range snap.Schedules {
+		b.Schedules[snap.Schedules[i].TemplateID] = &snap.Schedules[i]
+	}
+	return b
+}
+
+// the backend is written first, under the lock, so the model never runs
+// ahead of what is persisted and writes reach it in order
+func (s *Store) write(ctx context.Context, b *Batch, at time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return ErrClosed
+	}
+
+	seenAt := at
+	if seenAt.IsZero() {
+		seenAt = time.Now().UTC()
+	}
+	b.Lifetimes = s.lifetimesFor(b, seenAt)
+
+	if err := s.journalLocked(ctx, b, at); err != nil {
+		return err
+	}
+	if s.backend != nil {
+		if err := s.backend.Write(ctx, b); err != nil {
+			return fmt.Errorf("store: write: %w", err)
+		}
+	}
+	s.applyLocked(b, at)
+	return nil
+}
+
+func (s *Store) applyLocked(b *Batch, at time.Time) {
+	if b.Reset {
+		fresh := New()
+		s.quests, s.bundles, s.schedules = fresh.quests, fresh.bundles, fresh.schedules
+		s.questsByReward, s.bundlesByReward = fresh.questsByReward, fresh.bundlesByReward
+		s.bundlesByRarity, s.bundlesBySchedule = fresh.bundlesByRarity, fresh.bundlesBySchedule
+	}
+
+	for id, q := range b.Quests {
+		if old, ok := s.quests[id]; ok {
+			for _, r := range old.Rewards {
+				s.questsByReward.remove(rewardKey(r.TemplateID), id)
+			}
+			delete(s.quests, id)
+		}
+		if q == nil {
+			continue
+		}
+		s.quests[id] = q.Clone()
+		for _, r := range q.Rewards {
+			s.questsByReward.add(rewardKey(r.TemplateID), id)
+		}
+	}
+
+	for id, bundle := range b.Bundles {
+		if old, ok := s.bundles[id]; ok {
+			s.unindexBundle(id, &old)
+			delete(s.bundles, id)
+		}
+		if bundle == nil {
+			continue
+		}
+		s.bundles[id] = bundle.Clone()
+		s.indexBundle(id, bundle)
+	}
+
+	for id, sched := range b.Schedules {
+		if sched == nil {
+			delete(s.schedules, id)
+			continue
+		}
+		s.schedules[id] = *sched
+	}
+	s.applyLifetimesLocked(b.Lifetimes)
+
+	s.version++
+	if !at.IsZero() {
+		s.updated = at
+	}
+}
+
+func (s *Store) indexBundle(id string, b *hub.AthenaChallengeBundle) {
+	for _, key := range bundleRewardKeys(b) {
+		s.bundlesByReward.add(key, id)
+	}
+	s.bundlesByRarity.add(strings.ToLower(b.Rarity), id)
+	s.bundlesBySchedule.add(b.ChallengeBundleSchedule, id)
+}
+
+func (s *Store) unindexBundle(id string, b *hub.AthenaChallengeBundle) {
+	for _, key := range bundleRewardKeys(b) {
+		s.bundlesByReward.remove(key, id)
+	}
+	s.bundlesByRarity.remove(strings.ToLower(b.Rarity), id)
+	s.bundlesBySchedule.remove(b.ChallengeBundleSchedule, id)
+}
+
+// a bundle is indexed under the rewards of its quests and its completion
+func bundleRewardKeys(b *hub.AthenaChallengeBundle) []string {
+	var keys []string
+	for _, o := range b.Objects {
+		for _, r := range o.Rewards {
+			keys = append(keys, rewardKey(r.TemplateID))
+		}
+	}
+	for _, r := range b.CompletionRewards {
+		keys = append(keys, rewardKey(r.TemplateID))
+	}
+	return keys
+}
+
+// matches template IDs the way the rewards package does, so a bare
+// AthenaBattleStar finds AccountResource:athenabattlestar
+func rewardKey(templateID string) string {
+	return strings.ToLower(rewards.Parse(templateID).String())
+}
+
+type set map[string]struct{}
+
+// key -> IDs
+type index map[string]set
+
+func (ix index) add(key, id string) {
+	ids, ok := ix[key]
+	if !ok {
+		ids = make(set)
+		ix[key] = ids
+	}
+	ids[id] = struct{}{}
+}
+
+func (ix index) remove(key, id string) {
+	if ids, ok := ix[key]; ok {
+		delete(ids, id)
+		if len(ids) == 0 {
+			delete(ix, key)
+		}
+	}
+}
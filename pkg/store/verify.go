@@ -0,0 +1,93 @@
+package store
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+
+	"github.com/ilyskies/QuestHub/pkg/hub"
+)
+
+// Divergence is an entry the store holds differently from the hub. Table
+// is "quest", "bundle" or "schedule"; Fields lists what differs for stale
+// entries.
+type Divergence struct {
+	Table     string             `json:"table"`
+	ID        string             `json:"id"`
+	Kind      hub.DivergenceKind `json:"kind"`
+	LocalHash string             `json:"localHash,omitempty"`
+	LiveHash  string             `json:"liveHash,omitempty"`
+	Fields    []string           `json:"fields,omitempty"`
+}
+
+type VerifyReport struct {
+	Checked     int          `json:"checked"`
+	Divergences []Divergence `json:"divergences"`
+	Repaired    bool         `json:"repaired"`
+}
+
+// Verify compares the model with live, a fresh snapshot of the hub, for
+// when the events feeding the store may have been lost or misapplied. With
+// repair set and anything diverging, the store is Reset to live.
+func (s *Store) Verify(ctx context.Context, live *hub.Snapshot, repair bool) (*VerifyReport, error) {
+	if live == nil {
+		return nil, ErrNilSnapshot
+	}
+	report := diverge(s.Snapshot(), live)
+
+	if repair && len(report.Divergences) > 0 {
+		if err := s.Reset(ctx, live); err != nil {
+			return report, err
+		}
+		report.Repaired = true
+	}
+	return report, nil
+}
+
+// diverge reports how local differs from want, which is taken to be right
+func diverge(local, want *hub.Snapshot) *VerifyReport {
+	cs := hub.Diff(local, want)
+
+	report := &VerifyReport{
+		Checked:     len(want.DailyQuests) + len(want.Bundles) + len(want.Schedules),
+		Divergences: make([]Divergence, 0, cs.Len()),
+	}
+	for _, d := range cs.Quests {
+		report.add("quest", d.ID, d.Kind, d.Old, d.New, d.Fields)
+	}
+	for _, d := range cs.Bundles {
+		report.add("bundle", d.TemplateID, d.Kind, d.Old, d.New, d.Fields)
+	}
+	for _, d := range cs.Schedules {
+		report.add("schedule", d.TemplateID, d.Kind, d.Old, d.New, d.Fields)
+	}
+	return report
+}
+
+func (r *VerifyReport) add(table, id string, kind hub.ChangeKind, local, live interface{}, fields []string) {
+	d := Divergence{Table: table, ID: id, Fields: fields}
+
+	switch kind {
+	case hub.ChangeAdded:
+		d.Kind = hub.DivergenceMissing
+		d.LiveHash = entryHash(live)
+	case hub.ChangeRemoved:
+		r.Checked++
+		d.Kind = hub.DivergenceOrphaned
+		d.LocalHash = entryHash(local)
+	default:
+		d.Kind = hub.DivergenceStale
+		d.LocalHash, d.LiveHash = entryHash(local), entryHash(live)
+	}
+	r.Divergences = append(r.Divergences, d)
+}
+
+func entryHash(v interface{}) string {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
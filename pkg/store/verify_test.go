@@ -0,0 +1,66 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ilyskies/QuestHub/pkg/hub"
+)
+
+func TestVerifyReportsAndRepairs(t *testing.T) {
+	ctx := context.Background()
+
+	s := New()
+	local := &hub.Snapshot{DailyQuests: map[string]hub.BaseQuest{
+		"Quest_Changed": {Count: 3},
+		"Quest_Removed": {Count: 1},
+	}}
+	if err := s.Reset(ctx, local); err != nil {
+		t.Fatal(err)
+	}
+
+	live := &hub.Snapshot{DailyQuests: map[string]hub.BaseQuest{
+		"Quest_Changed": {Count: 4},
+		"Quest_Added":   {Count: 1},
+	}}
+	report, err := s.Verify(ctx, live, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]hub.DivergenceKind{
+		"Quest_Added":   hub.DivergenceMissing,
+		"Quest_Changed": hub.DivergenceStale,
+		"Quest_Removed": hub.DivergenceOrphaned,
+	}
+	if len(report.Divergences) != len(want) {
+		t.Fatalf("got %+v, want %d divergences", report.Divergences, len(want))
+	}
+	for _, d := range report.Divergences {
+		if d.Table != "quest" || want[d.ID] != d.Kind {
+			t.Errorf("%s: got %s %s, want quest %s", d.ID, d.Table, d.Kind, want[d.ID])
+		}
+	}
+	if report.Checked != 3 || report.Repaired {
+		t.Errorf("got checked %d, repaired %v; want 3, false", report.Checked, report.Repaired)
+	}
+
+	if report, err = s.Verify(ctx, live, true); err != nil || !report.Repaired {
+		t.Fatalf("repair: %+v, %v", report, err)
+	}
+	if report, err = s.Verify(ctx, live, false); err != nil || len(report.Divergences) != 0 {
+		t.Fatalf("after repair: %+v, %v", report, err)
+	}
+}
+
+func TestNilSnapshot(t *testing.T) {
+	ctx := context.Background()
+	s := New()
+	if err := s.Reset(ctx, nil); !errors.Is(err, ErrNilSnapshot) {
+		t.Errorf("Reset(nil) = %v, want ErrNilSnapshot", err)
+	}
+	if _, err := s.Verify(ctx, nil, true); !errors.Is(err, ErrNilSnapshot) {
+		t.Errorf("Verify(nil) = %v, want ErrNilSnapshot", err)
+	}
+}